@@ -0,0 +1,138 @@
+package lawapi
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultAbbreviations maps common abbreviations and short names of laws to their titles
+var DefaultAbbreviations = map[string]string{
+	"個情法":        "個人情報の保護に関する法律",
+	"個人情報保護法":    "個人情報の保護に関する法律",
+	"独禁法":        "私的独占の禁止及び公正取引の確保に関する法律",
+	"独占禁止法":      "私的独占の禁止及び公正取引の確保に関する法律",
+	"景表法":        "不当景品類及び不当表示防止法",
+	"景品表示法":      "不当景品類及び不当表示防止法",
+	"特商法":        "特定商取引に関する法律",
+	"特定商取引法":     "特定商取引に関する法律",
+	"消契法":        "消費者契約法",
+	"番号法":        "行政手続における特定の個人を識別するための番号の利用等に関する法律",
+	"マイナンバー法":    "行政手続における特定の個人を識別するための番号の利用等に関する法律",
+	"労基法":        "労働基準法",
+	"労契法":        "労働契約法",
+	"安衛法":        "労働安全衛生法",
+	"労安法":        "労働安全衛生法",
+	"派遣法":        "労働者派遣事業の適正な運営の確保及び派遣労働者の保護等に関する法律",
+	"労働者派遣法":     "労働者派遣事業の適正な運営の確保及び派遣労働者の保護等に関する法律",
+	"育介法":        "育児休業、介護休業等育児又は家族介護を行う労働者の福祉に関する法律",
+	"育児介護休業法":    "育児休業、介護休業等育児又は家族介護を行う労働者の福祉に関する法律",
+	"均等法":        "雇用の分野における男女の均等な機会及び待遇の確保等に関する法律",
+	"男女雇用機会均等法":  "雇用の分野における男女の均等な機会及び待遇の確保等に関する法律",
+	"高年齢者雇用安定法":  "高年齢者等の雇用の安定等に関する法律",
+	"障害者差別解消法":   "障害を理由とする差別の解消の推進に関する法律",
+	"金商法":        "金融商品取引法",
+	"犯収法":        "犯罪による収益の移転防止に関する法律",
+	"資金決済法":      "資金決済に関する法律",
+	"組織犯罪処罰法":    "組織的な犯罪の処罰及び犯罪収益の規制等に関する法律",
+	"不正アクセス禁止法":  "不正アクセス行為の禁止等に関する法律",
+	"特定電子メール法":   "特定電子メールの送信の適正化等に関する法律",
+	"情プラ法":       "特定電気通信による情報の流通によって発生する権利侵害等への対処に関する法律",
+	"プロバイダ責任制限法": "特定電気通信による情報の流通によって発生する権利侵害等への対処に関する法律",
+	"不競法":        "不正競争防止法",
+	"廃掃法":        "廃棄物の処理及び清掃に関する法律",
+	"廃棄物処理法":     "廃棄物の処理及び清掃に関する法律",
+	"風営法":        "風俗営業等の規制及び業務の適正化等に関する法律",
+	"道交法":        "道路交通法",
+	"入管法":        "出入国管理及び難民認定法",
+	"建基法":        "建築基準法",
+	"宅建業法":       "宅地建物取引業法",
+	"大店立地法":      "大規模小売店舗立地法",
+	"薬機法":        "医薬品、医療機器等の品質、有効性及び安全性の確保等に関する法律",
+	"食衛法":        "食品衛生法",
+	"電事法":        "電気事業法",
+	"電帳法":        "電子計算機を使用して作成する国税関係帳簿書類の保存方法等の特例に関する法律",
+	"電子帳簿保存法":    "電子計算機を使用して作成する国税関係帳簿書類の保存方法等の特例に関する法律",
+	"租特法":        "租税特別措置法",
+	"行手法":        "行政手続法",
+	"行訴法":        "行政事件訴訟法",
+	"行審法":        "行政不服審査法",
+	"情報公開法":      "行政機関の保有する情報の公開に関する法律",
+	"民訴法":        "民事訴訟法",
+	"刑訴法":        "刑事訴訟法",
+	"民執法":        "民事執行法",
+	"民再法":        "民事再生法",
+	"会更法":        "会社更生法",
+	"公選法":        "公職選挙法",
+	"国公法":        "国家公務員法",
+	"地公法":        "地方公務員法",
+	"地自法":        "地方自治法",
+}
+
+// AbbreviationDictionary expands abbreviations of law titles. Lookups are normalized with
+// NormalizeText, so full-width and half-width forms match.
+type AbbreviationDictionary struct {
+	mu      sync.RWMutex
+	entries map[string]string
+}
+
+// NewAbbreviationDictionary creates a dictionary containing DefaultAbbreviations
+func NewAbbreviationDictionary() *AbbreviationDictionary {
+	d := &AbbreviationDictionary{entries: map[string]string{}}
+	for abbreviation, title := range DefaultAbbreviations {
+		d.Add(abbreviation, title)
+	}
+	return d
+}
+
+// Add adds or replaces an abbreviation
+func (d *AbbreviationDictionary) Add(abbreviation, title string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[NormalizeText(abbreviation)] = title
+}
+
+// Load adds the abbreviations of a YAML mapping from abbreviations to titles:
+//
+//	電波法施規: 電波法施行規則
+//	免許規則: 無線局免許手続規則
+func (d *AbbreviationDictionary) Load(r io.Reader) error {
+	var entries map[string]string
+	if err := yaml.NewDecoder(r).Decode(&entries); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to parse abbreviations: %w", err)
+	}
+	for abbreviation, title := range entries {
+		if strings.TrimSpace(title) == "" {
+			return fmt.Errorf("abbreviation %q has no title", abbreviation)
+		}
+		d.Add(abbreviation, title)
+	}
+	return nil
+}
+
+// Lookup returns the title of an abbreviation
+func (d *AbbreviationDictionary) Lookup(abbreviation string) (string, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	title, ok := d.entries[NormalizeText(abbreviation)]
+	return title, ok
+}
+
+// Expand returns the title of an abbreviation, or s unchanged if it is not one
+func (d *AbbreviationDictionary) Expand(s string) string {
+	if title, ok := d.Lookup(strings.TrimSpace(s)); ok {
+		return title
+	}
+	return s
+}
+
+var defaultAbbreviationDictionary = sync.OnceValue(NewAbbreviationDictionary)
+
+// ExpandAbbreviation returns the title of a law abbreviated as s in DefaultAbbreviations
+// (e.g. "個人情報の保護に関する法律" for "個情法"), or s unchanged
+func ExpandAbbreviation(s string) string {
+	return defaultAbbreviationDictionary().Expand(s)
+}
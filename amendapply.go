@@ -0,0 +1,572 @@
+package lawapi
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// UnappliedInstruction is an amendment instruction that could not be applied
+type UnappliedInstruction struct {
+	Instruction AmendmentInstruction `json:"instruction"`
+	// Reason explains why the instruction was not applied
+	Reason string `json:"reason"`
+	// Err is the error the instruction failed with, e.g. wrapping ErrTextNotFound
+	Err error `json:"-"`
+}
+
+// AmendmentReport is the outcome of applying amendment instructions to a law
+type AmendmentReport struct {
+	// Applied is the number of instructions applied
+	Applied int `json:"applied"`
+	// Unapplied are the instructions that were not applied, in order
+	Unapplied []UnappliedInstruction `json:"unapplied,omitempty"`
+}
+
+// ParseAmendmentInstructions splits amendment instructions (改め文), such as the main
+// provision of an amending law or the output of GenerateAmendmentInstructions, into one
+// instruction per line. Lines following an instruction adding provisions (次の…を加える)
+// that are not instructions themselves are its body. Chained instructions are split at
+// the continuative verb (「…に改め、同条に次の一項を加える。」 becomes two instructions).
+// Provision is set to the amended article, resolving 同条 from the preceding instruction.
+func ParseAmendmentInstructions(text string) []AmendmentInstruction {
+	var instructions []AmendmentInstruction
+	var prev ProvisionID
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Trim(line, " \t\r　")
+		if line == "" {
+			continue
+		}
+		if n := len(instructions); n > 0 && expectsBody(instructions[n-1].Text) && !isInstruction(line) {
+			last := &instructions[n-1]
+			if last.Body != "" {
+				last.Body += "\n"
+			}
+			last.Body += line
+			continue
+		}
+		for _, text := range splitChainedInstructions(line) {
+			instruction := AmendmentInstruction{Text: text}
+			if id, _, ok := parseAmendTarget(text, prev); ok {
+				prev = id
+				instruction.Provision = ProvisionID{Article: id.Article}
+			}
+			instructions = append(instructions, instruction)
+		}
+	}
+	return instructions
+}
+
+// ApplyAmendment applies amendment instructions to the law in order, reconstructing the
+// amended text. Supported instructions are, for an article, paragraph, item or sub-item
+// cited from the article down (e.g. 第二条第一項第三号イ, 同条第二項):
+//
+//   - 「A」を「B」に改める, 「A」を削る, 「A」の下に「B」を加える and 「A」の上に「B」を
+//     加える, chained within one instruction, replacing every occurrence in the sentences
+//     of the provision including its items (…中), or in the article caption (…の見出し中)
+//   - deletions (…を削る) and renumbering within the same kind (…を第Y条とする)
+//   - additions of articles, paragraphs, items and sub-items (…の次に次の二条を加える,
+//     …に次の一項を加える, …に次のように加える) with the provisions in the body
+//   - captions (…に見出しとして「A」を付する, …の見出しを削る)
+//
+// Instructions that cannot be parsed or applied, e.g. because the quoted text does not
+// occur, are reported and skipped, leaving the law as it was before them: the text edits
+// chained in an instruction are applied all or none. Like the mutation helpers,
+// references to renumbered provisions in the text are not updated.
+func (n *LawNode) ApplyAmendment(instructions []AmendmentInstruction) AmendmentReport {
+	var report AmendmentReport
+	var prev ProvisionID
+	for _, instruction := range instructions {
+		err := errors.New("unsupported instruction")
+		if id, rest, ok := parseAmendTarget(instruction.Text, prev); ok {
+			prev = id
+			err = n.applyInstruction(id, rest, instruction.Body)
+		}
+		if err != nil {
+			report.Unapplied = append(report.Unapplied, UnappliedInstruction{Instruction: instruction, Reason: err.Error(), Err: err})
+			continue
+		}
+		report.Applied++
+	}
+	return report
+}
+
+const (
+	kanjiNumeral = `[〇一二三四五六七八九十百千]+`
+	// branchNumerals are the branch numbers (枝番) following a number (e.g. の二の三)
+	branchNumerals = `(?:の` + kanjiNumeral + `)*`
+	// iroha are the sub-item titles of the first level in order
+	iroha = "イロハニホヘトチリヌルヲワカヨタレソツネナラムウヰノオクヤマケフコエテアサキユメミシヱヒモセス"
+)
+
+var (
+	amendTargetPattern = regexp.MustCompile(`^(?:(同条)|第(` + kanjiNumeral + `)条(` + branchNumerals + `))` +
+		`(?:(同項)|第(` + kanjiNumeral + `)項)?` +
+		`(?:第(` + kanjiNumeral + `)号(` + branchNumerals + `))?` +
+		`((?:[` + iroha + `]|（[０-９0-9]+）)*)`)
+	amendAddPattern      = regexp.MustCompile(`^(の次に|の前に|に)次の(?:(` + kanjiNumeral + `)(条|項|号)を|ように)加える。$`)
+	amendRenumberPattern = regexp.MustCompile(`^を第(` + kanjiNumeral + `)(条|項|号)(` + branchNumerals + `)とする。$`)
+	amendCaptionPattern  = regexp.MustCompile(`^に見出しとして「([^「」]+)」を付する。$`)
+	amendVerbPattern     = regexp.MustCompile(`(改める|削る|加える|付する|とする)。$`)
+	// amendBodyPatterns match the lines of added provisions by level: article, paragraph,
+	// item and the first two sub-item levels
+	amendBodyPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`^第(` + kanjiNumeral + `条` + branchNumerals + `)　(.*)$`),
+		regexp.MustCompile(`^([０-９]+)　(.*)$`),
+		regexp.MustCompile(`^(` + kanjiNumeral + branchNumerals + `)　(.*)$`),
+		regexp.MustCompile(`^([` + iroha + `])　(.*)$`),
+		regexp.MustCompile(`^（([０-９]+)）　(.*)$`),
+	}
+)
+
+// amendBodyTags are the elements of the levels of amendBodyPatterns
+var amendBodyTags = []string{"Article", "Paragraph", "Item", "Subitem1", "Subitem2"}
+
+func expectsBody(text string) bool {
+	return strings.Contains(text, "次の") && strings.HasSuffix(text, "加える。")
+}
+
+// isInstruction reports whether a line is an instruction rather than provision text
+func isInstruction(line string) bool {
+	return amendTargetPattern.MatchString(line) && !strings.Contains(line, "　") && amendVerbPattern.MatchString(line)
+}
+
+// continuativeVerbs finish the instructions chained by their continuative forms
+var continuativeVerbs = map[string]string{"改め": "改める", "削り": "削る", "加え": "加える", "とし": "とする"}
+
+// splitChainedInstructions splits an instruction at the continuative verbs followed by
+// another cited provision, outside quotes
+func splitChainedInstructions(line string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range line {
+		switch r {
+		case '「':
+			depth++
+		case '」':
+			depth--
+		case '、':
+			if depth != 0 {
+				continue
+			}
+			next := line[i+len("、"):]
+			if !strings.HasPrefix(next, "同") && !strings.HasPrefix(next, "第") {
+				continue
+			}
+			for short, verb := range continuativeVerbs {
+				if strings.HasSuffix(line[start:i], short) {
+					parts = append(parts, strings.TrimSuffix(line[start:i], short)+verb+"。")
+					start = i + len("、")
+					break
+				}
+			}
+		}
+	}
+	return append(parts, line[start:])
+}
+
+// parseAmendTarget parses the provision cited at the start of an instruction, resolving
+// 同条 and 同項 from prev, and returns it with the rest of the instruction
+func parseAmendTarget(text string, prev ProvisionID) (ProvisionID, string, bool) {
+	m := amendTargetPattern.FindStringSubmatch(text)
+	if m == nil {
+		return ProvisionID{}, "", false
+	}
+	var id ProvisionID
+	if m[1] != "" {
+		if prev.Article == "" {
+			return ProvisionID{}, "", false
+		}
+		id.Article = prev.Article
+	} else {
+		id.Article = parseKanjiNum(m[2] + m[3])
+	}
+	switch {
+	case m[4] != "":
+		if prev.Article != id.Article || prev.Paragraph == "" {
+			return ProvisionID{}, "", false
+		}
+		id.Paragraph = prev.Paragraph
+	case m[5] != "":
+		id.Paragraph = parseKanjiNum(m[5])
+	}
+	if m[6] != "" {
+		nums := []string{parseKanjiNum(m[6] + m[7])}
+		for title := m[8]; title != ""; {
+			var num string
+			num, title = subitemNum(title)
+			nums = append(nums, num)
+		}
+		id.Item = strings.Join(nums, ".")
+		if id.Paragraph == "" {
+			id.Paragraph = "1"
+		}
+	}
+	return id, text[len(m[0]):], true
+}
+
+// parseKanjiNum parses a kanji number with branch numbers such as "二" or "二の三" as a Num
+// attribute ("2", "2_3")
+func parseKanjiNum(s string) string {
+	parts := strings.Split(s, "の")
+	for i, part := range parts {
+		if v, ok := ParseKanjiNumber(part); ok {
+			parts[i] = strconv.FormatInt(v, 10)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// subitemNum returns the Num attribute of the sub-item title at the start of s (イ, ロ, ...,
+// or （１）, （２）, ...) and the rest of s
+func subitemNum(s string) (string, string) {
+	if rest, ok := strings.CutPrefix(s, "（"); ok {
+		digits, rest, _ := strings.Cut(rest, "）")
+		return strconv.Itoa(parseDigits(digits)), rest
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return strconv.Itoa(slices.Index([]rune(iroha), r) + 1), s[size:]
+}
+
+// parseDigits parses half-width or full-width decimal digits
+func parseDigits(s string) int {
+	v := 0
+	for _, r := range s {
+		if r >= '０' && r <= '９' {
+			r = r - '０' + '0'
+		}
+		v = v*10 + int(r-'0')
+	}
+	return v
+}
+
+// applyInstruction applies the rest of an instruction to the provision id
+func (n *LawNode) applyInstruction(id ProvisionID, rest, body string) error {
+	target := n.Provision(id)
+	if target == nil {
+		return fmt.Errorf("%s: %w", provisionLabel(id), ErrProvisionNotFound)
+	}
+	caption := target.Child("ArticleCaption")
+
+	if edits, ok := strings.CutPrefix(rest, "の見出し中"); ok {
+		if caption == nil {
+			return fmt.Errorf("%s has no caption", provisionLabel(id))
+		}
+		return applyTextEdits([]*LawNode{caption}, edits)
+	}
+	if edits, ok := strings.CutPrefix(rest, "中"); ok {
+		return applyTextEdits(target.FindAll("Sentence"), edits)
+	}
+	switch rest {
+	case "を削る。":
+		if target.Tag == "Article" {
+			return n.RemoveArticle(target.Num())
+		}
+		parent := n.parentOf(target)
+		parent.Children = slices.DeleteFunc(parent.Children, func(c *LawNode) bool { return c == target })
+		return nil
+	case "の見出しを削る。":
+		if caption == nil {
+			return fmt.Errorf("%s has no caption", provisionLabel(id))
+		}
+		target.Children = slices.DeleteFunc(target.Children, func(c *LawNode) bool { return c == caption })
+		return nil
+	}
+	if m := amendCaptionPattern.FindStringSubmatch(rest); m != nil {
+		if target.Tag != "Article" || caption != nil {
+			return fmt.Errorf("cannot add a caption to %s", provisionLabel(id))
+		}
+		target.Children = slices.Insert(target.Children, 0, textElement("ArticleCaption", "（"+strings.Trim(m[1], "（）")+"）"))
+		return nil
+	}
+	if m := amendRenumberPattern.FindStringSubmatch(rest); m != nil {
+		return n.renumberProvision(target, parseKanjiNum(m[1]+m[3]), m[2])
+	}
+	if m := amendAddPattern.FindStringSubmatch(rest); m != nil {
+		return n.addProvisions(target, m[1], m[3], body)
+	}
+	return errors.New("unsupported instruction")
+}
+
+// provisionLabel cites a provision ID for error messages
+func provisionLabel(id ProvisionID) string {
+	label := articleTitle(id.Article)
+	if id.Paragraph != "" {
+		label += "第" + formatKanjiNum(id.Paragraph) + "項"
+	}
+	if id.Item != "" {
+		nums := strings.Split(id.Item, ".")
+		label += "第" + formatKanjiNum(nums[0]) + "号"
+		for i, num := range nums[1:] {
+			v, _ := strconv.Atoi(num)
+			if i == 0 && v >= 1 && v <= utf8.RuneCountInString(iroha) {
+				label += string([]rune(iroha)[v-1])
+			} else {
+				label += "（" + num + "）"
+			}
+		}
+	}
+	return label
+}
+
+// textEdit replaces old with new
+type textEdit struct {
+	old, new string
+}
+
+// applyTextEdits applies chained text edits (「A」を「B」に改め、「C」を削る。) to every
+// occurrence in the nodes. The edits are made on copies of the nodes in order, and the
+// nodes are only changed if every quoted text occurred.
+func applyTextEdits(nodes []*LawNode, s string) error {
+	edits, err := parseTextEdits(s)
+	if err != nil {
+		return err
+	}
+	copies := make([]*LawNode, len(nodes))
+	for i, node := range nodes {
+		copies[i] = node.clone()
+	}
+	for _, edit := range edits {
+		count := 0
+		for _, node := range copies {
+			count += replaceText(node, edit.old, edit.new)
+		}
+		if count == 0 {
+			return fmt.Errorf("「%s」: %w", edit.old, ErrTextNotFound)
+		}
+	}
+	for i, node := range nodes {
+		node.Text, node.Children = copies[i].Text, copies[i].Children
+	}
+	return nil
+}
+
+// parseTextEdits parses chained text edits
+func parseTextEdits(s string) ([]textEdit, error) {
+	var edits []textEdit
+	for s != "。" {
+		old, rest, err := cutQuote(s)
+		if err != nil {
+			return nil, err
+		}
+		var new string
+		switch {
+		case strings.HasPrefix(rest, "を「"):
+			if new, rest, err = cutQuote(strings.TrimPrefix(rest, "を")); err != nil {
+				return nil, err
+			}
+			var ok bool
+			if rest, ok = strings.CutPrefix(rest, "に"); !ok {
+				return nil, fmt.Errorf("unsupported edit of 「%s」", old)
+			}
+		case strings.HasPrefix(rest, "を削"):
+			rest = strings.TrimPrefix(rest, "を")
+		case strings.HasPrefix(rest, "の下に「"), strings.HasPrefix(rest, "の上に「"):
+			below := strings.HasPrefix(rest, "の下に")
+			var added string
+			if added, rest, err = cutQuote(strings.TrimPrefix(strings.TrimPrefix(rest, "の下に"), "の上に")); err != nil {
+				return nil, err
+			}
+			var ok bool
+			if rest, ok = strings.CutPrefix(rest, "を"); !ok {
+				return nil, fmt.Errorf("unsupported edit of 「%s」", old)
+			}
+			if below {
+				new = old + added
+			} else {
+				new = added + old
+			}
+		default:
+			return nil, fmt.Errorf("unsupported edit of 「%s」", old)
+		}
+		for _, verb := range []string{"改める", "改め", "削る", "削り", "加える", "加え"} {
+			if r, ok := strings.CutPrefix(rest, verb); ok {
+				rest = r
+				break
+			}
+		}
+		s = strings.TrimPrefix(rest, "、")
+		edits = append(edits, textEdit{old: old, new: new})
+	}
+	return edits, nil
+}
+
+// cutQuote returns the text of the quote (「…」, possibly nested) at the start of s and
+// the rest of s
+func cutQuote(s string) (string, string, error) {
+	if !strings.HasPrefix(s, "「") {
+		return "", "", fmt.Errorf("expected a quote at %q", s)
+	}
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '「':
+			depth++
+		case '」':
+			if depth--; depth == 0 {
+				return s[len("「"):i], s[i+len("」"):], nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("unterminated quote %q", s)
+}
+
+// renumberProvision changes the number of an article, paragraph or item within its parent
+func (n *LawNode) renumberProvision(target *LawNode, num, counter string) error {
+	parent := n.parentOf(target)
+	switch {
+	case counter == "条" && target.Tag == "Article":
+		if n.Article(num) != nil {
+			return fmt.Errorf("article %s already exists", num)
+		}
+		target.Attr["Num"] = num
+		if title := target.Child("ArticleTitle"); title != nil {
+			setText(title, articleTitle(num))
+		}
+		n.RefreshTOC()
+		return nil
+	case counter == "項" && target.Tag == "Paragraph", counter == "号" && target.Tag == "Item":
+		if childByNum(parent.ChildrenByTag(target.Tag), num) != nil {
+			return fmt.Errorf("%s %s already exists", target.Tag, num)
+		}
+		target.Attr["Num"] = num
+		titleTag, title := "ItemTitle", formatKanjiNum(num)
+		if target.Tag == "Paragraph" {
+			titleTag = "ParagraphNum"
+			title = strings.Map(func(r rune) rune { return r - '0' + '０' }, num)
+		}
+		if t := target.Child(titleTag); t != nil {
+			setText(t, title)
+		}
+		return nil
+	}
+	return fmt.Errorf("cannot renumber %s as %s", target.Tag, counter)
+}
+
+// addProvisions inserts the provisions of body after (の次に) or before (の前に) target,
+// or appends them to it (に)
+func (n *LawNode) addProvisions(target *LawNode, position, counter, body string) error {
+	tag := map[string]string{"条": "Article", "項": "Paragraph", "号": "Item"}[counter]
+	parent, index := n.parentOf(target), 0
+	switch {
+	case position != "に":
+		if tag != "" && tag != target.Tag {
+			return fmt.Errorf("cannot add %s next to %s", counter, target.Tag)
+		}
+		tag = target.Tag
+		index = slices.Index(parent.Children, target)
+		if position == "の次に" {
+			index++
+		}
+	case tag == "Article":
+		return errors.New("cannot add articles to an article")
+	default:
+		// Appended to the end of the article, paragraph or item; items of an article go
+		// to its only paragraph
+		parent = target
+		if tag == "Item" && target.Tag == "Article" {
+			if paragraphs := target.Paragraphs(); len(paragraphs) == 1 {
+				parent = paragraphs[0]
+			}
+		}
+		if tag == "" {
+			// Sub-items (次のように加える)
+			if level := subitemLevel(parent.Tag); parent.Tag == "Item" || level > 0 {
+				tag = "Subitem" + strconv.Itoa(level+1)
+			}
+		}
+		if tag == "" || (tag == "Paragraph") != (parent.Tag == "Article") || (tag == "Item") != (parent.Tag == "Paragraph") {
+			return fmt.Errorf("cannot add %s to %s", counter, parent.Tag)
+		}
+		index = len(parent.Children)
+	}
+
+	nodes, err := parseProvisionBody(body, tag)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if tag == "Article" && n.Article(node.Num()) != nil || tag != "Article" && childByNum(parent.ChildrenByTag(tag), node.Num()) != nil {
+			return fmt.Errorf("%s %s already exists", tag, node.Num())
+		}
+		var violations []SchemaViolation
+		validateNode(node, "/"+tag, &violations)
+		if len(violations) > 0 {
+			return fmt.Errorf("invalid %s: %s", tag, violations[0])
+		}
+	}
+	parent.Children = slices.Insert(parent.Children, index, nodes...)
+	if tag == "Article" {
+		n.RefreshTOC()
+	}
+	return nil
+}
+
+// parseProvisionBody builds the provisions added by an instruction from its body, one
+// line per caption, paragraph, item and sub-item as laid out by provisionLines. tag is
+// the element of the added provisions.
+func parseProvisionBody(body, tag string) ([]*LawNode, error) {
+	top := slices.Index(amendBodyTags, tag)
+	if top < 0 {
+		return nil, fmt.Errorf("cannot add %s", tag)
+	}
+	var nodes []*LawNode
+	// open are the latest provisions of each level
+	open := make([]*LawNode, len(amendBodyTags))
+	caption := ""
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.Trim(line, " \t\r　")
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "（") && strings.HasSuffix(line, "）") && !strings.Contains(line, "　") {
+			caption = line
+			continue
+		}
+		level := -1
+		var m []string
+		for i, pattern := range amendBodyPatterns {
+			if m = pattern.FindStringSubmatch(line); m != nil {
+				level = i
+				break
+			}
+		}
+		if level < top || (level > top && open[level-1] == nil) {
+			return nil, fmt.Errorf("unexpected line %q", line)
+		}
+
+		var node *LawNode
+		switch level {
+		case 0:
+			node = NewArticle(parseKanjiNum(strings.Replace(m[1], "条", "", 1)), caption, m[2])
+			caption = ""
+		case 1:
+			node = newProvision("Paragraph", strconv.Itoa(parseDigits(m[1])), m[1], m[2])
+		case 2:
+			node = newProvision("Item", parseKanjiNum(m[1]), m[1], m[2])
+		case 3:
+			num, _ := subitemNum(m[1])
+			node = newProvision("Subitem1", num, m[1], m[2])
+		case 4:
+			node = newProvision("Subitem2", strconv.Itoa(parseDigits(m[1])), "（"+m[1]+"）", m[2])
+		}
+		if level == top {
+			nodes = append(nodes, node)
+		} else {
+			open[level-1].Children = append(open[level-1].Children, node)
+		}
+		open[level] = node
+		clear(open[level+1:])
+		if level == 0 {
+			open[1] = node.Child("Paragraph")
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New("no provisions to add")
+	}
+	return nodes, nil
+}
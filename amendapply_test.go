@@ -0,0 +1,183 @@
+package lawapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const amendTestLaw = `<Law><LawBody><LawTitle>試験法</LawTitle><MainProvision>
+<Article Num="1"><ArticleCaption>（目的）</ArticleCaption><ArticleTitle>第一条</ArticleTitle>
+<Paragraph Num="1"><ParagraphNum/><ParagraphSentence><Sentence>この法律は、甲の保護を目的とする。</Sentence></ParagraphSentence></Paragraph>
+<Paragraph Num="2"><ParagraphNum>２</ParagraphNum><ParagraphSentence><Sentence>甲は、乙に従う。</Sentence></ParagraphSentence></Paragraph>
+</Article>
+<Article Num="2"><ArticleTitle>第二条</ArticleTitle>
+<Paragraph Num="1"><ParagraphNum/><ParagraphSentence><Sentence>次に掲げる者は、届け出なければならない。</Sentence></ParagraphSentence>
+<Item Num="1"><ItemTitle>一</ItemTitle><ItemSentence><Sentence>甲</Sentence></ItemSentence></Item>
+<Item Num="2"><ItemTitle>二</ItemTitle><ItemSentence><Sentence>乙</Sentence></ItemSentence></Item>
+</Paragraph>
+</Article>
+<Article Num="3"><ArticleTitle>第三条</ArticleTitle>
+<Paragraph Num="1"><ParagraphNum/><ParagraphSentence><Sentence>削除</Sentence></ParagraphSentence></Paragraph>
+</Article>
+</MainProvision></LawBody></Law>`
+
+// amendTestText returns the caption and sentences of a provision of the law, or "-" if it
+// does not exist
+func amendTestText(law *LawNode, id ProvisionID) string {
+	node := law.Provision(id)
+	if node == nil {
+		return "-"
+	}
+	var parts []string
+	if caption := node.Child("ArticleCaption"); caption != nil {
+		parts = append(parts, caption.PlainText())
+	}
+	for _, sentence := range node.FindAll("Sentence") {
+		parts = append(parts, sentence.PlainText())
+	}
+	return strings.Join(parts, "/")
+}
+
+func TestApplyAmendment(t *testing.T) {
+	article1 := "（目的）/この法律は、甲の保護を目的とする。/甲は、乙に従う。"
+	tests := []struct {
+		name         string
+		instructions string
+		applied      int
+		// err is the error of the first unapplied instruction
+		err  error
+		want map[ProvisionID]string
+	}{
+		{
+			name:         "replace",
+			instructions: "第一条第一項中「甲」を「丙」に改める。",
+			applied:      1,
+			want: map[ProvisionID]string{
+				{Article: "1", Paragraph: "1"}: "この法律は、丙の保護を目的とする。",
+				{Article: "1", Paragraph: "2"}: "甲は、乙に従う。",
+			},
+		},
+		{
+			name:         "chained edits in every paragraph",
+			instructions: "第一条中「甲」を「丙」に改め、「の保護」を削り、「乙」の下に「等」を加え、「この」の上に「特に」を加える。",
+			applied:      1,
+			want:         map[ProvisionID]string{{Article: "1"}: "（目的）/特にこの法律は、丙を目的とする。/丙は、乙等に従う。"},
+		},
+		{
+			name:         "edit in caption",
+			instructions: "第一条の見出し中「目的」を「趣旨」に改める。",
+			applied:      1,
+			want:         map[ProvisionID]string{{Article: "1"}: "（趣旨）/この法律は、甲の保護を目的とする。/甲は、乙に従う。"},
+		},
+		{
+			name:         "delete provisions",
+			instructions: "第二条第一号を削る。\n第三条を削る。",
+			applied:      2,
+			want: map[ProvisionID]string{
+				{Article: "2", Paragraph: "1", Item: "1"}: "-",
+				{Article: "2", Paragraph: "1", Item: "2"}: "乙",
+				{Article: "3"}: "-",
+			},
+		},
+		{
+			name:         "add article and paragraph",
+			instructions: "第二条の次に次の一条を加える。\n（届出）\n第二条の二　届出は、書面でしなければならない。\n第二条の二に次の一項を加える。\n２　前項の規定は、丙に適用しない。",
+			applied:      2,
+			want: map[ProvisionID]string{
+				{Article: "2_2"}:                 "（届出）/届出は、書面でしなければならない。/前項の規定は、丙に適用しない。",
+				{Article: "2_2", Paragraph: "2"}: "前項の規定は、丙に適用しない。",
+				{Article: "3"}:                   "削除",
+			},
+		},
+		{
+			name:         "add item",
+			instructions: "第二条に次の一号を加える。\n三　丙",
+			applied:      1,
+			want:         map[ProvisionID]string{{Article: "2", Paragraph: "1", Item: "3"}: "丙"},
+		},
+		{
+			name:         "renumber",
+			instructions: "第三条を第四条とする。\n第二条第一項第二号を第三号とする。",
+			applied:      2,
+			want: map[ProvisionID]string{
+				{Article: "3"}: "-",
+				{Article: "4"}: "削除",
+				{Article: "2", Paragraph: "1", Item: "2"}: "-",
+				{Article: "2", Paragraph: "1", Item: "3"}: "乙",
+			},
+		},
+		{
+			name:         "renumber to an existing article",
+			instructions: "第三条を第二条とする。",
+			want:         map[ProvisionID]string{{Article: "3"}: "削除"},
+		},
+		{
+			name:         "captions",
+			instructions: "第一条の見出しを削る。\n第二条に見出しとして「（届出）」を付する。",
+			applied:      2,
+			want: map[ProvisionID]string{
+				{Article: "1"}: "この法律は、甲の保護を目的とする。/甲は、乙に従う。",
+				{Article: "2"}: "（届出）/次に掲げる者は、届け出なければならない。/甲/乙",
+			},
+		},
+		{
+			name:         "partial failure leaves the text unchanged",
+			instructions: "第一条中「甲」を「丙」に改め、「丁」を削る。",
+			err:          ErrTextNotFound,
+			want:         map[ProvisionID]string{{Article: "1"}: article1},
+		},
+		{
+			name:         "later instructions apply after a failure",
+			instructions: "第一条中「丁」を「丙」に改める。\n同条第二項中「乙」を「丙」に改める。",
+			applied:      1,
+			err:          ErrTextNotFound,
+			want:         map[ProvisionID]string{{Article: "1", Paragraph: "2"}: "甲は、丙に従う。"},
+		},
+		{
+			name:         "missing provision",
+			instructions: "第九条中「甲」を「丙」に改める。",
+			err:          ErrProvisionNotFound,
+			want:         map[ProvisionID]string{{Article: "1"}: article1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			law, err := ParseLawXML(strings.NewReader(amendTestLaw))
+			if err != nil {
+				t.Fatal(err)
+			}
+			instructions := ParseAmendmentInstructions(tt.instructions)
+			report := law.ApplyAmendment(instructions)
+			if report.Applied != tt.applied || report.Applied+len(report.Unapplied) != len(instructions) {
+				t.Errorf("applied %d of %d, want %d: %+v", report.Applied, len(instructions), tt.applied, report.Unapplied)
+			}
+			if tt.err != nil && (len(report.Unapplied) == 0 || !errors.Is(report.Unapplied[0].Err, tt.err)) {
+				t.Errorf("unapplied = %+v, want an error wrapping %v", report.Unapplied, tt.err)
+			}
+			for id, want := range tt.want {
+				if got := amendTestText(law, id); got != want {
+					t.Errorf("%s = %q, want %q", provisionLabel(id), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAmendmentInstructions(t *testing.T) {
+	text := "第一条中「甲」を「丙」に改め、同条に次の一項を加える。\n３　丙は、丁に従う。\n第二条を削る。"
+	want := []AmendmentInstruction{
+		{Provision: ProvisionID{Article: "1"}, Text: "第一条中「甲」を「丙」に改める。"},
+		{Provision: ProvisionID{Article: "1"}, Text: "同条に次の一項を加える。", Body: "３　丙は、丁に従う。"},
+		{Provision: ProvisionID{Article: "2"}, Text: "第二条を削る。"},
+	}
+	got := ParseAmendmentInstructions(text)
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("instruction %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
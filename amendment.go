@@ -0,0 +1,399 @@
+package lawapi
+
+import (
+	"strings"
+)
+
+// AmendmentInstruction is one sentence of draft amendment instructions (改め文)
+type AmendmentInstruction struct {
+	// Provision identifies the amended article. LawID and Revision are empty.
+	Provision ProvisionID `json:"provision"`
+	// Text is the instruction (e.g. 第三条中「許可」を「登録」に改める。)
+	Text string `json:"text"`
+	// Body is the text of the provisions added by the instruction, one line per caption,
+	// paragraph, item and sub-item, or empty
+	Body string `json:"body,omitempty"`
+}
+
+// String returns the instruction followed by its body
+func (a AmendmentInstruction) String() string {
+	if a.Body == "" {
+		return a.Text
+	}
+	return a.Text + "\n" + a.Body
+}
+
+// GenerateAmendmentInstructions drafts amendment instructions (改め文) turning the old
+// revision of a law into the new one. Articles, paragraphs, items and sub-items are
+// paired by their Num attributes: unpaired provisions are added (「第五条の次に次の一条を
+// 加える。」) or deleted (「第七条を削る。」), and changed text becomes replacements,
+// deletions and insertions of quoted phrases, widened until each quote is unique within
+// its provision (「第三条第二項中「許可」を「登録」に改める。」). Supplementary provisions,
+// including those of amending laws in consolidated texts, are paired by their provision
+// IDs; those without articles are compared paragraph by paragraph.
+//
+// The result is a heuristic first draft: each provision gets its own instruction rather
+// than the combined sentences of real amending laws, renumbered provisions appear as
+// deleted and added, and tables and figures are ignored.
+func GenerateAmendmentInstructions(oldDoc, newDoc *LawNode) []AmendmentInstruction {
+	oldArticles, oldOrder := comparisonArticles(oldDoc)
+	newArticles, newOrder := comparisonArticles(newDoc)
+	toUnits := func(articles map[string]comparisonArticle, order []string) []amendUnit {
+		units := make([]amendUnit, len(order))
+		for i, key := range order {
+			a := articles[key]
+			units[i] = amendUnit{key: key, label: a.label, counter: "条", node: a.node, text: a.text}
+			if a.node.Tag == "SupplProvision" {
+				units[i].counter = ""
+			}
+		}
+		return units
+	}
+
+	d := &amendmentDrafter{}
+	d.diffUnits(toUnits(oldArticles, oldOrder), toUnits(newArticles, newOrder), "", func(key string) ProvisionID {
+		if a, ok := newArticles[key]; ok {
+			return a.id
+		}
+		return oldArticles[key].id
+	})
+	return d.instructions
+}
+
+// amendUnit is an article or a provision within an article
+type amendUnit struct {
+	// key identifies the unit among its counterparts in the other revision
+	key string
+	// parent is the key of the containing unit within the article, or empty
+	parent string
+	// label cites the unit (e.g. 第三条, or 第二項第一号 within an article)
+	label string
+	// counter is the counter word of the unit (条, 項 or 号), or empty for sub-items
+	counter string
+	node    *LawNode
+	// text is the text of the unit compared between the revisions
+	text string
+}
+
+type amendmentDrafter struct {
+	instructions []AmendmentInstruction
+	// id is the article of the instructions being drafted
+	id ProvisionID
+}
+
+func (d *amendmentDrafter) add(text, body string) {
+	d.instructions = append(d.instructions, AmendmentInstruction{Provision: d.id, Text: text, Body: body})
+}
+
+// diffUnits drafts the instructions turning units into newUnits, in the order of the new
+// revision with deleted units at their old position. Existing units are cited by their
+// old labels. prefix is the label of the article
+// containing the units, or empty for articles; articleID returns the ID of an article key.
+func (d *amendmentDrafter) diffUnits(oldUnits, newUnits []amendUnit, prefix string, articleID func(string) ProvisionID) {
+	oldIndex := map[string]int{}
+	for i, u := range oldUnits {
+		oldIndex[u.key] = i
+	}
+	newKeys := map[string]bool{}
+	for _, u := range newUnits {
+		newKeys[u.key] = true
+	}
+	label := func(u amendUnit) string {
+		if u.label == "" {
+			return prefix
+		}
+		return prefix + u.label
+	}
+	setID := func(key string) {
+		if articleID != nil {
+			d.id = articleID(key)
+		}
+	}
+
+	nextOld := 0
+	deleteUpTo := func(end int) {
+		for ; nextOld < end; nextOld++ {
+			// Provisions of a deleted provision go with it
+			if u := oldUnits[nextOld]; !newKeys[u.key] && (u.parent == "" || newKeys[u.parent]) {
+				setID(u.key)
+				d.add(label(u)+"を削る。", "")
+			}
+		}
+	}
+
+	for i := 0; i < len(newUnits); i++ {
+		u := newUnits[i]
+		if oi, ok := oldIndex[u.key]; ok {
+			deleteUpTo(oi)
+			nextOld = max(nextOld, oi+1)
+			setID(u.key)
+			if u.text != oldUnits[oi].text {
+				if articleID != nil {
+					d.diffArticle(oldUnits[oi], u)
+				} else {
+					// Edits apply throughout the provision, including its items
+					scope := []string{oldUnits[oi].text}
+					for _, sub := range oldUnits[oi+1:] {
+						if strings.HasPrefix(sub.key, u.key+"/") {
+							scope = append(scope, sub.text)
+						}
+					}
+					d.diffText(label(oldUnits[oi]), oldUnits[oi].text, u.text, strings.Join(scope, "\n"))
+				}
+			}
+			continue
+		}
+
+		// A run of added units with the same parent is added by one instruction, together
+		// with their own provisions
+		end := i + 1
+		inRun := map[string]bool{u.key: true}
+		for end < len(newUnits) && !containsKey(oldIndex, newUnits[end].key) &&
+			(newUnits[end].parent == u.parent || inRun[newUnits[end].parent]) {
+			inRun[newUnits[end].key] = true
+			end++
+		}
+		setID(u.key)
+		var body []string
+		count := 0
+		for _, added := range newUnits[i:end] {
+			if added.parent != u.parent {
+				continue
+			}
+			count++
+			for _, line := range provisionLines(added.node) {
+				body = append(body, strings.Repeat("　", line.indent)+line.text)
+			}
+		}
+		what := "次の" + FormatKanjiNumber(int64(count)) + u.counter + "を"
+		if u.counter == "" {
+			what = "次のように"
+		}
+
+		var anchor, next *amendUnit
+		for j := i - 1; j >= 0; j-- {
+			if newUnits[j].parent == u.parent && containsKey(oldIndex, newUnits[j].key) {
+				anchor = &newUnits[j]
+				break
+			}
+			if newUnits[j].key == u.parent {
+				break
+			}
+		}
+		for j := end; j < len(newUnits); j++ {
+			if newUnits[j].parent == u.parent && containsKey(oldIndex, newUnits[j].key) {
+				next = &newUnits[j]
+				break
+			}
+		}
+		switch {
+		case next == nil && articleID == nil:
+			// Appended at the end of the containing provision
+			parentLabel := prefix
+			if pi, ok := oldIndex[u.parent]; ok {
+				parentLabel = label(oldUnits[pi])
+			}
+			d.add(parentLabel+"に"+what+"加える。", strings.Join(body, "\n"))
+		case anchor != nil:
+			d.add(label(oldUnits[oldIndex[anchor.key]])+"の次に"+what+"加える。", strings.Join(body, "\n"))
+		case next != nil:
+			d.add(label(oldUnits[oldIndex[next.key]])+"の前に"+what+"加える。", strings.Join(body, "\n"))
+		default:
+			d.add(what+"加える。", strings.Join(body, "\n"))
+		}
+		i = end - 1
+	}
+	deleteUpTo(len(oldUnits))
+}
+
+func containsKey(index map[string]int, key string) bool {
+	_, ok := index[key]
+	return ok
+}
+
+// diffArticle drafts the instructions for a changed article
+func (d *amendmentDrafter) diffArticle(oldArticle, newArticle amendUnit) {
+	oldCaption := strings.TrimSpace(oldArticle.node.Child("ArticleCaption").PlainText())
+	newCaption := strings.TrimSpace(newArticle.node.Child("ArticleCaption").PlainText())
+	switch {
+	case oldCaption == newCaption:
+	case oldCaption == "":
+		d.add(oldArticle.label+"に見出しとして「"+newCaption+"」を付する。", "")
+	case newCaption == "":
+		d.add(oldArticle.label+"の見出しを削る。", "")
+	default:
+		d.diffText(oldArticle.label+"の見出し", oldCaption, newCaption, oldCaption)
+	}
+	d.diffUnits(articleUnits(oldArticle.node), articleUnits(newArticle.node), oldArticle.label, nil)
+}
+
+// articleUnits returns the paragraphs, items and sub-items of an article in document order
+func articleUnits(article *LawNode) []amendUnit {
+	var units []amendUnit
+	paragraphs := article.Paragraphs()
+	var walk func(node *LawNode, key, label string, parent string)
+	walk = func(node *LawNode, key, label, parent string) {
+		counter := ""
+		switch node.Tag {
+		case "Paragraph":
+			counter = "項"
+		case "Item":
+			counter = "号"
+		}
+		units = append(units, amendUnit{
+			key:     key,
+			parent:  parent,
+			label:   label,
+			counter: counter,
+			node:    node,
+			text:    sentenceText(node.Child(node.Tag + "Sentence")),
+		})
+		for _, item := range node.Items() {
+			itemLabel := label + item.ItemTitle()
+			if item.Tag == "Item" {
+				itemLabel = label + "第" + formatKanjiNum(item.Num()) + "号"
+			}
+			walk(item, key+"/"+item.Tag+item.Num(), itemLabel, key)
+		}
+	}
+	for _, paragraph := range paragraphs {
+		label := ""
+		if len(paragraphs) > 1 {
+			label = "第" + formatKanjiNum(paragraph.Num()) + "項"
+		}
+		walk(paragraph, "Paragraph"+paragraph.Num(), label, "")
+	}
+	return units
+}
+
+// textHunk is a changed span: old runes [start, end) became the new text
+type textHunk struct {
+	start, end       int
+	newStart, newEnd int
+}
+
+// minHunkGap is the length of unchanged text below which neighboring changes are merged,
+// so that quotes cover words rather than single characters
+const minHunkGap = 3
+
+// diffText drafts the instruction changing the text of a provision. Quotes are unique
+// within scope, the text of the provision and its items.
+func (d *amendmentDrafter) diffText(target, oldText, newText, scope string) {
+	a, b := []rune(oldText), []rune(newText)
+	oldSegs, newSegs := DiffText(oldText, newText)
+	oldChanged, newChanged := changedRunes(oldSegs), changedRunes(newSegs)
+
+	var hunks []textHunk
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		if i < len(a) && j < len(b) && !oldChanged[i] && !newChanged[j] {
+			i++
+			j++
+			continue
+		}
+		h := textHunk{start: i, newStart: j}
+		for i < len(a) && oldChanged[i] {
+			i++
+		}
+		for j < len(b) && newChanged[j] {
+			j++
+		}
+		h.end, h.newEnd = i, j
+		if n := len(hunks); n > 0 && h.start-hunks[n-1].end < minHunkGap {
+			hunks[n-1].end, hunks[n-1].newEnd = h.end, h.newEnd
+		} else {
+			hunks = append(hunks, h)
+		}
+	}
+
+	var phrases []amendPhrase
+	for _, h := range hunks {
+		phrases = append(phrases, quoteHunk(a, b, h, scope))
+	}
+	if len(phrases) > 0 {
+		d.add(target+"中"+joinPhrases(phrases)+"。", "")
+	}
+}
+
+func changedRunes(segments []DiffSegment) []bool {
+	var changed []bool
+	for _, s := range segments {
+		for range []rune(s.Text) {
+			changed = append(changed, s.Changed)
+		}
+	}
+	return changed
+}
+
+// amendPhrase is one edit of an instruction, without its verb
+type amendPhrase struct {
+	// verb is 改める, 削る or 加える
+	verb string
+	text string
+}
+
+// quoteHunk phrases a change, widening the quoted old text with unchanged neighbors until
+// it occurs only once in scope
+func quoteHunk(a, b []rune, h textHunk, scope string) amendPhrase {
+	unique := func(s []rune) bool { return strings.Count(scope, string(s)) == 1 }
+	left, right := 0, 0
+	widen := func() bool {
+		switch {
+		case h.start-left > 0:
+			left++
+		case h.end+right < len(a):
+			right++
+		default:
+			return false
+		}
+		return true
+	}
+
+	if h.start == h.end {
+		// Insertion: quote the preceding text (「X」の下に), or the following at the start (「X」の上に)
+		if h.start == 0 {
+			for right < 1 || (!unique(a[:right]) && right < len(a)) {
+				right++
+			}
+			return amendPhrase{verb: "加える", text: "「" + string(a[:right]) + "」の上に「" + string(b[h.newStart:h.newEnd]) + "」を"}
+		}
+		left = min(2, h.start)
+		for !unique(a[h.start-left:h.start]) && left < h.start {
+			left++
+		}
+		return amendPhrase{verb: "加える", text: "「" + string(a[h.start-left:h.start]) + "」の下に「" + string(b[h.newStart:h.newEnd]) + "」を"}
+	}
+
+	for !unique(a[h.start-left:h.end+right]) && widen() {
+	}
+	quotedOld := string(a[h.start-left : h.end+right])
+	quotedNew := string(a[h.start-left:h.start]) + string(b[h.newStart:h.newEnd]) + string(a[h.end:h.end+right])
+	if quotedNew == "" {
+		return amendPhrase{verb: "削る", text: "「" + quotedOld + "」を"}
+	}
+	return amendPhrase{verb: "改める", text: "「" + quotedOld + "」を「" + quotedNew + "」に"}
+}
+
+// continuativeForms are the 連用形 of the verbs, used before further edits
+var continuativeForms = map[string]string{"改める": "改め", "削る": "削り", "加える": "加え"}
+
+// joinPhrases chains edits in one sentence: consecutive replacements share their verb
+// (「A」を「B」に、「C」を「D」に改め), and other verbs take the continuative form before
+// the next edit (「E」を削り、)
+func joinPhrases(phrases []amendPhrase) string {
+	var sb strings.Builder
+	for i, p := range phrases {
+		sb.WriteString(p.text)
+		last := i == len(phrases)-1
+		if !last && p.verb == "改める" && phrases[i+1].verb == "改める" {
+			sb.WriteString("、")
+			continue
+		}
+		if last {
+			sb.WriteString(p.verb)
+		} else {
+			sb.WriteString(continuativeForms[p.verb] + "、")
+		}
+	}
+	return sb.String()
+}
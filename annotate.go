@@ -0,0 +1,163 @@
+package lawapi
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Annotation is an entity tagged in a text by an Annotator.
+// Start and End are rune offsets into the annotated text.
+type Annotation struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	// Source identifies the annotator that produced the annotation
+	Source string `json:"source,omitempty"`
+}
+
+// Annotator tags entities (organizations, dates, defined terms, ...) in a text.
+// Implementations may wrap external NLP tools such as MeCab/Sudachi bindings or HTTP services.
+type Annotator interface {
+	Annotate(ctx context.Context, text string) ([]Annotation, error)
+}
+
+// AnnotatorFunc adapts a function to the Annotator interface
+type AnnotatorFunc func(ctx context.Context, text string) ([]Annotation, error)
+
+// Annotate calls f(ctx, text)
+func (f AnnotatorFunc) Annotate(ctx context.Context, text string) ([]Annotation, error) {
+	return f(ctx, text)
+}
+
+// RegexpAnnotator tags every match of a regular expression with a fixed type
+type RegexpAnnotator struct {
+	Type    string
+	Pattern *regexp.Regexp
+}
+
+// Annotate implements Annotator
+func (a *RegexpAnnotator) Annotate(ctx context.Context, text string) ([]Annotation, error) {
+	var annotations []Annotation
+	for _, loc := range a.Pattern.FindAllStringIndex(text, -1) {
+		annotations = append(annotations, Annotation{
+			Start:  utf8.RuneCountInString(text[:loc[0]]),
+			End:    utf8.RuneCountInString(text[:loc[1]]),
+			Type:   a.Type,
+			Text:   text[loc[0]:loc[1]],
+			Source: "regexp",
+		})
+	}
+	return annotations, nil
+}
+
+// AnnotatedSentence is a sentence with the annotations produced for its plain text
+type AnnotatedSentence struct {
+	Ref         SentenceRef
+	Text        string
+	Annotations []Annotation
+}
+
+// AnnotateLaw applies the annotators to every sentence of a parsed law.
+// Offsets are relative to the plain text of each sentence; AnnotateLawText annotates the
+// rendered text instead.
+func AnnotateLaw(ctx context.Context, law *LawNode, annotators ...Annotator) ([]AnnotatedSentence, error) {
+	var sentences []AnnotatedSentence
+	for _, ref := range law.Sentences() {
+		if err := ctx.Err(); err != nil {
+			return sentences, err
+		}
+		sentence := AnnotatedSentence{Ref: ref, Text: ref.Sentence.PlainText()}
+		for _, annotator := range annotators {
+			annotations, err := annotator.Annotate(ctx, sentence.Text)
+			if err != nil {
+				return sentences, fmt.Errorf("failed to annotate %s: %w", ref.Label(), err)
+			}
+			sentence.Annotations = append(sentence.Annotations, annotations...)
+		}
+		sentences = append(sentences, sentence)
+	}
+	return sentences, nil
+}
+
+// AnnotatedLine is a line of a law rendered as text, laid out as in the EPUB, PDF and DOCX
+// exports, with the annotations of its text
+type AnnotatedLine struct {
+	// Text is the line text, starting with the article title or item numbering
+	Text string `json:"text"`
+	// Indent is the nesting depth: 0 for paragraphs, 1 for items and article captions, ...
+	Indent int `json:"indent"`
+	// Heading is the level of structural headings (1 for 編, 2 for 章, ... and 附則), or 0
+	Heading int `json:"heading,omitempty"`
+	// Article is the citation of the article starting at this line (e.g. 第一条（目的）)
+	Article string `json:"article,omitempty"`
+	// Annotations are the annotations of Text, with offsets into Text
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// AnnotateLawText renders a law, structural element or provision as lines of text and
+// applies the annotators to each line as it is rendered, so that annotation offsets are
+// rune offsets into the rendered lines. Headings and item numbering are annotated along
+// with the sentences; tables, figures and other non-sentence content are omitted.
+func AnnotateLawText(ctx context.Context, n *LawNode, annotators ...Annotator) ([]AnnotatedLine, error) {
+	var lines []AnnotatedLine
+	for _, l := range provisionLines(n) {
+		if err := ctx.Err(); err != nil {
+			return lines, err
+		}
+		line := AnnotatedLine{Text: l.text, Indent: l.indent, Heading: l.heading, Article: l.article}
+		for _, annotator := range annotators {
+			annotations, err := annotator.Annotate(ctx, line.Text)
+			if err != nil {
+				return lines, fmt.Errorf("failed to annotate %q: %w", line.Text, err)
+			}
+			line.Annotations = append(line.Annotations, annotations...)
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// AnnotatedChunk is a passage of consecutive rendered lines, with the annotations of the
+// lines shifted to offsets into the passage
+type AnnotatedChunk struct {
+	// Text is the text of the lines, joined by newlines
+	Text string `json:"text"`
+	// Heading is the level of the heading starting the chunk, or 0
+	Heading int `json:"heading,omitempty"`
+	// Article is the citation of the article of the chunk, or empty
+	Article string `json:"article,omitempty"`
+	// Annotations are the annotations of the lines, with offsets into Text
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// ChunkAnnotatedLines splits rendered lines into passages for indexing or embedding: a
+// chunk per heading, and per article with its caption, paragraphs and items. Paragraphs
+// of provisions without articles, such as those of 附則, are chunked with the heading
+// before them. Each annotation is moved by the offset of its line in the chunk, so that
+// offsets stay valid in the chunk text.
+func ChunkAnnotatedLines(lines []AnnotatedLine) []AnnotatedChunk {
+	var chunks []AnnotatedChunk
+	// length is the number of runes of the text of the last chunk
+	length := 0
+	for _, line := range lines {
+		if len(chunks) == 0 || line.Heading > 0 || line.Article != "" {
+			chunks = append(chunks, AnnotatedChunk{Heading: line.Heading, Article: line.Article})
+			length = 0
+		} else {
+			chunks[len(chunks)-1].Text += "\n"
+			length++
+		}
+		chunk := &chunks[len(chunks)-1]
+		for _, a := range line.Annotations {
+			a.Start += length
+			a.End += length
+			chunk.Annotations = append(chunk.Annotations, a)
+		}
+		chunk.Text += line.Text
+		length += utf8.RuneCountInString(line.Text)
+	}
+	return chunks
+}
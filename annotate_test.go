@@ -0,0 +1,88 @@
+package lawapi
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const annotateTestLaw = `<Law><LawBody><LawTitle>試験法</LawTitle><MainProvision>
+<Chapter Num="1"><ChapterTitle>第一章　総則</ChapterTitle>
+<Article Num="1"><ArticleCaption>（目的）</ArticleCaption><ArticleTitle>第一条</ArticleTitle>
+<Paragraph Num="1"><ParagraphNum/><ParagraphSentence><Sentence>この法律は、甲の保護を目的とする。</Sentence></ParagraphSentence></Paragraph>
+<Paragraph Num="2"><ParagraphNum>２</ParagraphNum><ParagraphSentence><Sentence>甲は、乙に従う。</Sentence><Sentence>ただし、乙が定める場合は、この限りでない。</Sentence></ParagraphSentence>
+<Item Num="1"><ItemTitle>一</ItemTitle><ItemSentence><Column><Sentence>甲</Sentence></Column><Column><Sentence>乙の長</Sentence></Column></ItemSentence></Item>
+</Paragraph>
+</Article>
+</Chapter>
+</MainProvision>
+<SupplProvision><SupplProvisionLabel>附　則</SupplProvisionLabel>
+<Paragraph Num="1"><ParagraphNum/><ParagraphSentence><Sentence>この法律は、乙が定める日から施行する。</Sentence></ParagraphSentence></Paragraph>
+</SupplProvision>
+</LawBody></Law>`
+
+func TestAnnotateLawText(t *testing.T) {
+	law, err := ParseLawXML(strings.NewReader(annotateTestLaw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotator := &RegexpAnnotator{Type: "party", Pattern: regexp.MustCompile(`[甲乙]`)}
+	lines, err := AnnotateLawText(context.Background(), law.Find("LawBody"), annotator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"第一章　総則",
+		"（目的）",
+		"第一条　この法律は、[甲]の保護を目的とする。",
+		"２　[甲]は、[乙]に従う。ただし、[乙]が定める場合は、この限りでない。",
+		"一　[甲]　[乙]の長",
+		"附　則",
+		"この法律は、[乙]が定める日から施行する。",
+	}
+	var got []string
+	for _, line := range lines {
+		got = append(got, markAnnotations(line.Text, line.Annotations))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+
+	chunks := ChunkAnnotatedLines(lines)
+	var articles []string
+	for _, chunk := range chunks {
+		articles = append(articles, chunk.Article)
+	}
+	if want := []string{"", "第一条（目的）", ""}; !slices.Equal(articles, want) {
+		t.Fatalf("chunk articles = %q, want %q", articles, want)
+	}
+	if want := strings.Join(want[1:5], "\n"); markAnnotations(chunks[1].Text, chunks[1].Annotations) != want {
+		t.Errorf("article chunk = %q, want %q", markAnnotations(chunks[1].Text, chunks[1].Annotations), want)
+	}
+	if want := strings.Join(want[5:], "\n"); markAnnotations(chunks[2].Text, chunks[2].Annotations) != want || chunks[2].Heading != 1 {
+		t.Errorf("supplementary provision chunk = %+v, want the heading and its paragraph", chunks[2])
+	}
+
+	failing := AnnotatorFunc(func(ctx context.Context, text string) ([]Annotation, error) {
+		return nil, errors.New("unavailable")
+	})
+	if _, err := AnnotateLawText(context.Background(), law, failing); err == nil {
+		t.Error("the error of the annotator was not returned")
+	}
+}
+
+// markAnnotations brackets the annotated parts of a text
+func markAnnotations(text string, annotations []Annotation) string {
+	runes := []rune(text)
+	var sb strings.Builder
+	pos := 0
+	for _, a := range annotations {
+		sb.WriteString(string(runes[pos:a.Start]) + "[" + string(runes[a.Start:a.End]) + "]")
+		pos = a.End
+	}
+	sb.WriteString(string(runes[pos:]))
+	return sb.String()
+}
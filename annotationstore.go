@@ -0,0 +1,378 @@
+package lawapi
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrAnnotationNotFound is returned for annotation IDs not in an AnnotationStore
+var ErrAnnotationNotFound = errors.New("annotation not found")
+
+// UserAnnotationKind is the kind of a user annotation
+type UserAnnotationKind string
+
+const (
+	// AnnotationBookmark marks a provision to return to, with an optional title in Text
+	AnnotationBookmark UserAnnotationKind = "bookmark"
+	// AnnotationNote attaches a note in Text to a provision
+	AnnotationNote UserAnnotationKind = "note"
+	// AnnotationHighlight marks a passage of a provision, with an optional note in Text
+	AnnotationHighlight UserAnnotationKind = "highlight"
+)
+
+// UserAnnotation is a bookmark, note or highlight a user attached to a provision
+type UserAnnotation struct {
+	// ID identifies the annotation in its store and across synced stores
+	ID string `json:"id"`
+	// ProvisionID is the canonical ID of the annotated provision (see ProvisionID). With an
+	// empty revision segment, the annotation follows the current revision.
+	ProvisionID string             `json:"provision_id"`
+	Kind        UserAnnotationKind `json:"kind"`
+	// Text is the note, or the title of a bookmark
+	Text string   `json:"text,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+	// Quote is the highlighted passage as written
+	Quote string `json:"quote,omitempty"`
+	// Start and End are the rune offsets of the highlighted passage in the plain text of
+	// the provision, if known
+	Start int `json:"start,omitempty"`
+	End   int `json:"end,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Deleted marks a removed annotation. It is kept as a tombstone so that the removal
+	// is synced by Import, and left out of List.
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// validate checks the provision ID, kind and highlight range of an annotation
+func (a UserAnnotation) validate() error {
+	if _, err := ParseProvisionID(a.ProvisionID); err != nil {
+		return WithErrorCategory(err, ErrorValidation)
+	}
+	switch a.Kind {
+	case AnnotationBookmark, AnnotationNote:
+	case AnnotationHighlight:
+		if a.Quote == "" && a.End <= a.Start {
+			return WithErrorCategory(errors.New("highlight has neither a quote nor a range"), ErrorValidation)
+		}
+	default:
+		return WithErrorCategory(fmt.Errorf("unknown annotation kind %q", a.Kind), ErrorValidation)
+	}
+	if a.Start < 0 || a.End < a.Start {
+		return WithErrorCategory(fmt.Errorf("invalid highlight range %d-%d", a.Start, a.End), ErrorValidation)
+	}
+	if a.Quote != "" && a.End > a.Start && utf8.RuneCountInString(a.Quote) != a.End-a.Start {
+		return WithErrorCategory(fmt.Errorf("highlight range %d-%d does not match the length of the quote", a.Start, a.End), ErrorValidation)
+	}
+	return nil
+}
+
+// AnnotationFilter selects annotations in AnnotationStore.List. Zero fields match all
+// annotations.
+type AnnotationFilter struct {
+	// LawID matches annotations of the law
+	LawID string
+	// ProvisionID matches annotations of the provision and of the provisions within it,
+	// in the same law and, if given, revision
+	ProvisionID string
+	Kind        UserAnnotationKind
+	// Tag matches annotations with the tag
+	Tag string
+}
+
+func (f AnnotationFilter) match(a *UserAnnotation) bool {
+	id, err := ParseProvisionID(a.ProvisionID)
+	if err != nil {
+		return false
+	}
+	if f.LawID != "" && id.LawID != f.LawID {
+		return false
+	}
+	if f.ProvisionID != "" {
+		outer, ok := splitProvisionID(f.ProvisionID)
+		if !ok || id.LawID != outer.LawID || (outer.Revision != "" && id.Revision != outer.Revision) || !provisionWithin(id, outer) {
+			return false
+		}
+	}
+	return (f.Kind == "" || a.Kind == f.Kind) && (f.Tag == "" || slices.Contains(a.Tags, f.Tag))
+}
+
+// AnnotationStoreOptions configures an AnnotationStore
+type AnnotationStoreOptions struct {
+	// Path is the JSON Lines file persisting the annotations, rewritten atomically after
+	// every change and created with its directory on the first one, or empty to keep them in memory. The file must not be shared by
+	// concurrent processes; use Export and Import to sync stores.
+	Path string
+	// Clock stamps the creation and update times (default: DefaultClock)
+	Clock Clock
+}
+
+// AnnotationStore stores the bookmarks, notes and highlights users attach to provisions,
+// the persistence layer of reading applications. Annotations refer to provisions by
+// provision ID, so they can be kept next to a jplaw-serve mirror and travel with exports
+// of its documents. An AnnotationStore is safe for concurrent use.
+type AnnotationStore struct {
+	opts AnnotationStoreOptions
+
+	mu          sync.Mutex
+	annotations map[string]*UserAnnotation
+}
+
+// NewAnnotationStore creates an annotation store, loading the annotations of opts.Path if
+// the file exists
+func NewAnnotationStore(opts AnnotationStoreOptions) (*AnnotationStore, error) {
+	s := &AnnotationStore{opts: opts, annotations: map[string]*UserAnnotation{}}
+	if opts.Path == "" {
+		return s, nil
+	}
+	f, err := os.Open(opts.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	annotations, err := readAnnotations(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", opts.Path, err)
+	}
+	for _, a := range annotations {
+		s.annotations[a.ID] = a
+	}
+	return s, nil
+}
+
+// Add stores a new annotation with a generated ID and timestamps, and returns it
+func (s *AnnotationStore) Add(a UserAnnotation) (UserAnnotation, error) {
+	if err := a.validate(); err != nil {
+		return UserAnnotation{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a.ID = newAnnotationID()
+	a.CreatedAt = clockOrDefault(s.opts.Clock).Now().UTC()
+	a.UpdatedAt, a.Deleted = a.CreatedAt, false
+	a.Tags = normalizeTags(a.Tags)
+	s.annotations[a.ID] = &a
+	return a, s.save()
+}
+
+// Update replaces the annotation with the ID of a, keeping its creation time, and returns
+// it
+func (s *AnnotationStore) Update(a UserAnnotation) (UserAnnotation, error) {
+	if err := a.validate(); err != nil {
+		return UserAnnotation{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.annotations[a.ID]
+	if !ok || old.Deleted {
+		return UserAnnotation{}, WithErrorCategory(fmt.Errorf("%w: %s", ErrAnnotationNotFound, a.ID), ErrorNotFound)
+	}
+	a.CreatedAt, a.Deleted = old.CreatedAt, false
+	a.UpdatedAt = clockOrDefault(s.opts.Clock).Now().UTC()
+	a.Tags = normalizeTags(a.Tags)
+	s.annotations[a.ID] = &a
+	return a, s.save()
+}
+
+// Remove removes an annotation, leaving a tombstone for Import
+func (s *AnnotationStore) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.annotations[id]
+	if !ok || a.Deleted {
+		return WithErrorCategory(fmt.Errorf("%w: %s", ErrAnnotationNotFound, id), ErrorNotFound)
+	}
+	removed := UserAnnotation{ID: id, ProvisionID: a.ProvisionID, Kind: a.Kind, CreatedAt: a.CreatedAt, Deleted: true}
+	removed.UpdatedAt = clockOrDefault(s.opts.Clock).Now().UTC()
+	s.annotations[id] = &removed
+	return s.save()
+}
+
+// Get returns the annotation with the ID
+func (s *AnnotationStore) Get(id string) (UserAnnotation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.annotations[id]
+	if !ok || a.Deleted {
+		return UserAnnotation{}, WithErrorCategory(fmt.Errorf("%w: %s", ErrAnnotationNotFound, id), ErrorNotFound)
+	}
+	return *a, nil
+}
+
+// List returns the annotations matching the filter, in order of provision ID and
+// creation time
+func (s *AnnotationStore) List(filter AnnotationFilter) []UserAnnotation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var annotations []UserAnnotation
+	for _, a := range s.annotations {
+		if !a.Deleted && filter.match(a) {
+			annotations = append(annotations, *a)
+		}
+	}
+	sortAnnotations(annotations)
+	return annotations
+}
+
+// Tags returns the tags in use with the number of annotations having each
+func (s *AnnotationStore) Tags() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tags := map[string]int{}
+	for _, a := range s.annotations {
+		if !a.Deleted {
+			for _, tag := range a.Tags {
+				tags[tag]++
+			}
+		}
+	}
+	return tags
+}
+
+// Export writes all annotations, including the tombstones of removed ones, as JSON lines
+// in the format of the store file, for bundling with exported documents or syncing with
+// another store
+func (s *AnnotationStore) Export(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return writeAnnotations(w, s.sorted())
+}
+
+// Import merges annotations written by Export: annotations not in the store are added,
+// and those in the store are replaced if the imported ones were updated later, so that
+// importing in both directions syncs two stores. It returns the number of annotations
+// added or replaced.
+func (s *AnnotationStore) Import(r io.Reader) (int, error) {
+	annotations, err := readAnnotations(r)
+	if err != nil {
+		return 0, err
+	}
+	return s.merge(annotations)
+}
+
+// merge adds or replaces annotations as Import does
+func (s *AnnotationStore) merge(annotations []*UserAnnotation) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := 0
+	for _, a := range annotations {
+		if old, ok := s.annotations[a.ID]; ok && !a.UpdatedAt.After(old.UpdatedAt) {
+			continue
+		}
+		s.annotations[a.ID] = a
+		changed++
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+	return changed, s.save()
+}
+
+// sorted returns all annotations in order of provision ID and creation time
+func (s *AnnotationStore) sorted() []UserAnnotation {
+	annotations := make([]UserAnnotation, 0, len(s.annotations))
+	for _, a := range s.annotations {
+		annotations = append(annotations, *a)
+	}
+	sortAnnotations(annotations)
+	return annotations
+}
+
+// save writes the annotations to the store file, if any
+func (s *AnnotationStore) save() error {
+	if s.opts.Path == "" {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := writeAnnotations(&buf, s.sorted()); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.opts.Path), 0o755); err != nil {
+		return err
+	}
+	return WriteFileAtomic(s.opts.Path, buf.Bytes(), 0o644)
+}
+
+func sortAnnotations(annotations []UserAnnotation) {
+	slices.SortFunc(annotations, func(a, b UserAnnotation) int {
+		return cmp.Or(
+			cmp.Compare(a.ProvisionID, b.ProvisionID),
+			a.CreatedAt.Compare(b.CreatedAt),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+}
+
+func writeAnnotations(w io.Writer, annotations []UserAnnotation) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, a := range annotations {
+		if err := enc.Encode(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readAnnotations reads annotations from JSON lines, validating those not deleted
+func readAnnotations(r io.Reader) ([]*UserAnnotation, error) {
+	var annotations []*UserAnnotation
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var a UserAnnotation
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if a.ID == "" {
+			return nil, fmt.Errorf("line %d: annotation has no ID", line)
+		}
+		if !a.Deleted {
+			if err := a.validate(); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		annotations = append(annotations, &a)
+	}
+	return annotations, scanner.Err()
+}
+
+// normalizeTags trims, sorts and deduplicates tags, dropping empty ones
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// newAnnotationID returns a random 16-digit hex ID
+func newAnnotationID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,23 @@
+package lawapi
+
+import "context"
+
+// API is the set of Japan Law API v2 operations. Client and CachingClient implement it.
+// Applications that depend on API rather than on a concrete client can swap in a cache,
+// a test double, or an adapter over a future API version without changing their code.
+type API interface {
+	GetLawsWithContext(ctx context.Context, params *GetLawsParams) (*LawsResponse, error)
+	GetRevisionsWithContext(ctx context.Context, lawIdOrNum string, params *GetRevisionsParams) (*LawRevisionsResponse, error)
+	GetLawDataWithContext(ctx context.Context, lawIdOrNumOrRevisionId string, params *GetLawDataParams) (*LawDataResponse, error)
+	GetLawFileWithContext(ctx context.Context, lawIdOrNumOrRevisionId string, fileType string, params *GetLawFileParams) (*string, error)
+	GetAttachmentWithContext(ctx context.Context, lawRevisionId string, params *GetAttachmentParams) (*string, error)
+	GetKeywordWithContext(ctx context.Context, params *GetKeywordParams) (*KeywordResponse, error)
+}
+
+var (
+	_ API = (*Client)(nil)
+	_ API = (*CachingClient)(nil)
+)
+
+// APIVersion is the major version of the Japan Law API implemented by this package
+const APIVersion = 2
@@ -0,0 +1,797 @@
+package lawapi
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// DependencyKind is how a provision depends on the provisions it cites
+type DependencyKind string
+
+const (
+	// DependencyMutatisMutandis applies provisions to another case with the necessary
+	// changes (準用,「第五条の規定は、前条の場合について準用する。」)
+	DependencyMutatisMutandis DependencyKind = "mutatis_mutandis"
+	// DependencyReplacement reads words of provisions as other words when applying them,
+	// without 準用 (読替え,「同条の規定の適用については、同条中「A」とあるのは「B」とする。」)
+	DependencyReplacement DependencyKind = "replacement"
+)
+
+// ProvisionReference is a provision cited by another
+type ProvisionReference struct {
+	// ProvisionID is the canonical ID of the provision
+	ProvisionID string `json:"provision_id"`
+	// Provision is the citation of the provision (e.g. 第五条第二項)
+	Provision string `json:"provision"`
+}
+
+// TextReplacement is a word of a provision to be read as another (「A」とあるのは「B」と)
+type TextReplacement struct {
+	// ProvisionID is the canonical ID of the provision in which the word is replaced,
+	// empty for all provisions of the dependency
+	ProvisionID string `json:"provision_id,omitempty"`
+	// Provision is the citation of the provision, if any
+	Provision string `json:"provision,omitempty"`
+	Old       string `json:"old"`
+	New       string `json:"new"`
+}
+
+// ProvisionDependency is a typed edge from a provision to the provisions of the same law
+// it applies or reads differently
+type ProvisionDependency struct {
+	Kind DependencyKind `json:"kind"`
+	// ProvisionID is the canonical ID of the provision stating the dependency
+	ProvisionID string `json:"provision_id"`
+	// Provision is the citation of the provision (e.g. 第二条第二項第三号ただし書)
+	Provision string `json:"provision"`
+	// To are the provisions applied (準用) or whose words are replaced (読替え)
+	To []ProvisionReference `json:"to"`
+	// Scope is the case the provisions are applied to, as written (e.g. 前条の場合), if
+	// stated
+	Scope string `json:"scope,omitempty"`
+	// Replacements are the words to be read differently in the provisions, in order
+	Replacements []TextReplacement `json:"replacements,omitempty"`
+	// Text is the text of the sentences stating the dependency, one per line
+	Text string `json:"text"`
+}
+
+var (
+	citationUnitPattern = regexp.MustCompile(`(附則)?第(` + kanjiNumeral + `)(条|項|号)(` + branchNumerals + `)` +
+		`|(前|次|同)(各|` + kanjiNumeral + `)?(条|項|号)|各(項|号)`)
+	// citationExternalPattern precedes citations of other laws (電波法第四条,
+	// 政令第百号, 同法第二条, （昭和二十五年法律第百三十一号）第五条)
+	citationExternalPattern = regexp.MustCompile(`(?:法|律|令|規則|条約|号）)$`)
+	// citationConjunctionPattern joins citations in a list
+	citationConjunctionPattern = regexp.MustCompile(`^(?:、|及び|並びに|又は|若しくは|から|まで)+$`)
+)
+
+// ExtractDependencies detects the provisions of a law applying other provisions of the
+// same law mutatis mutandis (準用) or reading their words differently (読替え), with the
+// replacements stated as 「A」とあるのは「B」と, including those listed in tables (次の表の
+// 上欄に掲げる規定中同表の中欄に掲げる字句は…). Replacements in the sentence following a
+// 準用 sentence of the same provision (「この場合において、…読み替えるものとする。」)
+// belong to its dependency. Citations are resolved within the law, including relative
+// ones (前条, 前項, 同条, 前各号) and ranges (第三条から第五条まで); citations of other laws,
+// those in parentheses such as exclusions (第二項を除く。) and those not found in the law
+// are left out. Provision IDs are formed with lawID and revision as in
+// SentenceRef.ProvisionID.
+func ExtractDependencies(law *LawNode, lawID, revision string) []ProvisionDependency {
+	r := &citationResolver{law: law}
+	var deps []ProvisionDependency
+	// applying is the index of the last 準用 dependency and ref its sentence
+	applying := -1
+	var applyingRef SentenceRef
+	for _, ref := range law.Sentences() {
+		text := strings.TrimSpace(ref.Sentence.PlainText())
+		r.ref = ref
+		citations := r.citations(text)
+		isApplication := strings.Contains(text, "準用する。") || strings.HasSuffix(text, "準用する")
+		if !isApplication && !strings.Contains(text, "とあるのは") && !strings.Contains(text, "読み替える") {
+			continue
+		}
+		replacements := r.replacements(text, citations)
+		if strings.Contains(text, "次の表") {
+			replacements = append(replacements, r.tableReplacements(ref)...)
+		}
+
+		if isApplication {
+			dep := ProvisionDependency{
+				Kind:         DependencyMutatisMutandis,
+				ProvisionID:  ref.ProvisionID(lawID, revision).String(),
+				Provision:    ref.Label(),
+				Replacements: r.references(replacements, lawID, revision),
+				Text:         text,
+			}
+			var to []ProvisionID
+			to, dep.Scope = applicationTargets(text, citations)
+			dep.To = r.provisionReferences(to, lawID, revision)
+			if len(dep.To) == 0 {
+				continue
+			}
+			deps = append(deps, dep)
+			applying, applyingRef = len(deps)-1, ref
+			continue
+		}
+		if len(replacements) == 0 {
+			continue
+		}
+		if applying >= 0 && strings.HasPrefix(text, "この場合において") &&
+			applyingRef.Paragraph == ref.Paragraph && applyingRef.Item == ref.Item && slices.Equal(applyingRef.Subitems, ref.Subitems) {
+			dep := &deps[applying]
+			dep.Replacements = append(dep.Replacements, r.references(replacements, lawID, revision)...)
+			dep.Text += "\n" + text
+			continue
+		}
+		var to []ProvisionID
+		for _, rep := range replacements {
+			if rep.id != nil && !slices.Contains(to, *rep.id) {
+				to = append(to, *rep.id)
+			}
+		}
+		if len(to) == 0 {
+			// 「第五条の規定の適用については、「A」とあるのは「B」とする。」
+			if i := strings.Index(text, "の規定の適用については"); i >= 0 {
+				to = citedBefore(citations, i)
+			}
+		}
+		dep := ProvisionDependency{
+			Kind:         DependencyReplacement,
+			ProvisionID:  ref.ProvisionID(lawID, revision).String(),
+			Provision:    ref.Label(),
+			To:           r.provisionReferences(to, lawID, revision),
+			Replacements: r.references(replacements, lawID, revision),
+			Text:         text,
+		}
+		if len(dep.To) > 0 {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// applicationTargets returns the provisions a 準用 sentence applies and the case it
+// applies them to, for 「Xの規定は、Yについて準用する。」 and 「Yについては、Xの規定を準用する。」
+func applicationTargets(text string, citations []citation) ([]ProvisionID, string) {
+	if end := strings.Index(text, "を準用する"); end >= 0 {
+		start := max(clauseEnd(text[:end], "は、"), clauseEnd(text[:end], "において、"))
+		var to []ProvisionID
+		for _, c := range citations {
+			if c.start >= start && c.end <= end && c.depth == 0 {
+				to = appendUnique(to, c.ids...)
+			}
+		}
+		scope := strings.TrimRight(text[:start], "、")
+		for _, suffix := range []string{"は", "において", "について", "に"} {
+			if s, ok := strings.CutSuffix(scope, suffix); ok {
+				scope = s
+				break
+			}
+		}
+		return to, scope
+	}
+	subjectEnd := firstTopLevelIndex(text, "は、")
+	if subjectEnd < 0 {
+		return nil, ""
+	}
+	to := citedBefore(citations, subjectEnd)
+	scope := text[subjectEnd+len("は、"):]
+	if i := strings.LastIndex(scope, "準用する"); i >= 0 {
+		scope = scope[:i]
+	}
+	for _, suffix := range []string{"について", "に"} {
+		if s, ok := strings.CutSuffix(scope, suffix); ok {
+			scope = s
+			break
+		}
+	}
+	return to, scope
+}
+
+// citedBefore returns the provisions cited outside parentheses before the offset end
+func citedBefore(citations []citation, end int) []ProvisionID {
+	var ids []ProvisionID
+	for _, c := range citations {
+		if c.end <= end && c.depth == 0 {
+			ids = appendUnique(ids, c.ids...)
+		}
+	}
+	return ids
+}
+
+func appendUnique(ids []ProvisionID, more ...ProvisionID) []ProvisionID {
+	for _, id := range more {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// firstTopLevelIndex returns the index of the first occurrence of sep outside quotes and
+// parentheses, or -1
+func firstTopLevelIndex(s, sep string) int {
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '「', '（':
+			depth++
+		case '」', '）':
+			depth--
+		default:
+			if depth == 0 && strings.HasPrefix(s[i:], sep) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// lastTopLevelIndex returns the index of the last occurrence of sep outside quotes and
+// parentheses, or -1
+func lastTopLevelIndex(s, sep string) int {
+	last := -1
+	for i := 0; i < len(s); {
+		j := firstTopLevelIndex(s[i:], sep)
+		if j < 0 {
+			break
+		}
+		last = i + j
+		i = last + len(sep)
+		if nestingDepth(s[:i]) != 0 {
+			break
+		}
+	}
+	return last
+}
+
+// clauseEnd returns the offset following the last occurrence of sep outside quotes and
+// parentheses, or 0
+func clauseEnd(s, sep string) int {
+	if i := lastTopLevelIndex(s, sep); i >= 0 {
+		return i + len(sep)
+	}
+	return 0
+}
+
+// citation is a citation of provisions of the law in a sentence
+type citation struct {
+	start, end int
+	// depth is the nesting depth of the citation in parentheses and quotes
+	depth int
+	// external reports whether the citation is of another law
+	external bool
+	ids      []ProvisionID
+}
+
+// citationResolver resolves citations in the sentences of a law, in document order
+type citationResolver struct {
+	law *LawNode
+	// ref is the sentence being resolved
+	ref SentenceRef
+	// last are the provisions cited last, for 同条, 同項 and 同号
+	last         []ProvisionID
+	lastExternal bool
+}
+
+// citations returns the citations of a sentence in order, resolving ranges, and updates
+// the provisions cited last
+func (r *citationResolver) citations(text string) []citation {
+	var citations []citation
+	matches := citationUnitPattern.FindAllStringSubmatchIndex(text, -1)
+	for i := 0; i < len(matches); {
+		// Consecutive units form one citation (第五条第二項第三号)
+		j := i + 1
+		for j < len(matches) && matches[j][0] == matches[j-1][1] {
+			j++
+		}
+		c := citation{start: matches[i][0], end: matches[j-1][1], depth: nestingDepth(text[:matches[i][0]])}
+		units := make([][]string, 0, j-i)
+		for _, m := range matches[i:j] {
+			unit := make([]string, len(m)/2)
+			for k := range unit {
+				if m[2*k] >= 0 {
+					unit[k] = text[m[2*k]:m[2*k+1]]
+				}
+			}
+			units = append(units, unit)
+		}
+		i = j
+
+		prev := len(citations) - 1
+		switch {
+		case citationExternalPattern.MatchString(text[:c.start]):
+			c.external = true
+		case prev >= 0 && citations[prev].external && citationConjunctionPattern.MatchString(text[citations[prev].end:c.start]):
+			c.external = true
+		case strings.HasPrefix(units[0][0], "同") && r.lastExternal:
+			c.external = true
+		default:
+			c.ids = r.resolve(units)
+		}
+		if prev >= 0 && !c.external && len(c.ids) == 1 && len(citations[prev].ids) == 1 &&
+			text[citations[prev].end:c.start] == "から" && strings.HasPrefix(text[c.end:], "まで") {
+			citations[prev].ids = r.expandRange(citations[prev].ids[0], c.ids[0])
+			citations[prev].end = c.end + len("まで")
+			r.last = citations[prev].ids
+			continue
+		}
+		if c.depth == 0 {
+			r.last, r.lastExternal = c.ids, c.external
+		}
+		citations = append(citations, c)
+	}
+	return citations
+}
+
+// nestingDepth returns the number of quotes and parentheses open at the end of s
+func nestingDepth(s string) int {
+	return strings.Count(s, "「") + strings.Count(s, "（") - strings.Count(s, "」") - strings.Count(s, "）")
+}
+
+// resolve resolves the units of a citation (e.g. 前条, 第二項) to provisions that exist in
+// the law
+func (r *citationResolver) resolve(units [][]string) []ProvisionID {
+	cur := r.ref.ProvisionID("", "")
+	cur.Item, _, _ = strings.Cut(cur.Item, ".")
+	var ids []ProvisionID
+	for k, unit := range units {
+		counter := unit[3] + unit[7] + unit[8]
+		level := map[string]int{"条": 1, "項": 2, "号": 3}[counter]
+		if unit[5] == "同" {
+			ids = nil
+			for _, id := range r.last {
+				if provisionLevel(id) >= level {
+					ids = appendUnique(ids, truncateProvision(id, level))
+				}
+			}
+			continue
+		}
+		parents := ids
+		if k == 0 {
+			parents = []ProvisionID{truncateProvision(cur, level-1)}
+		}
+		ids = nil
+		for _, parent := range parents {
+			ids = appendUnique(ids, r.resolveUnit(unit, level, parent, cur)...)
+		}
+	}
+	return slices.DeleteFunc(ids, func(id ProvisionID) bool { return r.law.Provision(id) == nil })
+}
+
+// resolveUnit resolves one unit of a citation within the parent provision
+func (r *citationResolver) resolveUnit(unit []string, level int, parent, cur ProvisionID) []ProvisionID {
+	child := func(num string) ProvisionID {
+		id := parent
+		switch level {
+		case 1:
+			id.Article = num
+		case 2:
+			id.Paragraph = num
+		case 3:
+			id.Item = num
+			if id.Paragraph == "" {
+				id.Paragraph = "1"
+			}
+		}
+		return id
+	}
+	if unit[2] != "" {
+		num := parseKanjiNum(unit[2] + unit[4])
+		if unit[1] != "" {
+			// 附則 cited within a supplementary provision is that provision
+			num = supplArticle(num, r.ref.SupplIndex)
+		}
+		return []ProvisionID{child(num)}
+	}
+
+	siblings := r.siblings(parent, level)
+	var ids []ProvisionID
+	if unit[8] != "" {
+		// 各項, 各号
+		for _, num := range siblings {
+			ids = append(ids, child(num))
+		}
+		return ids
+	}
+	curNum := []string{"", cur.Article, cur.Paragraph, cur.Item}[level]
+	pos := slices.Index(siblings, curNum)
+	var nums []string
+	switch count := unit[6]; {
+	case unit[5] == "次":
+		if pos >= 0 && pos+1 < len(siblings) {
+			nums = siblings[pos+1 : pos+2]
+		}
+	case count == "各" && pos < 0 && level == 3:
+		// 前各号 in the sentence of a paragraph after its items
+		nums = siblings
+	case pos < 0:
+	case count == "各":
+		nums = siblings[:pos]
+	case count == "":
+		nums = siblings[max(pos-1, 0):pos]
+	default:
+		n, _ := ParseKanjiNumber(count)
+		nums = siblings[max(pos-int(n), 0):pos]
+	}
+	for _, num := range nums {
+		ids = append(ids, child(num))
+	}
+	return ids
+}
+
+// siblings returns the Num attributes of the provisions of a level within the parent:
+// the articles of the main or current supplementary provision, or the paragraphs or items
+// of the parent
+func (r *citationResolver) siblings(parent ProvisionID, level int) []string {
+	var nodes []*LawNode
+	suppl := false
+	switch level {
+	case 1:
+		nodes = r.law.Articles()
+		if r.ref.SupplProvision {
+			suppl = true
+			nodes = r.ref.Suppl.FindAll("Article")
+		}
+	case 2:
+		if parent.Article == "" {
+			nodes = r.law.Find("MainProvision").ChildrenByTag("Paragraph")
+		} else {
+			nodes = r.law.Provision(parent).Paragraphs()
+		}
+	case 3:
+		nodes = r.law.Provision(parent).Items()
+	}
+	nums := make([]string, len(nodes))
+	for i, node := range nodes {
+		nums[i] = node.Num()
+		if suppl {
+			nums[i] = supplArticle(nums[i], r.ref.SupplIndex)
+		}
+	}
+	return nums
+}
+
+// expandRange returns the provisions from a to b (第三条から第五条まで) of the same level
+// and parent, or a and b if they are not such a range
+func (r *citationResolver) expandRange(a, b ProvisionID) []ProvisionID {
+	level := provisionLevel(a)
+	parent := truncateProvision(a, level-1)
+	if provisionLevel(b) != level || truncateProvision(b, level-1) != parent {
+		return appendUnique([]ProvisionID{a}, b)
+	}
+	siblings := r.siblings(parent, level)
+	if level == 1 && !strings.HasPrefix(a.Article, "s") {
+		siblings = siblings[:0]
+		for _, article := range r.law.Articles() {
+			siblings = append(siblings, article.Num())
+		}
+	}
+	nums := []string{"", a.Article, a.Paragraph, a.Item}
+	i := slices.Index(siblings, nums[level])
+	j := slices.Index(siblings, []string{"", b.Article, b.Paragraph, b.Item}[level])
+	if i < 0 || j < i {
+		return appendUnique([]ProvisionID{a}, b)
+	}
+	var ids []ProvisionID
+	for _, num := range siblings[i : j+1] {
+		id := a
+		switch level {
+		case 1:
+			id.Article = num
+		case 2:
+			id.Paragraph = num
+		case 3:
+			id.Item = num
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// provisionLevel returns 1 for articles, 2 for paragraphs and 3 for items and sub-items
+func provisionLevel(id ProvisionID) int {
+	switch {
+	case id.Item != "":
+		return 3
+	case id.Paragraph != "":
+		return 2
+	case id.Article != "":
+		return 1
+	}
+	return 0
+}
+
+// truncateProvision returns the provision of the given level containing id
+func truncateProvision(id ProvisionID, level int) ProvisionID {
+	id.LawID, id.Revision = "", ""
+	if level < 3 {
+		id.Item = ""
+	} else {
+		id.Item, _, _ = strings.Cut(id.Item, ".")
+	}
+	if level < 2 {
+		id.Paragraph = ""
+	}
+	if level < 1 {
+		id.Article = ""
+	}
+	return id
+}
+
+// replacement is a TextReplacement before formatting its provision ID
+type replacement struct {
+	id       *ProvisionID
+	old, new string
+}
+
+// replacements parses the replacements of a sentence (「A」とあるのは「B」と), each in the
+// provisions cited before 「中」 (第五条中), which carry over to the following ones
+func (r *citationResolver) replacements(text string, citations []citation) []replacement {
+	var reps []replacement
+	var in []ProvisionID
+	inExternal := false
+	for i := 0; i < len(text); {
+		start := strings.Index(text[i:], "「")
+		if start < 0 {
+			break
+		}
+		start += i
+		if nestingDepth(text[:start]) != 0 {
+			_, rest, err := cutQuote(text[start:])
+			if err != nil {
+				break
+			}
+			i = len(text) - len(rest)
+			continue
+		}
+		if before, ok := strings.CutSuffix(text[:start], "中"); ok {
+			in, inExternal = nil, false
+			for _, c := range citations {
+				if c.end == len(before) {
+					in, inExternal = c.ids, c.external
+				}
+			}
+		}
+		// 「A」とあり、及び「B」とあるのは「C」と
+		var olds []string
+		rest := text[start:]
+		for {
+			old, after, err := cutQuote(rest)
+			if err != nil {
+				return reps
+			}
+			olds = append(olds, old)
+			if next, ok := strings.CutPrefix(after, "とあり、"); ok {
+				for _, conj := range []string{"及び", "並びに", "又は", "若しくは"} {
+					next = strings.TrimPrefix(next, conj)
+				}
+				if strings.HasPrefix(next, "「") {
+					rest = next
+					continue
+				}
+			}
+			rest = after
+			break
+		}
+		next, ok := strings.CutPrefix(rest, "とあるのは")
+		if !ok || !strings.HasPrefix(next, "「") {
+			i = len(text) - len(rest)
+			continue
+		}
+		newText, after, err := cutQuote(next)
+		if err != nil {
+			break
+		}
+		i = len(text) - len(after)
+		if inExternal {
+			continue
+		}
+		for _, old := range olds {
+			if len(in) == 0 {
+				reps = append(reps, replacement{old: old, new: newText})
+			}
+			for _, id := range in {
+				reps = append(reps, replacement{id: &id, old: old, new: newText})
+			}
+		}
+	}
+	return reps
+}
+
+// tableReplacements parses the replacements of the tables of a provision with columns
+// for the provisions, the words replaced and the words read instead. An empty provision
+// cell continues the provisions of the row above.
+func (r *citationResolver) tableReplacements(ref SentenceRef) []replacement {
+	provision := ref.Paragraph
+	if len(ref.Subitems) > 0 {
+		provision = ref.Subitems[len(ref.Subitems)-1]
+	} else if ref.Item != nil {
+		provision = ref.Item
+	}
+	var reps []replacement
+	for _, table := range provision.ChildrenByTag("TableStruct") {
+		var in []ProvisionID
+		for _, row := range table.FindAll("TableRow") {
+			columns := row.ChildrenByTag("TableColumn")
+			if len(columns) != 3 {
+				continue
+			}
+			cells := make([]string, 3)
+			for i, column := range columns {
+				cells[i] = strings.TrimSpace(column.PlainText())
+			}
+			if cells[0] != "" {
+				in = nil
+				for _, c := range r.citations(cells[0]) {
+					if !c.external {
+						in = appendUnique(in, c.ids...)
+					}
+				}
+			}
+			if cells[1] == "" {
+				continue
+			}
+			for _, id := range in {
+				reps = append(reps, replacement{id: &id, old: cells[1], new: cells[2]})
+			}
+		}
+	}
+	return reps
+}
+
+// references formats the provisions of replacements
+func (r *citationResolver) references(reps []replacement, lawID, revision string) []TextReplacement {
+	var replacements []TextReplacement
+	for _, rep := range reps {
+		tr := TextReplacement{Old: rep.old, New: rep.new}
+		if rep.id != nil {
+			ref := r.provisionReferences([]ProvisionID{*rep.id}, lawID, revision)[0]
+			tr.ProvisionID, tr.Provision = ref.ProvisionID, ref.Provision
+		}
+		replacements = append(replacements, tr)
+	}
+	return replacements
+}
+
+func (r *citationResolver) provisionReferences(ids []ProvisionID, lawID, revision string) []ProvisionReference {
+	refs := make([]ProvisionReference, 0, len(ids))
+	for _, id := range ids {
+		sref, _ := r.law.provisionRef(id)
+		id.LawID, id.Revision = lawID, revision
+		refs = append(refs, ProvisionReference{ProvisionID: id.String(), Provision: sref.Label()})
+	}
+	return refs
+}
+
+// AppliedProvision is a provision as applied by a dependency, with its words replaced
+type AppliedProvision struct {
+	ProvisionReference
+	// Node is a copy of the provision with the replacements made
+	Node *LawNode `json:"-"`
+	// Text is the text of the copy, one paragraph per line after the article caption
+	Text string `json:"text"`
+}
+
+// AsApplied materializes the provisions a dependency applies as they read when applied:
+// copies of the provisions of dep.To with the words of its replacements replaced in their
+// sentences. Replacements are made simultaneously, preferring the longest word at each
+// position, so that words read as others are not replaced again. It also returns the
+// replacements whose words do not occur in the provisions they are stated for.
+func (n *LawNode) AsApplied(dep ProvisionDependency) ([]AppliedProvision, []TextReplacement) {
+	matched := make([]bool, len(dep.Replacements))
+	var applied []AppliedProvision
+	for _, to := range dep.To {
+		id, ok := splitProvisionID(to.ProvisionID)
+		if !ok || n.Provision(id) == nil {
+			continue
+		}
+		node := n.Provision(id).clone()
+		// reps holds the indices of the replacements made in each text node
+		reps := map[*LawNode][]int{}
+		for i, rep := range dep.Replacements {
+			target := node
+			if rep.ProvisionID != "" {
+				repID, ok := splitProvisionID(rep.ProvisionID)
+				switch {
+				case !ok:
+					continue
+				case provisionWithin(repID, id):
+					target = subProvision(node, id, repID)
+				case !provisionWithin(id, repID):
+					continue
+				}
+			}
+			for _, sentence := range target.FindAll("Sentence") {
+				sentence.Walk(func(t *LawNode) bool {
+					if t.IsText() {
+						reps[t] = append(reps[t], i)
+					}
+					return t.Tag != "Rt"
+				})
+			}
+		}
+		for t, indices := range reps {
+			t.Text = replaceWords(t.Text, dep.Replacements, indices, matched)
+		}
+
+		lines := []string{strings.TrimSpace(node.PlainText())}
+		if node.Tag == "Article" {
+			lines = []string{strings.TrimSpace(node.Child("ArticleCaption").PlainText())}
+			for _, paragraph := range node.Paragraphs() {
+				lines = append(lines, strings.TrimSpace(paragraph.PlainText()))
+			}
+		}
+		applied = append(applied, AppliedProvision{
+			ProvisionReference: to,
+			Node:               node,
+			Text:               strings.TrimSpace(strings.Join(lines, "\n")),
+		})
+	}
+	var unmatched []TextReplacement
+	for i, rep := range dep.Replacements {
+		if !matched[i] {
+			unmatched = append(unmatched, rep)
+		}
+	}
+	return applied, unmatched
+}
+
+// replaceWords replaces the words of the replacements with the given indices in s in one
+// pass, preferring the longest word at each position, and marks those made as matched
+func replaceWords(s string, reps []TextReplacement, indices []int, matched []bool) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		best := -1
+		for _, j := range indices {
+			if reps[j].Old != "" && strings.HasPrefix(s[i:], reps[j].Old) && (best < 0 || len(reps[j].Old) > len(reps[best].Old)) {
+				best = j
+			}
+		}
+		if best >= 0 {
+			sb.WriteString(reps[best].New)
+			i += len(reps[best].Old)
+			matched[best] = true
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		sb.WriteString(s[i : i+size])
+		i += size
+	}
+	return sb.String()
+}
+
+// splitProvisionID splits a provision ID like ParseProvisionID, allowing an empty law ID
+func splitProvisionID(s string) (ProvisionID, bool) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 5 {
+		return ProvisionID{}, false
+	}
+	return ProvisionID{LawID: parts[0], Revision: parts[1], Article: parts[2], Paragraph: parts[3], Item: parts[4]}, true
+}
+
+// provisionWithin reports whether the provision id is outer or one of its sub-provisions,
+// ignoring the law and revision segments
+func provisionWithin(id, outer ProvisionID) bool {
+	return id.Article == outer.Article &&
+		(outer.Paragraph == "" || id.Paragraph == outer.Paragraph) &&
+		(outer.Item == "" || id.Item == outer.Item || strings.HasPrefix(id.Item, outer.Item+"."))
+}
+
+// subProvision returns the provision sub within node, the provision id
+func subProvision(node *LawNode, id, sub ProvisionID) *LawNode {
+	if id.Paragraph == "" && sub.Paragraph != "" {
+		node = node.Paragraph(sub.Paragraph)
+	}
+	if sub.Item != "" {
+		nums := strings.Split(sub.Item, ".")
+		if id.Item != "" {
+			nums = nums[len(strings.Split(id.Item, ".")):]
+		}
+		for _, num := range nums {
+			node = node.Item(num)
+		}
+	}
+	return node
+}
@@ -0,0 +1,135 @@
+package lawapi
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// AtomicFile is a file that replaces the file at its path only when committed, so that
+// readers and crashes never see a partial file. It is written to a hidden temporary file
+// in the same directory, which Commit syncs and renames into place. Files created
+// concurrently for the same path do not interfere; the last commit wins.
+type AtomicFile struct {
+	*os.File
+	path string
+	perm fs.FileMode
+	done bool
+}
+
+// CreateAtomic creates an AtomicFile for path, with permissions perm once committed. The
+// directory of path must exist.
+func CreateAtomic(path string, perm fs.FileMode) (*AtomicFile, error) {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	// Hidden names keep temporary files out of manifests and directory listings
+	f, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+	return &AtomicFile{File: f, path: path, perm: perm}, nil
+}
+
+// Commit syncs the file to disk and renames it to its path, replacing any existing file.
+// The file is removed if any step fails.
+func (f *AtomicFile) Commit() error {
+	if f.done {
+		return os.ErrClosed
+	}
+	f.done = true
+	tmp := f.File.Name()
+	err := f.File.Sync()
+	if err == nil {
+		err = f.File.Chmod(f.perm)
+	}
+	if closeErr := f.File.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = renameReplacing(tmp, f.path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return syncDir(filepath.Dir(f.path))
+}
+
+// Close discards the file unless it was committed. It is safe to defer Close and call
+// Commit when the content is complete.
+func (f *AtomicFile) Close() error {
+	if f.done {
+		return nil
+	}
+	f.done = true
+	f.File.Close()
+	return os.Remove(f.File.Name())
+}
+
+// WriteAtomic writes the file at path atomically with the content written by write. The
+// file is left unchanged if write fails.
+func WriteAtomic(path string, perm fs.FileMode, write func(w io.Writer) error) error {
+	f, err := CreateAtomic(path, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := write(f); err != nil {
+		return err
+	}
+	return f.Commit()
+}
+
+// WriteFileAtomic is the atomic counterpart of os.WriteFile
+func WriteFileAtomic(path string, data []byte, perm fs.FileMode) error {
+	return WriteAtomic(path, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// renameReplacing renames a file over an existing one. On Windows, replacing a file that
+// another process has open (such as a virus scanner or a concurrent reader) fails
+// transiently, so the rename is retried for up to about a second. The retries wait in real
+// time rather than on a Clock: they wait for other processes, and a stopped test clock
+// would block the write.
+func renameReplacing(from, to string) error {
+	err := os.Rename(from, to)
+	if runtime.GOOS != "windows" {
+		return err
+	}
+	for delay := 10 * time.Millisecond; err != nil && delay <= 640*time.Millisecond; delay *= 2 {
+		var linkErr *os.LinkError
+		if !errors.As(err, &linkErr) || errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		time.Sleep(delay)
+		err = os.Rename(from, to)
+	}
+	return err
+}
+
+// syncDir syncs a directory so that a rename in it survives a crash. Windows cannot sync
+// directories, and renames there are journaled by NTFS.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	// Some file systems do not support syncing directories
+	if err := d.Sync(); err != nil && !errors.Is(err, errors.ErrUnsupported) && !errors.Is(err, syscall.EINVAL) {
+		return err
+	}
+	return nil
+}
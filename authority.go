@@ -0,0 +1,75 @@
+package lawapi
+
+import "strings"
+
+// authoritySuffixes map the issuing part of a law number to the suffix removed to obtain
+// the authority (e.g. 総務省令 → 総務省, 公正取引委員会規則 → 公正取引委員会)
+var authoritySuffixes = []struct{ suffix, replacement string }{
+	{"省令", "省"},
+	{"府令", "府"},
+	{"庁令", "庁"},
+	{"規則", ""},
+	{"告示", ""},
+	{"訓令", ""},
+}
+
+// InferAuthorities infers the authorities (府省庁) that issued a law from its law number:
+// 国会 for acts, 内閣 for cabinet orders and the ministries or commissions for ministerial
+// ordinances, rules and notices (e.g. ["内閣府", "総務省"] for 令和元年内閣府・総務省令第一号).
+// It returns nil when the law number does not name an authority, as for the constitution
+// and imperial orders.
+func InferAuthorities(lawNum string) []string {
+	lawNum = strings.TrimSpace(lawNum)
+	if strings.HasPrefix(lawNum, "人事院規則") {
+		// 人事院規則 are numbered without a year (e.g. 人事院規則一―三四)
+		return []string{"人事院"}
+	}
+	_, issuer, ok := strings.Cut(lawNum, "年")
+	if !ok {
+		return nil
+	}
+	issuer, _, ok = strings.Cut(issuer, "第")
+	if !ok {
+		return nil
+	}
+
+	switch issuer {
+	case "法律":
+		return []string{"国会"}
+	case "政令":
+		return []string{"内閣"}
+	case "", "憲法", "勅令":
+		return nil
+	}
+
+	var authorities []string
+	for _, part := range strings.Split(issuer, "・") {
+		for _, s := range authoritySuffixes {
+			if trimmed, ok := strings.CutSuffix(part, s.suffix); ok {
+				part = trimmed + s.replacement
+				break
+			}
+		}
+		if part != "" {
+			authorities = append(authorities, part)
+		}
+	}
+	return authorities
+}
+
+// IssuedBy matches laws issued by any of the authorities, as inferred by InferAuthorities
+// (e.g. IssuedBy("総務省")). Laws issued jointly match each of their authorities. The
+// condition is checked client-side, so combine it with conditions the API can evaluate.
+func IssuedBy(authorities ...string) Query {
+	return queryTerm(func(search *querySearch) {
+		search.authorities = intersect(search.authorities, authorities)
+	})
+}
+
+// lawItemAuthorities returns the inferred authorities of a law entry
+func lawItemAuthorities(item *LawItem) []string {
+	if item.LawInfo == nil {
+		return nil
+	}
+	return InferAuthorities(item.LawInfo.LawNum)
+}
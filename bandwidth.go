@@ -0,0 +1,128 @@
+package lawapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BandwidthLimit throttles the reading of response bodies to a rate in bytes per second
+// shared by all requests through its transport, so that background jobs such as mirroring
+// do not saturate constrained links. Jobs can override the rate with WithBandwidthLimit.
+type BandwidthLimit struct {
+	rate float64
+	// burst is the number of bytes that may be read at once after an idle period
+	burst float64
+	clock Clock
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewBandwidthLimit creates a limit of bytesPerSec bytes per second. A limit of 0 or less
+// does not throttle.
+func NewBandwidthLimit(bytesPerSec int) *BandwidthLimit {
+	rate := float64(bytesPerSec)
+	return &BandwidthLimit{rate: rate, burst: rate, tokens: rate}
+}
+
+// SetClock sets the clock measuring the rate (default: DefaultClock). Call it before the
+// limit is used.
+func (b *BandwidthLimit) SetClock(clock Clock) {
+	b.clock = clock
+}
+
+// wait takes n bytes from the limit, sleeping while the rate is exceeded
+func (b *BandwidthLimit) wait(ctx context.Context, n int) error {
+	if b.rate <= 0 {
+		return nil
+	}
+	clock := clockOrDefault(b.clock)
+	b.mu.Lock()
+	now := clock.Now()
+	if b.last.IsZero() {
+		b.last = now
+	}
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	// Tokens may go negative: concurrent readers queue up behind each other's debt
+	b.tokens -= float64(n)
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+	if delay == 0 {
+		return nil
+	}
+	return sleepContext(ctx, clock, delay)
+}
+
+// chunk returns the most bytes read at once, a tenth of a second at the rate, so that
+// reads are spread evenly
+func (b *BandwidthLimit) chunk() int {
+	return max(int(b.rate/10), 1024)
+}
+
+type bandwidthKey struct{}
+
+// WithBandwidthLimit returns a context whose requests are throttled to bytesPerSec
+// together, instead of by the BandwidthLimit of the transport, e.g. to give one sync job
+// a lower rate than interactive requests. A rate of 0 or less lifts the limit for the
+// context. It takes effect for requests through a BandwidthLimit transport.
+func WithBandwidthLimit(ctx context.Context, bytesPerSec int) context.Context {
+	return context.WithValue(ctx, bandwidthKey{}, NewBandwidthLimit(bytesPerSec))
+}
+
+// Transport returns an http.RoundTripper throttling the reading of the response bodies of
+// requests sent through base (default: http.DefaultTransport). Use it with
+// Client.SetHTTPClient.
+func (b *BandwidthLimit) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &bandwidthTransport{limit: b, base: base}
+}
+
+type bandwidthTransport struct {
+	limit *BandwidthLimit
+	base  http.RoundTripper
+}
+
+func (t *bandwidthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	limit := t.limit
+	if override, ok := req.Context().Value(bandwidthKey{}).(*BandwidthLimit); ok {
+		limit = override
+	}
+	if limit.rate > 0 {
+		resp.Body = &throttledBody{ReadCloser: resp.Body, ctx: req.Context(), limit: limit}
+	}
+	return resp, nil
+}
+
+// throttledBody reads a response body within a bandwidth limit
+type throttledBody struct {
+	io.ReadCloser
+	ctx   context.Context
+	limit *BandwidthLimit
+}
+
+func (b *throttledBody) Read(p []byte) (int, error) {
+	if chunk := b.limit.chunk(); len(p) > chunk {
+		p = p[:chunk]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		if waitErr := b.limit.wait(b.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,214 @@
+package lawapi
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultBatchConcurrency is the number of concurrent requests used by batch helpers when 0 is given
+const DefaultBatchConcurrency = 4
+
+// BatchOptions configures batch helpers
+type BatchOptions struct {
+	// Concurrency is the maximum number of concurrent requests (default: DefaultBatchConcurrency)
+	Concurrency int
+	// Progress receives progress notifications, or nil
+	Progress Progress
+	// Clock measures the durations of items and of the batch (default: the clock of the
+	// client, or DefaultClock)
+	Clock Clock
+}
+
+// batchOptions returns opts with the clock of the client if it has none
+func (c *Client) batchOptions(opts *BatchOptions) *BatchOptions {
+	withClock := BatchOptions{}
+	if opts != nil {
+		withClock = *opts
+	}
+	if withClock.Clock == nil {
+		withClock.Clock = c.currentClock()
+	}
+	return &withClock
+}
+
+// BatchResult is the outcome of one item of a batch operation.
+// Exactly one of Value and Err is meaningful.
+type BatchResult[T any] struct {
+	// Key identifies the item (law ID, attachment src, keyword, ...)
+	Key string
+	// Value is the result of the item
+	Value T
+	// Err is the error of the item, or nil
+	Err error
+	// Duration is the time spent on the item
+	Duration time.Duration
+}
+
+// BatchErrors joins the errors of failed items, or returns nil if all items succeeded
+func BatchErrors[T any](results []BatchResult[T]) error {
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Key, result.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runBatch calls fn for every key with bounded concurrency and returns the results in key order.
+// Once ctx is done no new items are started; the results of items not started carry ctx.Err().
+func runBatch[T any](ctx context.Context, keys []string, opts *BatchOptions, fn func(ctx context.Context, key string) (T, error)) []BatchResult[T] {
+	concurrency := DefaultBatchConcurrency
+	var progress Progress = nopProgress{}
+	clock := DefaultClock
+	if opts != nil {
+		if opts.Concurrency > 0 {
+			concurrency = opts.Concurrency
+		}
+		if opts.Progress != nil {
+			progress = opts.Progress
+		}
+		clock = clockOrDefault(opts.Clock)
+	}
+
+	batchStart := clock.Now()
+	progress.OnStart(len(keys))
+
+	results := make([]BatchResult[T], len(keys))
+	sem := make(chan struct{}, concurrency)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	summary := ProgressSummary{Total: len(keys)}
+	for i, key := range keys {
+		if ctx.Err() == nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			results[i] = BatchResult[T]{Key: key, Err: err}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			start := clock.Now()
+			value, err := fn(ctx, key)
+			results[i] = BatchResult[T]{Key: key, Value: value, Err: err, Duration: clock.Now().Sub(start)}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				summary.Failed++
+			} else {
+				summary.Succeeded++
+			}
+			progress.OnItem(summary.Succeeded+summary.Failed, key)
+		}(i, key)
+	}
+	wg.Wait()
+
+	summary.Duration = clock.Now().Sub(batchStart)
+	progress.OnFinish(summary)
+	return results
+}
+
+// BatchCheckpoint records the items of an interrupted batch operation that were not
+// completed, so the operation can be resumed without repeating finished work
+type BatchCheckpoint struct {
+	// Pending contains the keys of items that were not completed because the context was done
+	Pending []string `json:"pending"`
+	// Completed is the number of items that finished, successfully or not
+	Completed int `json:"completed"`
+}
+
+// NewBatchCheckpoint creates a checkpoint from the results of a batch operation.
+// Items whose error is a context cancellation or deadline are considered pending.
+func NewBatchCheckpoint[T any](results []BatchResult[T]) BatchCheckpoint {
+	checkpoint := BatchCheckpoint{}
+	for _, result := range results {
+		if errors.Is(result.Err, context.Canceled) || errors.Is(result.Err, context.DeadlineExceeded) {
+			checkpoint.Pending = append(checkpoint.Pending, result.Key)
+		} else {
+			checkpoint.Completed++
+		}
+	}
+	return checkpoint
+}
+
+// Done reports whether no items are pending
+func (c BatchCheckpoint) Done() bool {
+	return len(c.Pending) == 0
+}
+
+// Token encodes the checkpoint as an opaque resumable token
+func (c BatchCheckpoint) Token() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseBatchCheckpoint decodes a token created by BatchCheckpoint.Token
+func ParseBatchCheckpoint(token string) (BatchCheckpoint, error) {
+	var checkpoint BatchCheckpoint
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return checkpoint, fmt.Errorf("invalid checkpoint token: %w", err)
+	}
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return checkpoint, fmt.Errorf("invalid checkpoint token: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// GetLawDataBatch retrieves the law data of several laws concurrently.
+// Results are returned in the order of the given IDs. When ctx is done, the
+// remaining IDs are not requested; resume them with NewBatchCheckpoint(results).Pending.
+func (c *Client) GetLawDataBatch(ctx context.Context, lawIdOrNumOrRevisionIds []string, params *GetLawDataParams, opts *BatchOptions) []BatchResult[*LawDataResponse] {
+	return runBatch(ctx, lawIdOrNumOrRevisionIds, c.batchOptions(opts), func(ctx context.Context, id string) (*LawDataResponse, error) {
+		return c.GetLawDataWithContext(ctx, id, params)
+	})
+}
+
+// DownloadAllAttachments retrieves every attached file of a law revision concurrently.
+// Results are keyed by the src of each attachment and sorted by src, each src once.
+func (c *Client) DownloadAllAttachments(ctx context.Context, lawRevisionId string, opts *BatchOptions) ([]BatchResult[*string], error) {
+	data, err := c.GetLawDataWithContext(ctx, lawRevisionId, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attachments of %s: %w", lawRevisionId, err)
+	}
+
+	var srcs []string
+	if data.AttachedFilesInfo != nil && data.AttachedFilesInfo.AttachedFiles != nil {
+		for _, file := range *data.AttachedFilesInfo.AttachedFiles {
+			srcs = append(srcs, file.Src)
+		}
+	}
+	slices.Sort(srcs)
+	srcs = slices.Compact(srcs)
+	return runBatch(ctx, srcs, c.batchOptions(opts), func(ctx context.Context, src string) (*string, error) {
+		return c.GetAttachmentWithContext(ctx, lawRevisionId, &GetAttachmentParams{Src: StringPtr(src)})
+	}), nil
+}
+
+// SearchKeywords runs a keyword search for each keyword concurrently with otherwise identical parameters.
+// Results are returned in the order of the given keywords.
+func (c *Client) SearchKeywords(ctx context.Context, keywords []string, params *GetKeywordParams, opts *BatchOptions) []BatchResult[*KeywordResponse] {
+	return runBatch(ctx, keywords, c.batchOptions(opts), func(ctx context.Context, keyword string) (*KeywordResponse, error) {
+		p := GetKeywordParams{}
+		if params != nil {
+			p = *params
+		}
+		p.Keyword = keyword
+		return c.GetKeywordWithContext(ctx, &p)
+	})
+}
@@ -0,0 +1,184 @@
+package lawapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestBatchInterruption cancels a batch after some items finished and resumes it from
+// the checkpoint token
+func TestBatchInterruption(t *testing.T) {
+	tests := []struct {
+		name        string
+		items       int
+		concurrency int
+		cancelAfter int
+	}{
+		{"sequential", 10, 1, 3},
+		{"concurrent", 20, 4, 7},
+		{"before start", 5, 2, 0},
+		{"after last", 5, 2, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := make([]string, tt.items)
+			for i := range keys {
+				keys[i] = fmt.Sprintf("key%02d", i)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelAfter == 0 {
+				cancel()
+			}
+			var mu sync.Mutex
+			done := 0
+			results := runBatch(ctx, keys, &BatchOptions{Concurrency: tt.concurrency}, func(ctx context.Context, key string) (string, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				if err := ctx.Err(); err != nil {
+					return "", err
+				}
+				done++
+				if done == tt.cancelAfter {
+					cancel()
+				}
+				return strings.ToUpper(key), nil
+			})
+
+			if len(results) != len(keys) {
+				t.Fatalf("got %d results, want %d", len(results), len(keys))
+			}
+			for i, result := range results {
+				if result.Key != keys[i] {
+					t.Errorf("result %d has key %s, want %s", i, result.Key, keys[i])
+				}
+				if result.Err == nil && result.Value != strings.ToUpper(result.Key) {
+					t.Errorf("result %s has value %q", result.Key, result.Value)
+				}
+			}
+
+			checkpoint := NewBatchCheckpoint(results)
+			if checkpoint.Completed != tt.cancelAfter {
+				t.Errorf("completed = %d, want %d", checkpoint.Completed, tt.cancelAfter)
+			}
+			if len(checkpoint.Pending) != tt.items-tt.cancelAfter {
+				t.Errorf("pending = %v, want %d items", checkpoint.Pending, tt.items-tt.cancelAfter)
+			}
+			if checkpoint.Done() != (tt.cancelAfter == tt.items) {
+				t.Errorf("Done() = %v", checkpoint.Done())
+			}
+
+			resumed, err := ParseBatchCheckpoint(checkpoint.Token())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(resumed.Pending, checkpoint.Pending) || resumed.Completed != checkpoint.Completed {
+				t.Fatalf("token round trip = %+v, want %+v", resumed, checkpoint)
+			}
+			rest := runBatch(context.Background(), resumed.Pending, &BatchOptions{Concurrency: tt.concurrency}, func(ctx context.Context, key string) (string, error) {
+				return strings.ToUpper(key), nil
+			})
+			if err := BatchErrors(rest); err != nil {
+				t.Fatal(err)
+			}
+			finished := map[string]bool{}
+			for _, result := range append(results, rest...) {
+				if result.Err == nil {
+					if finished[result.Key] {
+						t.Errorf("%s processed twice", result.Key)
+					}
+					finished[result.Key] = true
+				}
+			}
+			if len(finished) != len(keys) {
+				t.Errorf("%d of %d items finished after resuming", len(finished), len(keys))
+			}
+		})
+	}
+}
+
+// TestGetLawDataBatchInterruption interrupts a batch of API calls while requests are in
+// flight
+func TestGetLawDataBatchInterruption(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		if requests == 3 {
+			cancel()
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"law_info":{"law_id":"` + strings.TrimPrefix(r.URL.Path, "/law_data/") + `"}}`))
+	}))
+	defer server.Close()
+	client := NewClient()
+	client.SetBaseURL(server.URL)
+
+	ids := []string{"A", "B", "C", "D", "E", "F"}
+	results := client.GetLawDataBatch(ctx, ids, nil, &BatchOptions{Concurrency: 1})
+	checkpoint := NewBatchCheckpoint(results)
+	if checkpoint.Done() {
+		t.Fatal("checkpoint of an interrupted batch is done")
+	}
+	for _, result := range results {
+		pending := slices.Contains(checkpoint.Pending, result.Key)
+		if pending != (errors.Is(result.Err, context.Canceled)) {
+			t.Errorf("%s: pending = %v with error %v", result.Key, pending, result.Err)
+		}
+		if result.Err == nil && result.Value.LawInfo.LawId != result.Key {
+			t.Errorf("%s: got law %s", result.Key, result.Value.LawInfo.LawId)
+		}
+	}
+	if checkpoint.Completed+len(checkpoint.Pending) != len(ids) {
+		t.Errorf("checkpoint %+v does not cover %d items", checkpoint, len(ids))
+	}
+}
+
+// summaryProgress keeps the summary of a run
+type summaryProgress struct {
+	nopProgress
+	summary ProgressSummary
+}
+
+func (p *summaryProgress) OnFinish(summary ProgressSummary) { p.summary = summary }
+
+// TestGetLawDataBatchClock measures a batch on the clock of the client, which the server
+// advances by five seconds for each request
+func TestGetLawDataBatchClock(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 4, 1, 9, 0, 0, 0, JST))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clock.Advance(5 * time.Second)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"law_info":{"law_id":"` + strings.TrimPrefix(r.URL.Path, "/law_data/") + `"}}`))
+	}))
+	defer server.Close()
+	client := NewClient()
+	client.SetBaseURL(server.URL)
+	client.SetClock(clock)
+
+	progress := &summaryProgress{}
+	results := client.GetLawDataBatch(context.Background(), []string{"A", "B", "C"}, nil, &BatchOptions{Concurrency: 1, Progress: progress})
+	if err := BatchErrors(results); err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Duration != 5*time.Second {
+			t.Errorf("%s took %s, want 5s", result.Key, result.Duration)
+		}
+	}
+	if progress.summary.Duration != 15*time.Second || progress.summary.Succeeded != 3 {
+		t.Errorf("summary = %+v, want 3 succeeded in 15s", progress.summary)
+	}
+}
@@ -0,0 +1,175 @@
+package lawapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExhausted is returned when a request budget is exhausted and does not wait
+var ErrBudgetExhausted = errors.New("request budget exhausted")
+
+// budgetBucket is the granularity of budget accounting
+const budgetBucket = time.Minute
+
+// BudgetOptions configures a Budget
+type BudgetOptions struct {
+	// Limit is the maximum number of requests in the window
+	Limit int
+	// Window is the length of the rolling window (default: 24 hours)
+	Window time.Duration
+	// Wait makes requests pause until the window has room again, instead of failing with ErrBudgetExhausted
+	Wait bool
+	// Path is a file in which counts are persisted between runs, or empty. The file is
+	// rewritten after every request and must not be shared by concurrent processes.
+	Path string
+	// Clock is the clock of the window (default: DefaultClock)
+	Clock Clock
+}
+
+// Budget limits the number of requests in a rolling window, to keep large crawls friendly
+// to the public API. Requests are counted per minute, and each minute leaves the window one
+// minute after its end, so the budget errs on the side of fewer requests.
+type Budget struct {
+	opts    BudgetOptions
+	mu      sync.Mutex
+	buckets map[int64]int
+}
+
+// NewBudget creates a budget, loading persisted counts from opts.Path if the file exists
+func NewBudget(opts BudgetOptions) (*Budget, error) {
+	if opts.Limit <= 0 {
+		return nil, fmt.Errorf("invalid budget limit %d", opts.Limit)
+	}
+	if opts.Window <= 0 {
+		opts.Window = 24 * time.Hour
+	}
+	b := &Budget{opts: opts, buckets: map[int64]int{}}
+	if opts.Path != "" {
+		data, err := os.ReadFile(opts.Path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, &b.buckets); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", opts.Path, err)
+			}
+		}
+	}
+	return b, nil
+}
+
+// Acquire counts one request. When the budget is exhausted it fails with ErrBudgetExhausted,
+// or with Wait set, blocks until the window has room or ctx is done.
+func (b *Budget) Acquire(ctx context.Context) error {
+	clock := clockOrDefault(b.opts.Clock)
+	for {
+		b.mu.Lock()
+		now := clock.Now()
+		b.expire(now)
+		if b.used() < b.opts.Limit {
+			b.buckets[now.Truncate(budgetBucket).Unix()]++
+			err := b.save()
+			b.mu.Unlock()
+			return err
+		}
+		wait := b.nextRoom(now)
+		b.mu.Unlock()
+
+		if !b.opts.Wait {
+			return fmt.Errorf("%w: %d requests in %s, room again in %s", ErrBudgetExhausted, b.opts.Limit, b.opts.Window, wait.Round(time.Second))
+		}
+		if err := sleepContext(ctx, clock, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Used returns the number of requests in the current window
+func (b *Budget) Used() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expire(clockOrDefault(b.opts.Clock).Now())
+	return b.used()
+}
+
+// Remaining returns the number of requests left in the current window
+func (b *Budget) Remaining() int {
+	return max(b.opts.Limit-b.Used(), 0)
+}
+
+// Transport returns an http.RoundTripper acquiring the budget before each request sent
+// through base (default: http.DefaultTransport). Use it with Client.SetHTTPClient.
+func (b *Budget) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &budgetTransport{budget: b, base: base}
+}
+
+type budgetTransport struct {
+	budget *Budget
+	base   http.RoundTripper
+}
+
+func (t *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.budget.Acquire(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// expire drops the buckets that have left the window
+func (b *Budget) expire(now time.Time) {
+	for start := range b.buckets {
+		if b.bucketEnd(start).Before(now) {
+			delete(b.buckets, start)
+		}
+	}
+}
+
+// bucketEnd returns when the bucket starting at start leaves the window
+func (b *Budget) bucketEnd(start int64) time.Time {
+	return time.Unix(start, 0).Add(budgetBucket + b.opts.Window)
+}
+
+func (b *Budget) used() int {
+	total := 0
+	for _, count := range b.buckets {
+		total += count
+	}
+	return total
+}
+
+// nextRoom returns how long until the oldest bucket leaves the window
+func (b *Budget) nextRoom(now time.Time) time.Duration {
+	var oldest int64
+	for start := range b.buckets {
+		if oldest == 0 || start < oldest {
+			oldest = start
+		}
+	}
+	return b.bucketEnd(oldest).Sub(now) + time.Millisecond
+}
+
+// save persists the counts to the budget file, if any
+func (b *Budget) save() error {
+	if b.opts.Path == "" {
+		return nil
+	}
+	data, err := json.Marshal(b.buckets)
+	if err != nil {
+		return err
+	}
+	// Write atomically so an interrupted run does not lose the counts
+	if err := WriteFileAtomic(b.opts.Path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save budget: %w", err)
+	}
+	return nil
+}
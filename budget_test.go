@@ -0,0 +1,67 @@
+package lawapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBudgetManualClock(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 4, 1, 12, 0, 0, 0, JST))
+	budget, err := NewBudget(BudgetOptions{Limit: 2, Window: 10 * time.Minute, Clock: clock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := budget.Acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := budget.Acquire(context.Background()); !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("got %v, want ErrBudgetExhausted", err)
+	}
+
+	// The minute of the requests leaves the window one minute after its end
+	steps := []struct {
+		advance   time.Duration
+		remaining int
+	}{
+		{10 * time.Minute, 0},
+		{time.Minute, 0},
+		{time.Millisecond, 2},
+	}
+	for _, step := range steps {
+		clock.Advance(step.advance)
+		if got := budget.Remaining(); got != step.remaining {
+			t.Errorf("at %s: %d remaining, want %d", clock.Now(), got, step.remaining)
+		}
+	}
+}
+
+func TestBudgetWaitManualClock(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 4, 1, 12, 0, 0, 0, JST))
+	budget, err := NewBudget(BudgetOptions{Limit: 1, Window: 10 * time.Minute, Wait: true, Clock: clock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := budget.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- budget.Acquire(context.Background()) }()
+	waitForWaiters(t, clock, 1)
+	clock.Advance(11 * time.Minute)
+	select {
+	case err := <-done:
+		t.Fatalf("acquired before the window had room: %v", err)
+	default:
+	}
+	clock.Advance(time.Millisecond)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if got := budget.Used(); got != 1 {
+		t.Errorf("%d used, want 1", got)
+	}
+}
@@ -0,0 +1,347 @@
+package lawapi
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores encoded API responses by key
+type Cache interface {
+	// Get returns the value stored for the key
+	Get(key string) ([]byte, bool)
+	// Set stores the value for the key
+	Set(key string, value []byte)
+	// Delete removes the value stored for the key
+	Delete(key string)
+	// DeletePrefix removes the values stored for all keys starting with the prefix
+	DeletePrefix(prefix string)
+}
+
+// MemoryCache is a bounded in-memory LRU cache with a time-to-live per entry.
+// It is safe for concurrent use.
+type MemoryCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	clock   Clock
+	entries *list.List
+	items   map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// DefaultMemoryCacheSize is the number of entries kept by a MemoryCache created with size 0
+const DefaultMemoryCacheSize = 128
+
+// NewMemoryCache creates an LRU cache holding at most size entries.
+// Entries expire after ttl; a ttl of 0 disables expiration.
+func NewMemoryCache(size int, ttl time.Duration) *MemoryCache {
+	if size <= 0 {
+		size = DefaultMemoryCacheSize
+	}
+	return &MemoryCache{
+		size:    size,
+		ttl:     ttl,
+		entries: list.New(),
+		items:   map[string]*list.Element{},
+	}
+}
+
+// SetClock sets the clock of entry expiration (default: DefaultClock)
+func (c *MemoryCache) SetClock(clock Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+}
+
+// Get implements Cache
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*memoryCacheEntry)
+	if !entry.expires.IsZero() && clockOrDefault(c.clock).Now().After(entry.expires) {
+		c.removeElement(elem)
+		return nil, false
+	}
+	c.entries.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Set implements Cache
+func (c *MemoryCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = clockOrDefault(c.clock).Now().Add(c.ttl)
+	}
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*memoryCacheEntry)
+		entry.value, entry.expires = value, expires
+		c.entries.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.entries.PushFront(&memoryCacheEntry{key: key, value: value, expires: expires})
+	for c.entries.Len() > c.size {
+		c.removeElement(c.entries.Back())
+	}
+}
+
+// Delete implements Cache
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// DeletePrefix implements Cache
+func (c *MemoryCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.removeElement(elem)
+		}
+	}
+}
+
+// Len returns the number of entries in the cache, including expired entries not yet evicted
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
+
+func (c *MemoryCache) removeElement(elem *list.Element) {
+	c.entries.Remove(elem)
+	delete(c.items, elem.Value.(*memoryCacheEntry).key)
+}
+
+// CachingClient is a Client serving GetLawData responses from a Cache
+type CachingClient struct {
+	*Client
+	cache Cache
+
+	mu           sync.Mutex
+	onInvalidate []func(lawID string)
+}
+
+// NewCachingClient creates a client caching GetLawData, GetLaws and GetKeyword responses in the given cache
+func NewCachingClient(client *Client, cache Cache) *CachingClient {
+	return &CachingClient{Client: client, cache: cache}
+}
+
+// GetLawData returns the law data from the cache, retrieving and storing it on a cache miss
+func (c *CachingClient) GetLawData(lawIdOrNumOrRevisionId string, params *GetLawDataParams) (*LawDataResponse, error) {
+	return c.GetLawDataWithContext(context.Background(), lawIdOrNumOrRevisionId, params)
+}
+
+// GetLawDataWithContext is like GetLawData but uses ctx for the request
+func (c *CachingClient) GetLawDataWithContext(ctx context.Context, lawIdOrNumOrRevisionId string, params *GetLawDataParams) (*LawDataResponse, error) {
+	return cachedCall(c, lawDataCacheKey(lawIdOrNumOrRevisionId, params, DateOf(c.currentClock().Now())), func() (*LawDataResponse, error) {
+		return c.Client.GetLawDataWithContext(ctx, lawIdOrNumOrRevisionId, params)
+	})
+}
+
+// GetLaws returns the search result from the cache, retrieving and storing it on a cache miss.
+// Parameters are normalized with NormalizeLawsParams, so equivalent queries share a cache entry.
+func (c *CachingClient) GetLaws(params *GetLawsParams) (*LawsResponse, error) {
+	return c.GetLawsWithContext(context.Background(), params)
+}
+
+// GetLawsWithContext is like GetLaws but uses ctx for the request
+func (c *CachingClient) GetLawsWithContext(ctx context.Context, params *GetLawsParams) (*LawsResponse, error) {
+	params = NormalizeLawsParams(params)
+	key, err := c.lawsCacheKey(params)
+	if err != nil {
+		return nil, err
+	}
+	return cachedCall(c, key, func() (*LawsResponse, error) {
+		return c.Client.GetLawsWithContext(ctx, params)
+	})
+}
+
+// GetKeyword returns the search result from the cache, retrieving and storing it on a cache miss.
+// Parameters are normalized with NormalizeKeywordParams, so equivalent queries share a cache entry.
+func (c *CachingClient) GetKeyword(params *GetKeywordParams) (*KeywordResponse, error) {
+	return c.GetKeywordWithContext(context.Background(), params)
+}
+
+// GetKeywordWithContext is like GetKeyword but uses ctx for the request
+func (c *CachingClient) GetKeywordWithContext(ctx context.Context, params *GetKeywordParams) (*KeywordResponse, error) {
+	params = NormalizeKeywordParams(params)
+	key, err := c.keywordCacheKey(params)
+	if err != nil {
+		return nil, err
+	}
+	return cachedCall(c, key, func() (*KeywordResponse, error) {
+		return c.Client.GetKeywordWithContext(ctx, params)
+	})
+}
+
+// cachedCall returns the JSON-encoded response stored under key, or calls fetch and stores its response
+func cachedCall[T any](c *CachingClient, key string, fetch func() (*T, error)) (*T, error) {
+	if data, ok := c.cache.Get(key); ok {
+		var result T
+		if err := json.Unmarshal(data, &result); err == nil {
+			return &result, nil
+		}
+		c.cache.Delete(key)
+	}
+
+	result, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response for cache: %w", err)
+	}
+	c.cache.Set(key, data)
+	c.tagLaws(key, cachedLawIDs(result))
+	return result, nil
+}
+
+// cachedLawIDs returns the IDs of the laws a response carries information of
+func cachedLawIDs(response any) []string {
+	var infos []*LawInfo
+	switch r := response.(type) {
+	case *LawDataResponse:
+		infos = append(infos, r.LawInfo)
+	case *LawsResponse:
+		for _, item := range r.Laws {
+			infos = append(infos, item.LawInfo)
+		}
+	case *KeywordResponse:
+		for _, item := range r.Items {
+			infos = append(infos, item.LawInfo)
+		}
+	}
+	var ids []string
+	for _, info := range infos {
+		if info != nil && info.LawId != "" && !slices.Contains(ids, info.LawId) {
+			ids = append(ids, info.LawId)
+		}
+	}
+	return ids
+}
+
+// lawTagKeyPrefix starts the keys of the tags of laws, which list the keys of the cached
+// responses carrying information of a law other than its law_data by law ID
+const lawTagKeyPrefix = "tags/law/"
+
+// tagLaws adds a cache key to the tags of laws
+func (c *CachingClient) tagLaws(key string, lawIDs []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, lawID := range lawIDs {
+		if strings.HasPrefix(key, lawDataCacheKeyPrefix+lawID+"?") || strings.HasPrefix(key, lawDataCacheKeyPrefix+lawID+"_") {
+			continue
+		}
+		keys := c.lawTag(lawID)
+		if slices.Contains(keys, key) {
+			continue
+		}
+		data, err := json.Marshal(append(keys, key))
+		if err != nil {
+			continue
+		}
+		c.cache.Set(lawTagKeyPrefix+lawID, data)
+	}
+}
+
+// lawTag returns the keys of the tag of a law
+func (c *CachingClient) lawTag(lawID string) []string {
+	var keys []string
+	if data, ok := c.cache.Get(lawTagKeyPrefix + lawID); ok {
+		json.Unmarshal(data, &keys)
+	}
+	return keys
+}
+
+// InvalidateLaw removes the cached responses of a law, including all of its revisions,
+// and notifies the functions registered with OnInvalidate. Besides the law data cached
+// under the law ID, it removes the responses tagged with the law when they were stored:
+// law data cached under the law number, and the GetLaws and GetKeyword results listing
+// the law. Tags are cache entries themselves; if the cache evicts the tag of a law before
+// the responses it lists, those responses stay until they expire. Tags are updated under
+// a lock of the client, so clients of other processes sharing the cache can lose each
+// other's additions.
+func (c *CachingClient) InvalidateLaw(lawID string) {
+	c.cache.DeletePrefix(lawDataCacheKeyPrefix + lawID + "?")
+	c.cache.DeletePrefix(lawDataCacheKeyPrefix + lawID + "_")
+
+	c.mu.Lock()
+	for _, key := range c.lawTag(lawID) {
+		c.cache.Delete(key)
+	}
+	c.cache.Delete(lawTagKeyPrefix + lawID)
+	listeners := append([]func(string){}, c.onInvalidate...)
+	c.mu.Unlock()
+	for _, fn := range listeners {
+		fn(lawID)
+	}
+}
+
+// OnInvalidate registers a function called after the cached responses of a law are invalidated,
+// so application-level caches can follow suit
+func (c *CachingClient) OnInvalidate(fn func(lawID string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onInvalidate = append(c.onInvalidate, fn)
+}
+
+const lawDataCacheKeyPrefix = "law_data/"
+
+// lawDataCacheKey builds the cache key of a GetLawData call from the law and every parameter affecting the response.
+// Calls by law ID or number without asof return the revision in force on the day of the call in Japan, so they are
+// keyed by today, the date the API defaults asof to.
+func lawDataCacheKey(lawIdOrNumOrRevisionId string, params *GetLawDataParams, today Date) string {
+	values := url.Values{}
+	if (params == nil || params.Asof == nil) && !strings.Contains(lawIdOrNumOrRevisionId, "_") {
+		values.Set("asof", today.String())
+	}
+	if params != nil {
+		if params.LawFullTextFormat != nil {
+			values.Set("law_full_text_format", string(*params.LawFullTextFormat))
+		}
+		if params.Asof != nil {
+			values.Set("asof", params.Asof.String())
+		}
+		if params.Elm != nil {
+			values.Set("elm", string(*params.Elm))
+		}
+		if params.OmitAmendmentSupplProvision != nil {
+			values.Set("omit_amendment_suppl_provision", fmt.Sprintf("%v", *params.OmitAmendmentSupplProvision))
+		}
+		if params.IncludeAttachedFileContent != nil {
+			values.Set("include_attached_file_content", fmt.Sprintf("%v", *params.IncludeAttachedFileContent))
+		}
+		if params.ResponseFormat != nil {
+			values.Set("response_format", string(*params.ResponseFormat))
+		}
+	}
+	return lawDataCacheKeyPrefix + lawIdOrNumOrRevisionId + "?" + values.Encode()
+}
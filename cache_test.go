@@ -0,0 +1,107 @@
+package lawapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheExpiryManualClock(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		advance time.Duration
+		found   bool
+	}{
+		{"fresh", time.Hour, 59 * time.Minute, true},
+		{"at the deadline", time.Hour, time.Hour, true},
+		{"expired", time.Hour, time.Hour + time.Nanosecond, false},
+		{"no expiration", 0, 24 * 365 * time.Hour, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewManualClock(time.Date(2024, 4, 1, 0, 0, 0, 0, JST))
+			cache := NewMemoryCache(0, tt.ttl)
+			cache.SetClock(clock)
+			cache.Set("key", []byte("value"))
+			clock.Advance(tt.advance)
+			if _, found := cache.Get("key"); found != tt.found {
+				t.Errorf("found = %v, want %v", found, tt.found)
+			}
+		})
+	}
+}
+
+// TestCachingClientInvalidateLaw checks that invalidating a law removes every cached
+// response carrying its information, and keeps the others
+func TestCachingClientInvalidateLaw(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/laws":
+			if r.URL.Query().Get("law_title") == "電波" {
+				w.Write([]byte(`{"count":1,"laws":[{"law_info":{"law_id":"325AC0000000131"}}]}`))
+				return
+			}
+			w.Write([]byte(`{"count":1,"laws":[{"law_info":{"law_id":"322AC0000000049"}}]}`))
+		case "/keyword":
+			w.Write([]byte(`{"items":[{"law_info":{"law_id":"322AC0000000049"}},{"law_info":{"law_id":"325AC0000000131"}}]}`))
+		default:
+			w.Write([]byte(`{"law_info":{"law_id":"325AC0000000131"}}`))
+		}
+	}))
+	defer server.Close()
+	client := NewClient()
+	client.SetBaseURL(server.URL)
+	cached := NewCachingClient(client, NewMemoryCache(0, 0))
+
+	radio, labor := "電波", "労働"
+	lawData := func(id string) func() error {
+		return func() error { _, err := cached.GetLawData(id, nil); return err }
+	}
+	calls := []struct {
+		name string
+		call func() error
+		// stale is whether the response carries information of 325AC0000000131
+		stale bool
+	}{
+		{"law data by ID", lawData("325AC0000000131"), true},
+		{"law data by number", lawData("昭和二十五年法律第百三十一号"), true},
+		{"laws listing the law", func() error { _, err := cached.GetLaws(&GetLawsParams{LawTitle: &radio}); return err }, true},
+		{"keyword listing the law", func() error { _, err := cached.GetKeyword(&GetKeywordParams{Keyword: "無線"}); return err }, true},
+		{"other laws", func() error { _, err := cached.GetLaws(&GetLawsParams{LawTitle: &labor}); return err }, false},
+	}
+	for _, c := range calls {
+		if err := c.call(); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+	}
+	var invalidated []string
+	cached.OnInvalidate(func(lawID string) { invalidated = append(invalidated, lawID) })
+	cached.InvalidateLaw("325AC0000000131")
+	if !slices.Equal(invalidated, []string{"325AC0000000131"}) {
+		t.Errorf("listeners notified of %v", invalidated)
+	}
+	for _, c := range calls {
+		mu.Lock()
+		before := len(requests)
+		mu.Unlock()
+		if err := c.call(); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		mu.Lock()
+		fetched := len(requests) > before
+		mu.Unlock()
+		if fetched != c.stale {
+			t.Errorf("%s: fetched again = %v, want %v", c.name, fetched, c.stale)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package lawapi
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+)
+
+// NormalizeLawsParams returns a copy of the parameters in canonical form, so that
+// semantically equivalent queries produce identical requests and cache keys:
+// text is trimmed and whitespace collapsed, kana titles are folded to hiragana,
+// lists are sorted and deduplicated, and parameters equal to the API defaults are removed.
+func NormalizeLawsParams(params *GetLawsParams) *GetLawsParams {
+	p := GetLawsParams{}
+	if params != nil {
+		p = *params
+	}
+	p.LawId = normalizeTextParam(p.LawId)
+	p.LawNum = normalizeTextParam(p.LawNum)
+	p.LawNumNum = normalizeTextParam(p.LawNumNum)
+	p.LawTitle = normalizeTextParam(p.LawTitle)
+	if kana := normalizeTextParam(p.LawTitleKana); kana != nil {
+		p.LawTitleKana = StringPtr(katakanaToHiragana(*kana))
+	} else {
+		p.LawTitleKana = nil
+	}
+	p.AmendmentLawId = normalizeTextParam(p.AmendmentLawId)
+	p.LawType = normalizeListParam(p.LawType)
+	p.CategoryCd = normalizeListParam(p.CategoryCd)
+	p.Mission = normalizeListParam(p.Mission)
+	p.RepealStatus = normalizeListParam(p.RepealStatus)
+	if p.OmitCurrentRevisionInfo != nil && !*p.OmitCurrentRevisionInfo {
+		p.OmitCurrentRevisionInfo = nil
+	}
+	p.Limit = omitDefault(p.Limit, 100)
+	p.Offset = omitDefault(p.Offset, 0)
+	p.Order = normalizeOrderParam(p.Order)
+	p.ResponseFormat = omitDefault(p.ResponseFormat, ResponseFormatJson)
+	return &p
+}
+
+// NormalizeKeywordParams returns a copy of the parameters in canonical form (see NormalizeLawsParams)
+func NormalizeKeywordParams(params *GetKeywordParams) *GetKeywordParams {
+	p := GetKeywordParams{}
+	if params != nil {
+		p = *params
+	}
+	p.Keyword = NormalizeKeyword(p.Keyword)
+	p.LawNum = normalizeTextParam(p.LawNum)
+	p.LawNumNum = normalizeTextParam(p.LawNumNum)
+	p.LawType = normalizeListParam(p.LawType)
+	p.CategoryCd = normalizeListParam(p.CategoryCd)
+	p.Limit = omitDefault(p.Limit, 100)
+	p.Offset = omitDefault(p.Offset, 0)
+	p.Order = normalizeOrderParam(p.Order)
+	p.ResponseFormat = omitDefault(p.ResponseFormat, ResponseFormatJson)
+	p.SentenceTextSize = omitDefault(p.SentenceTextSize, 100)
+	if p.HighlightTag != nil {
+		if tag, err := SanitizeHighlightTag(*p.HighlightTag); err == nil {
+			p.HighlightTag = &tag
+		}
+	}
+	p.HighlightTag = omitDefault(p.HighlightTag, string(HighlightSpan))
+	return &p
+}
+
+func normalizeTextParam(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	normalized := NormalizeKeyword(*s)
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
+
+func normalizeListParam[T cmp.Ordered](list *[]T) *[]T {
+	if list == nil || len(*list) == 0 {
+		return nil
+	}
+	sorted := slices.Compact(slices.Sorted(slices.Values(*list)))
+	return &sorted
+}
+
+func normalizeOrderParam(order *string) *string {
+	order = normalizeTextParam(order)
+	if order != nil && (*order == "law_info.law_id" || *order == "+law_info.law_id") {
+		return nil
+	}
+	return order
+}
+
+func omitDefault[T comparable](v *T, def T) *T {
+	if v != nil && *v == def {
+		return nil
+	}
+	return v
+}
+
+// katakanaToHiragana folds katakana to hiragana
+func katakanaToHiragana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ァ' && r <= 'ヶ' {
+			return r - 0x60
+		}
+		return r
+	}, s)
+}
+
+const (
+	lawsCacheKeyPrefix    = "laws?"
+	keywordCacheKeyPrefix = "keyword?"
+)
+
+// lawsCacheKey builds the cache key of normalized GetLaws parameters from their query string
+func (c *Client) lawsCacheKey(params *GetLawsParams) (string, error) {
+	req, err := c.BuildGetLawsRequest(context.Background(), params)
+	if err != nil {
+		return "", err
+	}
+	return lawsCacheKeyPrefix + req.URL.RawQuery, nil
+}
+
+// keywordCacheKey builds the cache key of normalized GetKeyword parameters from their query string
+func (c *Client) keywordCacheKey(params *GetKeywordParams) (string, error) {
+	req, err := c.BuildGetKeywordRequest(context.Background(), params)
+	if err != nil {
+		return "", err
+	}
+	return keywordCacheKeyPrefix + req.URL.RawQuery, nil
+}
@@ -0,0 +1,210 @@
+package lawapi
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SentenceClass is a kind of legal statement a sentence makes. Rules may use classes
+// beyond the predefined ones.
+type SentenceClass string
+
+const (
+	// ClassObligation is a duty to act (「…しなければならない」)
+	ClassObligation SentenceClass = "obligation"
+	// ClassProhibition is a prohibition (「…してはならない」)
+	ClassProhibition SentenceClass = "prohibition"
+	// ClassPermission is a permission or power (「…することができる」)
+	ClassPermission SentenceClass = "permission"
+	// ClassDefinition defines a term (「「X」とは、…をいう。」)
+	ClassDefinition SentenceClass = "definition"
+	// ClassPenalty imposes a punishment (「…に処する。」)
+	ClassPenalty SentenceClass = "penalty"
+)
+
+// ClassifierRule assigns a class to sentences matching a pattern, with a weight expressing
+// how reliably the pattern indicates the class
+type ClassifierRule struct {
+	// Name identifies the rule in classifications, defaulting to the pattern
+	Name  string        `yaml:"name,omitempty" json:"name,omitempty"`
+	Class SentenceClass `yaml:"class" json:"class"`
+	// Pattern is a regular expression (RE2 syntax) matched against the sentence text
+	Pattern string `yaml:"pattern" json:"pattern"`
+	// Unless is a regular expression that keeps the rule from applying when it matches
+	Unless string `yaml:"unless,omitempty" json:"unless,omitempty"`
+	// Weight is the confidence the rule gives the class, greater than 0 and at most 1
+	Weight float64 `yaml:"weight" json:"weight"`
+}
+
+// DefaultClassifierRules are the rules used by NewSentenceClassifier when none are given.
+// Predicates ending the sentence weigh more than the same predicates within it, which
+// often belong to clauses (「…しなければならない者」).
+var DefaultClassifierRules = []ClassifierRule{
+	{Name: "duty", Class: ClassObligation, Pattern: `なければならない。?$`, Weight: 0.9},
+	{Name: "duty-clause", Class: ClassObligation, Pattern: `なければならない[^。]`, Weight: 0.5},
+	{Name: "mono-to-suru", Class: ClassObligation, Pattern: `ものとする。?$`, Weight: 0.5},
+	{Name: "prohibition", Class: ClassProhibition, Pattern: `[てで]はならない。?$`, Weight: 0.9},
+	{Name: "prohibition-clause", Class: ClassProhibition, Pattern: `[てで]はならない[^。]`, Weight: 0.5},
+	{Name: "incapacity", Class: ClassProhibition, Pattern: `ことができない。?$`, Weight: 0.4},
+	{Name: "permission", Class: ClassPermission, Pattern: `ことができる。?$`, Weight: 0.85},
+	{Name: "permission-clause", Class: ClassPermission, Pattern: `ことができる[^。]`, Weight: 0.4},
+	{Name: "not-precluded", Class: ClassPermission, Pattern: `(?:を|することを)妨げない。?$`, Weight: 0.6},
+	{Name: "exception", Class: ClassPermission, Pattern: `この限りでない。?$`, Weight: 0.5},
+	{Name: "definition", Class: ClassDefinition, Pattern: `「[^」]+」とは、.+をいう`, Weight: 0.95},
+	{Name: "definition-list", Class: ClassDefinition, Pattern: `用語の意義は、`, Weight: 0.8},
+	{Name: "abbreviation", Class: ClassDefinition, Pattern: `（以下[^（）「」]*「[^」]+」という。）`, Weight: 0.4},
+	{Name: "punishment", Class: ClassPenalty, Pattern: `(?:に処する|に処し|を科する)`, Weight: 0.9},
+	{Name: "fine", Class: ClassPenalty, Pattern: `円以下の(?:罰金|科料|過料)`, Weight: 0.6},
+	{Name: "imprisonment", Class: ClassPenalty, Pattern: `(?:拘禁刑|懲役|禁錮)`, Weight: 0.4},
+}
+
+// SentenceClassifier classifies sentences by pattern rules. A sentence can have several
+// classes; the confidence of a class combines the weights w of its matching rules as
+// 1 - Π(1 - w), so that agreeing rules raise it. The zero value has no rules. A
+// SentenceClassifier is safe for concurrent use.
+type SentenceClassifier struct {
+	mu    sync.RWMutex
+	rules []classifierRule
+}
+
+type classifierRule struct {
+	ClassifierRule
+	pattern, unless *regexp.Regexp
+}
+
+// SentenceClassification is a class of a sentence and the confidence in it
+type SentenceClassification struct {
+	Class SentenceClass `json:"class"`
+	// Confidence is between 0 and 1
+	Confidence float64 `json:"confidence"`
+	// Rules are the names of the matching rules
+	Rules []string `json:"rules"`
+}
+
+// ClassifiedSentence is a sentence of a law with its classes
+type ClassifiedSentence struct {
+	// ProvisionID is the canonical ID of the provision containing the sentence
+	ProvisionID string `json:"provision_id"`
+	// Provision is the citation of the provision (e.g. 第二条第二項第三号ただし書)
+	Provision string `json:"provision"`
+	Text      string `json:"text"`
+	// Classes are in order of decreasing confidence
+	Classes []SentenceClassification `json:"classes"`
+}
+
+// NewSentenceClassifier creates a classifier with the rules, or DefaultClassifierRules
+// if none are given
+func NewSentenceClassifier(rules ...ClassifierRule) (*SentenceClassifier, error) {
+	if len(rules) == 0 {
+		rules = DefaultClassifierRules
+	}
+	c := &SentenceClassifier{}
+	for _, rule := range rules {
+		if err := c.Add(rule); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Add adds a rule
+func (c *SentenceClassifier) Add(rule ClassifierRule) error {
+	if rule.Name == "" {
+		rule.Name = rule.Pattern
+	}
+	if strings.TrimSpace(string(rule.Class)) == "" {
+		return fmt.Errorf("classifier rule %q has no class", rule.Name)
+	}
+	if rule.Weight <= 0 || rule.Weight > 1 {
+		return fmt.Errorf("classifier rule %q: weight %v is not in (0, 1]", rule.Name, rule.Weight)
+	}
+	if rule.Pattern == "" {
+		return fmt.Errorf("classifier rule %q has no pattern", rule.Name)
+	}
+	compiled := classifierRule{ClassifierRule: rule}
+	var err error
+	if compiled.pattern, err = regexp.Compile(rule.Pattern); err != nil {
+		return fmt.Errorf("classifier rule %q: invalid pattern: %w", rule.Name, err)
+	}
+	if rule.Unless != "" {
+		if compiled.unless, err = regexp.Compile(rule.Unless); err != nil {
+			return fmt.Errorf("classifier rule %q: invalid unless pattern: %w", rule.Name, err)
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rules = append(c.rules, compiled)
+	return nil
+}
+
+// Load adds the rules of a YAML list of ClassifierRule mappings using the yaml field
+// names, e.g. {class: permission, pattern: 承認を受けて, unless: 承認を受けないで, weight: 0.3}
+func (c *SentenceClassifier) Load(r io.Reader) error {
+	var rules []ClassifierRule
+	if err := yaml.NewDecoder(r).Decode(&rules); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to parse classifier rules: %w", err)
+	}
+	for _, rule := range rules {
+		if err := c.Add(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Classify returns the classes of a sentence in order of decreasing confidence, or none
+// if no rule matches
+func (c *SentenceClassifier) Classify(text string) []SentenceClassification {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var classes []SentenceClassification
+	// remaining holds Π(1 - w) per class, in the order of classes
+	var remaining []float64
+	for _, rule := range c.rules {
+		if !rule.pattern.MatchString(text) || (rule.unless != nil && rule.unless.MatchString(text)) {
+			continue
+		}
+		i := slices.IndexFunc(classes, func(sc SentenceClassification) bool { return sc.Class == rule.Class })
+		if i < 0 {
+			classes = append(classes, SentenceClassification{Class: rule.Class})
+			remaining = append(remaining, 1)
+			i = len(classes) - 1
+		}
+		remaining[i] *= 1 - rule.Weight
+		classes[i].Confidence = 1 - remaining[i]
+		classes[i].Rules = append(classes[i].Rules, rule.Name)
+	}
+	slices.SortStableFunc(classes, func(a, b SentenceClassification) int {
+		return cmp.Compare(b.Confidence, a.Confidence)
+	})
+	return classes
+}
+
+// ClassifyLaw classifies the sentences of a law and returns those with a class of at
+// least minConfidence, in document order. Provision IDs are formed with lawID and
+// revision as in SentenceRef.ProvisionID.
+func (c *SentenceClassifier) ClassifyLaw(law *LawNode, lawID, revision string, minConfidence float64) []ClassifiedSentence {
+	var sentences []ClassifiedSentence
+	for _, ref := range law.Sentences() {
+		text := strings.TrimSpace(ref.Sentence.PlainText())
+		classes := c.Classify(text)
+		if len(classes) == 0 || classes[0].Confidence < minConfidence {
+			continue
+		}
+		classes = slices.DeleteFunc(classes, func(sc SentenceClassification) bool { return sc.Confidence < minConfidence })
+		sentences = append(sentences, ClassifiedSentence{
+			ProvisionID: ref.ProvisionID(lawID, revision).String(),
+			Provision:   ref.Label(),
+			Text:        text,
+			Classes:     classes,
+		})
+	}
+	return sentences
+}
@@ -1,18 +1,22 @@
 package lawapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
 // Client provides access to the Japan Law API
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL      string
+	httpClient   *http.Client
+	clock        Clock
+	enumDecoding EnumDecoding
 }
 
 // NewClient creates a new API client
@@ -28,14 +32,35 @@ func (c *Client) SetHTTPClient(client *http.Client) {
 	c.httpClient = client
 }
 
+// SetBaseURL sets the API root (default: DefaultBaseURL), e.g. to use a mirror
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// APIError is returned when the API responds with an error status
+type APIError struct {
+	StatusCode int
+	Body       string
+	// CorrelationID is the correlation ID of the failed call, or empty
+	CorrelationID string
+}
+
+// Error implements error
+func (e *APIError) Error() string {
+	if e.CorrelationID != "" {
+		return fmt.Sprintf("API error %d: %s (correlation ID %s)", e.StatusCode, e.Body, e.CorrelationID)
+	}
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Body)
+}
+
 // GetAttachmentParams contains query parameters for GetAttachment
 type GetAttachmentParams struct {
 	// Src represents 法令XML中のFig要素のsrc属性 > jpgの例：`./pict/H11HO127-001.jpg` > pdfの例：`./pict/2FH00000007000.pdf`
 	Src *string
 }
 
-// GetAttachment field from the API response
-func (c *Client) GetAttachment(lawRevisionId string, params *GetAttachmentParams) (*string, error) {
+// BuildGetAttachmentRequest creates the HTTP request for GetAttachment without sending it
+func (c *Client) BuildGetAttachmentRequest(ctx context.Context, lawRevisionId string, params *GetAttachmentParams) (*http.Request, error) {
 	urlPath := c.baseURL + "/attachment" + "/" + lawRevisionId
 	if params != nil {
 		queryParams := url.Values{}
@@ -46,25 +71,42 @@ func (c *Client) GetAttachment(lawRevisionId string, params *GetAttachmentParams
 			urlPath += "?" + queryParams.Encode()
 		}
 	}
-	req, err := http.NewRequest("GET", urlPath, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", urlPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	setCorrelationID(req)
+	return req, nil
+}
+
+// GetAttachment field from the API response
+func (c *Client) GetAttachment(lawRevisionId string, params *GetAttachmentParams) (*string, error) {
+	return c.GetAttachmentWithContext(context.Background(), lawRevisionId, params)
+}
+
+// GetAttachmentWithContext is like GetAttachment but uses ctx for the request. The request carries the
+// correlation ID of ctx, or a new one, which errors report (see CorrelationIDOf).
+func (c *Client) GetAttachmentWithContext(ctx context.Context, lawRevisionId string, params *GetAttachmentParams) (*string, error) {
+	ctx, correlationID := ensureCorrelationID(ctx)
+	req, err := c.BuildGetAttachmentRequest(ctx, lawRevisionId, params)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, withCorrelationID(fmt.Errorf("failed to execute request: %w", err), correlationID)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body), CorrelationID: correlationID}
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, withCorrelationID(fmt.Errorf("failed to read response: %w", err), correlationID)
 	}
 
 	result := string(body)
@@ -111,8 +153,8 @@ type GetKeywordParams struct {
 	HighlightTag *string
 }
 
-// GetKeyword field from the API response
-func (c *Client) GetKeyword(params *GetKeywordParams) (*KeywordResponse, error) {
+// BuildGetKeywordRequest creates the HTTP request for GetKeyword without sending it
+func (c *Client) BuildGetKeywordRequest(ctx context.Context, params *GetKeywordParams) (*http.Request, error) {
 	urlPath := c.baseURL + "/keyword"
 	if params != nil {
 		queryParams := url.Values{}
@@ -170,31 +212,55 @@ func (c *Client) GetKeyword(params *GetKeywordParams) (*KeywordResponse, error)
 			queryParams.Set("sentence_text_size", fmt.Sprintf("%v", *params.SentenceTextSize))
 		}
 		if params.HighlightTag != nil {
-			queryParams.Set("highlight_tag", fmt.Sprintf("%v", *params.HighlightTag))
+			v, err := SanitizeHighlightTag(*params.HighlightTag)
+			if err != nil {
+				return nil, err
+			}
+			queryParams.Set("highlight_tag", fmt.Sprintf("%v", v))
 		}
 		if len(queryParams) > 0 {
 			urlPath += "?" + queryParams.Encode()
 		}
 	}
-	req, err := http.NewRequest("GET", urlPath, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", urlPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	setCorrelationID(req)
+	return req, nil
+}
+
+// GetKeyword field from the API response
+func (c *Client) GetKeyword(params *GetKeywordParams) (*KeywordResponse, error) {
+	return c.GetKeywordWithContext(context.Background(), params)
+}
+
+// GetKeywordWithContext is like GetKeyword but uses ctx for the request. The request carries the
+// correlation ID of ctx, or a new one, which errors report (see CorrelationIDOf).
+func (c *Client) GetKeywordWithContext(ctx context.Context, params *GetKeywordParams) (*KeywordResponse, error) {
+	ctx, correlationID := ensureCorrelationID(ctx)
+	req, err := c.BuildGetKeywordRequest(ctx, params)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, withCorrelationID(fmt.Errorf("failed to execute request: %w", err), correlationID)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body), CorrelationID: correlationID}
 	}
 
 	var result KeywordResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, withCorrelationID(fmt.Errorf("failed to decode response: %w", err), correlationID)
+	}
+	if err := c.checkEnums(&result); err != nil {
+		return nil, withCorrelationID(err, correlationID)
 	}
 
 	return &result, nil
@@ -216,8 +282,8 @@ type GetLawDataParams struct {
 	ResponseFormat *ResponseFormat
 }
 
-// GetLawData field from the API response
-func (c *Client) GetLawData(lawIdOrNumOrRevisionId string, params *GetLawDataParams) (*LawDataResponse, error) {
+// BuildGetLawDataRequest creates the HTTP request for GetLawData without sending it
+func (c *Client) BuildGetLawDataRequest(ctx context.Context, lawIdOrNumOrRevisionId string, params *GetLawDataParams) (*http.Request, error) {
 	urlPath := c.baseURL + "/law_data" + "/" + lawIdOrNumOrRevisionId
 	if params != nil {
 		queryParams := url.Values{}
@@ -243,25 +309,45 @@ func (c *Client) GetLawData(lawIdOrNumOrRevisionId string, params *GetLawDataPar
 			urlPath += "?" + queryParams.Encode()
 		}
 	}
-	req, err := http.NewRequest("GET", urlPath, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", urlPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	setCorrelationID(req)
+	return req, nil
+}
+
+// GetLawData field from the API response
+func (c *Client) GetLawData(lawIdOrNumOrRevisionId string, params *GetLawDataParams) (*LawDataResponse, error) {
+	return c.GetLawDataWithContext(context.Background(), lawIdOrNumOrRevisionId, params)
+}
+
+// GetLawDataWithContext is like GetLawData but uses ctx for the request. The request carries the
+// correlation ID of ctx, or a new one, which errors report (see CorrelationIDOf).
+func (c *Client) GetLawDataWithContext(ctx context.Context, lawIdOrNumOrRevisionId string, params *GetLawDataParams) (*LawDataResponse, error) {
+	ctx, correlationID := ensureCorrelationID(ctx)
+	req, err := c.BuildGetLawDataRequest(ctx, lawIdOrNumOrRevisionId, params)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, withCorrelationID(fmt.Errorf("failed to execute request: %w", err), correlationID)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body), CorrelationID: correlationID}
 	}
 
 	var result LawDataResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, withCorrelationID(fmt.Errorf("failed to decode response: %w", err), correlationID)
+	}
+	if err := c.checkEnums(&result); err != nil {
+		return nil, withCorrelationID(err, correlationID)
 	}
 
 	return &result, nil
@@ -273,8 +359,8 @@ type GetLawFileParams struct {
 	Asof *Date
 }
 
-// GetLawFile field from the API response
-func (c *Client) GetLawFile(lawIdOrNumOrRevisionId string, fileType string, params *GetLawFileParams) (*string, error) {
+// BuildGetLawFileRequest creates the HTTP request for GetLawFile without sending it
+func (c *Client) BuildGetLawFileRequest(ctx context.Context, lawIdOrNumOrRevisionId string, fileType string, params *GetLawFileParams) (*http.Request, error) {
 	urlPath := c.baseURL + "/law_file" + "/" + fileType + "/" + lawIdOrNumOrRevisionId
 	if params != nil {
 		queryParams := url.Values{}
@@ -285,25 +371,42 @@ func (c *Client) GetLawFile(lawIdOrNumOrRevisionId string, fileType string, para
 			urlPath += "?" + queryParams.Encode()
 		}
 	}
-	req, err := http.NewRequest("GET", urlPath, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", urlPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	setCorrelationID(req)
+	return req, nil
+}
+
+// GetLawFile field from the API response
+func (c *Client) GetLawFile(lawIdOrNumOrRevisionId string, fileType string, params *GetLawFileParams) (*string, error) {
+	return c.GetLawFileWithContext(context.Background(), lawIdOrNumOrRevisionId, fileType, params)
+}
+
+// GetLawFileWithContext is like GetLawFile but uses ctx for the request. The request carries the
+// correlation ID of ctx, or a new one, which errors report (see CorrelationIDOf).
+func (c *Client) GetLawFileWithContext(ctx context.Context, lawIdOrNumOrRevisionId string, fileType string, params *GetLawFileParams) (*string, error) {
+	ctx, correlationID := ensureCorrelationID(ctx)
+	req, err := c.BuildGetLawFileRequest(ctx, lawIdOrNumOrRevisionId, fileType, params)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, withCorrelationID(fmt.Errorf("failed to execute request: %w", err), correlationID)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body), CorrelationID: correlationID}
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, withCorrelationID(fmt.Errorf("failed to read response: %w", err), correlationID)
 	}
 
 	result := string(body)
@@ -356,8 +459,8 @@ type GetRevisionsParams struct {
 	ResponseFormat *ResponseFormat
 }
 
-// GetRevisions field from the API response
-func (c *Client) GetRevisions(lawIdOrNum string, params *GetRevisionsParams) (*LawRevisionsResponse, error) {
+// BuildGetRevisionsRequest creates the HTTP request for GetRevisions without sending it
+func (c *Client) BuildGetRevisionsRequest(ctx context.Context, lawIdOrNum string, params *GetRevisionsParams) (*http.Request, error) {
 	urlPath := c.baseURL + "/law_revisions" + "/" + lawIdOrNum
 	if params != nil {
 		queryParams := url.Values{}
@@ -438,25 +541,45 @@ func (c *Client) GetRevisions(lawIdOrNum string, params *GetRevisionsParams) (*L
 			urlPath += "?" + queryParams.Encode()
 		}
 	}
-	req, err := http.NewRequest("GET", urlPath, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", urlPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	setCorrelationID(req)
+	return req, nil
+}
+
+// GetRevisions field from the API response
+func (c *Client) GetRevisions(lawIdOrNum string, params *GetRevisionsParams) (*LawRevisionsResponse, error) {
+	return c.GetRevisionsWithContext(context.Background(), lawIdOrNum, params)
+}
+
+// GetRevisionsWithContext is like GetRevisions but uses ctx for the request. The request carries the
+// correlation ID of ctx, or a new one, which errors report (see CorrelationIDOf).
+func (c *Client) GetRevisionsWithContext(ctx context.Context, lawIdOrNum string, params *GetRevisionsParams) (*LawRevisionsResponse, error) {
+	ctx, correlationID := ensureCorrelationID(ctx)
+	req, err := c.BuildGetRevisionsRequest(ctx, lawIdOrNum, params)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, withCorrelationID(fmt.Errorf("failed to execute request: %w", err), correlationID)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body), CorrelationID: correlationID}
 	}
 
 	var result LawRevisionsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, withCorrelationID(fmt.Errorf("failed to decode response: %w", err), correlationID)
+	}
+	if err := c.checkEnums(&result); err != nil {
+		return nil, withCorrelationID(err, correlationID)
 	}
 
 	return &result, nil
@@ -508,8 +631,8 @@ type GetLawsParams struct {
 	ResponseFormat *ResponseFormat
 }
 
-// GetLaws field from the API response
-func (c *Client) GetLaws(params *GetLawsParams) (*LawsResponse, error) {
+// BuildGetLawsRequest creates the HTTP request for GetLaws without sending it
+func (c *Client) BuildGetLawsRequest(ctx context.Context, params *GetLawsParams) (*http.Request, error) {
 	urlPath := c.baseURL + "/laws"
 	if params != nil {
 		queryParams := url.Values{}
@@ -588,25 +711,45 @@ func (c *Client) GetLaws(params *GetLawsParams) (*LawsResponse, error) {
 			urlPath += "?" + queryParams.Encode()
 		}
 	}
-	req, err := http.NewRequest("GET", urlPath, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", urlPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	setCorrelationID(req)
+	return req, nil
+}
+
+// GetLaws field from the API response
+func (c *Client) GetLaws(params *GetLawsParams) (*LawsResponse, error) {
+	return c.GetLawsWithContext(context.Background(), params)
+}
+
+// GetLawsWithContext is like GetLaws but uses ctx for the request. The request carries the
+// correlation ID of ctx, or a new one, which errors report (see CorrelationIDOf).
+func (c *Client) GetLawsWithContext(ctx context.Context, params *GetLawsParams) (*LawsResponse, error) {
+	ctx, correlationID := ensureCorrelationID(ctx)
+	req, err := c.BuildGetLawsRequest(ctx, params)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, withCorrelationID(fmt.Errorf("failed to execute request: %w", err), correlationID)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body), CorrelationID: correlationID}
 	}
 
 	var result LawsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, withCorrelationID(fmt.Errorf("failed to decode response: %w", err), correlationID)
+	}
+	if err := c.checkEnums(&result); err != nil {
+		return nil, withCorrelationID(err, correlationID)
 	}
 
 	return &result, nil
@@ -0,0 +1,180 @@
+package lawapi
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// JST is the Asia/Tokyo time zone, in which law dates such as promulgation and
+// enforcement dates are defined. It falls back to a fixed UTC+9 zone if the time zone
+// database is not available; Japan has not observed daylight saving time since 1951.
+var JST = loadJST()
+
+func loadJST() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Tokyo"); err == nil {
+		return loc
+	}
+	return time.FixedZone("JST", 9*60*60)
+}
+
+// Clock tells the current time and waits, so that the time-dependent behavior of the
+// package (dates relative to today, cache expiry, budgets, retry backoff, debouncing) can
+// be controlled in tests
+type Clock interface {
+	Now() time.Time
+	// After waits for d and then sends the current time on the returned channel, as
+	// time.After does
+	After(d time.Duration) <-chan time.Time
+}
+
+// ClockFunc adapts a function to a Clock telling the time. It waits in real time.
+type ClockFunc func() time.Time
+
+// Now implements Clock
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// After implements Clock
+func (f ClockFunc) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// SystemClock is the Clock of the system time
+var SystemClock Clock = ClockFunc(time.Now)
+
+// DefaultClock is the clock of package functions such as Today, and of clients, caches,
+// budgets and bandwidth limits that were not given a clock. Replace it to control the time
+// of the whole package, e.g. in tests around midnight in Japan.
+var DefaultClock = SystemClock
+
+// clockOrDefault returns the clock, or DefaultClock if it is nil
+func clockOrDefault(clock Clock) Clock {
+	if clock == nil {
+		return DefaultClock
+	}
+	return clock
+}
+
+// SetClock sets the clock of retry backoff, debouncing and provenance times
+// (default: DefaultClock)
+func (c *Client) SetClock(clock Clock) {
+	c.clock = clock
+}
+
+// currentClock returns the clock of the client
+func (c *Client) currentClock() Clock {
+	return clockOrDefault(c.clock)
+}
+
+// sleepContext waits for d on the clock or until ctx is done
+func sleepContext(ctx context.Context, clock Clock, d time.Duration) error {
+	select {
+	case <-clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ManualClock is a Clock whose time only moves when Set or Advance is called, for tests.
+// Waits started with After end when the time reaches their deadline. It is safe for
+// concurrent use.
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []manualWaiter
+}
+
+type manualWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewManualClock creates a clock stopped at t
+func NewManualClock(t time.Time) *ManualClock {
+	return &ManualClock{now: t}
+}
+
+// Now implements Clock
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After implements Clock
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, manualWaiter{deadline: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the time forward by d
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(c.now.Add(d))
+}
+
+// Set moves the time to t, ending the waits whose deadline has passed
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(t)
+}
+
+func (c *ManualClock) set(t time.Time) {
+	c.now = t
+	c.waiters = slices.DeleteFunc(c.waiters, func(w manualWaiter) bool {
+		if t.Before(w.deadline) {
+			return false
+		}
+		w.ch <- t
+		return true
+	})
+}
+
+// Waiters returns the number of waits in progress, so that a test can wait for code to
+// block on the clock before advancing it
+func (c *ManualClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// DateOf returns the calendar date in Japan of a point in time. Between 15:00 and 24:00
+// UTC, this is the day after the UTC date.
+func DateOf(t time.Time) Date {
+	t = t.In(JST)
+	return Date(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+}
+
+// Today returns the current date in Japan according to DefaultClock, e.g. for the asof
+// parameters. Enforcement schedules take it for zero dates.
+func Today() Date {
+	return DateOf(DefaultClock.Now())
+}
+
+// dateOrToday returns d, or Today if d is zero
+func dateOrToday(d Date) Date {
+	if time.Time(d).IsZero() {
+		return Today()
+	}
+	return d
+}
+
+// Time returns the start of the date in Japan, e.g. to compare an instant with an
+// enforcement date
+func (d Date) Time() time.Time {
+	t := time.Time(d)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, JST)
+}
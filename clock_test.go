@@ -0,0 +1,280 @@
+package lawapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testDate(year int, month time.Month, day int) Date {
+	return Date(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// setDefaultClock replaces DefaultClock for the duration of a test
+func setDefaultClock(t *testing.T, clock Clock) {
+	saved := DefaultClock
+	DefaultClock = clock
+	t.Cleanup(func() { DefaultClock = saved })
+}
+
+func TestDateOfMidnight(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		want Date
+	}{
+		{"before midnight JST", time.Date(2024, 3, 31, 14, 59, 59, 999999999, time.UTC), testDate(2024, 3, 31)},
+		{"midnight JST", time.Date(2024, 3, 31, 15, 0, 0, 0, time.UTC), testDate(2024, 4, 1)},
+		{"UTC midnight", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), testDate(2024, 4, 1)},
+		{"end of UTC day", time.Date(2024, 4, 1, 23, 59, 59, 0, time.UTC), testDate(2024, 4, 2)},
+		{"new year", time.Date(2023, 12, 31, 15, 0, 0, 0, time.UTC), testDate(2024, 1, 1)},
+		{"leap day", time.Date(2024, 2, 28, 15, 0, 0, 0, time.UTC), testDate(2024, 2, 29)},
+		{"JST location", time.Date(2024, 4, 1, 0, 0, 0, 0, JST), testDate(2024, 4, 1)},
+		{"west of UTC", time.Date(2024, 3, 31, 11, 0, 0, 0, time.FixedZone("EDT", -4*60*60)), testDate(2024, 4, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DateOf(tt.t); got != tt.want {
+				t.Errorf("DateOf(%s) = %s, want %s", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTodayMidnight(t *testing.T) {
+	clock := NewManualClock(time.Date(2019, 4, 30, 14, 59, 59, 0, time.UTC))
+	setDefaultClock(t, clock)
+
+	steps := []struct {
+		advance time.Duration
+		date    Date
+		wareki  string
+	}{
+		{0, testDate(2019, 4, 30), "平成31年4月30日"},
+		{time.Second, testDate(2019, 5, 1), "令和元年5月1日"},
+		{9*time.Hour - time.Second, testDate(2019, 5, 1), "令和元年5月1日"},
+		{15*time.Hour + time.Second, testDate(2019, 5, 2), "令和元年5月2日"},
+	}
+	for _, step := range steps {
+		clock.Advance(step.advance)
+		today := Today()
+		if today != step.date {
+			t.Errorf("at %s: Today() = %s, want %s", clock.Now(), today, step.date)
+		}
+		if got := today.Wareki(); got != step.wareki {
+			t.Errorf("at %s: Wareki() = %s, want %s", clock.Now(), got, step.wareki)
+		}
+	}
+}
+
+func TestDateTime(t *testing.T) {
+	for _, d := range []Date{testDate(2024, 4, 1), testDate(2019, 5, 1), testDate(1989, 1, 8)} {
+		start := d.Time()
+		if start.Location() != JST || start.Hour() != 0 || start.Minute() != 0 {
+			t.Errorf("%s.Time() = %s, want midnight JST", d, start)
+		}
+		if got := DateOf(start); got != d {
+			t.Errorf("DateOf(%s.Time()) = %s", d, got)
+		}
+		if got := DateOf(start.Add(-time.Nanosecond)); got == d {
+			t.Errorf("the instant before %s.Time() is on the same date", d)
+		}
+	}
+}
+
+// TestEnforcementTodayMidnight checks that a stage taking effect on April 1 is pending until
+// midnight in Japan when schedules are checked against today
+func TestEnforcementTodayMidnight(t *testing.T) {
+	january1, april1 := testDate(2024, 1, 1), testDate(2024, 4, 1)
+	schedule := EnforcementSchedule{Stages: []EnforcementStage{
+		{Text: "令和六年一月一日", Date: &january1},
+		{Provisions: "第二条の規定", Text: "令和六年四月一日", Date: &april1},
+	}}
+	tests := []struct {
+		name    string
+		now     time.Time
+		inForce int
+		partial bool
+	}{
+		{"day before in UTC", time.Date(2024, 3, 31, 14, 59, 59, 0, time.UTC), 1, true},
+		{"midnight JST", time.Date(2024, 3, 31, 15, 0, 0, 0, time.UTC), 2, false},
+		{"same UTC day later", time.Date(2024, 3, 31, 23, 0, 0, 0, time.UTC), 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setDefaultClock(t, NewManualClock(tt.now))
+			if got := len(schedule.InForce(Date{})); got != tt.inForce {
+				t.Errorf("InForce: %d stages, want %d", got, tt.inForce)
+			}
+			if got := len(schedule.Pending(Date{})); got != len(schedule.Stages)-tt.inForce {
+				t.Errorf("Pending: %d stages, want %d", got, len(schedule.Stages)-tt.inForce)
+			}
+			if got := schedule.PartiallyInForce(Date{}); got != tt.partial {
+				t.Errorf("PartiallyInForce = %v, want %v", got, tt.partial)
+			}
+		})
+	}
+}
+
+// TestCachingClientTodayMidnight checks that laws cached without asof are fetched again
+// after midnight in Japan, while revisions and explicit dates stay cached
+func TestCachingClientTodayMidnight(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"law_info":{"law_id":"325AC0000000131"}}`))
+	}))
+	defer server.Close()
+
+	clock := NewManualClock(time.Date(2024, 3, 31, 23, 59, 0, 0, JST))
+	client := NewClient()
+	client.SetBaseURL(server.URL)
+	client.SetClock(clock)
+	cached := NewCachingClient(client, NewMemoryCache(0, 0))
+	asof := testDate(2024, 3, 1)
+
+	tests := []struct {
+		name     string
+		id       string
+		params   *GetLawDataParams
+		requests int32
+	}{
+		{"law ID", "325AC0000000131", nil, 2},
+		{"law number", "昭和二十五年法律第百三十一号", nil, 2},
+		{"revision ID", "325AC0000000131_20240401_506AC0000000001", nil, 1},
+		{"explicit asof", "325AC0000000131", &GetLawDataParams{Asof: &asof}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.Set(time.Date(2024, 3, 31, 23, 59, 0, 0, JST))
+			start := requests.Load()
+			for i := 0; i < 2; i++ {
+				if _, err := cached.GetLawData(tt.id, tt.params); err != nil {
+					t.Fatal(err)
+				}
+			}
+			clock.Advance(time.Minute)
+			if _, err := cached.GetLawData(tt.id, tt.params); err != nil {
+				t.Fatal(err)
+			}
+			if got := requests.Load() - start; got != tt.requests {
+				t.Errorf("%d requests, want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+// waitForWaiters waits until n waits are in progress on the clock, so that the test can
+// advance it past their deadlines
+func waitForWaiters(t *testing.T, clock *ManualClock, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for clock.Waiters() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d waiters, want %d", clock.Waiters(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2024, 4, 1, 0, 0, 0, 0, JST)
+	tests := []struct {
+		name string
+		// waits are the durations passed to After
+		waits []time.Duration
+		// steps move the clock forward in turn
+		steps []time.Duration
+		// ended is the number of waits ended after each step
+		ended []int
+	}{
+		{"zero duration ends at once", []time.Duration{0, -time.Second}, nil, nil},
+		{"ends at the deadline", []time.Duration{time.Minute}, []time.Duration{time.Minute - 1, 1}, []int{0, 1}},
+		{"one step past several deadlines", []time.Duration{time.Second, time.Minute, time.Hour}, []time.Duration{time.Hour}, []int{3}},
+		{"deadlines in turn", []time.Duration{time.Hour, time.Second, time.Minute}, []time.Duration{time.Second, time.Minute, time.Hour}, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewManualClock(start)
+			var channels []<-chan time.Time
+			for _, d := range tt.waits {
+				channels = append(channels, clock.After(d))
+			}
+			ended := func() int {
+				n := 0
+				for i, ch := range channels {
+					if ch == nil {
+						n++
+						continue
+					}
+					select {
+					case got := <-ch:
+						if want := start.Add(max(tt.waits[i], 0)); got.Before(want) {
+							t.Errorf("wait of %s ended at %s, before %s", tt.waits[i], got, want)
+						}
+						channels[i] = nil
+						n++
+					default:
+					}
+				}
+				return n
+			}
+			if len(tt.steps) == 0 {
+				if got := ended(); got != len(tt.waits) {
+					t.Errorf("%d waits ended, want %d", got, len(tt.waits))
+				}
+			}
+			for i, step := range tt.steps {
+				clock.Advance(step)
+				if got := ended(); got != tt.ended[i] {
+					t.Errorf("after step %d: %d waits ended, want %d", i, got, tt.ended[i])
+				}
+				if got := clock.Waiters(); got != len(tt.waits)-tt.ended[i] {
+					t.Errorf("after step %d: %d waiters, want %d", i, got, len(tt.waits)-tt.ended[i])
+				}
+			}
+		})
+	}
+
+	t.Run("Set", func(t *testing.T) {
+		clock := NewManualClock(start)
+		ch := clock.After(time.Hour)
+		clock.Set(start.Add(2 * time.Hour))
+		if got := <-ch; !got.Equal(start.Add(2 * time.Hour)) {
+			t.Errorf("wait ended at %s", got)
+		}
+		if got := clock.Now(); !got.Equal(start.Add(2 * time.Hour)) {
+			t.Errorf("Now() = %s", got)
+		}
+	})
+}
+
+func TestSleepContextManualClock(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 4, 1, 0, 0, 0, 0, JST))
+	done := make(chan error, 1)
+	go func() { done <- sleepContext(context.Background(), clock, time.Minute) }()
+	waitForWaiters(t, clock, 1)
+	clock.Advance(30 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("sleep ended before its deadline")
+	default:
+	}
+	clock.Advance(30 * time.Second)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { done <- sleepContext(ctx, clock, time.Minute) }()
+	waitForWaiters(t, clock, 1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
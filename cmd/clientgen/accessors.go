@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// accessorField is a struct field with a generated accessor
+type accessorField struct {
+	name   string
+	goType string
+}
+
+// additionalStructFields lists the fields of the structs written by
+// generateAdditionalStructs
+var additionalStructFields = map[string][]accessorField{
+	"LawItem": {
+		{"LawInfo", "*LawInfo"},
+		{"RevisionInfo", "*RevisionInfo"},
+		{"CurrentRevisionInfo", "*RevisionInfo"},
+	},
+	"KeywordItem": {
+		{"LawInfo", "*LawInfo"},
+		{"RevisionInfo", "*RevisionInfo"},
+		{"Sentences", "[]KeywordSentence"},
+	},
+	"KeywordSentence": {
+		{"Text", "string"},
+		{"Position", "string"},
+	},
+}
+
+// GenerateAccessors generates nil-safe GetX methods for the fields of the response
+// structs, in the style of protobuf getters: called on a nil pointer, they return the zero
+// value, and fields holding structs are returned as pointers, so that accessors can be
+// chained across optional fields (item.GetRevisionInfo().GetLawTitle()). Optional enum
+// fields are returned as values.
+func (g *Generator) GenerateAccessors() string {
+	var sb strings.Builder
+
+	sb.WriteString("// Code generated by clientgen; DO NOT EDIT.\n\n")
+	sb.WriteString(fmt.Sprintf("package %s\n", g.packageName))
+
+	structs := map[string][]accessorField{}
+	for _, name := range g.spec.GetSortedSchemas() {
+		schema := g.spec.Components.Schemas[name]
+		if len(schema.Enum) > 0 || schema.Type != "object" || len(schema.Properties) == 0 {
+			continue
+		}
+		structName := toPascalCase(name)
+		var propNames []string
+		for propName := range schema.Properties {
+			propNames = append(propNames, propName)
+		}
+		sort.Strings(propNames)
+		for _, propName := range propNames {
+			structs[structName] = append(structs[structName], accessorField{toPascalCase(propName), fieldGoType(structName, propName, &schema)})
+		}
+	}
+	for name, fields := range additionalStructFields {
+		structs[name] = fields
+	}
+
+	var names []string
+	for name := range structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, field := range structs[name] {
+			sb.WriteString("\n")
+			sb.WriteString(g.generateAccessor(name, field, structs))
+		}
+	}
+	return sb.String()
+}
+
+// generateAccessor generates the accessor of a field
+func (g *Generator) generateAccessor(structName string, field accessorField, structs map[string][]accessorField) string {
+	var sb strings.Builder
+
+	elem, isPointer := strings.CutPrefix(field.goType, "*")
+	_, isStruct := structs[elem]
+	switch {
+	case isStruct && isPointer:
+		// Optional structs are returned as is
+		sb.WriteString(fmt.Sprintf("// Get%s returns the %s field, or nil if x is nil\n", field.name, field.name))
+		sb.WriteString(fmt.Sprintf("func (x *%s) Get%s() *%s {\n", structName, field.name, elem))
+		sb.WriteString("\tif x == nil {\n\t\treturn nil\n\t}\n")
+		sb.WriteString(fmt.Sprintf("\treturn x.%s\n", field.name))
+	case isStruct:
+		// Required structs are returned by pointer, so that accessors chain
+		sb.WriteString(fmt.Sprintf("// Get%s returns a pointer to the %s field, or nil if x is nil\n", field.name, field.name))
+		sb.WriteString(fmt.Sprintf("func (x *%s) Get%s() *%s {\n", structName, field.name, elem))
+		sb.WriteString("\tif x == nil {\n\t\treturn nil\n\t}\n")
+		sb.WriteString(fmt.Sprintf("\treturn &x.%s\n", field.name))
+	case isPointer:
+		// Other optional values are dereferenced
+		sb.WriteString(fmt.Sprintf("// Get%s returns the %s field, or the zero value if x or the field is nil\n", field.name, field.name))
+		sb.WriteString(fmt.Sprintf("func (x *%s) Get%s() %s {\n", structName, field.name, elem))
+		sb.WriteString(fmt.Sprintf("\tif x == nil || x.%s == nil {\n", field.name))
+		sb.WriteString(fmt.Sprintf("\t\treturn %s\n", g.zeroValue(elem)))
+		sb.WriteString("\t}\n")
+		sb.WriteString(fmt.Sprintf("\treturn *x.%s\n", field.name))
+	default:
+		sb.WriteString(fmt.Sprintf("// Get%s returns the %s field, or the zero value if x is nil\n", field.name, field.name))
+		sb.WriteString(fmt.Sprintf("func (x *%s) Get%s() %s {\n", structName, field.name, field.goType))
+		sb.WriteString(fmt.Sprintf("\tif x == nil {\n\t\treturn %s\n\t}\n", g.zeroValue(field.goType)))
+		sb.WriteString(fmt.Sprintf("\treturn x.%s\n", field.name))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// zeroValue returns the zero value literal of a Go type
+func (g *Generator) zeroValue(goType string) string {
+	switch goType {
+	case "string":
+		return `""`
+	case "int", "int32", "int64", "float32", "float64":
+		return "0"
+	case "bool":
+		return "false"
+	case "Date", "DateTime", "time.Time":
+		return goType + "{}"
+	}
+	if strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") || goType == "interface{}" {
+		return "nil"
+	}
+	// Named types of the specification are string enums
+	for name, schema := range g.spec.Components.Schemas {
+		if toPascalCase(name) == goType && len(schema.Enum) > 0 {
+			return `""`
+		}
+	}
+	return goType + "{}"
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// GenerateFixtures generates NewExampleX constructors for the response structs, populated
+// from the example fields of the specification, for writing realistic tests of code
+// consuming the client. Fields without an example get the example of the schema they
+// refer to, the first value of their enum, or a fixture of their struct; arrays get one
+// element. Fields of which nothing is known are left at the zero value.
+func (g *Generator) GenerateFixtures() string {
+	var sb strings.Builder
+
+	sb.WriteString("// Code generated by clientgen; DO NOT EDIT.\n\n")
+	sb.WriteString(fmt.Sprintf("package %s\n\n", g.packageName))
+	sb.WriteString("import \"time\"\n")
+
+	// Property schemas of every struct, keyed by struct name, in the accessor field order
+	structs := map[string][]fixtureField{}
+	for _, name := range g.spec.GetSortedSchemas() {
+		schema := g.spec.Components.Schemas[name]
+		if len(schema.Enum) > 0 || schema.Type != "object" || len(schema.Properties) == 0 {
+			continue
+		}
+		structName := toPascalCase(name)
+		var propNames []string
+		for propName := range schema.Properties {
+			propNames = append(propNames, propName)
+		}
+		sort.Strings(propNames)
+		for _, propName := range propNames {
+			prop := schema.Properties[propName]
+			structs[structName] = append(structs[structName], fixtureField{
+				accessorField: accessorField{toPascalCase(propName), fieldGoType(structName, propName, &schema)},
+				schema:        &prop,
+			})
+		}
+	}
+	for name, schema := range g.additionalStructSchemas() {
+		for _, field := range additionalStructFields[name] {
+			f := fixtureField{accessorField: field, schema: &Schema{}}
+			if schema != nil {
+				if prop, ok := schema.Properties[toSnakeCase(field.name)]; ok {
+					f.schema = &prop
+				}
+			}
+			structs[name] = append(structs[name], f)
+		}
+	}
+
+	var names []string
+	for name := range structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("// NewExample%s returns an example %s populated from the API specification.\n", name, name))
+		sb.WriteString("// Each call returns a new value, which the caller may modify.\n")
+		sb.WriteString(fmt.Sprintf("func NewExample%s() *%s {\n", name, name))
+		sb.WriteString(fmt.Sprintf("\treturn &%s{\n", name))
+		for _, field := range structs[name] {
+			if value, ok := g.fixtureValue(field.goType, field.schema, structs); ok {
+				sb.WriteString(fmt.Sprintf("\t\t%s: %s,\n", field.name, value))
+			}
+		}
+		sb.WriteString("\t}\n")
+		sb.WriteString("}\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString("// examplePtr returns a pointer to a copy of v\n")
+	sb.WriteString("func examplePtr[T any](v T) *T {\n")
+	sb.WriteString("\treturn &v\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("// exampleTime parses an example date or date-time of the specification\n")
+	sb.WriteString("func exampleTime(layout, value string) time.Time {\n")
+	sb.WriteString("\tt, err := time.Parse(layout, value)\n")
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString("\t\tpanic(err)\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString("\treturn t\n")
+	sb.WriteString("}\n")
+
+	// Align the fields of the composite literals
+	formatted, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return sb.String()
+	}
+	return string(formatted)
+}
+
+// fixtureField is a struct field with the schema of its property
+type fixtureField struct {
+	accessorField
+	schema *Schema
+}
+
+// additionalStructSchemas returns the inline item schemas of the structs written by
+// generateAdditionalStructs
+func (g *Generator) additionalStructSchemas() map[string]*Schema {
+	items := func(schemaName, propName string) *Schema {
+		schema, ok := g.spec.Components.Schemas[schemaName]
+		if !ok {
+			return nil
+		}
+		prop, ok := schema.Properties[propName]
+		if !ok {
+			return nil
+		}
+		return prop.Items
+	}
+	keywordItem := items("keyword_response", "items")
+	var keywordSentence *Schema
+	if keywordItem != nil {
+		if prop, ok := keywordItem.Properties["sentences"]; ok {
+			keywordSentence = prop.Items
+		}
+	}
+	return map[string]*Schema{
+		"LawItem":         items("laws_response", "laws"),
+		"KeywordItem":     keywordItem,
+		"KeywordSentence": keywordSentence,
+	}
+}
+
+// fixtureValue returns the Go expression of the example value of a field, or false if
+// the specification has none
+func (g *Generator) fixtureValue(goType string, schema *Schema, structs map[string][]fixtureField) (string, bool) {
+	elem, isPointer := strings.CutPrefix(goType, "*")
+	value, ok := g.fixtureElemValue(elem, schema, structs)
+	if !ok {
+		return "", false
+	}
+	if !isPointer {
+		return value, true
+	}
+	if _, isStruct := structs[elem]; isStruct {
+		return strings.TrimPrefix(value, "*"), true
+	}
+	return fmt.Sprintf("examplePtr(%s)", value), true
+}
+
+// fixtureElemValue returns the Go expression of the example value of a non-pointer type
+func (g *Generator) fixtureElemValue(goType string, schema *Schema, structs map[string][]fixtureField) (string, bool) {
+	if _, isStruct := structs[goType]; isStruct {
+		return fmt.Sprintf("*NewExample%s()", goType), true
+	}
+	if itemType, isSlice := strings.CutPrefix(goType, "[]"); isSlice {
+		items := schema.Items
+		if items == nil {
+			items = &Schema{}
+		}
+		value, ok := g.fixtureValue(itemType, items, structs)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("[]%s{%s}", itemType, value), true
+	}
+
+	example := schema.Example
+	enum, enumType := g.referencedEnum(schema)
+	if example == nil && enumType != "" {
+		if ref := g.referencedSchema(schema); ref != nil && ref.Example != nil {
+			example = ref.Example
+		} else if len(enum) > 0 {
+			example = enum[0]
+		}
+	}
+	if example == nil {
+		return "", false
+	}
+
+	switch goType {
+	case "string":
+		return fmt.Sprintf("%q", fmt.Sprint(example)), true
+	case "int", "int32", "int64", "float32", "float64", "bool":
+		return fmt.Sprint(example), true
+	case "Date":
+		return fmt.Sprintf("Date(exampleTime(%q, %q))", "2006-01-02", exampleString(example, "2006-01-02")), true
+	case "DateTime":
+		return fmt.Sprintf("DateTime(exampleTime(time.RFC3339, %q))", exampleString(example, "2006-01-02T15:04:05Z07:00")), true
+	}
+	if goType == enumType {
+		return fmt.Sprintf("%s(%q)", goType, fmt.Sprint(example)), true
+	}
+	return "", false
+}
+
+// referencedSchema returns the component schema a property refers to, directly or
+// through allOf, or nil
+func (g *Generator) referencedSchema(schema *Schema) *Schema {
+	ref := schema.Ref
+	for _, s := range schema.AllOf {
+		if ref == "" {
+			ref = s.Ref
+		}
+	}
+	if ref == "" {
+		return nil
+	}
+	parts := strings.Split(ref, "/")
+	refSchema, ok := g.spec.Components.Schemas[parts[len(parts)-1]]
+	if !ok {
+		return nil
+	}
+	return &refSchema
+}
+
+// referencedEnum returns the values and Go type of the enum schema a property refers to,
+// or an empty type if it refers to none
+func (g *Generator) referencedEnum(schema *Schema) ([]interface{}, string) {
+	ref := g.referencedSchema(schema)
+	if ref == nil || len(ref.Enum) == 0 {
+		return nil, ""
+	}
+	return ref.Enum, schema.GoType()
+}
+
+// exampleString formats an example value, which the YAML decoder may have parsed as a
+// timestamp, as a string
+func exampleString(example interface{}, layout string) string {
+	if t, ok := example.(interface{ Format(string) string }); ok {
+		return t.Format(layout)
+	}
+	return fmt.Sprint(example)
+}
@@ -6,6 +6,12 @@ import (
 	"strings"
 )
 
+// paramSanitizers maps query parameters to the package functions that validate and
+// normalize their values before a request is built
+var paramSanitizers = map[string]string{
+	"highlight_tag": "SanitizeHighlightTag",
+}
+
 type Generator struct {
 	spec        *OpenAPISpec
 	packageName string
@@ -149,6 +155,23 @@ func (g *Generator) generateAdditionalStructs() string {
 	return sb.String()
 }
 
+// generateEnumIsKnown generates the IsKnown method of an enum type, which strict enum
+// decoding (see Client.SetEnumDecoding) checks the decoded values with
+func (g *Generator) generateEnumIsKnown(typeName string, constNames []string) string {
+	var sb strings.Builder
+	receiver := strings.ToLower(typeName[:1])
+	sb.WriteString(fmt.Sprintf("// IsKnown reports whether the value is one of the values of %s in the specification.\n", typeName))
+	sb.WriteString("// Values added to the API later are kept as received when decoding leniently.\n")
+	sb.WriteString(fmt.Sprintf("func (%s %s) IsKnown() bool {\n", receiver, typeName))
+	sb.WriteString(fmt.Sprintf("\tswitch %s {\n", receiver))
+	sb.WriteString(fmt.Sprintf("\tcase %s:\n", strings.Join(constNames, ", ")))
+	sb.WriteString("\t\treturn true\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString("\treturn false\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
 func (g *Generator) generateStruct(name string, schema *Schema) string {
 	var sb strings.Builder
 
@@ -168,6 +191,7 @@ func (g *Generator) generateStruct(name string, schema *Schema) string {
 		sb.WriteString(fmt.Sprintf("type %s string\n\n", structName))
 		sb.WriteString(fmt.Sprintf("const (\n"))
 		
+		var constNames []string
 		// Special handling for CategoryCd to use meaningful names
 		if structName == "CategoryCd" {
 			categoryNames := getCategoryNames()
@@ -176,10 +200,12 @@ func (g *Generator) generateStruct(name string, schema *Schema) string {
 					if englishName, exists := categoryNames[str]; exists {
 						constName := fmt.Sprintf("%s%s", structName, englishName)
 						sb.WriteString(fmt.Sprintf("\t%s %s = %q\n", constName, structName, str))
+						constNames = append(constNames, constName)
 					} else {
 						// Fallback to original logic if not found
 						constName := fmt.Sprintf("%s%s", structName, toPascalCase(str))
 						sb.WriteString(fmt.Sprintf("\t%s %s = %q\n", constName, structName, str))
+						constNames = append(constNames, constName)
 					}
 				}
 			}
@@ -189,10 +215,12 @@ func (g *Generator) generateStruct(name string, schema *Schema) string {
 				if str, ok := enumValue.(string); ok {
 					constName := fmt.Sprintf("%s%s", structName, toPascalCase(str))
 					sb.WriteString(fmt.Sprintf("\t%s %s = %q\n", constName, structName, str))
+					constNames = append(constNames, constName)
 				}
 			}
 		}
-		sb.WriteString(")\n")
+		sb.WriteString(")\n\n")
+		sb.WriteString(g.generateEnumIsKnown(structName, constNames))
 		return sb.String()
 	}
 
@@ -215,21 +243,7 @@ func (g *Generator) generateStruct(name string, schema *Schema) string {
 	for _, propName := range propNames {
 		propSchema := schema.Properties[propName]
 		fieldName := toPascalCase(propName)
-		goType := propSchema.GoType()
-
-		// Special case handling for specific fields
-		if structName == "LawsResponse" && propName == "laws" {
-			goType = "[]LawItem"
-		} else if structName == "KeywordResponse" && propName == "items" {
-			goType = "[]KeywordItem"
-		} else if structName == "LawRevisionsResponse" && propName == "revisions" {
-			goType = "[]RevisionInfo"
-		} else {
-			// Determine if pointer type should be used
-			if !schema.IsRequired(propName) && !isBasicType(goType) {
-				goType = "*" + goType
-			}
-		}
+		goType := fieldGoType(structName, propName, schema)
 
 		jsonTag := propName
 		if !schema.IsRequired(propName) {
@@ -251,24 +265,49 @@ func (g *Generator) generateStruct(name string, schema *Schema) string {
 	return sb.String()
 }
 
+// fieldGoType returns the Go type of a property of an object schema
+func fieldGoType(structName, propName string, schema *Schema) string {
+	propSchema := schema.Properties[propName]
+	goType := propSchema.GoType()
+
+	// Special case handling for specific fields
+	if structName == "LawsResponse" && propName == "laws" {
+		goType = "[]LawItem"
+	} else if structName == "KeywordResponse" && propName == "items" {
+		goType = "[]KeywordItem"
+	} else if structName == "LawRevisionsResponse" && propName == "revisions" {
+		goType = "[]RevisionInfo"
+	} else {
+		// Determine if pointer type should be used
+		if !schema.IsRequired(propName) && !isBasicType(goType) {
+			goType = "*" + goType
+		}
+	}
+	return goType
+}
+
 func (g *Generator) GenerateClient() string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("package %s\n\n", g.packageName))
 
 	sb.WriteString("import (\n")
+	sb.WriteString("\t\"context\"\n")
 	sb.WriteString("\t\"encoding/json\"\n")
 	sb.WriteString("\t\"fmt\"\n")
 	sb.WriteString("\t\"io\"\n")
 	sb.WriteString("\t\"net/http\"\n")
 	sb.WriteString("\t\"net/url\"\n")
+	sb.WriteString("\t\"strings\"\n")
 	sb.WriteString("\t\"time\"\n")
 	sb.WriteString(")\n\n")
 
 	sb.WriteString("// Client provides access to the Japan Law API\n")
 	sb.WriteString("type Client struct {\n")
-	sb.WriteString("\tbaseURL    string\n")
-	sb.WriteString("\thttpClient *http.Client\n")
+	sb.WriteString("\tbaseURL      string\n")
+	sb.WriteString("\thttpClient   *http.Client\n")
+	sb.WriteString("\tclock        Clock\n")
+	sb.WriteString("\tenumDecoding EnumDecoding\n")
 	sb.WriteString("}\n\n")
 
 	sb.WriteString("// NewClient creates a new API client\n")
@@ -284,6 +323,27 @@ func (g *Generator) GenerateClient() string {
 	sb.WriteString("\tc.httpClient = client\n")
 	sb.WriteString("}\n\n")
 
+	sb.WriteString("// SetBaseURL sets the API root (default: DefaultBaseURL), e.g. to use a mirror\n")
+	sb.WriteString("func (c *Client) SetBaseURL(baseURL string) {\n")
+	sb.WriteString("\tc.baseURL = strings.TrimSuffix(baseURL, \"/\")\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// APIError is returned when the API responds with an error status\n")
+	sb.WriteString("type APIError struct {\n")
+	sb.WriteString("\tStatusCode int\n")
+	sb.WriteString("\tBody       string\n")
+	sb.WriteString("\t// CorrelationID is the correlation ID of the failed call, or empty\n")
+	sb.WriteString("\tCorrelationID string\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// Error implements error\n")
+	sb.WriteString("func (e *APIError) Error() string {\n")
+	sb.WriteString("\tif e.CorrelationID != \"\" {\n")
+	sb.WriteString("\t\treturn fmt.Sprintf(\"API error %d: %s (correlation ID %s)\", e.StatusCode, e.Body, e.CorrelationID)\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString("\treturn fmt.Sprintf(\"API error %d: %s\", e.StatusCode, e.Body)\n")
+	sb.WriteString("}\n\n")
+
 	// Generate methods for each API endpoint
 	for _, path := range g.spec.GetSortedPaths() {
 		pathItem := g.spec.Paths[path]
@@ -335,14 +395,17 @@ func (g *Generator) generateMethod(path, httpMethod string, operation *Operation
 	}
 
 	// Build method signature
+	var args []string
 	// Add path parameters first
 	for _, param := range pathParams {
 		paramName := toCamelCase(param.Name)
 		params = append(params, fmt.Sprintf("%s string", paramName))
+		args = append(args, paramName)
 	}
 	// Then add query parameters
 	if len(queryParams) > 0 {
 		params = append(params, fmt.Sprintf("params *%sParams", methodName))
+		args = append(args, "params")
 	}
 
 	// Determine response type
@@ -362,22 +425,11 @@ func (g *Generator) generateMethod(path, httpMethod string, operation *Operation
 		sb.WriteString("\n")
 	}
 
-	// Method comment
-	if operation.Summary != "" {
-		cleanSummary := cleanDescription(operation.Summary)
-		if cleanSummary != "" {
-			sb.WriteString(fmt.Sprintf("// %s %s\n", methodName, cleanSummary))
-		} else {
-			sb.WriteString(fmt.Sprintf("// %s executes an API request\n", methodName))
-		}
-	}
-
-	// Method definition
-	sb.WriteString(fmt.Sprintf("func (c *Client) %s(", methodName))
-	if len(params) > 0 {
-		sb.WriteString(strings.Join(params, ", "))
-	}
-	sb.WriteString(fmt.Sprintf(") (*%s, error) {\n", responseType))
+	// Request builder definition
+	sb.WriteString(fmt.Sprintf("// Build%sRequest creates the HTTP request for %s without sending it\n", methodName, methodName))
+	sb.WriteString(fmt.Sprintf("func (c *Client) Build%sRequest(", methodName))
+	sb.WriteString(strings.Join(append([]string{"ctx context.Context"}, params...), ", "))
+	sb.WriteString(") (*http.Request, error) {\n")
 
 	// Build URL with path parameters
 	if len(pathParams) > 0 {
@@ -427,6 +479,12 @@ func (g *Generator) generateMethod(path, httpMethod string, operation *Operation
 					sb.WriteString(fmt.Sprintf("\t\t\tfor _, v := range *params.%s {\n", fieldName))
 					sb.WriteString(fmt.Sprintf("\t\t\t\tqueryParams.Add(%q, fmt.Sprintf(\"%%v\", v))\n", param.Name))
 					sb.WriteString("\t\t\t}\n")
+				} else if sanitizer, ok := paramSanitizers[param.Name]; ok {
+					sb.WriteString(fmt.Sprintf("\t\t\tv, err := %s(*params.%s)\n", sanitizer, fieldName))
+					sb.WriteString("\t\t\tif err != nil {\n")
+					sb.WriteString("\t\t\t\treturn nil, err\n")
+					sb.WriteString("\t\t\t}\n")
+					sb.WriteString(fmt.Sprintf("\t\t\tqueryParams.Set(%q, fmt.Sprintf(\"%%v\", v))\n", param.Name))
 				} else {
 					sb.WriteString(fmt.Sprintf("\t\t\tqueryParams.Set(%q, fmt.Sprintf(\"%%v\", *params.%s))\n", param.Name, fieldName))
 				}
@@ -439,35 +497,73 @@ func (g *Generator) generateMethod(path, httpMethod string, operation *Operation
 		sb.WriteString("\t}\n")
 	}
 
-	// Create and execute HTTP request
-	sb.WriteString(fmt.Sprintf("\treq, err := http.NewRequest(%q, urlPath, nil)\n", httpMethod))
+	// Create HTTP request
+	sb.WriteString(fmt.Sprintf("\treq, err := http.NewRequestWithContext(ctx, %q, urlPath, nil)\n", httpMethod))
 	sb.WriteString("\tif err != nil {\n")
 	sb.WriteString("\t\treturn nil, fmt.Errorf(\"failed to create request: %w\", err)\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString("\tsetCorrelationID(req)\n")
+	sb.WriteString("\treturn req, nil\n")
+	sb.WriteString("}\n\n")
+
+	// Method comment
+	if operation.Summary != "" {
+		cleanSummary := cleanDescription(operation.Summary)
+		if cleanSummary != "" {
+			sb.WriteString(fmt.Sprintf("// %s %s\n", methodName, cleanSummary))
+		} else {
+			sb.WriteString(fmt.Sprintf("// %s executes an API request\n", methodName))
+		}
+	}
+
+	// Method definition delegating to the context-aware variant
+	sb.WriteString(fmt.Sprintf("func (c *Client) %s(", methodName))
+	if len(params) > 0 {
+		sb.WriteString(strings.Join(params, ", "))
+	}
+	sb.WriteString(fmt.Sprintf(") (*%s, error) {\n", responseType))
+	sb.WriteString(fmt.Sprintf("\treturn c.%sWithContext(%s)\n", methodName, strings.Join(append([]string{"context.Background()"}, args...), ", ")))
+	sb.WriteString("}\n\n")
+
+	sb.WriteString(fmt.Sprintf("// %sWithContext is like %s but uses ctx for the request. The request carries the\n", methodName, methodName))
+	sb.WriteString("// correlation ID of ctx, or a new one, which errors report (see CorrelationIDOf).\n")
+	sb.WriteString(fmt.Sprintf("func (c *Client) %sWithContext(", methodName))
+	sb.WriteString(strings.Join(append([]string{"ctx context.Context"}, params...), ", "))
+	sb.WriteString(fmt.Sprintf(") (*%s, error) {\n", responseType))
+
+	// Build and execute HTTP request
+	sb.WriteString("\tctx, correlationID := ensureCorrelationID(ctx)\n")
+	sb.WriteString(fmt.Sprintf("\treq, err := c.Build%sRequest(%s)\n", methodName, strings.Join(append([]string{"ctx"}, args...), ", ")))
+	sb.WriteString("\tif err != nil {\n")
+	sb.WriteString("\t\treturn nil, err\n")
 	sb.WriteString("\t}\n\n")
 
 	sb.WriteString("\tresp, err := c.httpClient.Do(req)\n")
 	sb.WriteString("\tif err != nil {\n")
-	sb.WriteString("\t\treturn nil, fmt.Errorf(\"failed to execute request: %w\", err)\n")
+	sb.WriteString("\t\treturn nil, withCorrelationID(fmt.Errorf(\"failed to execute request: %w\", err), correlationID)\n")
 	sb.WriteString("\t}\n")
 	sb.WriteString("\tdefer resp.Body.Close()\n\n")
 
 	sb.WriteString("\tif resp.StatusCode >= 400 {\n")
 	sb.WriteString("\t\tbody, _ := io.ReadAll(resp.Body)\n")
-	sb.WriteString("\t\treturn nil, fmt.Errorf(\"API error %d: %s\", resp.StatusCode, string(body))\n")
+	sb.WriteString("\t\treturn nil, &APIError{StatusCode: resp.StatusCode, Body: string(body), CorrelationID: correlationID}\n")
 	sb.WriteString("\t}\n\n")
 
 	// Special handling for raw content endpoints (GetLawFile and GetAttachment return raw strings/bytes)
 	if methodName == "GetLawFile" || methodName == "GetAttachment" {
 		sb.WriteString("\tbody, err := io.ReadAll(resp.Body)\n")
 		sb.WriteString("\tif err != nil {\n")
-		sb.WriteString("\t\treturn nil, fmt.Errorf(\"failed to read response: %w\", err)\n")
+		sb.WriteString("\t\treturn nil, withCorrelationID(fmt.Errorf(\"failed to read response: %w\", err), correlationID)\n")
 		sb.WriteString("\t}\n\n")
 		sb.WriteString("\tresult := string(body)\n")
 		sb.WriteString("\treturn &result, nil\n")
 	} else {
 		sb.WriteString(fmt.Sprintf("\tvar result %s\n", responseType))
 		sb.WriteString("\tif err := json.NewDecoder(resp.Body).Decode(&result); err != nil {\n")
-		sb.WriteString("\t\treturn nil, fmt.Errorf(\"failed to decode response: %w\", err)\n")
+		sb.WriteString("\t\treturn nil, withCorrelationID(fmt.Errorf(\"failed to decode response: %w\", err), correlationID)\n")
+		sb.WriteString("\t}\n")
+		sb.WriteString("\tif err := c.checkEnums(&result); err != nil {\n")
+		sb.WriteString("\t\treturn nil, withCorrelationID(err, correlationID)\n")
 		sb.WriteString("\t}\n\n")
 		sb.WriteString("\treturn &result, nil\n")
 	}
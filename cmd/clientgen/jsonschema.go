@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// GenerateJSONSchemas returns a self-contained JSON Schema document for each
+// component schema, keyed by file name (e.g. "law_data_response.schema.json").
+// Referenced schemas are included under $defs.
+func (g *Generator) GenerateJSONSchemas() (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for _, name := range g.spec.GetSortedSchemas() {
+		schema := g.spec.Components.Schemas[name]
+
+		doc := g.jsonSchema(&schema)
+		doc["$schema"] = jsonSchemaDialect
+		doc["$id"] = name + ".schema.json"
+		doc["title"] = toPascalCase(name)
+
+		defs := map[string]interface{}{}
+		for _, ref := range g.collectRefs(&schema, map[string]bool{name: true}) {
+			refSchema := g.spec.Components.Schemas[ref]
+			defs[ref] = g.jsonSchema(&refSchema)
+		}
+		if len(defs) > 0 {
+			doc["$defs"] = defs
+		}
+
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files[name+".schema.json"] = append(data, '\n')
+	}
+	return files, nil
+}
+
+// jsonSchema converts an OpenAPI schema object to JSON Schema.
+// References to component schemas point to $defs of the enclosing document.
+func (g *Generator) jsonSchema(s *Schema) map[string]interface{} {
+	out := map[string]interface{}{}
+	if s.Ref != "" {
+		out["$ref"] = "#/$defs/" + refName(s.Ref)
+		return out
+	}
+
+	if s.Type != "" {
+		if s.Nullable {
+			out["type"] = []string{s.Type, "null"}
+		} else {
+			out["type"] = s.Type
+		}
+	}
+	if s.Format != "" {
+		out["format"] = s.Format
+	}
+	if desc := strings.TrimSpace(s.Description); desc != "" {
+		out["description"] = desc
+	}
+	if len(s.Enum) > 0 {
+		out["enum"] = s.Enum
+	}
+	if s.Items != nil {
+		out["items"] = g.jsonSchema(s.Items)
+	}
+	if len(s.Properties) > 0 {
+		properties := map[string]interface{}{}
+		for name, prop := range s.Properties {
+			properties[name] = g.jsonSchema(&prop)
+		}
+		out["properties"] = properties
+
+		// Only keep required properties that are actually declared
+		var required []string
+		for _, name := range s.Required {
+			if _, ok := s.Properties[name]; ok {
+				required = append(required, name)
+			}
+		}
+		if len(required) > 0 {
+			out["required"] = required
+		}
+	}
+	for keyword, schemas := range map[string][]Schema{"allOf": s.AllOf, "oneOf": s.OneOf, "anyOf": s.AnyOf} {
+		if len(schemas) == 0 {
+			continue
+		}
+		converted := make([]interface{}, len(schemas))
+		for i := range schemas {
+			converted[i] = g.jsonSchema(&schemas[i])
+		}
+		out[keyword] = converted
+	}
+	return out
+}
+
+// collectRefs returns the names of component schemas referenced by s, directly or
+// transitively, excluding those already in seen
+func (g *Generator) collectRefs(s *Schema, seen map[string]bool) []string {
+	var refs []string
+	var visit func(s *Schema)
+	visit = func(s *Schema) {
+		if s == nil {
+			return
+		}
+		if s.Ref != "" {
+			name := refName(s.Ref)
+			if seen[name] {
+				return
+			}
+			seen[name] = true
+			refs = append(refs, name)
+			refSchema, ok := g.spec.Components.Schemas[name]
+			if ok {
+				visit(&refSchema)
+			}
+			return
+		}
+		visit(s.Items)
+		for _, prop := range s.Properties {
+			visit(&prop)
+		}
+		for _, list := range [][]Schema{s.AllOf, s.OneOf, s.AnyOf} {
+			for i := range list {
+				visit(&list[i])
+			}
+		}
+	}
+	visit(s)
+	sort.Strings(refs)
+	return refs
+}
+
+// refName returns the schema name of a local reference such as "#/components/schemas/law_info"
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
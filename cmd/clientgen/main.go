@@ -3,7 +3,6 @@ package main
 import (
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
@@ -13,14 +12,18 @@ import (
 
 func main() {
 	var (
-		inputFile   = flag.String("input", "lawapi-v2.yaml", "OpenAPI specification file")
+		inputFile   = flag.String("input", "spec/v2/lawapi-v2.yaml", "OpenAPI specification file")
 		outputDir   = flag.String("output", ".", "Output directory for generated client")
 		packageName = flag.String("package", "lawapi", "Package name for generated code")
+		schemaDir   = flag.String("jsonschema", "", "Output directory for JSON Schema files (disabled if empty)")
+		lang        = flag.String("lang", "go", "Target language: go or typescript")
+		stubDir     = flag.String("stub", "", "Output directory for the operations of the lawapistub stub server package (disabled if empty)")
+		fixtures    = flag.Bool("fixtures", false, "Also generate fixtures.go with NewExampleX constructors populated from the spec examples (go only)")
 	)
 	flag.Parse()
 
 	// Read OpenAPI specification file
-	yamlData, err := ioutil.ReadFile(*inputFile)
+	yamlData, err := os.ReadFile(*inputFile)
 	if err != nil {
 		log.Fatalf("Failed to read input file %s: %v", *inputFile, err)
 	}
@@ -37,23 +40,90 @@ func main() {
 
 	generator := NewGenerator(&spec, *packageName)
 
-	// Generate type definitions file
-	typesContent := generator.GenerateTypes()
-	typesFile := filepath.Join(*outputDir, "types.go")
-	if err := ioutil.WriteFile(typesFile, []byte(typesContent), 0644); err != nil {
-		log.Fatalf("Failed to write types file: %v", err)
+	switch *lang {
+	case "go":
+		// Generate type definitions file
+		typesContent := generator.GenerateTypes()
+		typesFile := filepath.Join(*outputDir, "types.go")
+		if err := os.WriteFile(typesFile, []byte(typesContent), 0644); err != nil {
+			log.Fatalf("Failed to write types file: %v", err)
+		}
+		fmt.Printf("Generated types: %s\n", typesFile)
+
+		// Generate client file
+		clientContent := generator.GenerateClient()
+		clientFile := filepath.Join(*outputDir, "client.go")
+		if err := os.WriteFile(clientFile, []byte(clientContent), 0644); err != nil {
+			log.Fatalf("Failed to write client file: %v", err)
+		}
+		fmt.Printf("Generated client: %s\n", clientFile)
+
+		// Generate nil-safe accessors file
+		accessorsContent := generator.GenerateAccessors()
+		accessorsFile := filepath.Join(*outputDir, "accessors.go")
+		if err := os.WriteFile(accessorsFile, []byte(accessorsContent), 0644); err != nil {
+			log.Fatalf("Failed to write accessors file: %v", err)
+		}
+		fmt.Printf("Generated accessors: %s\n", accessorsFile)
+
+		// Generate example fixtures file
+		if *fixtures {
+			fixturesContent := generator.GenerateFixtures()
+			fixturesFile := filepath.Join(*outputDir, "fixtures.go")
+			if err := os.WriteFile(fixturesFile, []byte(fixturesContent), 0644); err != nil {
+				log.Fatalf("Failed to write fixtures file: %v", err)
+			}
+			fmt.Printf("Generated fixtures: %s\n", fixturesFile)
+		}
+	case "typescript":
+		// Generate TypeScript declarations file
+		tsContent := generator.GenerateTypeScript()
+		tsFile := filepath.Join(*outputDir, "types.d.ts")
+		if err := os.WriteFile(tsFile, []byte(tsContent), 0644); err != nil {
+			log.Fatalf("Failed to write TypeScript declarations file: %v", err)
+		}
+		fmt.Printf("Generated TypeScript declarations: %s\n", tsFile)
+	default:
+		log.Fatalf("Unknown target language %q (expected go or typescript)", *lang)
 	}
-	fmt.Printf("Generated types: %s\n", typesFile)
 
-	// Generate client file
-	clientContent := generator.GenerateClient()
-	clientFile := filepath.Join(*outputDir, "client.go")
-	if err := ioutil.WriteFile(clientFile, []byte(clientContent), 0644); err != nil {
-		log.Fatalf("Failed to write client file: %v", err)
+	// Generate JSON Schema files
+	if *schemaDir != "" {
+		schemas, err := generator.GenerateJSONSchemas()
+		if err != nil {
+			log.Fatalf("Failed to generate JSON Schemas: %v", err)
+		}
+		if err := os.MkdirAll(*schemaDir, 0755); err != nil {
+			log.Fatalf("Failed to create JSON Schema directory %s: %v", *schemaDir, err)
+		}
+		for name, data := range schemas {
+			if err := os.WriteFile(filepath.Join(*schemaDir, name), data, 0644); err != nil {
+				log.Fatalf("Failed to write JSON Schema file: %v", err)
+			}
+		}
+		fmt.Printf("Generated %d JSON Schemas: %s\n", len(schemas), *schemaDir)
+	}
+
+	// Generate the operations of the stub server package
+	if *stubDir != "" {
+		routesContent, err := NewGenerator(&spec, "lawapistub").GenerateStubRoutes()
+		if err != nil {
+			log.Fatalf("Failed to generate stub operations: %v", err)
+		}
+		if err := os.MkdirAll(*stubDir, 0755); err != nil {
+			log.Fatalf("Failed to create stub directory %s: %v", *stubDir, err)
+		}
+		routesFile := filepath.Join(*stubDir, "operations.go")
+		if err := os.WriteFile(routesFile, []byte(routesContent), 0644); err != nil {
+			log.Fatalf("Failed to write stub operations file: %v", err)
+		}
+		fmt.Printf("Generated stub operations: %s\n", routesFile)
 	}
-	fmt.Printf("Generated client: %s\n", clientFile)
 
 	fmt.Printf("Client library generated successfully in %s/\n", *outputDir)
+	if *lang != "go" {
+		return
+	}
 	fmt.Println("\nUsage example:")
 	fmt.Printf("  client := %s.NewClient()\n", *packageName)
 	fmt.Println("  // Use client methods to call API endpoints")
@@ -60,16 +60,26 @@ type Response struct {
 }
 
 type MediaType struct {
-	Schema *Schema `yaml:"schema"`
+	Schema   *Schema            `yaml:"schema"`
+	Examples map[string]Example `yaml:"examples"`
 }
 
 type Components struct {
-	Schemas map[string]Schema `yaml:"schemas"`
+	Schemas  map[string]Schema  `yaml:"schemas"`
+	Examples map[string]Example `yaml:"examples"`
+}
+
+// Example is an example payload, given inline or as a reference to a component example
+type Example struct {
+	Ref     string      `yaml:"$ref"`
+	Summary string      `yaml:"summary"`
+	Value   interface{} `yaml:"value"`
 }
 
 type Schema struct {
 	Type        string             `yaml:"type"`
 	Format      string             `yaml:"format"`
+	Nullable    bool               `yaml:"nullable"`
 	Description string             `yaml:"description"`
 	Properties  map[string]Schema  `yaml:"properties"`
 	Items       *Schema            `yaml:"items"`
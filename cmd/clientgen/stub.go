@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GenerateStubRoutes generates the operation table of the lawapistub package: the
+// parameters of every operation with the type and enum values of their schemas, and the
+// example payload of every response and content type. JSON examples are encoded from
+// their YAML values; other examples are used as written.
+func (g *Generator) GenerateStubRoutes() (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("// Code generated by clientgen; DO NOT EDIT.\n\n")
+	sb.WriteString(fmt.Sprintf("package %s\n\n", g.packageName))
+	sb.WriteString("// specVersion is the version of the specification the operations were generated from\n")
+	sb.WriteString(fmt.Sprintf("const specVersion = %q\n\n", g.spec.Info.Version))
+	sb.WriteString("// operations are the operations of the specification, sorted by path and method\n")
+	sb.WriteString("var operations = []operation{\n")
+
+	for _, path := range g.spec.GetSortedPaths() {
+		pathItem := g.spec.Paths[path]
+		methods := map[string]*Operation{"DELETE": pathItem.Delete, "GET": pathItem.Get, "POST": pathItem.Post, "PUT": pathItem.Put}
+		for _, method := range []string{"DELETE", "GET", "POST", "PUT"} {
+			op := methods[method]
+			if op == nil {
+				continue
+			}
+			sb.WriteString("\t{\n")
+			sb.WriteString(fmt.Sprintf("\t\tmethod: %q, path: %q, operationID: %q,\n", method, path, op.OperationID))
+			sb.WriteString("\t\tparams: []parameter{\n")
+			for _, param := range op.Parameters {
+				sb.WriteString(fmt.Sprintf("\t\t\t%s,\n", g.stubParameter(param)))
+			}
+			sb.WriteString("\t\t},\n")
+			sb.WriteString("\t\tresponses: []response{\n")
+			responses, err := g.stubResponses(op)
+			if err != nil {
+				return "", fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			for _, r := range responses {
+				sb.WriteString(fmt.Sprintf("\t\t\t{status: %d, contentType: %q, body: %s},\n", r.status, r.contentType, strconv.Quote(r.body)))
+			}
+			sb.WriteString("\t\t},\n")
+			sb.WriteString("\t},\n")
+		}
+	}
+	sb.WriteString("}\n")
+
+	formatted, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return sb.String(), nil
+	}
+	return string(formatted), nil
+}
+
+// stubParameter returns the Go literal of the parameter of an operation
+func (g *Generator) stubParameter(param Parameter) string {
+	fields := []string{fmt.Sprintf("name: %q", param.Name), fmt.Sprintf("in: %q", param.In)}
+	if param.Required {
+		fields = append(fields, "required: true")
+	}
+	schema := g.resolveSchema(param.Schema)
+	if schema != nil && schema.Type == "array" {
+		fields = append(fields, "array: true")
+		schema = g.resolveSchema(schema.Items)
+	}
+	kind := "string"
+	var enum []interface{}
+	if schema != nil {
+		switch {
+		case schema.Format == "date" || schema.Format == "date-time":
+			kind = schema.Format
+		case schema.Type != "":
+			kind = schema.Type
+		}
+		enum = schema.Enum
+	}
+	fields = append(fields, fmt.Sprintf("kind: %q", kind))
+	if len(enum) > 0 {
+		values := make([]string, len(enum))
+		for i, v := range enum {
+			values[i] = strconv.Quote(fmt.Sprint(v))
+		}
+		fields = append(fields, fmt.Sprintf("enum: []string{%s}", strings.Join(values, ", ")))
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// resolveSchema returns the component schema a schema refers to, directly or through
+// allOf, or the schema itself
+func (g *Generator) resolveSchema(schema *Schema) *Schema {
+	if schema == nil {
+		return nil
+	}
+	if ref := g.referencedSchema(schema); ref != nil {
+		return ref
+	}
+	return schema
+}
+
+// stubResponse is an example response of an operation
+type stubResponse struct {
+	status      int
+	contentType string
+	body        string
+}
+
+// stubResponses returns the first example of every response and content type of an
+// operation, sorted by status and content type
+func (g *Generator) stubResponses(op *Operation) ([]stubResponse, error) {
+	var responses []stubResponse
+	for code, response := range op.Responses {
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			// default responses have no status to serve
+			continue
+		}
+		for contentType, media := range response.Content {
+			var names []string
+			for name := range media.Examples {
+				names = append(names, name)
+			}
+			if len(names) == 0 {
+				continue
+			}
+			sort.Strings(names)
+			example := media.Examples[names[0]]
+			if example.Ref != "" {
+				parts := strings.Split(example.Ref, "/")
+				ref, ok := g.spec.Components.Examples[parts[len(parts)-1]]
+				if !ok {
+					return nil, fmt.Errorf("unknown example %s", example.Ref)
+				}
+				example = ref
+			}
+			body, err := exampleBody(contentType, g.conformExample(exampleJSONValue(example.Value), media.Schema))
+			if err != nil {
+				return nil, err
+			}
+			responses = append(responses, stubResponse{status, contentType, body})
+		}
+	}
+	sort.Slice(responses, func(i, j int) bool {
+		if responses[i].status != responses[j].status {
+			return responses[i].status < responses[j].status
+		}
+		return responses[i].contentType < responses[j].contentType
+	})
+	return responses, nil
+}
+
+// exampleBody returns the payload of an example value: JSON for JSON content types, and
+// the value as written for others
+func exampleBody(contentType string, value interface{}) (string, error) {
+	if s, ok := value.(string); ok && !strings.Contains(contentType, "json") {
+		return s, nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(value); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// exampleJSONValue converts the timestamps the YAML decoder parsed from unquoted dates
+// back to the strings written in the specification
+func exampleJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			m[key] = exampleJSONValue(elem)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, elem := range v {
+			s[i] = exampleJSONValue(elem)
+		}
+		return s
+	case time.Time:
+		if v.Equal(time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC)) && v.Location() == time.UTC {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339)
+	}
+	return value
+}
+
+// conformExample converts the scalars of an example to the types of its schema where
+// the example disagrees with it, as the YAML decoder parses unquoted words such as false
+// as booleans, and some examples quote numbers. Values outside the enum of their schema
+// are replaced with its first value, so that clients decoding enums strictly accept the
+// payloads.
+func (g *Generator) conformExample(value interface{}, schema *Schema) interface{} {
+	value = g.conformExampleType(value, schema)
+	schema = g.resolveSchema(schema)
+	if s, ok := value.(string); ok && schema != nil && len(schema.Enum) > 0 {
+		for _, v := range schema.Enum {
+			if fmt.Sprint(v) == s {
+				return value
+			}
+		}
+		return fmt.Sprint(schema.Enum[0])
+	}
+	return value
+}
+
+// conformExampleType converts the scalars of an example to the types of its schema
+func (g *Generator) conformExampleType(value interface{}, schema *Schema) interface{} {
+	schema = g.resolveSchema(schema)
+	if schema == nil {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			if prop, ok := schema.Properties[key]; ok {
+				v[key] = g.conformExample(elem, &prop)
+			}
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = g.conformExample(elem, schema.Items)
+		}
+	case bool, int, float64:
+		if schema.Type == "string" {
+			return fmt.Sprint(v)
+		}
+	case string:
+		if n, err := strconv.Atoi(v); err == nil && schema.Type == "integer" {
+			return n
+		}
+	}
+	return value
+}
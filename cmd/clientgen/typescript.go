@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GenerateTypeScript generates TypeScript declarations (.d.ts) matching the Go types
+// generated by GenerateTypes, using the JSON field names of the API
+func (g *Generator) GenerateTypeScript() string {
+	var sb strings.Builder
+
+	sb.WriteString("// Type definitions for Japan Law API\n")
+	sb.WriteString(fmt.Sprintf("// Version: %s\n", g.spec.Info.Version))
+	sb.WriteString("// Code generated by clientgen; DO NOT EDIT.\n\n")
+
+	for _, name := range g.spec.GetSortedSchemas() {
+		schema := g.spec.Components.Schemas[name]
+		sb.WriteString(g.generateInterface(name, &schema))
+		sb.WriteString("\n")
+	}
+
+	// Counterparts of generateAdditionalStructs
+	sb.WriteString("/** LawItem represents a single law entry from the laws array */\n")
+	sb.WriteString("export interface LawItem {\n")
+	sb.WriteString("  law_info?: LawInfo;\n")
+	sb.WriteString("  revision_info?: RevisionInfo;\n")
+	sb.WriteString("  current_revision_info?: RevisionInfo;\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("/** KeywordItem represents a single item from keyword search results */\n")
+	sb.WriteString("export interface KeywordItem {\n")
+	sb.WriteString("  law_info?: LawInfo;\n")
+	sb.WriteString("  revision_info?: RevisionInfo;\n")
+	sb.WriteString("  sentences?: KeywordSentence[];\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("/** KeywordSentence represents a sentence match from keyword search */\n")
+	sb.WriteString("export interface KeywordSentence {\n")
+	sb.WriteString("  text?: string;\n")
+	sb.WriteString("  position?: string;\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("/** Date represents a date in YYYY-MM-DD format */\n")
+	sb.WriteString("export type Date = string;\n\n")
+
+	sb.WriteString("/** DateTime represents a date-time in RFC3339 format */\n")
+	sb.WriteString("export type DateTime = string;\n")
+
+	return sb.String()
+}
+
+func (g *Generator) generateInterface(name string, schema *Schema) string {
+	var sb strings.Builder
+
+	typeName := toPascalCase(name)
+
+	if schema.Description != "" {
+		cleanDesc := cleanDescription(schema.Description)
+		if cleanDesc != "" {
+			sb.WriteString(fmt.Sprintf("/** %s represents %s */\n", typeName, cleanDesc))
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		var values []string
+		for _, enumValue := range schema.Enum {
+			if str, ok := enumValue.(string); ok {
+				values = append(values, fmt.Sprintf("%q", str))
+			}
+		}
+		sb.WriteString(fmt.Sprintf("export type %s = %s;\n", typeName, strings.Join(values, " | ")))
+		return sb.String()
+	}
+
+	if schema.Type != "object" || len(schema.Properties) == 0 {
+		sb.WriteString(fmt.Sprintf("export type %s = %s;\n", typeName, schema.TSType()))
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("export interface %s {\n", typeName))
+
+	var propNames []string
+	for propName := range schema.Properties {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+
+	for _, propName := range propNames {
+		propSchema := schema.Properties[propName]
+		tsType := propSchema.TSType()
+
+		// Same special cases as generateStruct
+		if typeName == "LawsResponse" && propName == "laws" {
+			tsType = "LawItem[]"
+		} else if typeName == "KeywordResponse" && propName == "items" {
+			tsType = "KeywordItem[]"
+		} else if typeName == "LawRevisionsResponse" && propName == "revisions" {
+			tsType = "RevisionInfo[]"
+		}
+
+		if propSchema.Description != "" {
+			cleanDesc := cleanDescription(propSchema.Description)
+			if cleanDesc != "" {
+				sb.WriteString(fmt.Sprintf("  /** %s */\n", cleanDesc))
+			}
+		}
+
+		optional := "?"
+		if schema.IsRequired(propName) {
+			optional = ""
+		}
+		sb.WriteString(fmt.Sprintf("  %s%s: %s;\n", propName, optional, tsType))
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// TSType returns the TypeScript type corresponding to GoType
+func (s *Schema) TSType() string {
+	tsType := s.tsType()
+	if s.Nullable {
+		tsType += " | null"
+	}
+	return tsType
+}
+
+func (s *Schema) tsType() string {
+	if s.Ref != "" || len(s.AllOf) > 0 {
+		if goType := s.GoType(); goType != "interface{}" {
+			return goType
+		}
+	}
+
+	switch s.Type {
+	case "string":
+		switch {
+		case len(s.Enum) > 0:
+			return "string"
+		case s.Format == "date-time":
+			return "DateTime"
+		case s.Format == "date":
+			return "Date"
+		default:
+			return "string"
+		}
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		if s.Items != nil {
+			itemType := s.Items.TSType()
+			if strings.Contains(itemType, " ") {
+				itemType = "(" + itemType + ")"
+			}
+			return itemType + "[]"
+		}
+		return "unknown[]"
+	case "object":
+		if len(s.Properties) == 0 {
+			return "Record<string, unknown>"
+		}
+		return "unknown"
+	default:
+		return "unknown"
+	}
+}
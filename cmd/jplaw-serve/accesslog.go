@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// accessEntry is one line of the access log
+type accessEntry struct {
+	Time   time.Time `json:"time"`
+	Remote string    `json:"remote"`
+	// User is the authenticated user, from the user header or basic auth, or empty
+	User   string `json:"user,omitempty"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Status int    `json:"status"`
+	Bytes  int64  `json:"bytes"`
+	// Source is "mirror" or "upstream" for responses with content
+	Source string `json:"source,omitempty"`
+	// ETag identifies the exact content served (see contentETag)
+	ETag       string  `json:"etag,omitempty"`
+	DurationMS float64 `json:"duration_ms"`
+	// CorrelationID is the correlation ID header of the request (see lawapi.CorrelationIDHeader)
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// accessLog appends JSON lines to a file, rotating it when it exceeds maxSize: the file
+// is renamed to path.1, older files shift up, and files beyond keep are removed
+type accessLog struct {
+	path    string
+	maxSize int64
+	keep    int
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+func openAccessLog(path string, maxSize int64, keep int) (*accessLog, error) {
+	l := &accessLog{path: path, maxSize: maxSize, keep: keep}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *accessLog) open() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.f, l.size = f, info.Size()
+	return nil
+}
+
+func (l *accessLog) write(entry accessEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.f.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+func (l *accessLog) rotate() error {
+	if err := l.f.Close(); err != nil {
+		return err
+	}
+	os.Remove(rotatedPath(l.path, l.keep))
+	for i := l.keep - 1; i >= 1; i-- {
+		if err := os.Rename(rotatedPath(l.path, i), rotatedPath(l.path, i+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if l.keep > 0 {
+		if err := os.Rename(l.path, rotatedPath(l.path, 1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(l.path); err != nil {
+		return err
+	}
+	return l.open()
+}
+
+func rotatedPath(path string, i int) string {
+	return path + "." + strconv.Itoa(i)
+}
+
+// loggingWriter records the status and size of a response
+type loggingWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+	source string
+}
+
+func (w *loggingWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *loggingWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// setSource records where the content of a response came from in the access log
+func setSource(w http.ResponseWriter, source string) {
+	if lw, ok := w.(*loggingWriter); ok {
+		lw.source = source
+	}
+}
+
+// logAccess wraps a handler to write an access log entry for every request. The user is
+// taken from userHeader when set (e.g. from an authenticating reverse proxy), or else
+// from basic auth.
+func logAccess(next http.Handler, log *accessLog, userHeader string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := lawapi.DefaultClock.Now()
+		lw := &loggingWriter{ResponseWriter: w}
+		next.ServeHTTP(lw, r)
+
+		entry := accessEntry{
+			Time:       start,
+			Remote:     r.RemoteAddr,
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Query:      r.URL.RawQuery,
+			Status:     lw.status,
+			Bytes:      lw.bytes,
+			Source:     lw.source,
+			ETag:       lw.Header().Get("ETag"),
+			DurationMS: float64(lawapi.DefaultClock.Now().Sub(start).Microseconds()) / 1000,
+		}
+		entry.CorrelationID = r.Header.Get(lawapi.CorrelationIDHeader)
+		if userHeader != "" {
+			entry.User = r.Header.Get(userHeader)
+		} else if user, _, ok := r.BasicAuth(); ok {
+			entry.User = user
+		}
+		if err := log.write(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "jplaw-serve: failed to write access log: %v\n", err)
+		}
+	})
+}
+
+// exportAudit writes the entries of the access log and its rotated files between since
+// and until (zero for no bound) as CSV, oldest first
+func exportAudit(w io.Writer, path string, keep int, since, until time.Time) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"time", "user", "remote", "method", "path", "query", "status", "source", "etag"})
+	for i := keep; i >= 0; i-- {
+		name := path
+		if i > 0 {
+			name = rotatedPath(path, i)
+		}
+		f, err := os.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 1<<20)
+		for line := 1; scanner.Scan(); line++ {
+			var entry accessEntry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				f.Close()
+				return fmt.Errorf("%s:%d: %w", name, line, err)
+			}
+			if (!since.IsZero() && entry.Time.Before(since)) || (!until.IsZero() && !entry.Time.Before(until)) {
+				continue
+			}
+			out.Write([]string{
+				entry.Time.Format(time.RFC3339), entry.User, entry.Remote, entry.Method,
+				entry.Path, entry.Query, strconv.Itoa(entry.Status), entry.Source, entry.ETag,
+			})
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// annotationsPath is the path of the annotations endpoint, outside the API root
+const annotationsPath = "/annotations"
+
+// withAnnotations serves the annotation store file at path read-only on the annotations
+// endpoint, as JSON lines exported by lawapi.AnnotationStore, and passes other requests
+// to next. Without parameters, all annotations including the tombstones of removed ones
+// are served, so that "jplaw annotations import" syncs from the server; law_id,
+// provision, kind and tag select annotations as in lawapi.AnnotationFilter.
+func withAnnotations(next http.Handler, path string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != annotationsPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "read-only annotations", http.StatusMethodNotAllowed)
+			return
+		}
+		// The file is read on every request, as it is changed by other processes
+		store, err := lawapi.NewAnnotationStore(lawapi.AnnotationStoreOptions{Path: path})
+		if err != nil {
+			log.Printf("jplaw-serve: %s: %v", path, err)
+			http.Error(w, "failed to read annotations", http.StatusInternalServerError)
+			return
+		}
+		query := r.URL.Query()
+		filter := lawapi.AnnotationFilter{
+			LawID:       query.Get("law_id"),
+			ProvisionID: query.Get("provision"),
+			Kind:        lawapi.UserAnnotationKind(query.Get("kind")),
+			Tag:         query.Get("tag"),
+		}
+		w.Header().Set("Content-Type", "application/jsonl; charset=utf-8")
+		if r.Method == http.MethodHead {
+			return
+		}
+		if filter == (lawapi.AnnotationFilter{}) {
+			store.Export(w)
+			return
+		}
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		for _, a := range store.List(filter) {
+			enc.Encode(a)
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// changelog lists the laws that changed in the mirror during one -fetch run
+type changelog struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Added are laws that were not in the mirror before
+	Added []changelogEntry `json:"added"`
+	// Amended are laws whose revision changed
+	Amended []changelogEntry `json:"amended"`
+	// Repealed are laws whose repeal status changed from None
+	Repealed []changelogEntry `json:"repealed"`
+}
+
+// changelogEntry is a changed law
+type changelogEntry struct {
+	LawID    string `json:"law_id"`
+	LawTitle string `json:"law_title"`
+	// PreviousRevisionID is the revision in the mirror before the run, or empty
+	PreviousRevisionID string `json:"previous_revision_id,omitempty"`
+	RevisionID         string `json:"revision_id,omitempty"`
+	AmendmentLawTitle  string `json:"amendment_law_title,omitempty"`
+	RepealStatus       string `json:"repeal_status,omitempty"`
+	// DiffURL links to the comparison of the previous and current revisions, if configured
+	DiffURL string `json:"diff_url,omitempty"`
+	// Path is the API path the change was seen in
+	Path string `json:"path"`
+}
+
+// changelogRecorder collects the changes of a run. diffURL is a URL template in which
+// {old} and {new} are replaced by revision IDs, or empty.
+type changelogRecorder struct {
+	diffURL string
+	log     changelog
+	seen    map[string]bool
+}
+
+func newChangelogRecorder(diffURL string) *changelogRecorder {
+	return &changelogRecorder{diffURL: diffURL, log: changelog{Start: lawapi.DefaultClock.Now()}, seen: map[string]bool{}}
+}
+
+// record compares the law entries of the previous (possibly nil) and fetched responses
+// to an API path. Each law is listed once per run, under the first change seen.
+func (c *changelogRecorder) record(apiPath string, previous, fetched *mirroredResponse) {
+	before := map[string]lawapi.LawItem{}
+	if previous != nil {
+		for _, item := range lawEntries(previous.body) {
+			before[item.LawInfo.LawId] = item
+		}
+	}
+	for _, item := range lawEntries(fetched.body) {
+		id := item.LawInfo.LawId
+		if c.seen[id] {
+			continue
+		}
+		rev := revisionOf(item)
+		entry := changelogEntry{
+			LawID:             id,
+			LawTitle:          rev.LawTitle,
+			RevisionID:        rev.LawRevisionId,
+			AmendmentLawTitle: rev.AmendmentLawTitle,
+			Path:              apiPath,
+		}
+		if rev.RepealStatus != nil {
+			entry.RepealStatus = string(*rev.RepealStatus)
+		}
+		old, ok := before[id]
+		if !ok {
+			c.seen[id] = true
+			c.log.Added = append(c.log.Added, entry)
+			continue
+		}
+		oldRev := revisionOf(old)
+		entry.PreviousRevisionID = oldRev.LawRevisionId
+		if c.diffURL != "" && entry.PreviousRevisionID != "" && entry.RevisionID != "" && entry.PreviousRevisionID != entry.RevisionID {
+			entry.DiffURL = strings.NewReplacer("{old}", entry.PreviousRevisionID, "{new}", entry.RevisionID).Replace(c.diffURL)
+		}
+		switch {
+		case isRepealed(rev) && !isRepealed(oldRev):
+			c.seen[id] = true
+			c.log.Repealed = append(c.log.Repealed, entry)
+		case entry.RevisionID != entry.PreviousRevisionID:
+			c.seen[id] = true
+			c.log.Amended = append(c.log.Amended, entry)
+		}
+	}
+}
+
+// write saves the changelog as JSON, with entries sorted by law ID
+func (c *changelogRecorder) write(path string) error {
+	c.log.End = lawapi.DefaultClock.Now()
+	for _, entries := range [][]changelogEntry{c.log.Added, c.log.Amended, c.log.Repealed} {
+		slices.SortFunc(entries, func(a, b changelogEntry) int { return cmp.Compare(a.LawID, b.LawID) })
+	}
+	// Empty lists are written as [] rather than null for consumers
+	for _, entries := range []*[]changelogEntry{&c.log.Added, &c.log.Amended, &c.log.Repealed} {
+		if *entries == nil {
+			*entries = []changelogEntry{}
+		}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	// Keep & in diff URLs readable
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c.log); err != nil {
+		return err
+	}
+	return lawapi.WriteFileAtomic(path, buf.Bytes(), 0o644)
+}
+
+// lawEntries returns the laws of a law_data or laws response body, or nil for other
+// responses
+func lawEntries(body []byte) []lawapi.LawItem {
+	var resp struct {
+		LawInfo      *lawapi.LawInfo      `json:"law_info"`
+		RevisionInfo *lawapi.RevisionInfo `json:"revision_info"`
+		Laws         []lawapi.LawItem     `json:"laws"`
+	}
+	if json.Unmarshal(body, &resp) != nil {
+		return nil
+	}
+	items := resp.Laws
+	if resp.LawInfo != nil && resp.RevisionInfo != nil {
+		items = append(items, lawapi.LawItem{LawInfo: resp.LawInfo, RevisionInfo: resp.RevisionInfo})
+	}
+	return slices.DeleteFunc(items, func(item lawapi.LawItem) bool {
+		return item.LawInfo == nil || item.LawInfo.LawId == "" || revisionOf(item) == (lawapi.RevisionInfo{})
+	})
+}
+
+// revisionOf returns the revision of a law entry, preferring the retrieved revision
+func revisionOf(item lawapi.LawItem) lawapi.RevisionInfo {
+	switch {
+	case item.RevisionInfo != nil:
+		return *item.RevisionInfo
+	case item.CurrentRevisionInfo != nil:
+		return *item.CurrentRevisionInfo
+	}
+	return lawapi.RevisionInfo{}
+}
+
+func isRepealed(rev lawapi.RevisionInfo) bool {
+	return rev.RepealStatus != nil && *rev.RepealStatus != lawapi.RepealStatusNone
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// gzipMinSize is the smallest body compressed for clients accepting gzip
+const gzipMinSize = 1024
+
+// contentETag returns a strong entity tag derived from the content hash of a body, so it
+// is the same for identical content whether served from the mirror or fetched upstream
+func contentETag(body []byte, encoding string) string {
+	sum := sha256.Sum256(body)
+	tag := hex.EncodeToString(sum[:16])
+	if encoding != "" {
+		// Each encoding is a distinct representation with its own strong tag
+		tag += "-" + encoding
+	}
+	return `"` + tag + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, using the weak
+// comparison RFC 9110 requires for If-None-Match
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip reports whether the request accepts gzip content coding
+func acceptsGzip(r *http.Request) bool {
+	for _, value := range r.Header.Values("Accept-Encoding") {
+		for _, coding := range strings.Split(value, ",") {
+			name, params, _ := strings.Cut(coding, ";")
+			if name = strings.TrimSpace(name); name != "gzip" && name != "*" {
+				continue
+			}
+			q, ok := strings.CutPrefix(strings.TrimSpace(params), "q=")
+			if !ok {
+				return true
+			}
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeResponse writes a mirrored response. Successful responses get a content-hash ETag,
+// are answered with 304 Not Modified when If-None-Match matches it, and are compressed
+// with gzip for clients accepting it.
+func writeResponse(w http.ResponseWriter, r *http.Request, resp *mirroredResponse) {
+	for _, name := range mirroredHeaders {
+		if v := resp.header.Get(name); v != "" {
+			w.Header().Set(name, v)
+		}
+	}
+	if resp.status != http.StatusOK {
+		w.WriteHeader(resp.status)
+		if r.Method != http.MethodHead {
+			w.Write(resp.body)
+		}
+		return
+	}
+
+	encoding := ""
+	if len(resp.body) >= gzipMinSize && acceptsGzip(r) {
+		encoding = "gzip"
+	}
+	etag := contentETag(resp.body, encoding)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Vary", "Accept-Encoding")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if encoding == "" {
+		w.Header().Set("Content-Length", strconv.Itoa(len(resp.body)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method != http.MethodHead {
+			w.Write(resp.body)
+		}
+		return
+	}
+	w.Header().Set("Content-Encoding", encoding)
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		gz := gzip.NewWriter(w)
+		gz.Write(resp.body)
+		gz.Close()
+	}
+}
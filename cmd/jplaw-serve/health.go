@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// healthPath is the path of the health endpoint, outside the API root
+const healthPath = "/healthz"
+
+// healthPingInterval is how long an upstream probe is reused, so that frequent health
+// checks do not spend the upstream request budget
+const healthPingInterval = 30 * time.Second
+
+// health reports whether the server can serve requests, for liveness and readiness
+// probes. The mirror must be readable; in proxy mode, the upstream API is probed too, and
+// its unavailability degrades the service without failing the check, as mirrored
+// responses are still served.
+type health struct {
+	dir string
+	// client probes the upstream API, or is nil without -proxy
+	client *lawapi.Client
+
+	mu       sync.Mutex
+	lastPing *lawapi.PingResult
+}
+
+// healthStatus is the body of health responses
+type healthStatus struct {
+	// Status is "ok", "degraded" (upstream unavailable) or "unavailable" (mirror unreadable)
+	Status   string             `json:"status"`
+	Error    string             `json:"error,omitempty"`
+	Upstream *lawapi.PingResult `json:"upstream,omitempty"`
+}
+
+// withHealth serves the health endpoint and passes other requests to next
+func withHealth(next http.Handler, h *health) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == healthPath {
+			h.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status := healthStatus{Status: "ok"}
+	code := http.StatusOK
+	if _, err := os.ReadDir(h.dir); err != nil {
+		status.Status, status.Error = "unavailable", err.Error()
+		code = http.StatusServiceUnavailable
+	} else if h.client != nil {
+		status.Upstream = h.ping(r.Context())
+		if !status.Upstream.Available {
+			status.Status = "degraded"
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
+
+// ping returns the last upstream probe, probing again when it is older than
+// healthPingInterval
+func (h *health) ping(ctx context.Context) *lawapi.PingResult {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.lastPing == nil || lawapi.DefaultClock.Now().Sub(h.lastPing.CheckedAt) >= healthPingInterval {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		h.lastPing, _ = h.client.Ping(ctx)
+	}
+	return h.lastPing
+}
@@ -0,0 +1,313 @@
+// Command jplaw-serve serves a local mirror of the Japan Law API v2 over HTTP, so internal
+// networks can use the API offline through the same endpoints, or share one rate-limited
+// upstream connection in proxy mode. Point clients at http://<addr>/api/2 with their base URL.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// apiRoot is the path prefix of the API, as upstream
+const apiRoot = "/api/2/"
+
+// mirroredHeaders are the response headers passed on from mirrored responses, including
+// the provenance stamped on fetched responses
+var mirroredHeaders = []string{
+	"Content-Type", "Content-Disposition", "Last-Modified",
+	lawapi.ProvenanceBaseURLHeader, lawapi.ProvenanceFetchedAtHeader, lawapi.ProvenanceRevisionHeader,
+	lawapi.ProvenancePackageVersionHeader, lawapi.ProvenanceContentSHA256Header,
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "Address to listen on")
+	dir := flag.String("dir", os.Getenv("JPLAW_MIRROR_DIR"), "Mirror directory (default $JPLAW_MIRROR_DIR)")
+	fetch := flag.Bool("fetch", false, "Download the API paths given as arguments into the mirror and exit")
+	proxy := flag.Bool("proxy", false, "Forward requests missing from the mirror upstream and store the responses")
+	upstreamURL := flag.String("upstream", lawapi.DefaultBaseURL, "Upstream API root")
+	concurrency := flag.Int("concurrency", 2, "Maximum number of concurrent upstream requests")
+	budget := flag.Int("budget", 0, "Maximum number of upstream requests per 24 hours (0: unlimited)")
+	bandwidth := flag.Int("bandwidth", 0, "Maximum upstream download rate in KiB per second (0: unlimited)")
+	budgetFile := flag.String("budget-file", "", "File persisting the budget between runs")
+	accessLogPath := flag.String("access-log", "", "File to append JSON access log lines to")
+	accessLogSize := flag.Int64("access-log-max-size", 100, "Size in MB at which the access log is rotated (0: never)")
+	accessLogKeep := flag.Int("access-log-keep", 10, "Number of rotated access log files to keep")
+	userHeader := flag.String("user-header", "", "Request header naming the authenticated user (default: basic auth user)")
+	export := flag.Bool("export-audit", false, "Write the access log entries between -since and -until as CSV and exit")
+	since := flag.String("since", "", "Start date (YYYY-MM-DD, JST) of -export-audit")
+	until := flag.String("until", "", "End date (YYYY-MM-DD, JST, inclusive) of -export-audit")
+	changelogPath := flag.String("changelog", "", "With -fetch, write the laws added, amended or repealed by the run as JSON to this file")
+	diffURL := flag.String("diff-url", "", "URL template of revision comparisons in the changelog, with {old} and {new} revision IDs")
+	filenames := flag.String("filenames", "id", "File names of stored law_data responses: id (request path) or title (law title in a directory named after the request path)")
+	annotations := flag.String("annotations", "", "Annotation store file (jplaw annotations) to serve read-only on "+annotationsPath+" for syncing")
+	manifestKey := flag.String("manifest-key", "", "With -fetch, write a MANIFEST.json of the mirror signed with this PEM ed25519 private key")
+	preset := flag.String("preset", "", "With -fetch, also download the laws of these comma-separated subset presets ("+strings.Join(lawapi.SubsetPresetNames(), ", ")+")")
+	categories := flag.String("category", "", "With -fetch, also download the laws of these comma-separated category codes (e.g. 015)")
+	lawTypes := flag.String("law-type", "", "Restrict -preset and -category to these comma-separated law types (e.g. Act,CabinetOrder)")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), `Usage: jplaw-serve [options]
+       jplaw-serve -fetch [-preset name,...] [-category code,...] [options] [<api-path>...]
+       jplaw-serve -export-audit -access-log <file> [-since date] [-until date]
+
+Serve the responses stored in a mirror directory read-only under /api/2/, like the
+upstream API. Requests without a mirrored response get 404, or are forwarded upstream
+with -proxy. Upstream requests are limited by -concurrency and -budget, downloads by -bandwidth,
+and concurrent requests for the same response share one upstream request.
+
+With -fetch, download upstream responses into the mirror instead. API paths are
+relative to the API root and may include a query (e.g. "law_data/322AC0000000049"
+or "laws?law_title=電波"). -preset and -category add the law_data paths of the laws
+in categories, so that a first mirror can hold only the part of the corpus needed
+(e.g. -preset tax, or -category 015). -changelog records the laws whose law_data or laws
+responses show them added, amended or repealed since the previous fetch.
+-filenames title stores law_data responses under the law title, in a directory
+named after the request path, for browsing the mirror by title. File names are safe
+on Windows and within path length limits either way.
+-manifest-key signs a manifest of the mirror after the run, which "jplaw manifest
+verify" checks against the public key.
+
+With -access-log, every request is logged as a JSON line with its time, client, user,
+status, source and the ETag of the content served, so it can be shown which text was
+consulted when. -export-audit writes the log, including rotated files, as CSV.
+
+With -annotations, GET /annotations serves the bookmarks, notes and highlights of an
+annotation store file as JSON lines, for "jplaw annotations import" to sync from.
+Keep the file in the mirror directory to include it in manifests.
+
+GET /healthz reports the health of the server as JSON: 503 if the mirror cannot be read,
+and with -proxy, a probe of the upstream API (status "degraded" when it is unavailable).
+
+Failures exit with status 1 (internal), 2 (usage), 3 (network or server error),
+4 (not found), 5 (rate limited or budget exhausted) or 6 (validation).
+
+Options:`)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *export {
+		if *accessLogPath == "" || flag.NArg() > 0 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		var from, to time.Time
+		var err error
+		if *since != "" {
+			if from, err = time.ParseInLocation(time.DateOnly, *since, lawapi.JST); err != nil {
+				fatal(err)
+			}
+		}
+		if *until != "" {
+			if to, err = time.ParseInLocation(time.DateOnly, *until, lawapi.JST); err != nil {
+				fatal(err)
+			}
+			to = to.AddDate(0, 0, 1)
+		}
+		if err := exportAudit(os.Stdout, *accessLogPath, *accessLogKeep, from, to); err != nil {
+			fatal(err)
+		}
+		return
+	}
+	subset, err := subsetSpec(*preset, *categories, *lawTypes)
+	if err != nil {
+		fatal(err)
+	}
+	hasSubset := len(subset.Categories) > 0
+	if *dir == "" || (flag.NArg() > 0 || hasSubset) != *fetch {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	m, err := openMirror(*dir, *filenames)
+	if err != nil {
+		fatal(err)
+	}
+	// Throttled bodies are read within the scheduler slot of their request
+	throttled := lawapi.NewBandwidthLimit(*bandwidth * 1024).Transport(nil)
+	transport := lawapi.NewScheduler(lawapi.SchedulerOptions{Concurrency: *concurrency}).Transport(throttled)
+	if *budget > 0 {
+		b, err := lawapi.NewBudget(lawapi.BudgetOptions{Limit: *budget, Path: *budgetFile})
+		if err != nil {
+			fatal(err)
+		}
+		transport = b.Transport(transport)
+	}
+	up := newUpstream(strings.TrimSuffix(*upstreamURL, "/"), transport, m)
+	if *fetch {
+		apiPaths := flag.Args()
+		if hasSubset {
+			lawIDs, err := newUpstreamClient(*upstreamURL, transport).ResolveSubset(context.Background(), subset, nil)
+			if err != nil {
+				fatal(err)
+			}
+			log.Printf("jplaw-serve: subset has %d laws", len(lawIDs))
+			apiPaths = append(apiPaths, lawapi.SubsetAPIPaths(lawIDs)...)
+		}
+		var changes *changelogRecorder
+		if *changelogPath != "" {
+			changes = newChangelogRecorder(*diffURL)
+		}
+		if err := fetchAll(up, apiPaths, changes); err != nil {
+			fatal(err)
+		}
+		if changes != nil {
+			if err := changes.write(*changelogPath); err != nil {
+				fatal(err)
+			}
+		}
+		if *manifestKey != "" {
+			if err := writeSignedManifest(*dir, *manifestKey); err != nil {
+				fatal(err)
+			}
+		}
+		return
+	}
+
+	s := &server{mirror: m}
+	h := &health{dir: m.dir}
+	if *proxy {
+		s.upstream = up
+		h.client = newUpstreamClient(*upstreamURL, transport)
+	}
+	var handler http.Handler = s
+	if *accessLogPath != "" {
+		accessLog, err := openAccessLog(*accessLogPath, *accessLogSize<<20, *accessLogKeep)
+		if err != nil {
+			fatal(err)
+		}
+		handler = logAccess(handler, accessLog, *userHeader)
+	}
+	if *annotations != "" {
+		handler = withAnnotations(handler, *annotations)
+	}
+	// Health checks are not logged
+	handler = withHealth(handler, h)
+	log.Printf("jplaw-serve: serving %s on http://%s%s", *dir, *addr, apiRoot)
+	if err := http.ListenAndServe(*addr, handler); err != nil {
+		fatal(err)
+	}
+}
+
+// server serves API requests from the mirror
+type server struct {
+	mirror *mirror
+	// upstream fetches responses missing from the mirror, or is nil
+	upstream *upstream
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	apiPath, ok := strings.CutPrefix(r.URL.Path, apiRoot)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "read-only mirror", http.StatusMethodNotAllowed)
+		return
+	}
+
+	source := "mirror"
+	resp, err := s.mirror.load(apiPath, r.URL.RawQuery)
+	if errors.Is(err, errNotMirrored) && s.upstream != nil {
+		source = "upstream"
+		resp, err = s.upstream.fetch(r.Context(), apiPath, r.URL.RawQuery)
+		if errors.Is(err, lawapi.ErrBudgetExhausted) {
+			http.Error(w, "upstream request budget exhausted", http.StatusServiceUnavailable)
+			return
+		} else if err != nil && r.Context().Err() == nil {
+			log.Printf("jplaw-serve: %s: %v", r.URL, err)
+			http.Error(w, "upstream request failed", http.StatusBadGateway)
+			return
+		}
+	}
+	if errors.Is(err, errNotMirrored) {
+		http.Error(w, "not in mirror", http.StatusNotFound)
+		return
+	} else if err != nil {
+		if r.Context().Err() == nil {
+			log.Printf("jplaw-serve: %s: %v", r.URL, err)
+			http.Error(w, "failed to read mirror", http.StatusInternalServerError)
+		}
+		return
+	}
+	setSource(w, source)
+	writeResponse(w, r, resp)
+}
+
+// fatal logs an error and exits with the status of its category (see
+// lawapi.ErrorCategory.ExitCode)
+func fatal(err error) {
+	log.Print(err)
+	os.Exit(lawapi.ErrorCategoryOf(err).ExitCode())
+}
+
+// subsetSpec merges the presets and categories given as comma-separated flag values
+func subsetSpec(presets, categories, lawTypes string) (lawapi.SubsetSpec, error) {
+	var spec lawapi.SubsetSpec
+	for _, name := range splitList(presets) {
+		preset, err := lawapi.SubsetPreset(name)
+		if err != nil {
+			return spec, err
+		}
+		spec = spec.Merge(preset)
+	}
+	for _, code := range splitList(categories) {
+		spec = spec.Merge(lawapi.SubsetSpec{Categories: []lawapi.CategoryCd{lawapi.CategoryCd(code)}})
+	}
+	for _, lawType := range splitList(lawTypes) {
+		spec.LawTypes = append(spec.LawTypes, lawapi.LawType(lawType))
+	}
+	return spec, nil
+}
+
+// splitList splits a comma-separated flag value, ignoring empty elements
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// fetchAll downloads upstream responses to API paths into the mirror, recording the
+// changed laws in changes if not nil
+func fetchAll(up *upstream, apiPaths []string, changes *changelogRecorder) error {
+	for _, arg := range apiPaths {
+		apiPath, rawQuery, _ := strings.Cut(strings.TrimPrefix(arg, "/"), "?")
+		previous, err := up.mirror.load(apiPath, rawQuery)
+		if err != nil && !errors.Is(err, errNotMirrored) {
+			return fmt.Errorf("%s: %w", arg, err)
+		}
+		resp, err := up.fetch(context.Background(), apiPath, rawQuery)
+		if err != nil {
+			return fmt.Errorf("%s: %w", arg, err)
+		}
+		if resp.status != http.StatusOK {
+			return fmt.Errorf("%s: %w", arg, &lawapi.APIError{StatusCode: resp.status, Body: http.StatusText(resp.status)})
+		}
+		log.Printf("jplaw-serve: fetched %s (%d bytes)", arg, len(resp.body))
+		if changes != nil {
+			changes.record(arg, previous, resp)
+		}
+	}
+	return nil
+}
+
+// newUpstreamClient creates an API client of the upstream API using the shared transport
+func newUpstreamClient(upstreamURL string, transport http.RoundTripper) *lawapi.Client {
+	client := lawapi.NewClient()
+	client.SetBaseURL(strings.TrimSuffix(upstreamURL, "/"))
+	client.SetHTTPClient(&http.Client{Timeout: 30 * time.Second, Transport: transport})
+	return client
+}
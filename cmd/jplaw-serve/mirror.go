@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// errNotMirrored is returned for requests without a mirrored response
+var errNotMirrored = errors.New("not in mirror")
+
+// mirror stores API responses in a directory, one file per request. The file of a request
+// is its path below the API root with ".resp" appended, preceded by "@" and a hash of the
+// sorted query for requests with parameters (e.g. law_data/322AC0000000049@1f3a….resp).
+// Path segments are made safe for Windows and length limits with lawapi.SafeFilename.
+// Files hold the response in HTTP/1.1 wire format, so headers such as Content-Type are kept.
+//
+// With the title filename strategy, law_data responses are stored in a directory named
+// after the request path, in a file named after the law title (e.g.
+// law_data/322AC0000000049/電波法.resp), so the mirror can be browsed by title. Both
+// layouts are read whatever the strategy.
+type mirror struct {
+	dir string
+	// filenames is the filename strategy of stored responses: filenamesByID or
+	// filenamesByTitle
+	filenames string
+}
+
+// Filename strategies of mirrors
+const (
+	filenamesByID    = "id"
+	filenamesByTitle = "title"
+)
+
+// mirrorVersion is the version of the mirror directory layout. Layout changes increment it
+// and append a migration to mirrorMigrations.
+const mirrorVersion = 2
+
+// mirrorMigrations upgrade a mirror directory: mirrorMigrations[i] upgrades a mirror from
+// version i+1 to i+2
+var mirrorMigrations = []func(dir string) error{
+	// Version 2 makes path segments safe file names
+	migrateSafeFilenames,
+}
+
+// queryHashPattern matches the query hash and extension ending the file of a request
+var queryHashPattern = regexp.MustCompile(`(@[0-9a-f]{16})?\.resp$`)
+
+// openMirror opens a mirror directory, creating it if needed and upgrading its layout to
+// mirrorVersion. Directories without a version file have the first layout.
+func openMirror(dir, filenames string) (*mirror, error) {
+	if filenames != filenamesByID && filenames != filenamesByTitle {
+		return nil, fmt.Errorf("unknown filename strategy %q", filenames)
+	}
+	// Absolute paths let Go lift the Windows path length limit (MAX_PATH)
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	versionFile := filepath.Join(dir, "VERSION")
+	version := 1
+	data, err := os.ReadFile(versionFile)
+	if err == nil {
+		if version, err = strconv.Atoi(strings.TrimSpace(string(data))); err != nil {
+			return nil, fmt.Errorf("invalid mirror version in %s: %w", versionFile, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if version > mirrorVersion {
+		return nil, fmt.Errorf("mirror %s has version %d, newer than the supported version %d; upgrade jplaw-serve", dir, version, mirrorVersion)
+	}
+	for ; version < mirrorVersion; version++ {
+		if err := mirrorMigrations[version-1](dir); err != nil {
+			return nil, fmt.Errorf("failed to upgrade mirror %s to version %d: %w", dir, version+1, err)
+		}
+		// Record each step, so an interrupted upgrade resumes where it stopped
+		if err := lawapi.WriteFileAtomic(versionFile, []byte(strconv.Itoa(version+1)+"\n"), 0o644); err != nil {
+			return nil, err
+		}
+	}
+	if data == nil {
+		if err := lawapi.WriteFileAtomic(versionFile, []byte(strconv.Itoa(mirrorVersion)+"\n"), 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return &mirror{dir: dir, filenames: filenames}, nil
+}
+
+// migrateSafeFilenames renames the files of a version 1 mirror whose path segments are not
+// safe file names
+func migrateSafeFilenames(dir string) error {
+	var files []string
+	err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() && queryHashPattern.MatchString(d.Name()) && !strings.HasPrefix(d.Name(), ".") {
+			files = append(files, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		suffix := queryHashPattern.FindString(rel)
+		segments := strings.Split(filepath.ToSlash(strings.TrimSuffix(rel, suffix)), "/")
+		for i, segment := range segments {
+			segments[i] = lawapi.SafeFilename(segment)
+		}
+		safe := filepath.Join(append([]string{dir}, segments...)...) + suffix
+		if safe == name {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(safe), 0o755); err != nil {
+			return err
+		}
+		if err := os.Rename(name, safe); err != nil {
+			return err
+		}
+		// Remove the directories left empty, up to the mirror root
+		parent := filepath.Dir(name)
+		for parent != dir && os.Remove(parent) == nil {
+			parent = filepath.Dir(parent)
+		}
+	}
+	return nil
+}
+
+// mirroredResponse is a stored API response
+type mirroredResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// file returns the path, without extension, of the file storing the response to an API
+// path (below the API root), and the suffix ending the file of the query
+func (m *mirror) file(apiPath, rawQuery string) (name, suffix string, err error) {
+	apiPath = strings.Trim(path.Clean("/"+apiPath), "/")
+	if apiPath == "" {
+		return "", "", errNotMirrored
+	}
+	segments := strings.Split(apiPath, "/")
+	for i, segment := range segments {
+		segments[i] = lawapi.SafeFilename(segment)
+	}
+	name = filepath.Join(append([]string{m.dir}, segments...)...)
+	suffix = ".resp"
+	if rawQuery != "" {
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return "", "", err
+		}
+		// Encode sorts by key, so parameter order does not matter
+		sum := sha256.Sum256([]byte(query.Encode()))
+		suffix = "@" + hex.EncodeToString(sum[:8]) + suffix
+	}
+	return name, suffix, nil
+}
+
+// titledFiles returns the files in the directory of a request path that store responses
+// to the query under a law title
+func titledFiles(name, suffix string) []string {
+	entries, _ := os.ReadDir(name)
+	var files []string
+	for _, entry := range entries {
+		// Titles cannot contain "@", so the suffix identifies the query
+		title, ok := strings.CutSuffix(entry.Name(), suffix)
+		if ok && title != "" && !strings.Contains(title, "@") && !strings.HasPrefix(title, ".") && entry.Type().IsRegular() {
+			files = append(files, filepath.Join(name, entry.Name()))
+		}
+	}
+	return files
+}
+
+// load returns the mirrored response to a request for an API path
+func (m *mirror) load(apiPath, rawQuery string) (*mirroredResponse, error) {
+	name, suffix, err := m.file(apiPath, rawQuery)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(name + suffix)
+	if errors.Is(err, os.ErrNotExist) && isLawDataPath(apiPath) {
+		files := titledFiles(name, suffix)
+		if len(files) == 0 {
+			return nil, errNotMirrored
+		}
+		data, err = os.ReadFile(files[0])
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNotMirrored
+	} else if err != nil {
+		return nil, err
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &mirroredResponse{status: resp.StatusCode, header: resp.Header, body: body}, nil
+}
+
+// store saves a response to a request for an API path. Only the headers in
+// mirroredHeaders are kept.
+func (m *mirror) store(apiPath, rawQuery string, status int, header http.Header, body []byte) error {
+	name, suffix, err := m.file(apiPath, rawQuery)
+	if err != nil {
+		return err
+	}
+	file := name + suffix
+	if title := m.title(apiPath, body); title != "" {
+		file = filepath.Join(name, title+suffix)
+	}
+	resp := &http.Response{
+		StatusCode:    status,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		ContentLength: int64(len(body)),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+	}
+	for _, h := range mirroredHeaders {
+		if v := header.Get(h); v != "" {
+			resp.Header.Set(h, v)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	if err := lawapi.WriteAtomic(file, 0o644, resp.Write); err != nil {
+		return err
+	}
+	// Remove the response stored under another strategy or an earlier title
+	for _, stale := range append(titledFiles(name, suffix), name+suffix) {
+		if stale != file {
+			if err := os.Remove(stale); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isLawDataPath reports whether an API path is that of a law_data response, which the
+// title strategy stores under the law title
+func isLawDataPath(apiPath string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(apiPath, "/"), "law_data/")
+}
+
+// title returns the file name of a law_data response under the title strategy, or empty
+// to store the response under its request path
+func (m *mirror) title(apiPath string, body []byte) string {
+	if m.filenames != filenamesByTitle || !isLawDataPath(apiPath) {
+		return ""
+	}
+	laws := lawEntries(body)
+	if len(laws) != 1 {
+		return ""
+	}
+	title := revisionOf(laws[0]).LawTitle
+	if title == "" {
+		return ""
+	}
+	// "@" starts query hashes
+	return lawapi.SafeFilename(strings.ReplaceAll(title, "@", "＠"))
+}
+
+// writeSignedManifest writes the manifest of a mirror directory, signed with the private
+// key in keyFile
+func writeSignedManifest(dir, keyFile string) error {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+	key, err := lawapi.ParseManifestPrivateKey(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", keyFile, err)
+	}
+	m, err := lawapi.BuildManifest(dir)
+	if err != nil {
+		return err
+	}
+	if err := m.Sign(key); err != nil {
+		return err
+	}
+	return lawapi.WriteManifest(dir, m)
+}
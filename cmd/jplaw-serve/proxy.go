@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// upstream fetches responses from the API for the mirror. Concurrent requests for the same
+// path and query share one upstream request, so many internal consumers cost one call.
+type upstream struct {
+	baseURL string
+	client  *http.Client
+	mirror  *mirror
+
+	mu       sync.Mutex
+	inflight map[string]*upstreamCall
+}
+
+type upstreamCall struct {
+	done chan struct{}
+	resp *mirroredResponse
+	err  error
+}
+
+func newUpstream(baseURL string, transport http.RoundTripper, m *mirror) *upstream {
+	return &upstream{
+		baseURL:  baseURL,
+		client:   &http.Client{Timeout: 30 * time.Second, Transport: transport},
+		mirror:   m,
+		inflight: map[string]*upstreamCall{},
+	}
+}
+
+// fetch returns the upstream response to an API path, storing successful responses in the
+// mirror. Error responses are returned but not stored.
+func (u *upstream) fetch(ctx context.Context, apiPath, rawQuery string) (*mirroredResponse, error) {
+	name, suffix, err := u.mirror.file(apiPath, rawQuery)
+	if err != nil {
+		return nil, err
+	}
+	key := name + suffix
+	u.mu.Lock()
+	call, ok := u.inflight[key]
+	if !ok {
+		call = &upstreamCall{done: make(chan struct{})}
+		u.inflight[key] = call
+		// The shared request must not be canceled when the first caller goes away
+		go func() {
+			call.resp, call.err = u.get(context.WithoutCancel(ctx), apiPath, rawQuery)
+			u.mu.Lock()
+			delete(u.inflight, key)
+			u.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	u.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.resp, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (u *upstream) get(ctx context.Context, apiPath, rawQuery string) (*mirroredResponse, error) {
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, err
+	}
+	rawQuery = query.Encode()
+	target := u.baseURL + "/" + apiPath
+	if rawQuery != "" {
+		target += "?" + rawQuery
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", apiPath, err)
+	}
+	header := resp.Header
+	if resp.StatusCode == http.StatusOK {
+		header = header.Clone()
+		provenanceOf(u.baseURL, apiPath, body).SetHeader(header)
+		if err := u.mirror.store(apiPath, rawQuery, resp.StatusCode, header, body); err != nil {
+			return nil, err
+		}
+	}
+	return &mirroredResponse{status: resp.StatusCode, header: header, body: body}, nil
+}
+
+// provenanceOf returns the provenance of a response fetched now, with the revision ID of
+// law_data responses
+func provenanceOf(baseURL, apiPath string, body []byte) lawapi.Provenance {
+	sum := sha256.Sum256(body)
+	p := lawapi.Provenance{
+		BaseURL:        baseURL,
+		FetchedAt:      lawapi.DefaultClock.Now(),
+		PackageVersion: lawapi.PackageVersion(),
+		ContentSHA256:  hex.EncodeToString(sum[:]),
+	}
+	if entries := lawEntries(body); strings.HasPrefix(apiPath, "law_data/") && len(entries) == 1 {
+		p.LawRevisionID = revisionOf(entries[0]).LawRevisionId
+	}
+	return p
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+func runAmend(args []string) error {
+	fs := flag.NewFlagSet("amend", flag.ExitOnError)
+	output := fs.String("o", "", "Output file (default: standard output)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: jplaw amend [options] <law> <instructions>")
+		fmt.Fprintln(fs.Output(), "\nApply amendment instructions (改め文) to a law and write the amended law XML.")
+		fmt.Fprintln(fs.Output(), "<law> is a law revision ID or a path to a law XML file. <instructions> is a text")
+		fmt.Fprintln(fs.Output(), "file with one instruction per line, or - for standard input. Instructions that")
+		fmt.Fprintln(fs.Output(), "cannot be applied are listed on standard error.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	law, err := loadLaw(newClient(), fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	var text []byte
+	if fs.Arg(1) == "-" {
+		text, err = io.ReadAll(os.Stdin)
+	} else {
+		text, err = os.ReadFile(fs.Arg(1))
+	}
+	if err != nil {
+		return err
+	}
+	report := law.ApplyAmendment(lawapi.ParseAmendmentInstructions(string(text)))
+
+	if err := writeOutput(*output, func(w io.Writer) error { return lawapi.WriteLawXML(w, law) }); err != nil {
+		return err
+	}
+	for _, u := range report.Unapplied {
+		fmt.Fprintf(os.Stderr, "not applied: %s (%s)\n", u.Instruction.Text, u.Reason)
+	}
+	if len(report.Unapplied) > 0 {
+		err := fmt.Errorf("%d of %d instructions not applied", len(report.Unapplied), report.Applied+len(report.Unapplied))
+		return lawapi.WithErrorCategory(err, lawapi.ErrorValidation)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// annotationsPath returns the path of the annotation store of a namespace, or without
+// namespace, $JPLAW_ANNOTATIONS_FILE or annotations.jsonl in the jplaw configuration
+// directory
+func annotationsPath(namespace string) (string, error) {
+	return storePath(namespace, "JPLAW_ANNOTATIONS_FILE", lawapi.AnnotationsFileName)
+}
+
+func openAnnotations(namespace string) (*lawapi.AnnotationStore, error) {
+	path, err := annotationsPath(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return lawapi.NewAnnotationStore(lawapi.AnnotationStoreOptions{Path: path})
+}
+
+func runAnnotations(args []string) error {
+	flags := flag.NewFlagSet("annotations", flag.ExitOnError)
+	kind := flags.String("kind", "", "Kind: bookmark, note or highlight (add: default note, or highlight with -quote)")
+	text := flags.String("text", "", "Note, or title of a bookmark (add)")
+	tags := flags.String("tags", "", "Comma-separated tags (add)")
+	quote := flags.String("quote", "", "Highlighted passage (add)")
+	lawID := flags.String("law-id", "", "Only annotations of this law (list)")
+	provisionID := flags.String("provision", "", "Only annotations of this provision and the provisions within it (list)")
+	tag := flags.String("tag", "", "Only annotations with this tag (list)")
+	namespace := addNamespaceFlag(flags)
+	output, maxWidth := addOutputFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), `Usage: jplaw annotations <command> [options] [arguments]
+
+Attach bookmarks, notes and highlights to provisions, identified by provision IDs
+(e.g. 325AC0000000131::4:1:).
+
+  add <provision-id>             Add an annotation and print its ID
+  remove <id>...                 Remove annotations
+  list                           List annotations
+  tags                           List the tags in use with their counts
+  export                         Write all annotations as JSON lines
+  import <file-or-url>...        Merge exported annotations, keeping the latest version
+                                 of each (e.g. http://mirror:8080/annotations)
+
+Options:`)
+		flags.PrintDefaults()
+	}
+	if len(args) < 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	command := args[0]
+	flags.Parse(args[1:])
+
+	store, err := openAnnotations(*namespace)
+	if err != nil {
+		return err
+	}
+	args = flags.Args()
+	switch {
+	case command == "add" && len(args) == 1:
+		a := lawapi.UserAnnotation{
+			ProvisionID: args[0],
+			Kind:        lawapi.UserAnnotationKind(*kind),
+			Text:        *text,
+			Tags:        splitTags(*tags),
+			Quote:       *quote,
+		}
+		if a.Kind == "" {
+			a.Kind = lawapi.AnnotationNote
+			if a.Quote != "" {
+				a.Kind = lawapi.AnnotationHighlight
+			}
+		}
+		a, err := store.Add(a)
+		if err != nil {
+			return err
+		}
+		fmt.Println(a.ID)
+		return nil
+
+	case command == "remove" && len(args) >= 1:
+		for _, id := range args {
+			if err := store.Remove(id); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case command == "list" && len(args) == 0:
+		annotations := store.List(lawapi.AnnotationFilter{
+			LawID:       *lawID,
+			ProvisionID: *provisionID,
+			Kind:        lawapi.UserAnnotationKind(*kind),
+			Tag:         *tag,
+		})
+		t := &lawapi.Table{Header: []string{"ID", "PROVISION ID", "KIND", "TEXT", "QUOTE", "TAGS", "UPDATED"}}
+		for _, a := range annotations {
+			t.Rows = append(t.Rows, []string{a.ID, a.ProvisionID, string(a.Kind), a.Text, a.Quote, strings.Join(a.Tags, ","), a.UpdatedAt.In(lawapi.JST).Format("2006-01-02 15:04")})
+		}
+		return writeResponse(*output, *maxWidth, t, annotations)
+
+	case command == "tags" && len(args) == 0:
+		counts := store.Tags()
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		t := &lawapi.Table{Header: []string{"TAG", "ANNOTATIONS"}}
+		for _, name := range names {
+			t.Rows = append(t.Rows, []string{name, strconv.Itoa(counts[name])})
+		}
+		return writeResponse(*output, *maxWidth, t, counts)
+
+	case command == "export" && len(args) == 0:
+		return store.Export(os.Stdout)
+
+	case command == "import" && len(args) >= 1:
+		for _, source := range args {
+			n, err := importAnnotations(store, source)
+			if err != nil {
+				return fmt.Errorf("%s: %w", source, err)
+			}
+			fmt.Printf("%s\t%d annotations added or updated\n", source, n)
+		}
+		return nil
+	}
+
+	flags.Usage()
+	os.Exit(2)
+	return nil
+}
+
+// importAnnotations imports the annotations exported to a file, or served by jplaw-serve
+// at an http or https URL
+func importAnnotations(store *lawapi.AnnotationStore, source string) (int, error) {
+	var r io.Reader
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, source, nil)
+		if err != nil {
+			return 0, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return 0, lawapi.WithErrorCategory(err, lawapi.ErrorNetwork)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return 0, lawapi.WithErrorCategory(fmt.Errorf("unexpected status %s", resp.Status), lawapi.ErrorNetwork)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(source)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return store.Import(r)
+}
+
+func splitTags(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+func runClassify(args []string) error {
+	flags := flag.NewFlagSet("classify", flag.ExitOnError)
+	rulesFile := flags.String("rules", "", "YAML file of additional classifier rules")
+	noDefaults := flags.Bool("no-default-rules", false, "Use only the rules of -rules")
+	minConfidence := flags.Float64("min-confidence", 0.5, "Leave out classes with a lower confidence")
+	lawID := flags.String("law-id", "", "Law ID of the provision IDs (default: from the revision or argument)")
+	output, maxWidth := addOutputFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), `Usage: jplaw classify [options] <law-id-or-revision-id-or-xml-file>
+
+Classify the sentences of a law as obligation, prohibition, permission, definition or
+penalty, with confidence scores.
+
+Options:`)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 || (*noDefaults && *rulesFile == "") {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	classifier := &lawapi.SentenceClassifier{}
+	if !*noDefaults {
+		var err error
+		if classifier, err = lawapi.NewSentenceClassifier(); err != nil {
+			return err
+		}
+	}
+	if *rulesFile != "" {
+		f, err := os.Open(*rulesFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := classifier.Load(f); err != nil {
+			return lawapi.WithErrorCategory(fmt.Errorf("%s: %w", *rulesFile, err), lawapi.ErrorValidation)
+		}
+	}
+
+	arg := flags.Arg(0)
+	law, err := loadLaw(newClient(), arg)
+	if err != nil {
+		return err
+	}
+	if *lawID == "" {
+		*lawID = lawIDOf(law, arg)
+	}
+	sentences := classifier.ClassifyLaw(law, *lawID, law.Provenance().LawRevisionID, *minConfidence)
+
+	t := &lawapi.Table{Header: []string{"PROVISION ID", "PROVISION", "CLASSES", "TEXT"}}
+	for _, s := range sentences {
+		var classes []string
+		for _, c := range s.Classes {
+			classes = append(classes, fmt.Sprintf("%s:%.2f", c.Class, c.Confidence))
+		}
+		t.Rows = append(t.Rows, []string{s.ProvisionID, s.Provision, strings.Join(classes, " "), s.Text})
+	}
+	return writeResponse(*output, *maxWidth, t, sentences)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+func runCompare(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	output := fs.String("o", "", "Output file (default: standard output)")
+	format := fs.String("format", "", "Output format: html or docx (default: from the output file extension, else html)")
+	all := fs.Bool("all", false, "Include unchanged articles")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: jplaw compare [options] <old> <new>")
+		fmt.Fprintln(fs.Output(), "\n<old> and <new> are law revision IDs or paths to law XML files.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	client := newClient()
+	oldLaw, err := loadLaw(client, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	newLaw, err := loadLaw(client, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	table := lawapi.GenerateComparisonTable(oldLaw, newLaw)
+
+	if *format == "" && strings.EqualFold(filepath.Ext(*output), ".docx") {
+		*format = "docx"
+	}
+	switch *format {
+	case "", "html":
+		notice, err := lawapi.Notice{Titles: []string{newLaw.Title()}, Edited: true}.Text()
+		if err != nil {
+			return err
+		}
+		var page strings.Builder
+		err = comparisonTemplate.Execute(&page, map[string]interface{}{
+			"Title":    newLaw.Title(),
+			"OldLabel": fs.Arg(0),
+			"NewLabel": fs.Arg(1),
+			"Rows":     compareRows(table, *all),
+			"Notice":   notice,
+		})
+		if err != nil {
+			return err
+		}
+		html := page.String()
+		if lawapi.HTMLOutputPolicy != nil {
+			html = lawapi.HTMLOutputPolicy.Sanitize(html)
+		}
+		return writeOutput(*output, func(w io.Writer) error {
+			_, err := io.WriteString(w, html)
+			return err
+		})
+	case "docx":
+		if !*all {
+			var changed []lawapi.ComparisonRow
+			for _, row := range table {
+				if row.Status != lawapi.ComparisonUnchanged {
+					changed = append(changed, row)
+				}
+			}
+			table = changed
+		}
+		return writeOutput(*output, func(w io.Writer) error {
+			return lawapi.WriteComparisonDOCX(w, newLaw.Title(), table)
+		})
+	default:
+		return lawapi.WithErrorCategory(fmt.Errorf("unknown format %q", *format), lawapi.ErrorValidation)
+	}
+}
+
+// loadLaw reads a law XML file if the argument is an existing path, or retrieves the revision from the API
+func loadLaw(client *lawapi.Client, arg string) (*lawapi.LawNode, error) {
+	if f, err := os.Open(arg); err == nil {
+		defer f.Close()
+		return lawapi.ParseLawXML(f)
+	}
+	law, err := client.GetLawTree(context.Background(), arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve %s: %w", arg, err)
+	}
+	return law, nil
+}
+
+// compareRow is one row of the rendered table
+type compareRow struct {
+	Label string
+	Old   []lawapi.DiffSegment
+	New   []lawapi.DiffSegment
+}
+
+// compareRows converts comparison rows for rendering, marking added and deleted articles
+func compareRows(table []lawapi.ComparisonRow, includeUnchanged bool) []compareRow {
+	var rows []compareRow
+	for _, row := range table {
+		r := compareRow{Label: row.Label, Old: row.Old, New: row.New}
+		switch row.Status {
+		case lawapi.ComparisonUnchanged:
+			if !includeUnchanged {
+				continue
+			}
+		case lawapi.ComparisonAdded:
+			r.Old = []lawapi.DiffSegment{{Text: "（新設）"}}
+		case lawapi.ComparisonDeleted:
+			r.New = []lawapi.DiffSegment{{Text: "（削除）"}}
+		}
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+var comparisonTemplate = template.Must(template.New("compare").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} 新旧対照表</title>
+<style>
+body { font-family: serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; table-layout: fixed; }
+th, td { border: 1px solid #444; padding: 0.5em; vertical-align: top; white-space: pre-wrap; }
+th { background: #eee; }
+td.provision { width: 8em; white-space: nowrap; }
+.notice { margin-top: 2em; font-size: smaller; white-space: pre-wrap; }
+.changed { text-decoration: underline; background: #fff3a8; }
+</style>
+</head>
+<body>
+<h1>{{.Title}} 新旧対照表</h1>
+<table>
+<thead><tr><th class="provision"></th><th>改正後（{{.NewLabel}}）</th><th>改正前（{{.OldLabel}}）</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr><td class="provision">{{.Label}}</td><td>{{range .New}}{{if .Changed}}<span class="changed">{{.Text}}</span>{{else}}{{.Text}}{{end}}{{end}}</td><td>{{range .Old}}{{if .Changed}}<span class="changed">{{.Text}}</span>{{else}}{{.Text}}{{end}}{{end}}</td></tr>
+{{- else}}
+<tr><td colspan="3">変更はありません。</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- with .Notice}}
+<footer class="notice">{{.}}</footer>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// appliedDependency is a provision applied by a dependency, as written by
+// jplaw dependencies -applied
+type appliedDependency struct {
+	// From is the provision stating the dependency
+	From string `json:"from"`
+	lawapi.AppliedProvision
+	// Unmatched are the replacements of the dependency whose words were not found
+	Unmatched []lawapi.TextReplacement `json:"unmatched,omitempty"`
+}
+
+func runDependencies(args []string) error {
+	flags := flag.NewFlagSet("dependencies", flag.ExitOnError)
+	lawID := flags.String("law-id", "", "Law ID of the provision IDs (default: from the revision or argument)")
+	applied := flags.Bool("applied", false, "Write the applied provisions as they read with the replacements made")
+	output, maxWidth := addOutputFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), `Usage: jplaw dependencies [options] <law-id-or-revision-id-or-xml-file>
+
+List the provisions of a law applying other provisions mutatis mutandis (準用) or
+reading their words differently (読替え), with the replacements. With -applied, write
+the text of the applied provisions as it reads with the replacements made.
+
+Options:`)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	arg := flags.Arg(0)
+	law, err := loadLaw(newClient(), arg)
+	if err != nil {
+		return err
+	}
+	if *lawID == "" {
+		*lawID = lawIDOf(law, arg)
+	}
+	deps := lawapi.ExtractDependencies(law, *lawID, law.Provenance().LawRevisionID)
+
+	if *applied {
+		var provisions []appliedDependency
+		t := &lawapi.Table{Header: []string{"FROM", "PROVISION ID", "PROVISION", "TEXT", "UNMATCHED"}}
+		for _, dep := range deps {
+			appliedProvisions, unmatched := law.AsApplied(dep)
+			var words []string
+			for _, rep := range unmatched {
+				words = append(words, rep.Old)
+			}
+			for _, p := range appliedProvisions {
+				provisions = append(provisions, appliedDependency{From: dep.Provision, AppliedProvision: p, Unmatched: unmatched})
+				t.Rows = append(t.Rows, []string{dep.Provision, p.ProvisionID, p.Provision, p.Text, strings.Join(words, "\n")})
+			}
+		}
+		return writeResponse(*output, *maxWidth, t, provisions)
+	}
+
+	t := &lawapi.Table{Header: []string{"PROVISION ID", "PROVISION", "KIND", "TO", "REPLACEMENTS"}}
+	for _, dep := range deps {
+		var to, replacements []string
+		for _, ref := range dep.To {
+			to = append(to, ref.Provision)
+		}
+		for _, rep := range dep.Replacements {
+			replacements = append(replacements, strings.TrimSpace(rep.Provision+" 「"+rep.Old+"」→「"+rep.New+"」"))
+		}
+		t.Rows = append(t.Rows, []string{dep.ProvisionID, dep.Provision, string(dep.Kind), strings.Join(to, "\n"), strings.Join(replacements, "\n")})
+	}
+	return writeResponse(*output, *maxWidth, t, deps)
+}
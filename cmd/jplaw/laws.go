@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// outputFormats are the values of the -output flag of commands printing API responses
+const outputFormats = "table, csv or json"
+
+// addOutputFlags adds the flags selecting the output format
+func addOutputFlags(flags *flag.FlagSet) (output *string, maxWidth *int) {
+	output = flags.String("output", "table", "Output format: "+outputFormats)
+	maxWidth = flags.Int("max-width", 40, "Truncate table cells wider than this many columns (0: no limit)")
+	return output, maxWidth
+}
+
+// writeResponse writes a response as an aligned table, as CSV with the same columns, or as
+// JSON of v
+func writeResponse(format string, maxWidth int, t *lawapi.Table, v any) error {
+	switch format {
+	case "table":
+		t.MaxColumnWidth = maxWidth
+		// Terminals in Japanese locales draw ambiguous-width characters wide
+		t.AmbiguousWide = lang == lawapi.LanguageJapanese
+		return t.Write(os.Stdout)
+	case "csv":
+		cw := csv.NewWriter(os.Stdout)
+		cw.Write(t.Header)
+		cw.WriteAll(t.Rows)
+		return cw.Error()
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	return lawapi.WithErrorCategory(fmt.Errorf("unknown output format %q (expected %s)", format, outputFormats), lawapi.ErrorValidation)
+}
+
+func runLaws(args []string) error {
+	flags := flag.NewFlagSet("laws", flag.ExitOnError)
+	title := flags.String("title", "", "Search laws whose title contains this text")
+	categories := flags.String("category", "", "Comma-separated category codes (e.g. 015)")
+	lawTypes := flags.String("type", "", "Comma-separated law types (e.g. Act,CabinetOrder)")
+	limit := flags.Int("limit", 20, "Maximum number of laws")
+	output, maxWidth := addOutputFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), `Usage: jplaw laws [options]
+
+Search laws and print them as a table, CSV or JSON records.
+
+Options:`)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	params := &lawapi.GetLawsParams{Limit: lawapi.Int32Ptr(int32(*limit))}
+	if *title != "" {
+		params.LawTitle = title
+	}
+	if *categories != "" {
+		var codes []lawapi.CategoryCd
+		for _, code := range strings.Split(*categories, ",") {
+			codes = append(codes, lawapi.CategoryCd(strings.TrimSpace(code)))
+		}
+		params.CategoryCd = &codes
+	}
+	if *lawTypes != "" {
+		var types []lawapi.LawType
+		for _, t := range strings.Split(*lawTypes, ",") {
+			types = append(types, lawapi.LawType(strings.TrimSpace(t)))
+		}
+		params.LawType = &types
+	}
+	result, err := newClient().GetLawsWithContext(context.Background(), params)
+	if err != nil {
+		return err
+	}
+	return writeResponse(*output, *maxWidth, result.Table(), result.Flatten())
+}
+
+func runRevisions(args []string) error {
+	flags := flag.NewFlagSet("revisions", flag.ExitOnError)
+	output, maxWidth := addOutputFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), `Usage: jplaw revisions [options] <law-id-or-num>
+
+Print the revision history of a law as a table, CSV or JSON.
+
+Options:`)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	result, err := newClient().GetRevisionsWithContext(context.Background(), flags.Arg(0), nil)
+	if err != nil {
+		return err
+	}
+	return writeResponse(*output, *maxWidth, result.Table(), result.Revisions)
+}
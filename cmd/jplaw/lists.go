@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// listsPath returns the path of the watch list file of a namespace, or without
+// namespace, $JPLAW_LISTS_FILE or lists.yaml in the jplaw configuration directory
+func listsPath(namespace string) (string, error) {
+	return storePath(namespace, "JPLAW_LISTS_FILE", lawapi.WatchListsFileName)
+}
+
+func loadLists(namespace string) (lawapi.WatchLists, error) {
+	path, err := listsPath(namespace)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lawapi.WatchLists{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	lists, err := lawapi.LoadWatchLists(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return lists, nil
+}
+
+func saveLists(namespace string, lists lawapi.WatchLists) error {
+	path, err := listsPath(namespace)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := lists.Save(&buf); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return lawapi.WriteFileAtomic(path, buf.Bytes(), 0o644)
+}
+
+func runList(args []string) error {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	title := flags.Bool("title", false, "Treat arguments as title queries instead of law IDs (add, remove)")
+	namespace := addNamespaceFlag(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), `Usage: jplaw list <command> [options] [arguments]
+
+Manage named watch lists of laws, given by law ID or by title query.
+
+  add <list> <law-id>...       Add laws to a list, creating it if needed
+  remove <list> [<law-id>...]  Remove laws from a list, or the whole list
+  show [<list>]                Show all lists, or the entries of one list
+  resolve <list>               Print the law IDs of a list, searching its title queries
+
+Options:`)
+		flags.PrintDefaults()
+	}
+	if len(args) < 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	command := args[0]
+	flags.Parse(args[1:])
+
+	lists, err := loadLists(*namespace)
+	if err != nil {
+		return err
+	}
+	args = flags.Args()
+	switch {
+	case command == "add" && len(args) >= 2:
+		list := lists[args[0]]
+		if list == nil {
+			list = &lawapi.WatchList{}
+			lists[args[0]] = list
+		}
+		entries := &list.LawIDs
+		if *title {
+			entries = &list.Titles
+		}
+		for _, entry := range args[1:] {
+			if !slices.Contains(*entries, entry) {
+				*entries = append(*entries, entry)
+			}
+		}
+		return saveLists(*namespace, lists)
+
+	case command == "remove" && len(args) >= 1:
+		list := lists[args[0]]
+		if list == nil {
+			return lawapi.WithErrorCategory(fmt.Errorf("list %q does not exist", args[0]), lawapi.ErrorNotFound)
+		}
+		if len(args) == 1 {
+			delete(lists, args[0])
+			return saveLists(*namespace, lists)
+		}
+		entries := &list.LawIDs
+		if *title {
+			entries = &list.Titles
+		}
+		for _, entry := range args[1:] {
+			i := slices.Index(*entries, entry)
+			if i < 0 {
+				return lawapi.WithErrorCategory(fmt.Errorf("%q is not in list %q", entry, args[0]), lawapi.ErrorNotFound)
+			}
+			*entries = slices.Delete(*entries, i, i+1)
+		}
+		return saveLists(*namespace, lists)
+
+	case command == "show" && len(args) == 0:
+		names := make([]string, 0, len(lists))
+		for name := range lists {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%s\t%d laws, %d title queries\n", name, len(lists[name].LawIDs), len(lists[name].Titles))
+		}
+		return nil
+
+	case command == "show" && len(args) == 1:
+		list := lists[args[0]]
+		if list == nil {
+			return lawapi.WithErrorCategory(fmt.Errorf("list %q does not exist", args[0]), lawapi.ErrorNotFound)
+		}
+		for _, id := range list.LawIDs {
+			fmt.Println(id)
+		}
+		for _, t := range list.Titles {
+			fmt.Printf("title:%s\n", t)
+		}
+		return nil
+
+	case command == "resolve" && len(args) == 1:
+		list := lists[args[0]]
+		if list == nil {
+			return lawapi.WithErrorCategory(fmt.Errorf("list %q does not exist", args[0]), lawapi.ErrorNotFound)
+		}
+		ids, err := newClient().ResolveWatchList(context.Background(), list)
+		if err != nil {
+			return err
+		}
+		fmt.Println(strings.Join(ids, "\n"))
+		return nil
+	}
+
+	flags.Usage()
+	os.Exit(2)
+	return nil
+}
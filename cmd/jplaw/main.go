@@ -0,0 +1,238 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+const usageTextEn = `Usage: jplaw <command> [options] [arguments]
+
+Commands:
+  amend        Apply amendment instructions (改め文) to a law and write the amended XML
+  annotations  Attach bookmarks, notes and highlights to provisions
+  classify     Classify the sentences of a law as obligations, permissions, definitions, ...
+  compare      Render an HTML side-by-side comparison (新旧対照表) of two revisions
+  dependencies List provisions applying others mutatis mutandis (準用) with replacements (読替え)
+  laws         Search laws and print them as a table, CSV or JSON
+  list         Manage named watch lists of laws
+  manifest     Create or verify signed manifests of mirror and export directories
+  namespaces   Manage, export and import the namespaces of annotations, pins and lists
+  obligations  List the duties and prohibitions of a law as a compliance checklist
+  patches      Write JSON Patches between consecutive revisions of a law
+  pin          Pin laws to revisions and report pins with newer revisions
+  revisions    Print the revision history of a law as a table, CSV or JSON
+  search       Search the laws of a mirror directory with a boolean query
+  usage        Enable, inspect or export opt-in local usage counts
+  validate     Check law XML against the structure of the law XML schema
+  verify       Check revisions against their amending laws
+
+Exit status:
+  0  success
+  1  internal error
+  2  usage error
+  3  network error or server error (5xx)
+  4  law, revision, provision, file or list not found
+  5  rate limited (429) or request budget exhausted
+  6  validation failure: invalid input, schema violations, failed checks
+
+Environment:
+  JPLAW_LANG  language of help and error messages (ja or en; default from LANG)
+`
+
+const usageTextJa = `使い方: jplaw <コマンド> [オプション] [引数]
+
+コマンド:
+  amend        改め文を法令に適用し、改正後の XML を出力する
+  annotations  条項にブックマーク・メモ・ハイライトを付ける
+  classify     法令の文を義務・禁止・許可・定義・罰則に分類する
+  compare      二つの版の新旧対照表を HTML で出力する
+  dependencies 法令中の準用・読替え規定と準用される規定を出力する
+  laws         法令を検索し、表・CSV・JSON で出力する
+  list         法令のウォッチリストを管理する
+  manifest     ミラー・エクスポートのディレクトリの署名付きマニフェストを作成・検証する
+  namespaces   注釈・固定・リストの名前空間を管理・エクスポート・インポートする
+  obligations  法令の義務・禁止規定をコンプライアンスのチェックリストとして出力する
+  patches      法令の連続する版の間の JSON Patch を出力する
+  pin          法令を版に固定し、新しい版がある固定を報告する
+  revisions    法令の改正履歴を表・CSV・JSON で出力する
+  search       ミラーのディレクトリの法令を検索式で検索する
+  usage        ローカルの利用統計（オプトイン）を有効化・表示・出力する
+  validate     法令 XML を法令標準 XML スキーマの構造に照らして検査する
+  verify       版を改正法令と照合して検査する
+
+終了ステータス:
+  0  成功
+  1  内部エラー
+  2  使い方の誤り
+  3  ネットワークエラー、サーバーエラー（5xx）
+  4  法令・版・条項・ファイル・リストが見つからない
+  5  リクエスト制限（429）、リクエスト数の上限
+  6  検証エラー：不正な入力、スキーマ違反、検査の失敗
+
+環境変数:
+  JPLAW_LANG  ヘルプとエラーメッセージの言語（ja または en、既定は LANG による）
+`
+
+// lang is the language of help and error messages
+var lang = language()
+
+// language returns the language selected by $JPLAW_LANG or the locale environment
+// variables, or English
+func language() lawapi.Language {
+	for _, env := range []string{"JPLAW_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(env); value != "" {
+			if l, ok := lawapi.ParseLanguage(value); ok {
+				return l
+			}
+			if env == "JPLAW_LANG" {
+				continue
+			}
+			// The first locale variable set takes effect, as with setlocale
+			break
+		}
+	}
+	return lawapi.LanguageEnglish
+}
+
+// usageText returns the help text in the selected language
+func usageText() string {
+	if lang == lawapi.LanguageJapanese {
+		return usageTextJa
+	}
+	return usageTextEn
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usageText())
+		os.Exit(2)
+	}
+
+	command := os.Args[1]
+	if command != "usage" {
+		var err error
+		if usage, err = loadUsage(); err != nil {
+			fmt.Fprintf(os.Stderr, "jplaw: ignoring usage stats: %v\n", err)
+		} else if usage != nil {
+			usage.recordCommand(command)
+		}
+	}
+
+	if err := loadNoticeTemplate(); err != nil {
+		fmt.Fprintf(os.Stderr, "jplaw: %s\n", lawapi.LocalizeError(err, lang))
+		os.Exit(lawapi.ErrorCategoryOf(err).ExitCode())
+	}
+
+	var err error
+	switch command {
+	case "amend":
+		err = runAmend(os.Args[2:])
+	case "annotations":
+		err = runAnnotations(os.Args[2:])
+	case "classify":
+		err = runClassify(os.Args[2:])
+	case "compare":
+		err = runCompare(os.Args[2:])
+	case "dependencies":
+		err = runDependencies(os.Args[2:])
+	case "laws":
+		err = runLaws(os.Args[2:])
+	case "list":
+		err = runList(os.Args[2:])
+	case "manifest":
+		err = runManifest(os.Args[2:])
+	case "namespaces":
+		err = runNamespaces(os.Args[2:])
+	case "obligations":
+		err = runObligations(os.Args[2:])
+	case "patches":
+		err = runPatches(os.Args[2:])
+	case "pin":
+		err = runPin(os.Args[2:])
+	case "revisions":
+		err = runRevisions(os.Args[2:])
+	case "search":
+		err = runSearch(os.Args[2:])
+	case "usage":
+		err = runUsage(os.Args[2:])
+	case "validate":
+		err = runValidate(os.Args[2:])
+	case "verify":
+		err = runVerify(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usageText())
+		return
+	default:
+		if lang == lawapi.LanguageJapanese {
+			fmt.Fprintf(os.Stderr, "不明なコマンド %q\n\n%s", command, usageText())
+		} else {
+			fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", command, usageText())
+		}
+		os.Exit(2)
+	}
+	if usage != nil {
+		if err := usage.save(); err != nil {
+			fmt.Fprintf(os.Stderr, "jplaw: failed to save usage stats: %v\n", err)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jplaw %s: %s\n", command, lawapi.LocalizeError(err, lang))
+		os.Exit(lawapi.ErrorCategoryOf(err).ExitCode())
+	}
+}
+
+// configPath returns the path of a configuration file: the value of the environment
+// variable env if set, or name in the jplaw directory of the user configuration directory
+func configPath(env, name string) (string, error) {
+	if path := os.Getenv(env); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "jplaw", name), nil
+}
+
+// writeOutput writes the output of a command to a file atomically, so that a failed run
+// leaves any previous file intact, or to standard output if path is empty
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+	return lawapi.WriteAtomic(path, 0o644, write)
+}
+
+// loadNoticeTemplate replaces the notice embedded in generated documents with the template
+// in $JPLAW_NOTICE_FILE, or notice.tmpl in the jplaw configuration directory, if it exists.
+// An empty template leaves notices out.
+func loadNoticeTemplate() error {
+	path, err := configPath("JPLAW_NOTICE_FILE", "notice.tmpl")
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		lawapi.NoticeTemplate = nil
+		return nil
+	}
+	tmpl, err := template.New("notice").Parse(string(data))
+	if err != nil {
+		return lawapi.WithErrorCategory(fmt.Errorf("invalid notice template %s: %w", path, err), lawapi.ErrorValidation)
+	}
+	lawapi.NoticeTemplate = tmpl
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+func runManifest(args []string) error {
+	flags := flag.NewFlagSet("manifest", flag.ExitOnError)
+	keyFile := flags.String("key", os.Getenv("JPLAW_MANIFEST_KEY"), "PEM ed25519 private key to sign with (create; default $JPLAW_MANIFEST_KEY)")
+	pubFile := flags.String("pub", os.Getenv("JPLAW_MANIFEST_PUB"), "Trusted PEM ed25519 public key (verify; default $JPLAW_MANIFEST_PUB)")
+	asJSON := flags.Bool("json", false, "Write the mismatching files as JSON lines (verify)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), `Usage: jplaw manifest <command> [options] [arguments]
+
+List the files of a snapshot directory, such as a jplaw-serve mirror or exported
+documents, with their SHA-256 hashes in MANIFEST.json, optionally signed with ed25519.
+
+  keygen <name>   Write a new key pair to <name>.key and <name>.pub
+  create <dir>    Write the manifest of a directory, signed with -key if given
+  verify <dir>    Check the manifest signature with -pub, then the files of a directory
+
+Options:`)
+		flags.PrintDefaults()
+	}
+	if len(args) < 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	command := args[0]
+	flags.Parse(args[1:])
+
+	args = flags.Args()
+	switch {
+	case command == "keygen" && len(args) == 1:
+		private, public, err := lawapi.GenerateManifestKey()
+		if err != nil {
+			return err
+		}
+		// Never overwrite an existing key
+		f, err := os.OpenFile(args[0]+".key", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(private); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return lawapi.WriteFileAtomic(args[0]+".pub", public, 0o644)
+
+	case command == "create" && len(args) == 1:
+		m, err := lawapi.BuildManifest(args[0])
+		if err != nil {
+			return err
+		}
+		if *keyFile != "" {
+			data, err := os.ReadFile(*keyFile)
+			if err != nil {
+				return err
+			}
+			key, err := lawapi.ParseManifestPrivateKey(data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", *keyFile, err)
+			}
+			if err := m.Sign(key); err != nil {
+				return err
+			}
+		}
+		if err := lawapi.WriteManifest(args[0], m); err != nil {
+			return err
+		}
+		if m.KeyID != "" {
+			fmt.Printf("Listed %d files, signed with key %s\n", len(m.Files), m.KeyID)
+		} else {
+			fmt.Printf("Listed %d files, unsigned\n", len(m.Files))
+		}
+		return nil
+
+	case command == "verify" && len(args) == 1 && *pubFile != "":
+		data, err := os.ReadFile(*pubFile)
+		if err != nil {
+			return err
+		}
+		key, err := lawapi.ParseManifestPublicKey(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", *pubFile, err)
+		}
+		mismatches, err := lawapi.VerifySnapshot(args[0], key)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(os.Stdout)
+		for _, mismatch := range mismatches {
+			if *asJSON {
+				if err := enc.Encode(mismatch); err != nil {
+					return err
+				}
+				continue
+			}
+			fmt.Println(mismatch)
+		}
+		if len(mismatches) > 0 {
+			return lawapi.WithErrorCategory(fmt.Errorf("%d files differ from the manifest", len(mismatches)), lawapi.ErrorValidation)
+		}
+		return nil
+	}
+
+	flags.Usage()
+	os.Exit(2)
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// namespacesRoot returns the directory of the namespaces: $JPLAW_NAMESPACES_DIR, or
+// namespaces in the jplaw configuration directory
+func namespacesRoot() (string, error) {
+	return configPath("JPLAW_NAMESPACES_DIR", "namespaces")
+}
+
+func openNamespaces() (*lawapi.Namespaces, error) {
+	root, err := namespacesRoot()
+	if err != nil {
+		return nil, err
+	}
+	return lawapi.NewNamespaces(root), nil
+}
+
+// addNamespaceFlag defines the -namespace flag of the commands storing data
+func addNamespaceFlag(flags *flag.FlagSet) *string {
+	return flags.String("namespace", os.Getenv("JPLAW_NAMESPACE"), "Namespace (tenant or user) of the data (default $JPLAW_NAMESPACE, or none)")
+}
+
+// storePath returns the path of a data file of a namespace, or without namespace, the
+// file in $env or name in the jplaw configuration directory
+func storePath(namespace, env, name string) (string, error) {
+	if namespace == "" {
+		return configPath(env, name)
+	}
+	namespaces, err := openNamespaces()
+	if err != nil {
+		return "", err
+	}
+	dir, err := namespaces.Dir(namespace)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+func runNamespaces(args []string) error {
+	flags := flag.NewFlagSet("namespaces", flag.ExitOnError)
+	outputPath := flags.String("o", "", "Write the export to this file (export; default: standard output)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), `Usage: jplaw namespaces <command> [options] [arguments]
+
+Manage the namespaces partitioning annotations, pins and watch lists by tenant or
+user. The annotations, pin and list commands use the namespace of their -namespace
+option or $JPLAW_NAMESPACE. Namespaces are stored in $JPLAW_NAMESPACES_DIR, or the
+namespaces directory of the jplaw configuration directory.
+
+  list                      List the namespaces
+  export <namespace>        Write the annotations, pins and watch lists of a namespace as JSON
+  import <namespace> [file] Merge an export into a namespace (default: standard input)
+  remove <namespace>        Delete a namespace with all its data
+
+Options:`)
+		flags.PrintDefaults()
+	}
+	if len(args) < 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	command := args[0]
+	flags.Parse(args[1:])
+
+	namespaces, err := openNamespaces()
+	if err != nil {
+		return err
+	}
+	args = flags.Args()
+	switch {
+	case command == "list" && len(args) == 0:
+		names, err := namespaces.List()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+
+	case command == "export" && len(args) == 1:
+		return writeOutput(*outputPath, func(w io.Writer) error {
+			return namespaces.Export(args[0], w)
+		})
+
+	case command == "import" && (len(args) == 1 || len(args) == 2):
+		var r io.Reader = os.Stdin
+		if len(args) == 2 {
+			f, err := os.Open(args[1])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		return namespaces.Import(args[0], r)
+
+	case command == "remove" && len(args) == 1:
+		return namespaces.Remove(args[0])
+	}
+
+	flags.Usage()
+	os.Exit(2)
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+func runObligations(args []string) error {
+	flags := flag.NewFlagSet("obligations", flag.ExitOnError)
+	lawID := flags.String("law-id", "", "Law ID of the provision IDs (default: from the revision or argument)")
+	output, maxWidth := addOutputFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), `Usage: jplaw obligations [options] <law-id-or-revision-id-or-xml-file>
+
+List the duties (しなければならない) and prohibitions (してはならない) of a law as a
+compliance checklist: provision, kind, subject and text.
+
+Options:`)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	arg := flags.Arg(0)
+	law, err := loadLaw(newClient(), arg)
+	if err != nil {
+		return err
+	}
+	revision := law.Provenance().LawRevisionID
+	if *lawID == "" {
+		*lawID = lawIDOf(law, arg)
+	}
+
+	obligations := lawapi.ExtractObligations(law, *lawID, revision)
+	t := &lawapi.Table{Header: []string{"PROVISION ID", "PROVISION", "KIND", "SUBJECT", "TEXT", "ITEMS"}}
+	for _, o := range obligations {
+		t.Rows = append(t.Rows, []string{o.ProvisionID, o.Provision, string(o.Kind), o.Subject, o.Text, strings.Join(o.Items, "\n")})
+	}
+	return writeResponse(*output, *maxWidth, t, obligations)
+}
+
+// lawIDOf returns the law ID of a law loaded by loadLaw from arg: from its revision ID,
+// or arg itself if it is not a file
+func lawIDOf(law *lawapi.LawNode, arg string) string {
+	if lawID, _, _ := strings.Cut(law.Provenance().LawRevisionID, "_"); lawID != "" {
+		return lawID
+	}
+	if _, err := os.Stat(arg); err != nil {
+		return arg
+	}
+	return ""
+}
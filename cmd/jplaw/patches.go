@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+func runPatches(args []string) error {
+	flags := flag.NewFlagSet("patches", flag.ExitOnError)
+	since := flags.String("since", "", "Start from this revision ID instead of adding the whole first revision")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), `Usage: jplaw patches [options] <law-id>
+
+Write a JSON Patch (RFC 6902) per revision of a law as JSON lines, each turning the law
+data of the preceding revision into that of the revision.
+
+Options:`)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	return newClient().RevisionPatches(context.Background(), flags.Arg(0), *since, func(patch lawapi.RevisionPatch) error {
+		return enc.Encode(patch)
+	})
+}
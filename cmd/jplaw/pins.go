@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// pinsPath returns the path of the pin file of a namespace, or without namespace,
+// $JPLAW_PINS_FILE or pins.yaml in the jplaw configuration directory
+func pinsPath(namespace string) (string, error) {
+	return storePath(namespace, "JPLAW_PINS_FILE", lawapi.PinsFileName)
+}
+
+func loadPins(namespace string) (lawapi.Pins, error) {
+	path, err := pinsPath(namespace)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lawapi.Pins{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	pins, err := lawapi.LoadPins(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return pins, nil
+}
+
+func savePins(namespace string, pins lawapi.Pins) error {
+	path, err := pinsPath(namespace)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := pins.Save(&buf); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return lawapi.WriteFileAtomic(path, buf.Bytes(), 0o644)
+}
+
+func runPin(args []string) error {
+	flags := flag.NewFlagSet("pin", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "Write the statuses as JSON lines (check)")
+	namespace := addNamespaceFlag(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), `Usage: jplaw pin <command> [options] [arguments]
+
+Pin laws to the revisions an application was validated against.
+
+  add <law-id> [<revision-id>]  Pin a law to a revision, or to its revision in force
+  remove <law-id>...            Remove pins
+  show                          Show all pins
+  check                         Report pins with newer revisions; fails if there are any
+
+Options:`)
+		flags.PrintDefaults()
+	}
+	if len(args) < 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	command := args[0]
+	flags.Parse(args[1:])
+
+	pins, err := loadPins(*namespace)
+	if err != nil {
+		return err
+	}
+	args = flags.Args()
+	switch {
+	case command == "add" && len(args) == 1:
+		revisionID, err := newClient().PinCurrent(context.Background(), pins, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(revisionID)
+		return savePins(*namespace, pins)
+
+	case command == "add" && len(args) == 2:
+		if !strings.HasPrefix(args[1], args[0]+"_") {
+			return lawapi.WithErrorCategory(fmt.Errorf("%s is not a revision ID of %s", args[1], args[0]), lawapi.ErrorValidation)
+		}
+		pins[args[0]] = args[1]
+		return savePins(*namespace, pins)
+
+	case command == "remove" && len(args) >= 1:
+		for _, lawID := range args {
+			if _, ok := pins[lawID]; !ok {
+				return lawapi.WithErrorCategory(fmt.Errorf("%s is not pinned", lawID), lawapi.ErrorNotFound)
+			}
+			delete(pins, lawID)
+		}
+		return savePins(*namespace, pins)
+
+	case command == "show" && len(args) == 0:
+		lawIDs := make([]string, 0, len(pins))
+		for lawID := range pins {
+			lawIDs = append(lawIDs, lawID)
+		}
+		slices.Sort(lawIDs)
+		for _, lawID := range lawIDs {
+			fmt.Printf("%s\t%s\n", lawID, pins[lawID])
+		}
+		return nil
+
+	case command == "check" && len(args) == 0:
+		statuses, err := newClient().CheckPins(context.Background(), pins)
+		if err != nil {
+			return err
+		}
+		failed := 0
+		enc := json.NewEncoder(os.Stdout)
+		for _, status := range statuses {
+			if status.Outdated() || status.Error != "" {
+				failed++
+			}
+			if *asJSON {
+				if err := enc.Encode(status); err != nil {
+					return err
+				}
+				continue
+			}
+			switch {
+			case status.Error != "":
+				fmt.Printf("%s\terror: %s\n", status.LawID, status.Error)
+			case status.Outdated():
+				fmt.Printf("%s\t%d newer revisions (pinned %s, in force %s)\n", status.LawID, len(status.Newer), status.RevisionID, status.CurrentRevisionID)
+			default:
+				fmt.Printf("%s\tup to date\n", status.LawID)
+			}
+		}
+		if failed > 0 {
+			return lawapi.WithErrorCategory(fmt.Errorf("%d pins are outdated or could not be checked", failed), lawapi.ErrorValidation)
+		}
+		return nil
+	}
+
+	flags.Usage()
+	os.Exit(2)
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+func runSearch(args []string) error {
+	flags := flag.NewFlagSet("search", flag.ExitOnError)
+	mirror := flags.String("mirror", ".", "Mirror directory of law XML files or jplaw-serve responses")
+	limit := flags.Int("limit", 100, "Maximum number of hits (0: no limit)")
+	timeout := flags.Duration("timeout", 30*time.Second, "Stop searching after this long (0: no limit)")
+	exact := flags.Bool("exact", false, "Match the text as written, without normalization")
+	contextWidth := flags.Int("context", 20, "Characters of context on each side of a hit")
+	concurrency := flags.Int("concurrency", 0, "Documents searched at a time (default: the number of CPUs)")
+	output, maxWidth := addOutputFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), `Usage: jplaw search [options] <query>
+
+Search the laws of a mirror directory with a query of words, "phrases" and
+/regular expressions/, combined with AND (or a space), OR, NOT (or -) and
+parentheses, evaluated per sentence. Hits are printed by file, in document order.
+Put -- before a query starting with -.
+
+Options:`)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	q, err := lawapi.ParseLawQuery(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	hits, report, err := lawapi.SearchMirror(context.Background(), *mirror, q, &lawapi.MirrorSearchOptions{
+		Exact:        *exact,
+		ContextWidth: *contextWidth,
+		Limit:        *limit,
+		Timeout:      *timeout,
+		Concurrency:  *concurrency,
+	})
+	if report == nil {
+		return err
+	}
+	for _, failure := range report.Failures {
+		fmt.Fprintf(os.Stderr, "jplaw search: %s: %s\n", failure.Path, lawapi.LocalizeError(failure.Err, lang))
+	}
+
+	t := &lawapi.Table{Header: []string{"PATH", "LAW TITLE", "PROVISION ID", "PROVISION", "OFFSET", "TEXT"}}
+	for _, h := range hits {
+		t.Rows = append(t.Rows, []string{h.Path, h.LawTitle, h.ProvisionID, h.Provision, strconv.Itoa(h.Offset), h.Before + "[" + h.Match + "]" + h.After})
+	}
+	if werr := writeResponse(*output, *maxWidth, t, hits); werr != nil {
+		return werr
+	}
+	return err
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+// usageStats are the locally recorded usage counts. Only subcommand and endpoint names are
+// recorded: no arguments, law IDs, search terms or other request parameters.
+type usageStats struct {
+	Since     time.Time      `json:"since"`
+	Updated   time.Time      `json:"updated"`
+	Commands  map[string]int `json:"commands"`
+	Endpoints map[string]int `json:"endpoints"`
+
+	mu sync.Mutex
+}
+
+// usage holds the stats of the running command, or nil when recording is disabled
+var usage *usageStats
+
+// usagePath returns the path of the stats file: $JPLAW_USAGE_FILE, or usage.json in the jplaw
+// configuration directory. Recording is enabled exactly when the file exists.
+func usagePath() (string, error) {
+	return configPath("JPLAW_USAGE_FILE", "usage.json")
+}
+
+// loadUsage reads the stats file, returning nil when recording is not enabled
+func loadUsage() (*usageStats, error) {
+	path, err := usagePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	stats := &usageStats{}
+	if err := json.Unmarshal(data, stats); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if stats.Commands == nil {
+		stats.Commands = map[string]int{}
+	}
+	if stats.Endpoints == nil {
+		stats.Endpoints = map[string]int{}
+	}
+	return stats, nil
+}
+
+func (s *usageStats) save() error {
+	path, err := usagePath()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.Updated = lawapi.DefaultClock.Now().UTC()
+	data, err := json.MarshalIndent(s, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return lawapi.WriteFileAtomic(path, append(data, '\n'), 0o644)
+}
+
+func (s *usageStats) recordCommand(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Commands[name]++
+}
+
+func (s *usageStats) recordEndpoint(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Endpoints[name]++
+}
+
+// newClient creates an API client, counting requests when usage recording is enabled
+func newClient() *lawapi.Client {
+	client := lawapi.NewClient()
+	if usage != nil {
+		client.SetHTTPClient(&http.Client{
+			Timeout: 30 * time.Second,
+			Transport: lawapi.HookTransport(nil, func(event lawapi.RequestEvent) {
+				usage.recordEndpoint(event.Method + " " + event.Endpoint)
+			}),
+		})
+	}
+	return client
+}
+
+func runUsage(args []string) error {
+	flags := flag.NewFlagSet("usage", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), `Usage: jplaw usage <enable|disable|show|export|reset>
+
+Usage recording is off by default. When enabled, the names of subcommands and API
+endpoints used are counted in a local file; nothing is sent anywhere. Arguments, law
+IDs and search terms are never recorded.
+
+  enable     Start recording
+  disable    Stop recording and delete the recorded counts
+  show       Print the recorded counts
+  export     Print the recorded counts as JSON
+  reset      Clear the recorded counts`)
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	path, err := usagePath()
+	if err != nil {
+		return err
+	}
+	stats, err := loadUsage()
+	if err != nil {
+		return err
+	}
+
+	switch flags.Arg(0) {
+	case "enable", "reset":
+		if stats != nil && flags.Arg(0) == "enable" {
+			fmt.Printf("Usage recording is already enabled (%s)\n", path)
+			return nil
+		}
+		fresh := &usageStats{Since: lawapi.DefaultClock.Now().UTC(), Commands: map[string]int{}, Endpoints: map[string]int{}}
+		if err := fresh.save(); err != nil {
+			return err
+		}
+		fmt.Printf("Recording usage to %s\n", path)
+	case "disable":
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		fmt.Println("Usage recording disabled")
+	case "show":
+		if stats == nil {
+			fmt.Println("Usage recording is disabled; run `jplaw usage enable` to start")
+			return nil
+		}
+		fmt.Printf("Since %s\n", stats.Since.Local().Format(time.DateTime))
+		printCounts("Commands", stats.Commands)
+		printCounts("Endpoints", stats.Endpoints)
+	case "export":
+		if stats == nil {
+			return errors.New("usage recording is disabled")
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+	return nil
+}
+
+// printCounts prints counts in descending order
+func printCounts(title string, counts map[string]int) {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	fmt.Printf("\n%s:\n", title)
+	for _, name := range names {
+		fmt.Printf("  %6d  %s\n", counts[name], name)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+func runValidate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: jplaw validate <law>...")
+		fmt.Fprintln(flags.Output(), "\nCheck laws against the structure of the law XML schema. <law> is a law revision ID")
+		fmt.Fprintln(flags.Output(), "or a path to a law XML file. Violations are printed with their element paths.")
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	client := newClient()
+	count := 0
+	for _, arg := range flags.Args() {
+		law, err := loadLaw(client, arg)
+		if err != nil {
+			return err
+		}
+		for _, v := range law.Validate() {
+			fmt.Printf("%s: %s\n", arg, v)
+			count++
+		}
+	}
+	if count > 0 {
+		return lawapi.WithErrorCategory(fmt.Errorf("%d schema violations", count), lawapi.ErrorValidation)
+	}
+	return nil
+}
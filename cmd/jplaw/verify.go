@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Write the reports as JSON lines")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: jplaw verify [options] <law-id>...")
+		fmt.Fprintln(fs.Output(), "\nCheck that applying each amending law to the preceding revision reproduces the")
+		fmt.Fprintln(fs.Output(), "revisions served by the API, and list unapplied instructions and differing articles.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	client := newClient()
+	failed := 0
+	enc := json.NewEncoder(os.Stdout)
+	for _, lawID := range fs.Args() {
+		reports, err := client.VerifyRevisions(context.Background(), lawID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", lawID, err)
+		}
+		for _, report := range reports {
+			if !report.OK() {
+				failed++
+			}
+			if *asJSON {
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+				continue
+			}
+			printConsolidationReport(report)
+		}
+	}
+	if failed > 0 {
+		return lawapi.WithErrorCategory(fmt.Errorf("%d revisions not reproduced", failed), lawapi.ErrorValidation)
+	}
+	return nil
+}
+
+func printConsolidationReport(report lawapi.ConsolidationReport) {
+	status := "ok"
+	if !report.OK() {
+		status = "FAILED"
+	}
+	fmt.Printf("%s: %s (from %s by %s)\n", report.RevisionID, status, report.BaseRevisionID, report.AmendmentLawID)
+	if report.Error != "" {
+		fmt.Printf("  error: %s\n", report.Error)
+	}
+	for _, u := range report.Amendment.Unapplied {
+		fmt.Printf("  not applied: %s (%s)\n", u.Instruction.Text, u.Reason)
+	}
+	for _, row := range report.Discrepancies {
+		fmt.Printf("  differs: %s (%s)\n", row.Label, row.Status)
+	}
+}
@@ -0,0 +1,221 @@
+package lawapi
+
+import "strings"
+
+// ComparisonStatus describes how an article changed between two revisions
+type ComparisonStatus string
+
+const (
+	// ComparisonUnchanged means the article text is identical in both revisions
+	ComparisonUnchanged ComparisonStatus = "unchanged"
+	// ComparisonModified means the article exists in both revisions with different text
+	ComparisonModified ComparisonStatus = "modified"
+	// ComparisonAdded means the article only exists in the new revision (新設)
+	ComparisonAdded ComparisonStatus = "added"
+	// ComparisonDeleted means the article only exists in the old revision (削除)
+	ComparisonDeleted ComparisonStatus = "deleted"
+)
+
+// DiffSegment is a run of text in one column of a comparison, highlighted when Changed
+type DiffSegment struct {
+	Text    string `json:"text"`
+	Changed bool   `json:"changed,omitempty"`
+}
+
+// ComparisonRow is one article of a 新旧対照表 (old/new comparison table)
+type ComparisonRow struct {
+	// Provision identifies the article. LawID and Revision are empty.
+	Provision ProvisionID `json:"provision"`
+	// Label is the citation of the article (e.g. 第二条, 附則第三条)
+	Label string `json:"label"`
+	// Status is how the article changed
+	Status ComparisonStatus `json:"status"`
+	// Old is the text of the old revision, empty for added articles
+	Old []DiffSegment `json:"old"`
+	// New is the text of the new revision, empty for deleted articles
+	New []DiffSegment `json:"new"`
+}
+
+// OldText returns the old text without highlighting
+func (r ComparisonRow) OldText() string {
+	return joinSegments(r.Old)
+}
+
+// NewText returns the new text without highlighting
+func (r ComparisonRow) NewText() string {
+	return joinSegments(r.New)
+}
+
+func joinSegments(segments []DiffSegment) string {
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteString(s.Text)
+	}
+	return b.String()
+}
+
+// GenerateComparisonTable pairs the articles of two revisions of a law by provision ID and
+// diffs their text (caption and paragraphs, one paragraph per line) character by character.
+// Rows are in the article order of the new revision, followed by deleted articles in the
+// order of the old revision. Unchanged articles are included with Status
+// ComparisonUnchanged so callers can choose whether to show them.
+func GenerateComparisonTable(oldDoc, newDoc *LawNode) []ComparisonRow {
+	oldArticles, oldOrder := comparisonArticles(oldDoc)
+	newArticles, newOrder := comparisonArticles(newDoc)
+
+	var order []string
+	seen := map[string]bool{}
+	for _, id := range append(newOrder, oldOrder...) {
+		if !seen[id] {
+			seen[id] = true
+			order = append(order, id)
+		}
+	}
+
+	rows := make([]ComparisonRow, 0, len(order))
+	for _, id := range order {
+		oldArticle, inOld := oldArticles[id]
+		newArticle, inNew := newArticles[id]
+		var row ComparisonRow
+		switch {
+		case !inOld:
+			row = ComparisonRow{Provision: newArticle.id, Label: newArticle.label, Status: ComparisonAdded}
+			row.New = []DiffSegment{{Text: newArticle.text, Changed: true}}
+		case !inNew:
+			row = ComparisonRow{Provision: oldArticle.id, Label: oldArticle.label, Status: ComparisonDeleted}
+			row.Old = []DiffSegment{{Text: oldArticle.text, Changed: true}}
+		case oldArticle.text == newArticle.text:
+			row = ComparisonRow{Provision: newArticle.id, Label: newArticle.label, Status: ComparisonUnchanged}
+			row.Old = []DiffSegment{{Text: oldArticle.text}}
+			row.New = []DiffSegment{{Text: newArticle.text}}
+		default:
+			row = ComparisonRow{Provision: newArticle.id, Label: newArticle.label, Status: ComparisonModified}
+			row.Old, row.New = DiffText(oldArticle.text, newArticle.text)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+type comparisonArticle struct {
+	id    ProvisionID
+	label string
+	text  string
+	node  *LawNode
+}
+
+// comparisonArticles returns the articles of a law keyed by provision ID, and the IDs in
+// document order. Supplementary provisions without articles are compared as a whole, like
+// an article.
+func comparisonArticles(law *LawNode) (map[string]comparisonArticle, []string) {
+	articles := map[string]comparisonArticle{}
+	var order []string
+	for _, ref := range law.provisionRefs() {
+		if ref.Article == nil {
+			if !ref.SupplProvision {
+				continue
+			}
+			supplRef := SentenceRef{SupplProvision: true, Suppl: ref.Suppl, SupplIndex: ref.SupplIndex}
+			id := supplRef.ProvisionID("", "")
+			key := id.String()
+			if _, ok := articles[key]; ok {
+				// A later paragraph of the same supplementary provision
+				continue
+			}
+			var lines []string
+			for _, paragraph := range ref.Suppl.Paragraphs() {
+				lines = append(lines, strings.TrimSpace(paragraph.PlainText()))
+			}
+			articles[key] = comparisonArticle{id: id, label: supplRef.Label(), text: strings.TrimSpace(strings.Join(lines, "\n")), node: ref.Suppl}
+			order = append(order, key)
+			continue
+		}
+		id := ref.ProvisionID("", "")
+		key := id.String()
+		lines := []string{strings.TrimSpace(ref.Article.Child("ArticleCaption").PlainText())}
+		for _, paragraph := range ref.Article.Paragraphs() {
+			lines = append(lines, strings.TrimSpace(paragraph.PlainText()))
+		}
+		articles[key] = comparisonArticle{id: id, label: ref.Label(), text: strings.TrimSpace(strings.Join(lines, "\n")), node: ref.Article}
+		order = append(order, key)
+	}
+	return articles, order
+}
+
+// maxDiffCells bounds the size of the LCS table; longer changed spans are marked as replaced wholesale
+const maxDiffCells = 4_000_000
+
+// DiffText computes a character-level diff of two texts and returns the segments of
+// each side, with characters not in the longest common subsequence marked as changed
+func DiffText(oldText, newText string) (oldSegments, newSegments []DiffSegment) {
+	a, b := []rune(oldText), []rune(newText)
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var oldSegs, newSegs segmentBuilder
+	oldSegs.add(a[:prefix], false)
+	newSegs.add(b[:prefix], false)
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > maxDiffCells {
+		oldSegs.add(midA, true)
+		newSegs.add(midB, true)
+	} else {
+		// lcs[i][j] is the length of the LCS of midA[i:] and midB[j:]
+		lcs := make([][]int32, len(midA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, len(midB)+1)
+		}
+		for i := len(midA) - 1; i >= 0; i-- {
+			for j := len(midB) - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(midA) || j < len(midB) {
+			switch {
+			case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+				oldSegs.add(midA[i:i+1], false)
+				newSegs.add(midB[j:j+1], false)
+				i++
+				j++
+			case j < len(midB) && (i == len(midA) || lcs[i][j+1] >= lcs[i+1][j]):
+				newSegs.add(midB[j:j+1], true)
+				j++
+			default:
+				oldSegs.add(midA[i:i+1], true)
+				i++
+			}
+		}
+	}
+
+	oldSegs.add(a[len(a)-suffix:], false)
+	newSegs.add(b[len(b)-suffix:], false)
+	return oldSegs.segments, newSegs.segments
+}
+
+// segmentBuilder merges consecutive runs with the same highlighting
+type segmentBuilder struct {
+	segments []DiffSegment
+}
+
+func (s *segmentBuilder) add(text []rune, changed bool) {
+	if len(text) == 0 {
+		return
+	}
+	if n := len(s.segments); n > 0 && s.segments[n-1].Changed == changed {
+		s.segments[n-1].Text += string(text)
+		return
+	}
+	s.segments = append(s.segments, DiffSegment{Text: string(text), Changed: changed})
+}
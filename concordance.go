@@ -0,0 +1,149 @@
+package lawapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ConcordanceDocument is a parsed law included in a concordance
+type ConcordanceDocument struct {
+	// LawID identifies the law (law ID or revision ID)
+	LawID string
+	// Law is the parsed law document
+	Law *LawNode
+}
+
+// ConcordanceOptions configures BuildConcordance
+type ConcordanceOptions struct {
+	// Terms are the terms to count and list in context
+	Terms []string
+	// ContextWidth is the number of characters shown on each side of a hit (default: 20)
+	ContextWidth int
+}
+
+// Concordance contains term frequency tables and keyword-in-context listings
+type Concordance struct {
+	// Frequencies contains one entry per law and term
+	Frequencies []TermFrequency `json:"frequencies"`
+	// Lines contains one keyword-in-context line per hit
+	Lines []ConcordanceLine `json:"lines"`
+}
+
+// TermFrequency is the number of occurrences of a term in a law
+type TermFrequency struct {
+	LawID string `json:"law_id"`
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+// ConcordanceLine is a keyword-in-context listing of a single hit
+type ConcordanceLine struct {
+	LawID       string `json:"law_id"`
+	Provision   string `json:"provision"`
+	ProvisionID string `json:"provision_id"`
+	Left        string `json:"left"`
+	Term        string `json:"term"`
+	Right       string `json:"right"`
+}
+
+// BuildConcordance builds term frequency tables and keyword-in-context listings
+// over the sentences of the given laws
+func BuildConcordance(docs []ConcordanceDocument, opts ConcordanceOptions) *Concordance {
+	width := opts.ContextWidth
+	if width <= 0 {
+		width = 20
+	}
+
+	c := &Concordance{}
+	for _, doc := range docs {
+		counts := make([]int, len(opts.Terms))
+		for _, ref := range doc.Law.Sentences() {
+			text := []rune(ref.Sentence.PlainText())
+			for i, term := range opts.Terms {
+				termRunes := []rune(term)
+				if len(termRunes) == 0 {
+					continue
+				}
+				for _, pos := range runeIndexAll(text, termRunes) {
+					counts[i]++
+					left := pos - width
+					if left < 0 {
+						left = 0
+					}
+					right := pos + len(termRunes) + width
+					if right > len(text) {
+						right = len(text)
+					}
+					c.Lines = append(c.Lines, ConcordanceLine{
+						LawID:       doc.LawID,
+						Provision:   ref.Label(),
+						ProvisionID: ref.ProvisionID(doc.LawID, "").String(),
+						Left:        string(text[left:pos]),
+						Term:        term,
+						Right:       string(text[pos+len(termRunes) : right]),
+					})
+				}
+			}
+		}
+		for i, term := range opts.Terms {
+			c.Frequencies = append(c.Frequencies, TermFrequency{LawID: doc.LawID, Term: term, Count: counts[i]})
+		}
+	}
+	return c
+}
+
+// runeIndexAll returns the positions of non-overlapping occurrences of sub in s
+func runeIndexAll(s, sub []rune) []int {
+	var positions []int
+	for i := 0; i+len(sub) <= len(s); {
+		if slices.Equal(s[i:i+len(sub)], sub) {
+			positions = append(positions, i)
+			i += len(sub)
+			continue
+		}
+		i++
+	}
+	return positions
+}
+
+// WriteJSON writes the concordance as JSON
+func (c *Concordance) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
+
+// WriteFrequenciesCSV writes the term frequency table as CSV
+func (c *Concordance) WriteFrequenciesCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"law_id", "term", "count"}); err != nil {
+		return err
+	}
+	for _, f := range c.Frequencies {
+		if err := cw.Write([]string{f.LawID, f.Term, strconv.Itoa(f.Count)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteLinesCSV writes the keyword-in-context listing as CSV
+func (c *Concordance) WriteLinesCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"law_id", "provision", "provision_id", "left", "term", "right"}); err != nil {
+		return err
+	}
+	for _, line := range c.Lines {
+		record := []string{line.LawID, line.Provision, line.ProvisionID, strings.TrimSpace(line.Left), line.Term, strings.TrimSpace(line.Right)}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,142 @@
+package lawapi
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ConsolidationReport is the outcome of checking one consolidated revision of a law
+// against its amending law
+type ConsolidationReport struct {
+	LawID string `json:"law_id,omitempty"`
+	// BaseRevisionID is the revision the amendment was applied to
+	BaseRevisionID string `json:"base_revision_id,omitempty"`
+	// RevisionID is the revision expected to result from the amendment
+	RevisionID string `json:"revision_id,omitempty"`
+	// AmendmentLawID is the ID of the amending law
+	AmendmentLawID string `json:"amendment_law_id,omitempty"`
+	// Amendment reports the instructions applied to the base revision
+	Amendment AmendmentReport `json:"amendment"`
+	// Discrepancies are the main provision articles whose text differs between the
+	// amended base revision (Old) and the revision (New)
+	Discrepancies []ComparisonRow `json:"discrepancies,omitempty"`
+	// Error is why the revision could not be checked, if so
+	Error string `json:"error,omitempty"`
+}
+
+// OK reports whether the amendment was applied completely and reproduced the revision
+func (r ConsolidationReport) OK() bool {
+	return r.Error == "" && len(r.Amendment.Unapplied) == 0 && len(r.Discrepancies) == 0
+}
+
+// ExtractAmendmentInstructions returns the amendment instructions of an amending law
+// that amend the law titled lawTitle: the AmendProvision elements following the
+// paragraphs 「<lawTitle>（…）の一部を次のように改正する。」, with the provisions they add as
+// bodies. If lawTitle is empty, the instructions amending any law are returned. Laws
+// amended in several stages by one amending law yield the instructions of all stages.
+func ExtractAmendmentInstructions(amendingLaw *LawNode, lawTitle string) []AmendmentInstruction {
+	var lines []string
+	for _, paragraph := range amendingLaw.FindAll("Paragraph") {
+		amendments := paragraph.ChildrenByTag("AmendProvision")
+		if len(amendments) == 0 {
+			continue
+		}
+		statement := sentenceText(paragraph.Child("ParagraphSentence"))
+		if !strings.Contains(statement, "改正する") {
+			continue
+		}
+		// The title is followed by the law number or directly by の一部, not by more of a
+		// longer title (電波法施行令 for 電波法)
+		if lawTitle != "" && !strings.HasPrefix(statement, lawTitle+"（") && !strings.HasPrefix(statement, lawTitle+"の") {
+			continue
+		}
+		for _, amendment := range amendments {
+			lines = append(lines, sentenceText(amendment.Child("AmendProvisionSentence")))
+			for _, provision := range amendment.ChildrenByTag("NewProvision") {
+				for _, c := range provision.Children {
+					for _, line := range provisionLines(c) {
+						lines = append(lines, strings.Repeat("　", line.indent)+line.text)
+					}
+				}
+			}
+		}
+	}
+	return ParseAmendmentInstructions(strings.Join(lines, "\n"))
+}
+
+// VerifyConsolidation applies amendment instructions to the base revision of a law and
+// compares the result with the next revision, e.g. as consolidated by the API. The base
+// tree is amended in place. Supplementary provisions are not compared, since
+// consolidated texts append those of each amending law.
+func VerifyConsolidation(base, next *LawNode, instructions []AmendmentInstruction) ConsolidationReport {
+	report := ConsolidationReport{Amendment: base.ApplyAmendment(instructions)}
+	for _, row := range GenerateComparisonTable(base, next) {
+		if row.Status != ComparisonUnchanged && !strings.HasPrefix(row.Provision.Article, "s") {
+			report.Discrepancies = append(report.Discrepancies, row)
+		}
+	}
+	return report
+}
+
+// VerifyRevisions checks every revision of a law served by the API against its amending
+// law: the instructions amending the law are extracted from the amending law with
+// ExtractAmendmentInstructions and applied to the preceding revision with
+// VerifyConsolidation. Revisions are checked in chronological order of their revision
+// IDs; the first revision has no base and is skipped. Failures to retrieve or extract a
+// revision are reported in its Error, and only failing to list the revisions is
+// returned as an error.
+func (c *Client) VerifyRevisions(ctx context.Context, lawID string) ([]ConsolidationReport, error) {
+	resp, err := c.GetRevisionsWithContext(ctx, lawID, nil)
+	if err != nil {
+		return nil, err
+	}
+	revisions := slices.Clone(resp.Revisions)
+	slices.SortFunc(revisions, func(a, b RevisionInfo) int {
+		return cmp.Compare(a.LawRevisionId, b.LawRevisionId)
+	})
+
+	var reports []ConsolidationReport
+	for i := 1; i < len(revisions); i++ {
+		prev, rev := revisions[i-1], revisions[i]
+		report := ConsolidationReport{
+			LawID:          lawID,
+			BaseRevisionID: prev.LawRevisionId,
+			RevisionID:     rev.LawRevisionId,
+			AmendmentLawID: rev.AmendmentLawId,
+		}
+		if err := c.verifyRevision(ctx, &report, prev.LawTitle); err != nil {
+			if ctx.Err() != nil {
+				return reports, ctx.Err()
+			}
+			report.Error = err.Error()
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func (c *Client) verifyRevision(ctx context.Context, report *ConsolidationReport, lawTitle string) error {
+	if report.AmendmentLawID == "" {
+		return fmt.Errorf("revision %s has no amending law", report.RevisionID)
+	}
+	var laws [3]*LawNode
+	for i, id := range []string{report.BaseRevisionID, report.RevisionID, report.AmendmentLawID} {
+		data, err := c.GetLawDataWithContext(ctx, id, nil)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve %s: %w", id, err)
+		}
+		if laws[i], err = ParseLawFullText(data); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", id, err)
+		}
+	}
+	instructions := ExtractAmendmentInstructions(laws[2], lawTitle)
+	if len(instructions) == 0 {
+		return fmt.Errorf("no instructions amending %s in %s", lawTitle, report.AmendmentLawID)
+	}
+	verified := VerifyConsolidation(laws[0], laws[1], instructions)
+	report.Amendment, report.Discrepancies = verified.Amendment, verified.Discrepancies
+	return nil
+}
@@ -0,0 +1,85 @@
+package lawapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+)
+
+// CorrelationIDHeader is the request header carrying the correlation ID of a call
+const CorrelationIDHeader = "X-Correlation-ID"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context whose calls use id as their correlation ID, e.g.
+// to follow the ID of an incoming request through the API calls made for it. Without
+// one, every call of the client generates its own.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID carried by ctx, or empty
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// NewCorrelationID returns a random correlation ID of 16 hexadecimal digits
+func NewCorrelationID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// ensureCorrelationID returns ctx carrying a correlation ID, generating one unless ctx
+// already has one, and the ID
+func ensureCorrelationID(ctx context.Context) (context.Context, string) {
+	if id := CorrelationIDFromContext(ctx); id != "" {
+		return ctx, id
+	}
+	id := NewCorrelationID()
+	return WithCorrelationID(ctx, id), id
+}
+
+// setCorrelationID sets the correlation ID header of a request from its context
+func setCorrelationID(req *http.Request) {
+	if id := CorrelationIDFromContext(req.Context()); id != "" {
+		req.Header.Set(CorrelationIDHeader, id)
+	}
+}
+
+// correlatedError is a failed call with its correlation ID
+type correlatedError struct {
+	err error
+	id  string
+}
+
+func (e *correlatedError) Error() string         { return e.err.Error() + " (correlation ID " + e.id + ")" }
+func (e *correlatedError) Unwrap() error         { return e.err }
+func (e *correlatedError) CorrelationID() string { return e.id }
+
+// withCorrelationID returns err annotated with the correlation ID of its call, unless it
+// already carries one
+func withCorrelationID(err error, id string) error {
+	if err == nil || id == "" || CorrelationIDOf(err) != "" {
+		return err
+	}
+	return &correlatedError{err: err, id: id}
+}
+
+// CorrelationIDOf returns the correlation ID of the call that failed with err, the same
+// for all the attempts of a retried call and logged with its requests, or empty if err
+// does not carry one
+func CorrelationIDOf(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.CorrelationID != "" {
+		return apiErr.CorrelationID
+	}
+	var correlated interface{ CorrelationID() string }
+	if errors.As(err, &correlated) {
+		return correlated.CorrelationID()
+	}
+	return ""
+}
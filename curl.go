@@ -0,0 +1,49 @@
+package lawapi
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// CurlCommand renders a prepared request as an equivalent curl command line,
+// quoted for POSIX shells, e.g. for sharing reproducible examples in bug reports:
+//
+//	req, _ := client.BuildGetLawsRequest(ctx, params)
+//	fmt.Println(lawapi.CurlCommand(req))
+func CurlCommand(req *http.Request) string {
+	args := []string{"curl"}
+	if req.Method != "" && req.Method != http.MethodGet {
+		args = append(args, "-X", shellQuote(req.Method))
+	}
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			args = append(args, "-H", shellQuote(name+": "+value))
+		}
+	}
+
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, err := io.ReadAll(body)
+			body.Close()
+			if err == nil && len(data) > 0 {
+				args = append(args, "--data-binary", shellQuote(string(data)))
+			}
+		}
+	}
+
+	args = append(args, shellQuote(req.URL.String()))
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes a string for POSIX shells using single quotes
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
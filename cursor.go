@@ -0,0 +1,136 @@
+package lawapi
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Cursor is an opaque pagination token encoding the offset of the next page and
+// a hash of the query it belongs to. Services built on this client can hand
+// cursors to their own clients instead of raw offsets.
+type Cursor string
+
+// ErrCursorMismatch is returned when a cursor is used with a query other than the one it was created for
+var ErrCursorMismatch = errors.New("cursor does not belong to this query")
+
+const cursorVersion = "v1"
+
+// NewCursor creates a cursor for the given offset and query hash
+func NewCursor(offset int64, queryHash string) Cursor {
+	raw := fmt.Sprintf("%s:%d:%s", cursorVersion, offset, queryHash)
+	return Cursor(base64.RawURLEncoding.EncodeToString([]byte(raw)))
+}
+
+// Decode returns the offset and query hash encoded in the cursor. Offsets beyond the
+// 32-bit offsets of the API are rejected. Errors are in the ErrorValidation category.
+func (c Cursor) Decode() (offset int64, queryHash string, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil {
+		return 0, "", WithErrorCategory(fmt.Errorf("invalid cursor: %w", err), ErrorValidation)
+	}
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 || parts[0] != cursorVersion {
+		return 0, "", WithErrorCategory(errors.New("invalid cursor"), ErrorValidation)
+	}
+	offset, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || offset < 0 {
+		return 0, "", WithErrorCategory(errors.New("invalid cursor offset"), ErrorValidation)
+	}
+	// The API takes offsets as 32-bit integers
+	if offset > math.MaxInt32 {
+		return 0, "", WithErrorCategory(fmt.Errorf("cursor offset %d exceeds the largest offset of the API", offset), ErrorValidation)
+	}
+	return offset, parts[2], nil
+}
+
+// queryHash hashes query parameters, excluding the offset
+func queryHash(params interface{}) string {
+	data, _ := json.Marshal(params)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// NextCursor returns the cursor of the next page, or an empty cursor if this is the last page
+func (r *LawsResponse) NextCursor(params *GetLawsParams) Cursor {
+	if r == nil || r.NextOffset <= 0 {
+		return ""
+	}
+	return NewCursor(r.NextOffset, params.queryHash())
+}
+
+// WithCursor returns a copy of the parameters positioned at the cursor.
+// An empty cursor positions at the first page.
+func (p *GetLawsParams) WithCursor(cursor Cursor) (*GetLawsParams, error) {
+	next := GetLawsParams{}
+	if p != nil {
+		next = *p
+	}
+	next.Offset = nil
+	if cursor == "" {
+		return &next, nil
+	}
+	offset, hash, err := cursor.Decode()
+	if err != nil {
+		return nil, err
+	}
+	if hash != p.queryHash() {
+		return nil, ErrCursorMismatch
+	}
+	next.Offset = Int32Ptr(int32(offset))
+	return &next, nil
+}
+
+func (p *GetLawsParams) queryHash() string {
+	q := GetLawsParams{}
+	if p != nil {
+		q = *p
+	}
+	q.Offset = nil
+	return queryHash(q)
+}
+
+// NextCursor returns the cursor of the next page, or an empty cursor if this is the last page
+func (r *KeywordResponse) NextCursor(params *GetKeywordParams) Cursor {
+	if r == nil || r.NextOffset <= 0 {
+		return ""
+	}
+	return NewCursor(r.NextOffset, params.queryHash())
+}
+
+// WithCursor returns a copy of the parameters positioned at the cursor.
+// An empty cursor positions at the first page.
+func (p *GetKeywordParams) WithCursor(cursor Cursor) (*GetKeywordParams, error) {
+	next := GetKeywordParams{}
+	if p != nil {
+		next = *p
+	}
+	next.Offset = nil
+	if cursor == "" {
+		return &next, nil
+	}
+	offset, hash, err := cursor.Decode()
+	if err != nil {
+		return nil, err
+	}
+	if hash != p.queryHash() {
+		return nil, ErrCursorMismatch
+	}
+	next.Offset = Int32Ptr(int32(offset))
+	return &next, nil
+}
+
+func (p *GetKeywordParams) queryHash() string {
+	q := GetKeywordParams{}
+	if p != nil {
+		q = *p
+	}
+	q.Offset = nil
+	return queryHash(q)
+}
@@ -0,0 +1,37 @@
+package lawapi
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestCursorDecodeErrors(t *testing.T) {
+	encode := func(raw string) Cursor { return Cursor(base64.RawURLEncoding.EncodeToString([]byte(raw))) }
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"not base64", "!!"},
+		{"missing parts", encode("v1:10")},
+		{"unknown version", encode("v0:10:abc")},
+		{"offset not a number", encode("v1:ten:abc")},
+		{"negative offset", encode("v1:-1:abc")},
+		{"offset beyond 32 bits", encode("v1:2147483648:abc")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.cursor.Decode()
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if got := ErrorCategoryOf(err); got != ErrorValidation {
+				t.Errorf("category of %v = %v, want ErrorValidation", err, got)
+			}
+		})
+	}
+
+	offset, hash, err := NewCursor(2147483647, "abc").Decode()
+	if err != nil || offset != 2147483647 || hash != "abc" {
+		t.Errorf("Decode() = %d, %q, %v", offset, hash, err)
+	}
+}
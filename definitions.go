@@ -0,0 +1,146 @@
+package lawapi
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefinitionKind classifies how a term is defined in law text
+type DefinitionKind string
+
+const (
+	// DefinitionKindSentence is a definition sentence (「この法律において「X」とは、…をいう。」)
+	DefinitionKindSentence DefinitionKind = "sentence"
+	// DefinitionKindItem is a definition given as an item of a definition list (次の各号に掲げる用語の意義)
+	DefinitionKindItem DefinitionKind = "item"
+	// DefinitionKindAbbreviation is an abbreviation introduced inline (（以下「X」という。）)
+	DefinitionKindAbbreviation DefinitionKind = "abbreviation"
+)
+
+// Definition is a defined term (定義規定) extracted from a law
+type Definition struct {
+	// Term is the defined term
+	Term string `json:"term"`
+	// Definition is the text defining the term
+	Definition string `json:"definition"`
+	// Scope is the scope of the definition (e.g. "この法律", "この章", "この条")
+	Scope string `json:"scope,omitempty"`
+	// Provision is the citation of the defining provision (e.g. "第二条第一項")
+	Provision string `json:"provision"`
+	// Kind is how the term was defined
+	Kind DefinitionKind `json:"kind"`
+}
+
+var (
+	definitionSentencePattern = regexp.MustCompile(`^(この[^「」。]*?|前[^「」。]*?|次[^「」。]*?)において、?「([^」]+)」とは、(.+)$`)
+	definitionListPattern     = regexp.MustCompile(`用語の意義は、(?:それぞれ)?(?:次の各号|当該各号)`)
+	abbreviationPattern       = regexp.MustCompile(`（以下(?:[^（）「」]*?)「([^」]+)」という。）`)
+)
+
+// ExtractDefinitions extracts defined terms from a parsed law document
+func ExtractDefinitions(law *LawNode) []Definition {
+	var definitions []Definition
+	listParagraphs := map[*LawNode]string{}
+
+	for _, ref := range law.Sentences() {
+		text := strings.TrimSpace(ref.Sentence.PlainText())
+
+		if ref.Item != nil {
+			if scope, ok := listParagraphs[ref.Paragraph]; ok {
+				if def, ok := itemDefinition(ref, scope); ok {
+					definitions = append(definitions, def)
+				}
+				continue
+			}
+		}
+
+		if m := definitionSentencePattern.FindStringSubmatch(text); m != nil {
+			definitions = append(definitions, Definition{
+				Term:       m[2],
+				Definition: strings.TrimSpace(m[3]),
+				Scope:      definitionScope(m[1]),
+				Provision:  ref.Label(),
+				Kind:       DefinitionKindSentence,
+			})
+		} else if ref.Paragraph != nil && definitionListPattern.MatchString(text) {
+			if i := strings.Index(text, "において"); i > 0 {
+				listParagraphs[ref.Paragraph] = definitionScope(text[:i])
+			} else {
+				listParagraphs[ref.Paragraph] = ""
+			}
+		}
+
+		for _, loc := range abbreviationPattern.FindAllStringSubmatchIndex(text, -1) {
+			definitions = append(definitions, Definition{
+				Term:       text[loc[2]:loc[3]],
+				Definition: precedingClause(text[:loc[0]]),
+				Provision:  ref.Label(),
+				Kind:       DefinitionKindAbbreviation,
+			})
+		}
+	}
+
+	return definitions
+}
+
+// itemDefinition extracts a definition from an item of a definition list.
+// Items are either split into Column elements or separated by a full-width space.
+func itemDefinition(ref SentenceRef, scope string) (Definition, bool) {
+	sentence := ref.Item.Child("ItemSentence")
+	if sentence == nil {
+		return Definition{}, false
+	}
+	// Only handle the first sentence of an item to avoid duplicates
+	if first := sentence.Find("Sentence"); first != ref.Sentence {
+		return Definition{}, false
+	}
+
+	var term, definition string
+	if columns := sentence.ChildrenByTag("Column"); len(columns) >= 2 {
+		term = columns[0].PlainText()
+		var parts []string
+		for _, column := range columns[1:] {
+			parts = append(parts, column.PlainText())
+		}
+		definition = strings.Join(parts, "")
+	} else {
+		text := strings.TrimSpace(sentence.PlainText())
+		i := strings.Index(text, "　")
+		if i < 0 {
+			return Definition{}, false
+		}
+		term, definition = text[:i], text[i+len("　"):]
+	}
+
+	term = strings.Trim(strings.TrimSpace(term), "「」")
+	if term == "" {
+		return Definition{}, false
+	}
+	return Definition{
+		Term:       term,
+		Definition: strings.TrimSpace(definition),
+		Scope:      scope,
+		Provision:  ref.Label(),
+		Kind:       DefinitionKindItem,
+	}, true
+}
+
+// definitionScope normalizes a scope phrase such as "この法律（第五章を除く。）" to "この法律"
+func definitionScope(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "（"); i > 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// precedingClause returns the clause immediately before an inline abbreviation
+func precedingClause(s string) string {
+	cut := 0
+	for _, sep := range []string{"、", "。"} {
+		if i := strings.LastIndex(s, sep); i >= 0 && i+len(sep) > cut && i+len(sep) < len(s) {
+			cut = i + len(sep)
+		}
+	}
+	return strings.TrimSpace(s[cut:])
+}
@@ -0,0 +1,104 @@
+package lawapi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Delegation is a provision delegating details to a subordinate regulation
+// (「政令で定める」「総務省令で定める」等)
+type Delegation struct {
+	// Provision is the citation of the delegating provision (e.g. "第二条第二項")
+	Provision string `json:"provision"`
+	// Text is the sentence text
+	Text string `json:"text"`
+	// Authority is the form of the delegated regulation (e.g. "政令", "総務省令", "個人情報保護委員会規則")
+	Authority string `json:"authority"`
+	// Kind is the law type of the delegated regulation
+	Kind LawType `json:"kind"`
+}
+
+// DelegationMap maps an act to the delegations it contains and the subordinate regulations found for them
+type DelegationMap struct {
+	// LawTitle is the title of the delegating act
+	LawTitle string `json:"law_title"`
+	// Delegations contains the delegating provisions
+	Delegations []Delegation `json:"delegations"`
+	// Regulations contains the subordinate regulations found for each kind of delegation
+	Regulations map[LawType][]LawItem `json:"regulations,omitempty"`
+}
+
+var delegationPattern = regexp.MustCompile(`([\p{Han}\p{Katakana}ー]*?(?:政令|省令|府令|規則))で定め`)
+
+// DetectDelegations detects provisions delegating details to cabinet orders,
+// ministerial ordinances or rules in a parsed law document
+func DetectDelegations(law *LawNode) []Delegation {
+	var delegations []Delegation
+	for _, ref := range law.Sentences() {
+		text := strings.TrimSpace(ref.Sentence.PlainText())
+		seen := map[string]bool{}
+		for _, m := range delegationPattern.FindAllStringSubmatch(text, -1) {
+			authority := m[1]
+			if seen[authority] {
+				continue
+			}
+			seen[authority] = true
+			delegations = append(delegations, Delegation{
+				Provision: ref.Label(),
+				Text:      text,
+				Authority: authority,
+				Kind:      delegationKind(authority),
+			})
+		}
+	}
+	return delegations
+}
+
+func delegationKind(authority string) LawType {
+	switch {
+	case strings.HasSuffix(authority, "政令"):
+		return LawTypeCabinetorder
+	case strings.HasSuffix(authority, "規則"):
+		return LawTypeRule
+	default:
+		return LawTypeMinisterialordinance
+	}
+}
+
+// ResolveDelegations detects the delegations in a parsed law and searches for the
+// subordinate regulations by title (e.g. "電波法施行令" for cabinet orders, "電波法施行規則"
+// for ministerial ordinances and rules). Regulations are sorted with SortLawItems.
+func (c *Client) ResolveDelegations(law *LawNode) (*DelegationMap, error) {
+	title := law.Title()
+	if title == "" {
+		return nil, fmt.Errorf("law title is not present in the document")
+	}
+
+	result := &DelegationMap{
+		LawTitle:    title,
+		Delegations: DetectDelegations(law),
+		Regulations: map[LawType][]LawItem{},
+	}
+
+	for _, delegation := range result.Delegations {
+		if _, ok := result.Regulations[delegation.Kind]; ok {
+			continue
+		}
+		suffix := "施行規則"
+		if delegation.Kind == LawTypeCabinetorder {
+			suffix = "施行令"
+		}
+		laws, err := c.GetLaws(&GetLawsParams{
+			LawTitle: StringPtr(title + suffix),
+			LawType:  &[]LawType{delegation.Kind},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search regulations for %s: %w", delegation.Authority, err)
+		}
+		SortLawItems(laws.Laws)
+		result.Regulations[delegation.Kind] = laws.Laws
+	}
+
+	return result, nil
+}
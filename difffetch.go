@@ -0,0 +1,156 @@
+package lawapi
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DiffFetcher compares the revisions of laws in force on given dates. It retrieves the
+// two revisions of a comparison concurrently and keeps the revision lists and parsed trees
+// it retrieved, so that comparing the same law against other dates only retrieves the
+// revisions not seen yet. Concurrent requests for the same revision share one retrieval;
+// failed retrievals are not kept. A DiffFetcher is safe for concurrent use.
+type DiffFetcher struct {
+	client *Client
+
+	mu        sync.Mutex
+	revisions map[string]*fetchCall[[]RevisionInfo]
+	trees     map[string]*fetchCall[*LawNode]
+}
+
+type fetchCall[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// LawDiff is the comparison of the revisions of a law in force on two dates
+type LawDiff struct {
+	LawID string `json:"law_id"`
+	From  Date   `json:"from"`
+	To    Date   `json:"to"`
+	// FromRevisionID and ToRevisionID are the revisions in force on From and To
+	FromRevisionID string `json:"from_revision_id"`
+	ToRevisionID   string `json:"to_revision_id"`
+	// Rows are the articles as returned by GenerateComparisonTable, empty if both dates
+	// fall into the same revision
+	Rows []ComparisonRow `json:"rows"`
+}
+
+// NewDiffFetcher creates a fetcher retrieving revisions with client
+func NewDiffFetcher(client *Client) *DiffFetcher {
+	return &DiffFetcher{
+		client:    client,
+		revisions: map[string]*fetchCall[[]RevisionInfo]{},
+		trees:     map[string]*fetchCall[*LawNode]{},
+	}
+}
+
+// Diff compares the revisions of a law in force on two dates with GenerateComparisonTable
+func (f *DiffFetcher) Diff(ctx context.Context, lawID string, from, to Date) (*LawDiff, error) {
+	fromRev, err := f.Revision(ctx, lawID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := f.Revision(ctx, lawID, to)
+	if err != nil {
+		return nil, err
+	}
+	diff := &LawDiff{LawID: lawID, From: from, To: to, FromRevisionID: fromRev.LawRevisionId, ToRevisionID: toRev.LawRevisionId}
+	if diff.FromRevisionID == diff.ToRevisionID {
+		return diff, nil
+	}
+
+	var trees [2]*LawNode
+	var errs [2]error
+	var wg sync.WaitGroup
+	for i, id := range []string{diff.FromRevisionID, diff.ToRevisionID} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			trees[i], errs[i] = f.RevisionTree(ctx, id)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	diff.Rows = GenerateComparisonTable(trees[0], trees[1])
+	return diff, nil
+}
+
+// Revision returns the revision of a law in force on a date: the revision with the latest
+// amendment enforcement date not after the date, with revisions without an enforcement
+// date taken to be in force from the start
+func (f *DiffFetcher) Revision(ctx context.Context, lawID string, on Date) (RevisionInfo, error) {
+	revisions, err := fetchOnce(ctx, &f.mu, f.revisions, lawID, func(ctx context.Context) ([]RevisionInfo, error) {
+		resp, err := f.client.GetRevisionsWithContext(ctx, lawID, nil)
+		if err != nil {
+			return nil, err
+		}
+		revisions := slices.Clone(resp.Revisions)
+		slices.SortFunc(revisions, func(a, b RevisionInfo) int {
+			return cmp.Or(
+				time.Time(a.AmendmentEnforcementDate).Compare(time.Time(b.AmendmentEnforcementDate)),
+				cmp.Compare(a.LawRevisionId, b.LawRevisionId),
+			)
+		})
+		return revisions, nil
+	})
+	if err != nil {
+		return RevisionInfo{}, err
+	}
+	i, _ := slices.BinarySearchFunc(revisions, on, func(rev RevisionInfo, on Date) int {
+		if time.Time(rev.AmendmentEnforcementDate).After(time.Time(on)) {
+			return 1
+		}
+		return -1
+	})
+	if i == 0 {
+		return RevisionInfo{}, WithErrorCategory(fmt.Errorf("no revision of %s is in force on %s", lawID, on), ErrorNotFound)
+	}
+	return revisions[i-1], nil
+}
+
+// RevisionTree returns the tree of a revision, retrieving it with Client.GetLawTree unless
+// the fetcher has it. Trees are shared between callers and must not be modified.
+func (f *DiffFetcher) RevisionTree(ctx context.Context, revisionID string) (*LawNode, error) {
+	return fetchOnce(ctx, &f.mu, f.trees, revisionID, func(ctx context.Context) (*LawNode, error) {
+		return f.client.GetLawTree(ctx, revisionID)
+	})
+}
+
+// fetchOnce returns the value kept in calls for key, waiting for a retrieval in progress,
+// or retrieves it with fetch. Failed retrievals are removed so that they can be retried.
+func fetchOnce[T any](ctx context.Context, mu *sync.Mutex, calls map[string]*fetchCall[T], key string, fetch func(context.Context) (T, error)) (T, error) {
+	mu.Lock()
+	call, ok := calls[key]
+	if !ok {
+		call = &fetchCall[T]{done: make(chan struct{})}
+		calls[key] = call
+		mu.Unlock()
+		call.value, call.err = fetch(ctx)
+		if call.err != nil {
+			mu.Lock()
+			delete(calls, key)
+			mu.Unlock()
+		}
+		close(call.done)
+		return call.value, call.err
+	}
+	mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
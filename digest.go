@@ -0,0 +1,152 @@
+package lawapi
+
+import (
+	"cmp"
+	"io"
+	"slices"
+	"text/template"
+	"time"
+)
+
+// DigestPeriod is the interval a change digest summarizes
+type DigestPeriod string
+
+const (
+	// DigestDaily summarizes the changes of a calendar day
+	DigestDaily DigestPeriod = "daily"
+	// DigestWeekly summarizes the changes of a week starting on Monday
+	DigestWeekly DigestPeriod = "weekly"
+)
+
+// Start returns the start of the period containing t. Days begin at midnight in Japan,
+// where the changes take effect.
+func (p DigestPeriod) Start(t time.Time) time.Time {
+	t = t.In(JST)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, JST)
+	if p == DigestWeekly {
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	}
+	return start
+}
+
+// End returns the end (exclusive) of the period starting at start
+func (p DigestPeriod) End(start time.Time) time.Time {
+	if p == DigestWeekly {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// LawChange is a change to a watched law
+type LawChange struct {
+	// List is the name of the watch list the law belongs to, or empty
+	List string `json:"list,omitempty"`
+	// LawID is the ID of the law
+	LawID string `json:"law_id"`
+	// LawTitle is the title of the new revision
+	LawTitle string `json:"law_title"`
+	// LawRevisionID is the ID of the new revision
+	LawRevisionID string `json:"law_revision_id,omitempty"`
+	// Category is the category name of the law
+	Category string `json:"category,omitempty"`
+	// AmendmentLawTitle is the title of the amending law, if any
+	AmendmentLawTitle string `json:"amendment_law_title,omitempty"`
+	// Time is when the change was detected
+	Time time.Time `json:"time"`
+	// Rows are the changed articles, if a comparison was made
+	Rows []ComparisonRow `json:"rows,omitempty"`
+}
+
+// NewLawChange creates a change for the revision of a law entry, detected at t
+func NewLawChange(list string, item LawItem, t time.Time) LawChange {
+	change := LawChange{List: list, Time: t}
+	if item.LawInfo != nil {
+		change.LawID = item.LawInfo.LawId
+	}
+	rev := item.RevisionInfo
+	if rev == nil {
+		rev = item.CurrentRevisionInfo
+	}
+	if rev != nil {
+		change.LawTitle = rev.LawTitle
+		change.LawRevisionID = rev.LawRevisionId
+		change.Category = rev.Category
+		change.AmendmentLawTitle = rev.AmendmentLawTitle
+	}
+	return change
+}
+
+// DigestGroup is the changes of one watch list and category
+type DigestGroup struct {
+	List     string      `json:"list,omitempty"`
+	Category string      `json:"category,omitempty"`
+	Changes  []LawChange `json:"changes"`
+}
+
+// Digest summarizes the changes of one period
+type Digest struct {
+	Period DigestPeriod `json:"period"`
+	// Start is the start of the period
+	Start time.Time `json:"start"`
+	// End is the end of the period (exclusive)
+	End time.Time `json:"end"`
+	// Groups are sorted by list and category
+	Groups []DigestGroup `json:"groups"`
+}
+
+// Len returns the number of changes in the digest
+func (d Digest) Len() int {
+	n := 0
+	for _, g := range d.Groups {
+		n += len(g.Changes)
+	}
+	return n
+}
+
+// BuildDigests batches changes into one digest for each period containing changes, in
+// chronological order. Within a group, changes are ordered by time and law ID.
+func BuildDigests(period DigestPeriod, changes []LawChange) []Digest {
+	changes = slices.Clone(changes)
+	slices.SortStableFunc(changes, func(a, b LawChange) int {
+		return cmp.Or(
+			period.Start(a.Time).Compare(period.Start(b.Time)),
+			cmp.Compare(a.List, b.List),
+			cmp.Compare(a.Category, b.Category),
+			a.Time.Compare(b.Time),
+			cmp.Compare(a.LawID, b.LawID),
+		)
+	})
+
+	var digests []Digest
+	for _, change := range changes {
+		start := period.Start(change.Time)
+		if len(digests) == 0 || !digests[len(digests)-1].Start.Equal(start) {
+			digests = append(digests, Digest{Period: period, Start: start, End: period.End(start)})
+		}
+		d := &digests[len(digests)-1]
+		if n := len(d.Groups); n == 0 || d.Groups[n-1].List != change.List || d.Groups[n-1].Category != change.Category {
+			d.Groups = append(d.Groups, DigestGroup{List: change.List, Category: change.Category})
+		}
+		g := &d.Groups[len(d.Groups)-1]
+		g.Changes = append(g.Changes, change)
+	}
+	return digests
+}
+
+// DefaultDigestTemplate renders a digest as plain text, listing the changed articles of
+// each law. Custom templates receive a Digest.
+var DefaultDigestTemplate = template.Must(template.New("digest").Parse(
+	`{{if eq .Period "weekly"}}法令改正 週間まとめ {{.Start.Format "2006-01-02"}}週{{else}}法令改正 日次まとめ {{.Start.Format "2006-01-02"}}{{end}} ({{.Len}}件)
+{{range .Groups}}
+■ {{with .List}}{{.}}{{else}}(リストなし){{end}}{{with .Category}} / {{.}}{{end}}
+{{range .Changes}}- {{.LawTitle}} ({{.LawID}}){{with .AmendmentLawTitle}} 改正: {{.}}{{end}}
+{{range .Rows}}{{if ne .Status "unchanged"}}    {{.Label}}{{if eq .Status "added"}}（新設）{{else if eq .Status "deleted"}}（削除）{{else}}（改正）{{end}}
+{{end}}{{end}}{{end}}{{end}}`))
+
+// WriteDigest renders a digest with tmpl, or DefaultDigestTemplate if nil
+func WriteDigest(w io.Writer, d Digest, tmpl *template.Template) error {
+	if tmpl == nil {
+		tmpl = DefaultDigestTemplate
+	}
+	return tmpl.Execute(w, d)
+}
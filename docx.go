@@ -0,0 +1,266 @@
+package lawapi
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteComparisonDOCX writes a comparison table (新旧対照表) as a Word document, with the new
+// text on the left and the old text on the right as in official tables. Changed characters are
+// underlined and highlighted. Rows are written as given; filter out ComparisonUnchanged rows
+// to show changed articles only. The notice of NoticeTemplate follows the table.
+func WriteComparisonDOCX(w io.Writer, title string, rows []ComparisonRow) error {
+	var doc docxBuilder
+	doc.paragraph("Title", 0, docxRun{text: title + " 新旧対照表"})
+
+	doc.body.WriteString(`<w:tbl><w:tblPr><w:tblW w:w="5000" w:type="pct"/><w:tblBorders>`)
+	for _, side := range []string{"top", "left", "bottom", "right", "insideH", "insideV"} {
+		fmt.Fprintf(&doc.body, `<w:%s w:val="single" w:sz="4" w:space="0" w:color="000000"/>`, side)
+	}
+	doc.body.WriteString(`</w:tblBorders><w:tblLayout w:type="fixed"/></w:tblPr><w:tblGrid><w:gridCol w:w="1400"/><w:gridCol w:w="4119"/><w:gridCol w:w="4119"/></w:tblGrid>`)
+	doc.body.WriteString(`<w:tr><w:trPr><w:tblHeader/></w:trPr>`)
+	for _, header := range []string{"", "改正後", "改正前"} {
+		doc.cell(func() { doc.paragraph("", 0, docxRun{text: header, bold: true}) })
+	}
+	doc.body.WriteString(`</w:tr>`)
+
+	for _, row := range rows {
+		newSegments, oldSegments := row.New, row.Old
+		switch row.Status {
+		case ComparisonAdded:
+			oldSegments = []DiffSegment{{Text: "（新設）"}}
+		case ComparisonDeleted:
+			newSegments = []DiffSegment{{Text: "（削除）"}}
+		}
+		doc.body.WriteString(`<w:tr><w:trPr><w:cantSplit/></w:trPr>`)
+		doc.cell(func() { doc.paragraph("", 0, docxRun{text: row.Label}) })
+		for _, segments := range [][]DiffSegment{newSegments, oldSegments} {
+			doc.cell(func() { doc.segments(segments) })
+		}
+		doc.body.WriteString(`</w:tr>`)
+	}
+	doc.body.WriteString(`</w:tbl>`)
+	notice := Notice{Edited: true}
+	if title != "" {
+		notice.Titles = []string{title}
+	}
+	if err := doc.notice(notice); err != nil {
+		return err
+	}
+	return doc.write(w)
+}
+
+// WriteExtractDOCX writes the provisions of a law identified by ids as a Word document, each
+// under a heading citing it. It fails if an ID does not resolve in the law. The provenance
+// of the law is stored as custom document properties (jplaw.base_url, jplaw.fetched_at, ...),
+// and the notice of NoticeTemplate closes the document.
+func WriteExtractDOCX(w io.Writer, law *LawNode, ids []ProvisionID) error {
+	doc := docxBuilder{provenance: law.Provenance()}
+	doc.paragraph("Title", 0, docxRun{text: law.Title()})
+	for _, id := range ids {
+		ref, ok := law.provisionRef(id)
+		if !ok {
+			return fmt.Errorf("provision %s not found", id)
+		}
+		doc.paragraph("Heading1", 0, docxRun{text: ref.Label()})
+		for _, line := range provisionLines(law.Provision(id)) {
+			if line.heading > 0 {
+				doc.paragraph("Heading2", 0, docxRun{text: line.text})
+			} else {
+				doc.paragraph("", line.indent, docxRun{text: line.text})
+			}
+		}
+	}
+	if err := doc.notice(lawNotice(false, law)); err != nil {
+		return err
+	}
+	return doc.write(w)
+}
+
+// docxRun is a run of text with uniform formatting
+type docxRun struct {
+	text      string
+	bold      bool
+	highlight bool
+}
+
+// docxBuilder accumulates the body of a WordprocessingML document
+type docxBuilder struct {
+	body bytes.Buffer
+	// provenance is written as custom document properties unless zero
+	provenance Provenance
+}
+
+// paragraph writes a paragraph with the given style ID (empty for Normal) and left indent
+// in characters. Newlines in run text become line breaks.
+func (d *docxBuilder) paragraph(style string, indent int, runs ...docxRun) {
+	d.body.WriteString(`<w:p>`)
+	if style != "" || indent > 0 {
+		d.body.WriteString(`<w:pPr>`)
+		if style != "" {
+			fmt.Fprintf(&d.body, `<w:pStyle w:val="%s"/>`, style)
+		}
+		if indent > 0 {
+			fmt.Fprintf(&d.body, `<w:ind w:leftChars="%d" w:left="%d"/>`, indent*100, indent*210)
+		}
+		d.body.WriteString(`</w:pPr>`)
+	}
+	for _, run := range runs {
+		d.body.WriteString(`<w:r>`)
+		if run.bold || run.highlight {
+			d.body.WriteString(`<w:rPr>`)
+			if run.bold {
+				d.body.WriteString(`<w:b/>`)
+			}
+			if run.highlight {
+				d.body.WriteString(`<w:highlight w:val="yellow"/><w:u w:val="single"/>`)
+			}
+			d.body.WriteString(`</w:rPr>`)
+		}
+		for i, line := range strings.Split(run.text, "\n") {
+			if i > 0 {
+				d.body.WriteString(`<w:br/>`)
+			}
+			d.body.WriteString(`<w:t xml:space="preserve">`)
+			xml.EscapeText(&d.body, []byte(line))
+			d.body.WriteString(`</w:t>`)
+		}
+		d.body.WriteString(`</w:r>`)
+	}
+	d.body.WriteString(`</w:p>`)
+}
+
+// notice writes the lines of a notice as paragraphs, after an empty paragraph
+func (d *docxBuilder) notice(n Notice) error {
+	lines, err := n.lines()
+	if err != nil || len(lines) == 0 {
+		return err
+	}
+	d.paragraph("", 0)
+	for _, line := range lines {
+		d.paragraph("", 0, docxRun{text: line})
+	}
+	return nil
+}
+
+// segments writes diff segments as one paragraph per line, highlighting changed runs
+func (d *docxBuilder) segments(segments []DiffSegment) {
+	var line []docxRun
+	for _, segment := range segments {
+		parts := strings.Split(segment.Text, "\n")
+		for i, part := range parts {
+			if part != "" {
+				line = append(line, docxRun{text: part, highlight: segment.Changed})
+			}
+			if i < len(parts)-1 {
+				d.paragraph("", 0, line...)
+				line = nil
+			}
+		}
+	}
+	d.paragraph("", 0, line...)
+}
+
+// cell writes a table cell whose content is written by fn. Cells must contain a paragraph.
+func (d *docxBuilder) cell(fn func()) {
+	d.body.WriteString(`<w:tc>`)
+	fn()
+	d.body.WriteString(`</w:tc>`)
+}
+
+// write packages the document with its styles as a .docx archive
+func (d *docxBuilder) write(w io.Writer) error {
+	document := xml.Header + `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		d.body.String() +
+		`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1701" w:right="1134" w:bottom="1701" w:left="1134" w:header="851" w:footer="992" w:gutter="0"/></w:sectPr></w:body></w:document>`
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRels},
+		{"word/_rels/document.xml.rels", docxDocumentRels},
+		{"word/styles.xml", docxStyles},
+		{"word/document.xml", document},
+	}
+	if !d.provenance.IsZero() {
+		parts[0].content = strings.Replace(docxContentTypes, `</Types>`, `<Override PartName="/docProps/custom.xml" ContentType="application/vnd.openxmlformats-officedocument.custom-properties+xml"/></Types>`, 1)
+		parts[1].content = strings.Replace(docxRels, `</Relationships>`, `<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/custom-properties" Target="docProps/custom.xml"/></Relationships>`, 1)
+		parts = append(parts, struct{ name, content string }{"docProps/custom.xml", docxCustomProperties(d.provenance)})
+	}
+
+	archive := zip.NewWriter(w)
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+const docxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`</Types>`
+
+const docxRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`</Relationships>`
+
+const docxDocumentRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// docxStyles uses Mincho for body text at 10.5pt, the usual size of Japanese documents
+var docxStyles = xml.Header + `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="ＭＳ 明朝" w:eastAsia="ＭＳ 明朝" w:hAnsi="ＭＳ 明朝"/><w:sz w:val="21"/><w:lang w:val="en-US" w:eastAsia="ja-JP"/></w:rPr></w:rPrDefault></w:docDefaults>` +
+	`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+	docxHeadingStyle("Title", "Title", 32) +
+	docxHeadingStyle("Heading1", "heading 1", 24) +
+	docxHeadingStyle("Heading2", "heading 2", 22) +
+	`</w:styles>`
+
+// docxCustomProperties returns the custom properties part recording a provenance
+func docxCustomProperties(p Provenance) string {
+	var b strings.Builder
+	b.WriteString(xml.Header + `<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/custom-properties" xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes">`)
+	fetchedAt := ""
+	if !p.FetchedAt.IsZero() {
+		fetchedAt = p.FetchedAt.UTC().Format(time.RFC3339)
+	}
+	pid := 2
+	for _, prop := range []struct{ name, value string }{
+		{"jplaw.base_url", p.BaseURL},
+		{"jplaw.fetched_at", fetchedAt},
+		{"jplaw.law_revision_id", p.LawRevisionID},
+		{"jplaw.package_version", p.PackageVersion},
+		{"jplaw.content_sha256", p.ContentSHA256},
+	} {
+		if prop.value == "" {
+			continue
+		}
+		// Property IDs start at 2; fmtid is the one reserved for user-defined properties
+		fmt.Fprintf(&b, `<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="%d" name="%s"><vt:lpwstr>`, pid, prop.name)
+		xml.EscapeText(&b, []byte(prop.value))
+		b.WriteString(`</vt:lpwstr></property>`)
+		pid++
+	}
+	b.WriteString(`</Properties>`)
+	return b.String()
+}
+
+func docxHeadingStyle(id, name string, size int) string {
+	return `<w:style w:type="paragraph" w:styleId="` + id + `"><w:name w:val="` + name + `"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/>` +
+		`<w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/></w:pPr>` +
+		`<w:rPr><w:rFonts w:ascii="ＭＳ ゴシック" w:eastAsia="ＭＳ ゴシック" w:hAnsi="ＭＳ ゴシック"/><w:b/><w:sz w:val="` + strconv.Itoa(size) + `"/></w:rPr></w:style>`
+}
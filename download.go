@@ -0,0 +1,267 @@
+package lawapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrDownloadChanged is returned when a download is interrupted and the content changed on
+// the server before it could be resumed, so the bytes already written cannot be completed
+var ErrDownloadChanged = errors.New("content changed during download")
+
+// DownloadOptions configure streaming downloads
+type DownloadOptions struct {
+	// MaxAttempts is the maximum number of requests, including the first one (default 5)
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled for each further retry, unless
+	// the server asks for a delay with Retry-After (default 1s)
+	Backoff time.Duration
+}
+
+// Download streams the response to a GET request into w, such as one built by
+// BuildGetLawFileRequest or BuildGetAttachmentRequest for large law files and attachments.
+// Connection failures, interrupted transfers, 429 and 5xx responses are retried. An
+// interrupted transfer resumes from the last byte received with a Range request. If the
+// server sent an ETag or Last-Modified date, the request is guarded by If-Range so that a
+// changed file is not spliced, and ErrDownloadChanged is returned instead. Servers that do
+// not support ranges send the whole content again, and the bytes already written are
+// skipped. It returns the number of bytes written.
+func (c *Client) Download(ctx context.Context, req *http.Request, w io.Writer, opts *DownloadOptions) (int64, error) {
+	return c.download(ctx, req, w, nil, opts)
+}
+
+// DownloadLawFile streams a law file (xml, json, html, rtf or docx) into w with Download
+func (c *Client) DownloadLawFile(ctx context.Context, w io.Writer, lawIdOrNumOrRevisionId string, fileType string, params *GetLawFileParams, opts *DownloadOptions) (int64, error) {
+	req, err := c.BuildGetLawFileRequest(ctx, lawIdOrNumOrRevisionId, fileType, params)
+	if err != nil {
+		return 0, err
+	}
+	return c.Download(ctx, req, w, opts)
+}
+
+// DownloadAttachment streams an attachment of a law revision into w with Download
+func (c *Client) DownloadAttachment(ctx context.Context, w io.Writer, lawRevisionId string, params *GetAttachmentParams, opts *DownloadOptions) (int64, error) {
+	req, err := c.BuildGetAttachmentRequest(ctx, lawRevisionId, params)
+	if err != nil {
+		return 0, err
+	}
+	return c.Download(ctx, req, w, opts)
+}
+
+// DownloadFile downloads the response to a GET request to a file with Download. The file
+// is written atomically (see WriteAtomic), and a download whose content changed on the
+// server while resuming starts over instead of failing with ErrDownloadChanged.
+func (c *Client) DownloadFile(ctx context.Context, req *http.Request, path string, opts *DownloadOptions) (int64, error) {
+	f, err := CreateAtomic(path, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	restart := func() error {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		return f.Truncate(0)
+	}
+	n, err := c.download(ctx, req, f.File, restart, opts)
+	if err != nil {
+		return n, err
+	}
+	return n, f.Commit()
+}
+
+// download implements Download. restart, if not nil, discards the bytes written to w so
+// that a changed content can be downloaded again. All attempts carry the correlation ID of
+// req, else of ctx, else a new one, which the returned error reports.
+func (c *Client) download(ctx context.Context, req *http.Request, w io.Writer, restart func() error, opts *DownloadOptions) (int64, error) {
+	correlationID := req.Header.Get(CorrelationIDHeader)
+	if correlationID != "" {
+		ctx = WithCorrelationID(ctx, correlationID)
+	} else {
+		ctx, correlationID = ensureCorrelationID(ctx)
+	}
+	n, err := c.downloadAttempts(ctx, req, w, restart, opts)
+	return n, withCorrelationID(err, correlationID)
+}
+
+// downloadAttempts sends req until its response is written to w or the attempts are
+// exhausted
+func (c *Client) downloadAttempts(ctx context.Context, req *http.Request, w io.Writer, restart func() error, opts *DownloadOptions) (int64, error) {
+	maxAttempts, backoff := 5, time.Second
+	if opts != nil && opts.MaxAttempts > 0 {
+		maxAttempts = opts.MaxAttempts
+	}
+	if opts != nil && opts.Backoff > 0 {
+		backoff = opts.Backoff
+	}
+
+	var written int64
+	// validator is the ETag or Last-Modified of the content being written, if any
+	var validator string
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			if err := sleepContext(ctx, c.currentClock(), retryDelay(lastErr, backoff)); err != nil {
+				return written, err
+			}
+			backoff *= 2
+		}
+
+		r := req.Clone(ctx)
+		setCorrelationID(r)
+		if written > 0 {
+			r.Header.Set("Range", "bytes="+strconv.FormatInt(written, 10)+"-")
+			if validator != "" {
+				r.Header.Set("If-Range", validator)
+			}
+		}
+		resp, err := c.httpClient.Do(r)
+		if err != nil {
+			if ctx.Err() != nil {
+				return written, ctx.Err()
+			}
+			lastErr = fmt.Errorf("failed to execute request: %w", err)
+			continue
+		}
+
+		// skip is the number of bytes of the body that were already written
+		var skip int64
+		switch {
+		case resp.StatusCode == http.StatusPartialContent && written > 0:
+			start, ok := contentRangeStart(resp.Header.Get("Content-Range"))
+			if !ok || start > written {
+				resp.Body.Close()
+				return written, fmt.Errorf("unexpected Content-Range %q resuming at byte %d", resp.Header.Get("Content-Range"), written)
+			}
+			skip = written - start
+		case resp.StatusCode == http.StatusOK:
+			if written > 0 {
+				// The range was ignored, or If-Range failed because the content changed
+				if responseValidator(resp) != validator {
+					if restart == nil {
+						resp.Body.Close()
+						return written, ErrDownloadChanged
+					}
+					if err := restart(); err != nil {
+						resp.Body.Close()
+						return written, err
+					}
+					written = 0
+				}
+				skip = written
+			}
+			validator = responseValidator(resp)
+		default:
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+			lastErr = &APIError{StatusCode: resp.StatusCode, Body: string(body), CorrelationID: CorrelationIDFromContext(ctx)}
+			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+				if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), c.currentClock().Now()); ok {
+					lastErr = &retryAfterError{err: lastErr, delay: delay}
+				}
+				continue
+			}
+			return written, lastErr
+		}
+
+		if skip > 0 {
+			if _, err := io.CopyN(io.Discard, resp.Body, skip); err != nil {
+				resp.Body.Close()
+				lastErr = fmt.Errorf("failed to read response: %w", err)
+				continue
+			}
+		}
+		dst := &downloadWriter{w: w}
+		n, err := io.Copy(dst, resp.Body)
+		written += n
+		resp.Body.Close()
+		if err == nil {
+			return written, nil
+		}
+		if ctx.Err() != nil {
+			return written, ctx.Err()
+		}
+		if dst.err != nil {
+			// Writing failed, not reading
+			return written, dst.err
+		}
+		lastErr = fmt.Errorf("failed to read response: %w", err)
+	}
+	return written, lastErr
+}
+
+// downloadWriter records write errors, to tell them from read errors of io.Copy
+type downloadWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (d *downloadWriter) Write(p []byte) (int, error) {
+	n, err := d.w.Write(p)
+	if err != nil {
+		d.err = err
+	}
+	return n, err
+}
+
+// responseValidator returns the value for If-Range of a response: a strong ETag, else the
+// Last-Modified date, else empty
+func responseValidator(resp *http.Response) string {
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return resp.Header.Get("Last-Modified")
+}
+
+// contentRangeStart parses the first byte position of a Content-Range header
+func contentRangeStart(header string) (int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	first, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	return start, err == nil
+}
+
+// retryAfterError is a retryable error with the delay the server asked for
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+// retryDelay returns the delay before retrying after err: the Retry-After delay if given,
+// else backoff
+func retryDelay(err error, backoff time.Duration) time.Duration {
+	var retryAfter *retryAfterError
+	if errors.As(err, &retryAfter) {
+		return retryAfter.delay
+	}
+	return backoff
+}
+
+// parseRetryAfter parses a Retry-After header in seconds or as an HTTP date, relative to now
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
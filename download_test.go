@@ -0,0 +1,57 @@
+package lawapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestDownloadRetryAfterManualClock checks that a download waits for the delay the server
+// asks for on the clock of the client
+func TestDownloadRetryAfterManualClock(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("<Law/>"))
+	}))
+	defer server.Close()
+	clock := NewManualClock(time.Date(2024, 4, 1, 9, 0, 0, 0, JST))
+	client := NewClient()
+	client.SetClock(clock)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Download(context.Background(), req, &buf, &DownloadOptions{Backoff: time.Hour})
+		done <- err
+	}()
+	waitForWaiters(t, clock, 1)
+	clock.Advance(29 * time.Second)
+	select {
+	case err := <-done:
+		t.Fatalf("download ended before the delay: %v", err)
+	default:
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("%d requests before the delay, want 1", got)
+	}
+	clock.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "<Law/>" || requests.Load() != 2 {
+		t.Errorf("got %q after %d requests", buf.String(), requests.Load())
+	}
+}
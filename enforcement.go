@@ -0,0 +1,275 @@
+package lawapi
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// EnforcementKind is the form of an enforcement date (施行期日)
+type EnforcementKind string
+
+const (
+	// EnforcementFixed is a calendar date (e.g. 令和六年四月一日)
+	EnforcementFixed EnforcementKind = "fixed"
+	// EnforcementPromulgation is the day of promulgation (公布の日), or a period after it
+	// (公布の日から起算して六月を経過した日)
+	EnforcementPromulgation EnforcementKind = "promulgation"
+	// EnforcementDelegated is a day set by a cabinet order or other regulation (政令で定める日),
+	// possibly within a period after promulgation
+	EnforcementDelegated EnforcementKind = "delegated"
+	// EnforcementOther is any other expression, such as a reference to another event
+	EnforcementOther EnforcementKind = "other"
+)
+
+// EnforcementPeriod is a period counted from the day of promulgation
+type EnforcementPeriod struct {
+	Years  int `json:"years,omitempty"`
+	Months int `json:"months,omitempty"`
+	Days   int `json:"days,omitempty"`
+}
+
+// EnforcementStage is a set of provisions taking effect on the same date
+type EnforcementStage struct {
+	// Provisions describes the provisions as written (e.g. "第二条中電波法第百三条の改正規定"),
+	// or is empty for the law as a whole, that is the provisions not listed in other stages
+	Provisions string `json:"provisions,omitempty"`
+	// Text is the enforcement date as written (e.g. "公布の日から起算して一年を超えない範囲内において政令で定める日")
+	Text string `json:"text"`
+	// Kind is the form of the date
+	Kind EnforcementKind `json:"kind"`
+	// Date is the enforcement date, if fixed or computable from the promulgation date
+	Date *Date `json:"date,omitempty"`
+	// After is the period after promulgation, for EnforcementPromulgation dates other than
+	// the day of promulgation itself
+	After *EnforcementPeriod `json:"after,omitempty"`
+	// Authority is the form of the regulation setting the date (e.g. "政令"), for EnforcementDelegated
+	Authority string `json:"authority,omitempty"`
+	// Deadline is the last possible date of an EnforcementDelegated date bounded by a period
+	// after promulgation (…を超えない範囲内において), if the promulgation date is known
+	Deadline *Date `json:"deadline,omitempty"`
+	// Source is the citation of the supplementary provision (e.g. "附則第一条第二号")
+	Source string `json:"source"`
+}
+
+// EnforcementSchedule is the staged enforcement of a law or amendment, parsed from the
+// enforcement clause (施行期日) of its supplementary provisions
+type EnforcementSchedule struct {
+	// AmendLawNum is the law number of the amendment whose supplementary provision this is,
+	// or empty for the original supplementary provision of the law
+	AmendLawNum string `json:"amend_law_num,omitempty"`
+	// Promulgated is the promulgation date, if known
+	Promulgated *Date `json:"promulgated,omitempty"`
+	// Stages are the enforcement stages: the law as a whole first, then the exceptions in
+	// the order of the text
+	Stages []EnforcementStage `json:"stages"`
+}
+
+// Staged reports whether provisions take effect on different dates
+func (s EnforcementSchedule) Staged() bool {
+	return len(s.Stages) > 1
+}
+
+// InForce returns the stages in force on a date: those with a date on or before it. A
+// zero date is taken as Today.
+func (s EnforcementSchedule) InForce(on Date) []EnforcementStage {
+	on = dateOrToday(on)
+	var stages []EnforcementStage
+	for _, stage := range s.Stages {
+		if stage.Date != nil && !time.Time(*stage.Date).After(time.Time(on)) {
+			stages = append(stages, stage)
+		}
+	}
+	return stages
+}
+
+// Pending returns the stages not yet in force on a date, including stages whose date is
+// not known, such as dates still to be set by cabinet order. A zero date is taken as
+// Today.
+func (s EnforcementSchedule) Pending(on Date) []EnforcementStage {
+	on = dateOrToday(on)
+	var stages []EnforcementStage
+	for _, stage := range s.Stages {
+		if stage.Date == nil || time.Time(*stage.Date).After(time.Time(on)) {
+			stages = append(stages, stage)
+		}
+	}
+	return stages
+}
+
+// PartiallyInForce reports whether some but not all stages are in force on a date, or
+// today if it is zero
+func (s EnforcementSchedule) PartiallyInForce(on Date) bool {
+	return len(s.InForce(on)) > 0 && len(s.Pending(on)) > 0
+}
+
+// enforcementItemsDate is the date of 「次の各号に掲げる規定は、当該各号に定める日から施行する」
+const enforcementItemsDate = "当該各号に定める日"
+
+var (
+	// enforcementClausePattern matches 「…は、…から施行する」 and captures the subject and the date
+	enforcementClausePattern = regexp.MustCompile(`^(?:ただし、)?(.+?)は、(.+?)から施行する`)
+	// enforcementLawPattern matches the subject of the enforcement of the whole law
+	enforcementLawPattern       = regexp.MustCompile(`^この(?:法律|政令|省令|府令|規則|命令|告示|訓令|条例)$`)
+	enforcementAfterPattern     = regexp.MustCompile(`^公布の日から起算して(` + kanjiNumberClass + `)(日|月|年)を経過した日$`)
+	enforcementDelegatedPattern = regexp.MustCompile(`^(?:公布の日から起算して(` + kanjiNumberClass + `)(日|月|年)を超えない範囲内において)?([\p{Han}\p{Katakana}ー]*?(?:政令|省令|府令|規則))で定める日$`)
+	amendLawNumDatePattern      = regexp.MustCompile(`^(?:明治|大正|昭和|平成|令和)[^年]+年[^月]+月[^日]+日`)
+)
+
+// EnforcementSchedules parses the enforcement schedules of the supplementary provisions of
+// a law: that of the law itself, and in consolidated texts those of its amendments.
+// Supplementary provisions without an enforcement clause are skipped.
+func (n *LawNode) EnforcementSchedules() []EnforcementSchedule {
+	promulgated := lawPromulgationDate(n)
+	var schedules []EnforcementSchedule
+	for _, suppl := range n.FindAll("SupplProvision") {
+		amendLawNum := suppl.Attr["AmendLawNum"]
+		date := promulgated
+		if amendLawNum != "" {
+			date = nil
+			if d, err := ParseWareki(amendLawNumDatePattern.FindString(amendLawNum)); err == nil {
+				date = &d
+			}
+		}
+		schedule := ParseEnforcementSchedule(suppl, date)
+		if len(schedule.Stages) == 0 {
+			continue
+		}
+		schedule.AmendLawNum = amendLawNum
+		schedules = append(schedules, schedule)
+	}
+	return schedules
+}
+
+// ParseEnforcementSchedule parses the enforcement clause of a supplementary provision
+// (SupplProvision element). Dates relative to promulgation are computed if promulgated is
+// not nil. Staged enforcement is recognized in the forms 「ただし、…の規定は、…から施行する」,
+// separate paragraphs or articles, and 「次の各号に掲げる規定は、当該各号に定める日から施行する」
+// followed by items listing provisions and dates.
+func ParseEnforcementSchedule(suppl *LawNode, promulgated *Date) EnforcementSchedule {
+	schedule := EnforcementSchedule{Promulgated: promulgated}
+	// itemsParagraph is the paragraph whose items list provisions and dates
+	var itemsParagraph *LawNode
+	items := map[*LawNode]*EnforcementStage{}
+	for _, ref := range suppl.Sentences() {
+		text := strings.TrimSpace(ref.Sentence.PlainText())
+		if ref.Item != nil {
+			if ref.Paragraph != itemsParagraph || len(ref.Subitems) > 0 {
+				continue
+			}
+			stage, ok := items[ref.Item]
+			if !ok {
+				stage = &EnforcementStage{Source: SentenceRef{Article: ref.Article, Paragraph: ref.Paragraph, Item: ref.Item, SupplProvision: true}.Label()}
+				items[ref.Item] = stage
+			}
+			// Items have the provisions and the date in two columns, or separated by a space
+			if stage.Provisions == "" {
+				provisions, date, ok := strings.Cut(text, "　")
+				stage.Provisions = strings.TrimSpace(provisions)
+				if !ok {
+					continue
+				}
+				text = date
+			}
+			stage.Text = strings.TrimSpace(text)
+			schedule.Stages = append(schedule.Stages, *stage)
+			continue
+		}
+
+		m := enforcementClausePattern.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		subject, dateText := m[1], m[2]
+		if dateText == enforcementItemsDate {
+			itemsParagraph = ref.Paragraph
+			continue
+		}
+		stage := EnforcementStage{Text: dateText, Source: SentenceRef{Article: ref.Article, Paragraph: ref.Paragraph, SupplProvision: true}.Label()}
+		if !enforcementLawPattern.MatchString(subject) {
+			stage.Provisions = subject
+		}
+		schedule.Stages = append(schedule.Stages, stage)
+	}
+	for i := range schedule.Stages {
+		schedule.Stages[i].resolve(promulgated)
+	}
+	return schedule
+}
+
+// resolve parses the date text of the stage
+func (s *EnforcementStage) resolve(promulgated *Date) {
+	text := strings.TrimSpace(s.Text)
+	s.Kind = EnforcementOther
+	switch {
+	case text == "公布の日":
+		s.Kind = EnforcementPromulgation
+	case text == "公布の日の翌日":
+		s.Kind = EnforcementPromulgation
+		s.After = &EnforcementPeriod{Days: 1}
+	default:
+		if date, err := ParseWareki(text); err == nil {
+			s.Kind = EnforcementFixed
+			s.Date = &date
+			return
+		}
+		if m := enforcementAfterPattern.FindStringSubmatch(text); m != nil {
+			s.Kind = EnforcementPromulgation
+			period := enforcementPeriod(m[1], m[2])
+			s.After = &period
+		} else if m := enforcementDelegatedPattern.FindStringSubmatch(text); m != nil {
+			s.Kind = EnforcementDelegated
+			s.Authority = m[3]
+			if m[1] != "" && promulgated != nil {
+				// The period includes the day of promulgation (起算して)
+				period := enforcementPeriod(m[1], m[2])
+				deadline := Date(time.Time(*promulgated).AddDate(period.Years, period.Months, period.Days-1))
+				s.Deadline = &deadline
+			}
+			return
+		}
+	}
+	if s.Kind == EnforcementPromulgation && promulgated != nil {
+		date := *promulgated
+		if s.After != nil {
+			date = Date(time.Time(date).AddDate(s.After.Years, s.After.Months, s.After.Days))
+		}
+		s.Date = &date
+	}
+}
+
+// enforcementPeriod converts a number and a unit (日, 月 or 年) to a period
+func enforcementPeriod(number, unit string) EnforcementPeriod {
+	n, _ := ParseKanjiNumber(number)
+	switch unit {
+	case "年":
+		return EnforcementPeriod{Years: int(n)}
+	case "月":
+		return EnforcementPeriod{Months: int(n)}
+	}
+	return EnforcementPeriod{Days: int(n)}
+}
+
+// lawPromulgationDate returns the promulgation date of a law from the attributes of the
+// Law element, or nil
+func lawPromulgationDate(law *LawNode) *Date {
+	root := law
+	if root.Tag != "Law" {
+		root = law.Find("Law")
+	}
+	if root == nil {
+		return nil
+	}
+	era, ok := ParseEra(root.Attr["Era"])
+	if !ok {
+		era = LawNumEra(root.Attr["Era"])
+	}
+	year, _ := ParseKanjiNumber(root.Attr["Year"])
+	month, _ := ParseKanjiNumber(root.Attr["PromulgateMonth"])
+	day, _ := ParseKanjiNumber(root.Attr["PromulgateDay"])
+	if era.Year(int(year)) == 0 || month == 0 || day == 0 {
+		return nil
+	}
+	date := Date(time.Date(era.Year(int(year)), time.Month(month), int(day), 0, 0, 0, 0, time.UTC))
+	return &date
+}
@@ -0,0 +1,98 @@
+package lawapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrUnknownEnumValue is the cause of the errors of strict enum decoding
+var ErrUnknownEnumValue = errors.New("unknown enum value")
+
+// EnumDecoding is how the client decodes enum values that are not in the specification,
+// such as law types or categories added to the API after this package was generated
+type EnumDecoding int
+
+const (
+	// EnumLenient keeps unknown values as received; IsKnown reports them. This is the
+	// default, so that additions to the API do not break existing programs.
+	EnumLenient EnumDecoding = iota
+	// EnumStrict fails calls whose responses contain unknown values with errors wrapping
+	// ErrUnknownEnumValue, for programs that must handle every value
+	EnumStrict
+)
+
+// SetEnumDecoding sets how the client decodes enum values not in the specification
+// (default: EnumLenient)
+func (c *Client) SetEnumDecoding(decoding EnumDecoding) {
+	c.enumDecoding = decoding
+}
+
+// checkEnums checks the enum values of a decoded response in strict mode
+func (c *Client) checkEnums(v any) error {
+	if c.enumDecoding != EnumStrict {
+		return nil
+	}
+	return CheckEnums(v)
+}
+
+// knownEnum is implemented by the enum types of the specification
+type knownEnum interface {
+	IsKnown() bool
+}
+
+var knownEnumType = reflect.TypeOf((*knownEnum)(nil)).Elem()
+
+// CheckEnums returns an error listing the enum values of a decoded response, or of any
+// value containing the types of the specification, that are not in the specification,
+// e.g. "revision_info.law_type: unknown enum value "Treaty" for LawType". Empty values
+// are taken as absent. Use it for responses decoded without a client, such as mirrored
+// ones.
+func CheckEnums(v any) error {
+	var errs []error
+	checkEnumValue(reflect.ValueOf(v), "", &errs)
+	return WithErrorCategory(errors.Join(errs...), ErrorValidation)
+}
+
+// checkEnumValue appends the unknown enum values within v to errs
+func checkEnumValue(v reflect.Value, path string, errs *[]error) {
+	if !v.IsValid() {
+		return
+	}
+	if v.Type().Implements(knownEnumType) && v.Kind() == reflect.String {
+		if v.Len() > 0 && !v.Interface().(knownEnum).IsKnown() {
+			*errs = append(*errs, fmt.Errorf("%s: %w %q for %s", strings.TrimPrefix(path, "."), ErrUnknownEnumValue, v.String(), v.Type().Name()))
+		}
+		return
+	}
+	// Interface values, such as law_full_text, only hold decoded JSON types and are not walked
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			checkEnumValue(v.Elem(), path, errs)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" {
+				name = field.Name
+			}
+			checkEnumValue(v.Field(i), path+"."+name, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			checkEnumValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			checkEnumValue(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()), errs)
+		}
+	}
+}
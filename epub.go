@@ -0,0 +1,258 @@
+package lawapi
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/xml"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strings"
+)
+
+// WriteEPUB packages laws into an EPUB 3 book for offline reading. Each law becomes a
+// chapter of the book, and the table of contents links to the parts, chapters, sections,
+// supplementary provisions and articles of every law. An NCX table of contents is included
+// for EPUB 2 readers. The provenance of each law is written as a jplaw-provenance meta
+// element of its chapter. The notice of NoticeTemplate closes each chapter, and the notice
+// for all laws is the dc:rights of the book.
+func WriteEPUB(w io.Writer, title string, laws ...*LawNode) error {
+	var toc []*epubTOCNode
+	var files []string
+	var contents []string
+	for i, law := range laws {
+		file := fmt.Sprintf("law%d.xhtml", i+1)
+		notice, err := lawNotice(false, law).lines()
+		if err != nil {
+			return err
+		}
+		content, node := epubLawDocument(law, file, notice)
+		files = append(files, file)
+		contents = append(contents, content)
+		toc = append(toc, node)
+	}
+
+	// The identifier is derived from the content so that identical books share it
+	hash := sha256.New()
+	for _, content := range contents {
+		io.WriteString(hash, content)
+	}
+	sum := hash.Sum(nil)
+	identifier := fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+	rights, err := lawNotice(false, laws...).Text()
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+	// The mimetype must be the first entry, stored uncompressed without a data descriptor
+	const mimetypeContent = "application/epub+zip"
+	mimetype, err := archive.CreateRaw(&zip.FileHeader{
+		Name:               "mimetype",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE([]byte(mimetypeContent)),
+		CompressedSize64:   uint64(len(mimetypeContent)),
+		UncompressedSize64: uint64(len(mimetypeContent)),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mimetype, mimetypeContent); err != nil {
+		return err
+	}
+
+	parts := []struct{ name, content string }{
+		{"META-INF/container.xml", epubContainer},
+		{"OEBPS/content.opf", epubPackage(title, identifier, rights, files)},
+		{"OEBPS/nav.xhtml", epubNav(title, toc)},
+		{"OEBPS/toc.ncx", epubNCX(title, identifier, toc)},
+		{"OEBPS/style.css", epubStyle},
+	}
+	for i, file := range files {
+		parts = append(parts, struct{ name, content string }{"OEBPS/" + file, contents[i]})
+	}
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// epubTOCNode is an entry of the table of contents
+type epubTOCNode struct {
+	label    string
+	href     string
+	level    int
+	children []*epubTOCNode
+}
+
+// epubLawDocument renders a law as an XHTML content document ending with the notice lines,
+// and returns it with its table of contents
+func epubLawDocument(law *LawNode, file string, notice []string) (string, *epubTOCNode) {
+	root := &epubTOCNode{label: law.Title(), href: file}
+	stack := []*epubTOCNode{root}
+	headingLevel := 0
+	addEntry := func(label, anchor string, level int) {
+		for len(stack) > 1 && stack[len(stack)-1].level >= level {
+			stack = stack[:len(stack)-1]
+		}
+		node := &epubTOCNode{label: label, href: file + "#" + anchor, level: level}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, node)
+		stack = append(stack, node)
+	}
+
+	var b strings.Builder
+	b.WriteString(xml.Header + "<!DOCTYPE html>\n")
+	b.WriteString(`<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="ja" lang="ja">` + "\n")
+	b.WriteString("<head>\n<meta charset=\"utf-8\"/>\n")
+	if meta := provenanceMeta(law.Provenance()); meta != "" {
+		fmt.Fprintf(&b, "<meta name=\"jplaw-provenance\" content=\"%s\"/>\n", epubEscape(meta))
+	}
+	fmt.Fprintf(&b, "<title>%s</title>\n<link rel=\"stylesheet\" type=\"text/css\" href=\"style.css\"/>\n</head>\n<body>\n", epubEscape(law.Title()))
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", epubEscape(law.Title()))
+	if lawNum := strings.TrimSpace(law.Find("LawNum").PlainText()); lawNum != "" {
+		fmt.Fprintf(&b, "<p class=\"lawnum\">%s</p>\n", epubEscape(lawNum))
+	}
+	for i, line := range provisionLines(law.Find("LawBody")) {
+		anchor := fmt.Sprintf("l%d", i+1)
+		switch {
+		case line.heading > 0:
+			headingLevel = line.heading
+			addEntry(line.text, anchor, line.heading)
+			level := min(line.heading+1, 6)
+			fmt.Fprintf(&b, "<h%d id=\"%s\">%s</h%d>\n", level, anchor, epubEscape(line.text), level)
+			continue
+		case line.article != "":
+			addEntry(line.article, anchor, headingLevel+1)
+		}
+		fmt.Fprintf(&b, "<p id=\"%s\" class=\"indent%d\">%s</p>\n", anchor, min(line.indent, 5), epubEscape(line.text))
+	}
+	if len(notice) > 0 {
+		b.WriteString("<footer class=\"notice\">\n")
+		for _, line := range notice {
+			fmt.Fprintf(&b, "<p>%s</p>\n", epubEscape(line))
+		}
+		b.WriteString("</footer>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String(), root
+}
+
+func epubPackage(title, identifier, rights string, files []string) string {
+	var manifest, spine, metadata strings.Builder
+	if rights != "" {
+		fmt.Fprintf(&metadata, "<dc:rights>%s</dc:rights>\n", epubEscape(rights))
+	}
+	for i, file := range files {
+		fmt.Fprintf(&manifest, "<item id=\"law%d\" href=\"%s\" media-type=\"application/xhtml+xml\"/>\n", i+1, file)
+		fmt.Fprintf(&spine, "<itemref idref=\"law%d\"/>\n", i+1)
+	}
+	return xml.Header + `<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="ja">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:identifier id="book-id">` + identifier + `</dc:identifier>
+<dc:title>` + epubEscape(title) + `</dc:title>
+<dc:language>ja</dc:language>
+` + metadata.String() + `<meta property="dcterms:modified">` + DefaultClock.Now().UTC().Format("2006-01-02T15:04:05Z") + `</meta>
+</metadata>
+<manifest>
+<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+<item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+<item id="style" href="style.css" media-type="text/css"/>
+` + manifest.String() + `</manifest>
+<spine toc="ncx">
+` + spine.String() + `</spine>
+</package>
+`
+}
+
+func epubNav(title string, toc []*epubTOCNode) string {
+	var b strings.Builder
+	var write func(nodes []*epubTOCNode)
+	write = func(nodes []*epubTOCNode) {
+		b.WriteString("<ol>\n")
+		for _, node := range nodes {
+			fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a>", node.href, epubEscape(node.label))
+			if len(node.children) > 0 {
+				b.WriteString("\n")
+				write(node.children)
+			}
+			b.WriteString("</li>\n")
+		}
+		b.WriteString("</ol>\n")
+	}
+	write(toc)
+	return xml.Header + "<!DOCTYPE html>\n" +
+		`<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="ja" lang="ja">
+<head>
+<meta charset="utf-8"/>
+<title>` + epubEscape(title) + `</title>
+</head>
+<body>
+<nav epub:type="toc" id="toc">
+<h1>目次</h1>
+` + b.String() + `</nav>
+</body>
+</html>
+`
+}
+
+func epubNCX(title, identifier string, toc []*epubTOCNode) string {
+	var b strings.Builder
+	order, depth := 0, 0
+	var write func(nodes []*epubTOCNode, level int)
+	write = func(nodes []*epubTOCNode, level int) {
+		for _, node := range nodes {
+			depth = max(depth, level)
+			order++
+			fmt.Fprintf(&b, "<navPoint id=\"nav%d\" playOrder=\"%d\"><navLabel><text>%s</text></navLabel><content src=\"%s\"/>\n", order, order, epubEscape(node.label), node.href)
+			write(node.children, level+1)
+			b.WriteString("</navPoint>\n")
+		}
+	}
+	write(toc, 1)
+	return xml.Header + `<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1" xml:lang="ja">
+<head>
+<meta name="dtb:uid" content="` + identifier + `"/>
+<meta name="dtb:depth" content="` + fmt.Sprint(depth) + `"/>
+<meta name="dtb:totalPageCount" content="0"/>
+<meta name="dtb:maxPageNumber" content="0"/>
+</head>
+<docTitle><text>` + epubEscape(title) + `</text></docTitle>
+<navMap>
+` + b.String() + `</navMap>
+</ncx>
+`
+}
+
+func epubEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const epubContainer = xml.Header + `<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles>
+<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+</rootfiles>
+</container>
+`
+
+const epubStyle = `body { font-family: serif; line-height: 1.7; }
+h1, h2, h3, h4, h5, h6 { font-family: sans-serif; }
+.lawnum { text-align: right; }
+p { margin: 0; text-indent: -1em; }
+.indent0 { padding-left: 1em; }
+.indent1 { padding-left: 2em; }
+.indent2 { padding-left: 3em; }
+.indent3 { padding-left: 4em; }
+.indent4 { padding-left: 5em; }
+.indent5 { padding-left: 6em; }
+.notice { margin-top: 2em; font-size: smaller; }
+.notice p { text-indent: 0; }
+`
@@ -0,0 +1,107 @@
+package lawapi
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// ErrorCategory classifies failures so that programs and scripts can react to the kind of
+// failure rather than to error messages
+type ErrorCategory string
+
+// Error categories
+const (
+	// ErrorInternal covers failures that fit no other category, such as I/O errors and bugs
+	ErrorInternal ErrorCategory = "internal"
+	// ErrorNetwork covers connection failures, timeouts and server errors (5xx)
+	ErrorNetwork ErrorCategory = "network"
+	// ErrorNotFound covers laws, revisions, provisions and files that do not exist
+	ErrorNotFound ErrorCategory = "not_found"
+	// ErrorRateLimited covers 429 responses and exhausted request budgets
+	ErrorRateLimited ErrorCategory = "rate_limited"
+	// ErrorValidation covers invalid input and checks that failed, such as schema
+	// violations, bad requests (400) and invalid manifest signatures
+	ErrorValidation ErrorCategory = "validation"
+)
+
+// ExitCode returns the documented process exit status of the jplaw commands for the
+// category: 1 internal, 3 network, 4 not found, 5 rate limited, 6 validation. Status 2
+// is reserved for usage errors, as with the flag package.
+func (c ErrorCategory) ExitCode() int {
+	switch c {
+	case ErrorNetwork:
+		return 3
+	case ErrorNotFound:
+		return 4
+	case ErrorRateLimited:
+		return 5
+	case ErrorValidation:
+		return 6
+	}
+	return 1
+}
+
+// categorizedError is an error with an explicit category
+type categorizedError struct {
+	err      error
+	category ErrorCategory
+}
+
+func (e *categorizedError) Error() string                { return e.err.Error() }
+func (e *categorizedError) Unwrap() error                { return e.err }
+func (e *categorizedError) ErrorCategory() ErrorCategory { return e.category }
+
+// WithErrorCategory returns err with a category that ErrorCategoryOf reports instead of
+// the one it would infer. Errors of other packages can also declare their category with
+// an ErrorCategory() ErrorCategory method.
+func WithErrorCategory(err error, category ErrorCategory) error {
+	if err == nil {
+		return nil
+	}
+	return &categorizedError{err: err, category: category}
+}
+
+// ErrorCategoryOf returns the category of an error: an explicit category set with
+// WithErrorCategory, else the one inferred from APIError status codes, ErrBudgetExhausted,
+// network errors, missing files and the package's validation errors. Nil errors and
+// errors of no known kind are ErrorInternal.
+func ErrorCategoryOf(err error) ErrorCategory {
+	var categorized interface{ ErrorCategory() ErrorCategory }
+	if errors.As(err, &categorized) {
+		return categorized.ErrorCategory()
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return ErrorRateLimited
+		case apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusGone:
+			return ErrorNotFound
+		case apiErr.StatusCode >= 500:
+			return ErrorNetwork
+		case apiErr.StatusCode >= 400:
+			return ErrorValidation
+		}
+	}
+	// Not net.Error: system call errors such as ENOENT implement it too
+	var urlErr *url.Error
+	var opErr *net.OpError
+	var syntaxErr *xml.SyntaxError
+	switch {
+	case errors.Is(err, ErrBudgetExhausted):
+		return ErrorRateLimited
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, ErrProvisionNotFound), errors.Is(err, ErrTextNotFound):
+		return ErrorNotFound
+	case errors.As(err, &urlErr), errors.As(err, &opErr), errors.Is(err, context.DeadlineExceeded):
+		return ErrorNetwork
+	case errors.Is(err, ErrManifestSignature), errors.Is(err, ErrManifestUnsigned),
+		errors.Is(err, ErrCursorMismatch), errors.Is(err, ErrInvalidHighlightTag), errors.Is(err, ErrInvalidSearchQuery), errors.As(err, &syntaxErr):
+		return ErrorValidation
+	}
+	return ErrorInternal
+}
@@ -30,11 +30,7 @@ func main() {
 	}
 
 	for _, law := range result.Laws {
-		if law.LawInfo != nil {
-			fmt.Printf("Law ID: %s\n", law.LawInfo.LawId)
-		}
-		if law.RevisionInfo != nil {
-			fmt.Printf("Law Title: %s\n", law.RevisionInfo.LawTitle)
-		}
+		fmt.Printf("Law ID: %s\n", law.GetLawInfo().GetLawId())
+		fmt.Printf("Law Title: %s\n", law.GetRevisionInfo().GetLawTitle())
 	}
 }
@@ -0,0 +1,132 @@
+package lawapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LawFamily is an act together with its enforcement orders (施行令) and
+// enforcement regulations (施行規則)
+type LawFamily struct {
+	// Act is the act at the root of the family, or nil if it could not be found
+	Act *LawItem `json:"act,omitempty"`
+	// EnforcementOrders contains the cabinet orders enforcing the act (施行令)
+	EnforcementOrders []LawItem `json:"enforcement_orders,omitempty"`
+	// EnforcementRegulations contains the ministerial ordinances and rules enforcing the act (施行規則)
+	EnforcementRegulations []LawItem `json:"enforcement_regulations,omitempty"`
+}
+
+var familySuffixes = []string{"施行規則", "施行令"}
+
+// ResolveFamily finds the act, enforcement orders and enforcement regulations
+// related to the given law. The law may be any member of the family.
+//
+// Candidates are found by title (e.g. "電波法", "電波法施行令", "電波法施行規則").
+// Candidates whose title only starts with the expected title are kept when their
+// enact statement (制定文) cites the act. Members are sorted with SortLawItems, and
+// the act is the first matching act in that order.
+func (c *Client) ResolveFamily(lawID string) (*LawFamily, error) {
+	laws, err := c.GetLaws(&GetLawsParams{LawId: StringPtr(lawID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve law %s: %w", lawID, err)
+	}
+	var title string
+	for _, item := range laws.Laws {
+		if item.LawInfo != nil && item.LawInfo.LawId == lawID {
+			title = lawItemTitle(item)
+			break
+		}
+	}
+	if title == "" {
+		return nil, fmt.Errorf("law %s not found", lawID)
+	}
+
+	base := title
+	for _, suffix := range familySuffixes {
+		if strings.HasSuffix(title, suffix) {
+			base = strings.TrimSuffix(title, suffix)
+			break
+		}
+	}
+
+	family := &LawFamily{}
+	acts, err := c.findFamilyMembers(base, base, []LawType{LawTypeAct})
+	if err != nil {
+		return nil, err
+	}
+	if len(acts) > 0 {
+		family.Act = &acts[0]
+	}
+	family.EnforcementOrders, err = c.findFamilyMembers(base, base+"施行令", []LawType{LawTypeCabinetorder})
+	if err != nil {
+		return nil, err
+	}
+	family.EnforcementRegulations, err = c.findFamilyMembers(base, base+"施行規則", []LawType{LawTypeMinisterialordinance, LawTypeRule})
+	if err != nil {
+		return nil, err
+	}
+	return family, nil
+}
+
+// findFamilyMembers searches laws by title and returns exact title matches, or
+// prefix matches whose enact statement cites the act when there is no exact match
+func (c *Client) findFamilyMembers(actTitle, title string, lawTypes []LawType) ([]LawItem, error) {
+	laws, err := c.GetLaws(&GetLawsParams{
+		LawTitle: StringPtr(title),
+		LawType:  &lawTypes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", title, err)
+	}
+
+	var exact, prefixed []LawItem
+	for _, item := range laws.Laws {
+		itemTitle := lawItemTitle(item)
+		switch {
+		case itemTitle == title:
+			exact = append(exact, item)
+		case strings.HasPrefix(itemTitle, title):
+			prefixed = append(prefixed, item)
+		}
+	}
+	if len(exact) > 0 || actTitle == title {
+		SortLawItems(exact)
+		return exact, nil
+	}
+
+	var cited []LawItem
+	for _, item := range prefixed {
+		if c.citesAct(item, actTitle) {
+			cited = append(cited, item)
+		}
+	}
+	SortLawItems(cited)
+	return cited, nil
+}
+
+// citesAct reports whether the enact statement of the law cites the act
+func (c *Client) citesAct(item LawItem, actTitle string) bool {
+	if item.LawInfo == nil {
+		return false
+	}
+	data, err := c.GetLawData(item.LawInfo.LawId, &GetLawDataParams{Elm: (*Elm)(StringPtr("EnactStatement"))})
+	if err != nil {
+		return false
+	}
+	law, err := ParseLawFullText(data)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(law.PlainText(), actTitle)
+}
+
+// lawItemTitle returns the law title of a law entry, preferring the retrieved revision
+func lawItemTitle(item LawItem) string {
+	if item.RevisionInfo != nil && item.RevisionInfo.LawTitle != "" {
+		return item.RevisionInfo.LawTitle
+	}
+	if item.CurrentRevisionInfo != nil {
+		return item.CurrentRevisionInfo.LawTitle
+	}
+	return ""
+}
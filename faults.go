@@ -0,0 +1,294 @@
+package lawapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInjectedFault is the cause of the connection failures and truncated bodies of a
+// FaultInjector
+var ErrInjectedFault = errors.New("injected fault")
+
+// FaultOptions configures a FaultInjector. Rates are probabilities per request between
+// 0 and 1; the zero value injects nothing.
+type FaultOptions struct {
+	// Latency delays every request, plus a random delay up to LatencyJitter
+	Latency       time.Duration
+	LatencyJitter time.Duration
+	// ErrorRate fails requests with a connection error wrapping ErrInjectedFault, without
+	// sending them
+	ErrorRate float64
+	// ServerErrorRate answers requests with 503 Service Unavailable, without sending them
+	ServerErrorRate float64
+	// RateLimitRate starts bursts of RateLimitBurst consecutive requests (default 3)
+	// answered with 429 Too Many Requests and a Retry-After of RetryAfter (default 1s),
+	// without sending them
+	RateLimitRate  float64
+	RateLimitBurst int
+	RetryAfter     time.Duration
+	// TruncateRate makes response bodies fail with io.ErrUnexpectedEOF wrapping
+	// ErrInjectedFault after a random part of their content
+	TruncateRate float64
+	// MalformedRate cuts response bodies short and appends bytes that are neither JSON
+	// nor XML, so that decoding fails
+	MalformedRate float64
+	// Seed makes the faults reproducible for the same sequence of requests; 0 picks a
+	// random seed
+	Seed uint64
+	// Clock is the clock of the latency (default: DefaultClock)
+	Clock Clock
+}
+
+// FaultStats counts the requests through a FaultInjector and the faults injected
+type FaultStats struct {
+	Requests     int `json:"requests"`
+	Errors       int `json:"errors"`
+	ServerErrors int `json:"server_errors"`
+	RateLimited  int `json:"rate_limited"`
+	// Truncated counts the bodies that were cut short, not the ones that ended before
+	// the cut, such as empty bodies
+	Truncated int `json:"truncated"`
+	Malformed int `json:"malformed"`
+}
+
+// FaultInjector injects latency, connection failures, error responses, 429 bursts and
+// broken bodies into requests, for testing how retries, circuit breakers and callers
+// behave before they meet these failures in production. It is safe for concurrent use.
+//
+//	faults, _ := lawapi.NewFaultInjector(lawapi.FaultOptions{ErrorRate: 0.1, RateLimitRate: 0.05, Seed: 1})
+//	client.SetHTTPClient(&http.Client{Transport: faults.Transport(nil)})
+type FaultInjector struct {
+	opts FaultOptions
+
+	mu  sync.Mutex
+	rng *rand.Rand
+	// burst is the number of requests left in the current 429 burst
+	burst    int
+	disabled bool
+	stats    FaultStats
+}
+
+// NewFaultInjector creates a fault injector
+func NewFaultInjector(opts FaultOptions) (*FaultInjector, error) {
+	rates := map[string]float64{
+		"ErrorRate": opts.ErrorRate, "ServerErrorRate": opts.ServerErrorRate, "RateLimitRate": opts.RateLimitRate,
+		"TruncateRate": opts.TruncateRate, "MalformedRate": opts.MalformedRate,
+	}
+	for name, rate := range rates {
+		if rate < 0 || rate > 1 {
+			return nil, WithErrorCategory(fmt.Errorf("fault option %s: %v is not between 0 and 1", name, rate), ErrorValidation)
+		}
+	}
+	if opts.Latency < 0 || opts.LatencyJitter < 0 {
+		return nil, WithErrorCategory(errors.New("fault latency must not be negative"), ErrorValidation)
+	}
+	if opts.RateLimitBurst <= 0 {
+		opts.RateLimitBurst = 3
+	}
+	if opts.RetryAfter <= 0 {
+		opts.RetryAfter = time.Second
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	return &FaultInjector{opts: opts, rng: rand.New(rand.NewPCG(seed, seed))}, nil
+}
+
+// SetEnabled turns the injection of faults on or off, e.g. to let the setup of a test
+// through. Requests are counted either way.
+func (f *FaultInjector) SetEnabled(enabled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.disabled = !enabled
+}
+
+// Stats returns the counts of requests and injected faults
+func (f *FaultInjector) Stats() FaultStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stats
+}
+
+// Transport returns an http.RoundTripper injecting faults into requests sent through
+// base (default: http.DefaultTransport). Use it with Client.SetHTTPClient.
+func (f *FaultInjector) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &faultTransport{faults: f, base: base}
+}
+
+// faultKind is a fault injected into a request
+type faultKind int
+
+const (
+	faultNone faultKind = iota
+	faultError
+	faultServerError
+	faultRateLimit
+	faultTruncate
+	faultMalformed
+)
+
+// plan decides the latency and fault of a request
+func (f *FaultInjector) plan() (time.Duration, faultKind) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stats.Requests++
+	if f.disabled {
+		return 0, faultNone
+	}
+	latency := f.opts.Latency
+	if f.opts.LatencyJitter > 0 {
+		latency += time.Duration(f.rng.Int64N(int64(f.opts.LatencyJitter) + 1))
+	}
+	switch {
+	case f.burst > 0:
+		f.burst--
+		f.stats.RateLimited++
+		return latency, faultRateLimit
+	case f.chance(f.opts.RateLimitRate):
+		f.burst = f.opts.RateLimitBurst - 1
+		f.stats.RateLimited++
+		return latency, faultRateLimit
+	case f.chance(f.opts.ErrorRate):
+		f.stats.Errors++
+		return latency, faultError
+	case f.chance(f.opts.ServerErrorRate):
+		f.stats.ServerErrors++
+		return latency, faultServerError
+	case f.chance(f.opts.TruncateRate):
+		return latency, faultTruncate
+	case f.chance(f.opts.MalformedRate):
+		f.stats.Malformed++
+		return latency, faultMalformed
+	}
+	return latency, faultNone
+}
+
+// chance reports whether an event of a probability happens. f.mu must be held.
+func (f *FaultInjector) chance(rate float64) bool {
+	return rate > 0 && f.rng.Float64() < rate
+}
+
+// cut returns a random length shorter than a length n, or 0 if n is 0. A negative n is
+// an unknown length, for which the length is below 1024.
+func (f *FaultInjector) cut(n int64) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case n < 0:
+		return f.rng.Int64N(1024)
+	case n == 0:
+		return 0
+	}
+	return f.rng.Int64N(n)
+}
+
+type faultTransport struct {
+	faults *FaultInjector
+	base   http.RoundTripper
+}
+
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	latency, fault := t.faults.plan()
+	if latency > 0 {
+		if err := sleepContext(req.Context(), clockOrDefault(t.faults.opts.Clock), latency); err != nil {
+			return nil, err
+		}
+	}
+	switch fault {
+	case faultError:
+		return nil, fmt.Errorf("connection reset: %w", ErrInjectedFault)
+	case faultServerError:
+		return faultResponse(req, http.StatusServiceUnavailable, nil), nil
+	case faultRateLimit:
+		header := http.Header{}
+		header.Set("Retry-After", strconv.Itoa(int(max(t.faults.opts.RetryAfter.Round(time.Second), time.Second)/time.Second)))
+		return faultResponse(req, http.StatusTooManyRequests, header), nil
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	switch fault {
+	case faultTruncate:
+		resp.Body = &truncatedBody{ReadCloser: resp.Body, faults: t.faults, remaining: t.faults.cut(resp.ContentLength)}
+	case faultMalformed:
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data[:t.faults.cut(int64(len(data)))], "\x00}<]"...)
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		resp.ContentLength = int64(len(data))
+		resp.Header.Del("Content-Length")
+		resp.Header.Del("ETag")
+	}
+	return resp, nil
+}
+
+// faultResponse returns a response the server did not send, with an error_info body
+func faultResponse(req *http.Request, status int, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	body := fmt.Sprintf(`{"code":"%d","message":"%s (injected)"}`, status, http.StatusText(status))
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// truncatedBody ends a response body with io.ErrUnexpectedEOF after a number of bytes.
+// Bodies that end before are passed through, and are not counted as truncated.
+type truncatedBody struct {
+	io.ReadCloser
+	faults    *FaultInjector
+	remaining int64
+	truncated bool
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	if b.truncated {
+		return 0, fmt.Errorf("%w (%w)", io.ErrUnexpectedEOF, ErrInjectedFault)
+	}
+	if b.remaining <= 0 {
+		// Look for a byte past the cut, so that bodies ending there are not cut short
+		var next [1]byte
+		n, err := io.ReadFull(b.ReadCloser, next[:])
+		if n == 0 {
+			return 0, err
+		}
+		b.truncated = true
+		b.faults.mu.Lock()
+		b.faults.stats.Truncated++
+		b.faults.mu.Unlock()
+		return 0, fmt.Errorf("%w (%w)", io.ErrUnexpectedEOF, ErrInjectedFault)
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
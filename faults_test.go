@@ -0,0 +1,112 @@
+package lawapi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newFaultBodyServer serves a body of n bytes, chunked without a Content-Length if
+// chunked is set
+func newFaultBodyServer(t *testing.T, status, n int, chunked bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !chunked {
+			w.Header().Set("Content-Length", strconv.Itoa(n))
+		}
+		w.WriteHeader(status)
+		w.Write(bytes.Repeat([]byte("a"), n))
+		if chunked {
+			w.(http.Flusher).Flush()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestFaultInjectorBodies breaks empty, sized and chunked bodies with many seeds
+func TestFaultInjectorBodies(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		status  int
+		size    int
+		chunked bool
+		opts    FaultOptions
+	}{
+		{"malformed no content", http.MethodGet, http.StatusNoContent, 0, false, FaultOptions{MalformedRate: 1}},
+		{"malformed not modified", http.MethodGet, http.StatusNotModified, 0, false, FaultOptions{MalformedRate: 1}},
+		{"malformed HEAD", http.MethodHead, http.StatusOK, 512, false, FaultOptions{MalformedRate: 1}},
+		{"malformed empty", http.MethodGet, http.StatusOK, 0, false, FaultOptions{MalformedRate: 1}},
+		{"malformed sized", http.MethodGet, http.StatusOK, 512, false, FaultOptions{MalformedRate: 1}},
+		{"malformed chunked", http.MethodGet, http.StatusOK, 4096, true, FaultOptions{MalformedRate: 1}},
+		{"truncate no content", http.MethodGet, http.StatusNoContent, 0, false, FaultOptions{TruncateRate: 1}},
+		{"truncate HEAD", http.MethodHead, http.StatusOK, 512, false, FaultOptions{TruncateRate: 1}},
+		{"truncate empty", http.MethodGet, http.StatusOK, 0, false, FaultOptions{TruncateRate: 1}},
+		{"truncate sized", http.MethodGet, http.StatusOK, 512, false, FaultOptions{TruncateRate: 1}},
+		{"truncate chunked", http.MethodGet, http.StatusOK, 4096, true, FaultOptions{TruncateRate: 1}},
+		{"truncate short chunked", http.MethodGet, http.StatusOK, 8, true, FaultOptions{TruncateRate: 1}},
+		{"truncate empty chunked", http.MethodGet, http.StatusOK, 0, true, FaultOptions{TruncateRate: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFaultBodyServer(t, tt.status, tt.size, tt.chunked)
+			// size is the length of the body the client receives without faults
+			size := tt.size
+			if tt.method == http.MethodHead || tt.status != http.StatusOK {
+				size = 0
+			}
+			for seed := uint64(1); seed <= 20; seed++ {
+				opts := tt.opts
+				opts.Seed = seed
+				faults, err := NewFaultInjector(opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				req, err := http.NewRequest(tt.method, server.URL, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp, err := faults.Transport(nil).RoundTrip(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				stats := faults.Stats()
+
+				if tt.opts.MalformedRate > 0 {
+					if err != nil {
+						t.Fatalf("seed %d: %v", seed, err)
+					}
+					content, found := bytes.CutSuffix(body, []byte("\x00}<]"))
+					if !found || len(bytes.Trim(content, "a")) > 0 || len(content) > max(size-1, 0) {
+						t.Errorf("seed %d: malformed body %q of %d bytes", seed, body, size)
+					}
+					if stats.Malformed != 1 {
+						t.Errorf("seed %d: %d malformed, want 1", seed, stats.Malformed)
+					}
+					continue
+				}
+
+				cut := err != nil
+				if cut && (!errors.Is(err, io.ErrUnexpectedEOF) || !errors.Is(err, ErrInjectedFault)) {
+					t.Fatalf("seed %d: got %v", seed, err)
+				}
+				if cut && len(body) >= size || !cut && len(body) != size {
+					t.Errorf("seed %d: read %d of %d bytes with error %v", seed, len(body), size, err)
+				}
+				// Bodies are cut below their length, or below 1024 bytes if it is unknown
+				if mustCut := size > 0 && (!tt.chunked || size > 1024); mustCut && !cut {
+					t.Errorf("seed %d: body of %d bytes was not cut short", seed, size)
+				}
+				if cut != (stats.Truncated == 1) || stats.Truncated > 1 {
+					t.Errorf("seed %d: %d truncated with error %v", seed, stats.Truncated, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package lawapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxFilenameBytes is the length in UTF-8 bytes to which SafeFilename shortens names. File
+// systems limit names to 255 bytes (most Unix file systems) or 255 UTF-16 units
+// (Windows); the margin leaves room for extensions and suffixes.
+const MaxFilenameBytes = 200
+
+// filenameReplacer replaces the characters Windows forbids in file names, and path
+// separators, with their full-width forms, which read naturally in Japanese titles
+var filenameReplacer = strings.NewReplacer(
+	`<`, "＜", `>`, "＞", `:`, "：", `"`, "＂", `/`, "／", `\`, "＼", `|`, "｜", `?`, "？", `*`, "＊",
+)
+
+// SafeFilename turns a law title or other text into a file name that is valid on Windows,
+// macOS and Unix: forbidden characters become their full-width forms, control characters
+// become "_", trailing dots and spaces are dropped, and device names such as CON and
+// LPT1 are prefixed with "_". Names longer than MaxFilenameBytes are cut and end with
+// "~" and a hash of the whole name, so distinct long names stay distinct. Names that
+// are already safe are returned unchanged.
+func SafeFilename(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == utf8.RuneError {
+			return '_'
+		}
+		return r
+	}, filenameReplacer.Replace(name))
+	safe = strings.TrimRight(safe, ". ")
+	if safe == "" {
+		return "_"
+	}
+	if isReservedFilename(safe) {
+		safe = "_" + safe
+	}
+	if len(safe) > MaxFilenameBytes {
+		sum := sha256.Sum256([]byte(name))
+		suffix := "~" + hex.EncodeToString(sum[:8])
+		cut := MaxFilenameBytes - len(suffix)
+		for cut > 0 && !utf8.RuneStart(safe[cut]) {
+			cut--
+		}
+		safe = strings.TrimRight(safe[:cut], ". ") + suffix
+	}
+	return safe
+}
+
+// isReservedFilename reports whether a name is a Windows device name, which is reserved
+// with any extension
+func isReservedFilename(name string) bool {
+	base, _, _ := strings.Cut(name, ".")
+	switch strings.ToUpper(strings.TrimRight(base, " ")) {
+	case "CON", "PRN", "AUX", "NUL",
+		"COM0", "COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+		"LPT0", "LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9":
+		return true
+	}
+	return false
+}
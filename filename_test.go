@@ -0,0 +1,163 @@
+package lawapi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// filenameRules checks a name against the rules of a platform
+var filenameRules = map[string]func(name string) string{
+	"windows": func(name string) string {
+		if strings.ContainsAny(name, `<>:"/\|?*`) {
+			return "forbidden character"
+		}
+		for _, r := range name {
+			if r < 0x20 {
+				return "control character"
+			}
+		}
+		if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+			return "trailing dot or space"
+		}
+		if isReservedFilename(name) {
+			return "device name"
+		}
+		if len(utf16.Encode([]rune(name))) > 255 {
+			return "longer than 255 UTF-16 units"
+		}
+		return ""
+	},
+	"darwin": func(name string) string {
+		if strings.ContainsAny(name, "/:\x00") {
+			return "slash, colon or NUL"
+		}
+		if len(name) > 255 {
+			return "longer than 255 bytes"
+		}
+		return ""
+	},
+	"linux": func(name string) string {
+		if strings.ContainsAny(name, "/\x00") {
+			return "slash or NUL"
+		}
+		if name == "." || name == ".." {
+			return "dot entry"
+		}
+		if len(name) > 255 {
+			return "longer than 255 bytes"
+		}
+		return ""
+	},
+}
+
+func TestSafeFilename(t *testing.T) {
+	long := strings.Repeat("個人情報の保護に関する法律", 10)
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"safe name unchanged", "民法", "民法"},
+		{"ASCII unchanged", "405AC0000000088.xml", "405AC0000000088.xml"},
+		{"forbidden characters", `a<b>c:d"e/f\g|h?i*j`, "a＜b＞c：d＂e／f＼g｜h？i＊j"},
+		{"control characters", "a\tb\nc\x00d\x7f", "a_b_c_d_"},
+		{"invalid UTF-8", "a\xffb", "a_b"},
+		{"trailing dots and spaces", "民法. . ", "民法"},
+		{"only dots", "..", "_"},
+		{"empty", "", "_"},
+		{"device name", "CON", "_CON"},
+		{"device name with extension", "nul.txt", "_nul.txt"},
+		{"device name with trailing space", "LPT1 .xml", "_LPT1 .xml"},
+		{"device name prefix", "CONSOLE", "CONSOLE"},
+		{"COM10 is not reserved", "COM10", "COM10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SafeFilename(tt.in); got != tt.want {
+				t.Errorf("SafeFilename(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("long names", func(t *testing.T) {
+		a, b := SafeFilename(long+"第一"), SafeFilename(long+"第二")
+		if a == b {
+			t.Errorf("distinct long names map to %q", a)
+		}
+		for _, name := range []string{a, b} {
+			if len(name) > MaxFilenameBytes || !utf8.ValidString(name) {
+				t.Errorf("%q is %d bytes or not valid UTF-8", name, len(name))
+			}
+			if !strings.Contains(name, "~") {
+				t.Errorf("%q has no hash suffix", name)
+			}
+		}
+	})
+}
+
+// TestSafeFilenamePlatforms checks the output for the rules of every platform, and creates
+// each file on the platform the test runs on
+func TestSafeFilenamePlatforms(t *testing.T) {
+	inputs := []string{
+		"民法",
+		`日本国憲法/第九条: "戦争の放棄"?`,
+		"PRN",
+		"aux.resp",
+		"com1 ",
+		"末尾の点...",
+		" 先頭の空白",
+		".",
+		"..",
+		"\x00\x01\x1f",
+		strings.Repeat("あ", 300),
+		strings.Repeat("a", 300) + ".",
+	}
+	dir := t.TempDir()
+	for _, in := range inputs {
+		name := SafeFilename(in)
+		for platform, check := range filenameRules {
+			if problem := check(name); problem != "" {
+				t.Errorf("SafeFilename(%q) = %q: %s on %s", in, name, problem, platform)
+			}
+		}
+		if SafeFilename(name) != name {
+			t.Errorf("SafeFilename(%q) = %q changes again", in, name)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(in), 0o644); err != nil {
+			t.Errorf("SafeFilename(%q) = %q: %v", in, name, err)
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestIsReservedFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"CON", true},
+		{"con", true},
+		{"Nul.txt", true},
+		{"com9.tar.gz", true},
+		{"LPT0", true},
+		{"AUX ", true},
+		{"CONFIG", false},
+		{"COM", false},
+		{"LPT10", false},
+		{"_CON", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isReservedFilename(tt.name); got != tt.want {
+			t.Errorf("isReservedFilename(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package lawapi
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+)
+
+// LawRecord is a law entry of a search result flattened into its commonly used fields,
+// for tables and CSV export
+type LawRecord struct {
+	LawID     string  `json:"law_id"`
+	Title     string  `json:"title"`
+	TitleKana string  `json:"title_kana,omitempty"`
+	LawNum    string  `json:"law_num"`
+	LawType   LawType `json:"law_type,omitempty"`
+	// Category is the category name (e.g. 憲法)
+	Category string `json:"category,omitempty"`
+	// PromulgationDate is nil if the entry has none
+	PromulgationDate *Date `json:"promulgation_date,omitempty"`
+	// RevisionID is the ID of the revision the entry describes
+	RevisionID string `json:"revision_id,omitempty"`
+	// CurrentRevisionID is the ID of the current revision, if the response includes it
+	CurrentRevisionID string `json:"current_revision_id,omitempty"`
+}
+
+// Record flattens the law entry. Titles and the category come from the revision of the
+// entry, or from the current revision if the entry has no revision information.
+func (item *LawItem) Record() LawRecord {
+	revision := item.GetRevisionInfo()
+	if revision == nil {
+		revision = item.GetCurrentRevisionInfo()
+	}
+	return lawRecord(item.GetLawInfo(), revision, item.GetCurrentRevisionInfo())
+}
+
+// Record flattens the law entry of the keyword search result
+func (item *KeywordItem) Record() LawRecord {
+	return lawRecord(item.GetLawInfo(), item.GetRevisionInfo(), nil)
+}
+
+func lawRecord(info *LawInfo, revision, current *RevisionInfo) LawRecord {
+	record := LawRecord{
+		LawID:             info.GetLawId(),
+		Title:             revision.GetLawTitle(),
+		TitleKana:         revision.GetLawTitleKana(),
+		LawNum:            info.GetLawNum(),
+		LawType:           info.GetLawType(),
+		Category:          revision.GetCategory(),
+		RevisionID:        revision.GetLawRevisionId(),
+		CurrentRevisionID: current.GetLawRevisionId(),
+	}
+	if record.LawType == "" {
+		record.LawType = revision.GetLawType()
+	}
+	if date := info.GetPromulgationDate(); !time.Time(date).IsZero() {
+		record.PromulgationDate = &date
+	}
+	return record
+}
+
+// Flatten returns the law entries of the response as records
+func (r *LawsResponse) Flatten() []LawRecord {
+	if r == nil {
+		return nil
+	}
+	records := make([]LawRecord, len(r.Laws))
+	for i := range r.Laws {
+		records[i] = r.Laws[i].Record()
+	}
+	return records
+}
+
+// Flatten returns the law entries of the keyword search result as records
+func (r *KeywordResponse) Flatten() []LawRecord {
+	if r == nil {
+		return nil
+	}
+	records := make([]LawRecord, len(r.Items))
+	for i := range r.Items {
+		records[i] = r.Items[i].Record()
+	}
+	return records
+}
+
+// WriteLawRecordsCSV writes records as CSV with a header row
+func WriteLawRecordsCSV(w io.Writer, records []LawRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"law_id", "title", "title_kana", "law_num", "law_type", "category", "promulgation_date", "revision_id", "current_revision_id"}); err != nil {
+		return err
+	}
+	for _, r := range records {
+		promulgated := ""
+		if r.PromulgationDate != nil {
+			promulgated = r.PromulgationDate.String()
+		}
+		record := []string{r.LawID, r.Title, r.TitleKana, r.LawNum, string(r.LawType), r.Category, promulgated, r.RevisionID, r.CurrentRevisionID}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,277 @@
+package lawapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LawDocument is a law document of a mirror passed to the function of ForEachLaw
+type LawDocument struct {
+	// Path is the path of the file relative to the mirror directory, with slashes
+	Path string
+	// ID is the law ID or law revision ID the file is named after
+	ID string
+	// File is the content of the document. It is closed when the function returns.
+	File *LawFile
+}
+
+// ForEachLawOptions configures ForEachLaw
+type ForEachLawOptions[T any] struct {
+	// Filter skips the documents whose relative path it returns false for, before they are
+	// opened
+	Filter func(path string) bool
+	// Aggregate receives the value of every document the function succeeded for, one
+	// document at a time, so that it can update totals without locking. The File of doc is
+	// closed by then.
+	Aggregate func(doc *LawDocument, value T)
+	// OnFailure is called for every document that could not be opened or the function
+	// failed or panicked for, one document at a time
+	OnFailure func(failure LawFailure)
+	// ReadFiles reads documents onto the heap instead of mapping them into memory
+	ReadFiles bool
+	// Progress receives progress notifications, or nil
+	Progress Progress
+	// Clock measures the duration of the run (default: DefaultClock)
+	Clock Clock
+}
+
+// LawFailure is a document ForEachLaw failed for
+type LawFailure struct {
+	Path string
+	ID   string
+	Err  error
+}
+
+// ForEachLawReport summarizes a run of ForEachLaw
+type ForEachLawReport struct {
+	// Documents is the number of documents found, after Filter
+	Documents int
+	Succeeded int
+	// Failures are the documents that failed, in path order
+	Failures []LawFailure
+	// Skipped is the number of documents not processed because the context was done
+	Skipped  int
+	Duration time.Duration
+}
+
+// Err joins the errors of the failed documents, or returns nil if none failed
+func (r *ForEachLawReport) Err() error {
+	var errs []error
+	for _, failure := range r.Failures {
+		errs = append(errs, fmt.Errorf("%s: %w", failure.Path, failure.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// mirroredLawDataPattern matches the query hash and extension ending the file of a
+// law_data response in a jplaw-serve mirror
+var mirroredLawDataPattern = regexp.MustCompile(`(@[0-9a-f]{16})?\.resp$`)
+
+// ForEachLaw streams every law document of a mirror directory through fn, with at most
+// concurrency documents at a time (default: the number of CPUs). Documents are the law
+// XML files (*.xml) of the directory and its subdirectories, and the law_data responses
+// of a jplaw-serve mirror; hidden files are ignored. XML files are mapped into memory and
+// parsed only as fn asks, so that scans of the whole corpus keep a small resident memory.
+// The JSON bodies of law_data responses are read onto the heap, and decoded only as fn
+// asks as well.
+//
+// Failures are isolated: a document that cannot be opened, or that fn returns an error
+// or panics for, is recorded in the report and the others go on. The returned error is
+// only set if the directory cannot be read or ctx is done, in which case the report
+// counts the documents not processed as skipped.
+//
+//	articles := map[string]int{}
+//	report, err := lawapi.ForEachLaw(ctx, "mirror", 8, func(ctx context.Context, doc *lawapi.LawDocument) (int, error) {
+//		law, err := doc.File.Law()
+//		if err != nil {
+//			return 0, err
+//		}
+//		return len(law.Articles()), nil
+//	}, &lawapi.ForEachLawOptions[int]{Aggregate: func(doc *lawapi.LawDocument, n int) { articles[doc.ID] = n }})
+func ForEachLaw[T any](ctx context.Context, mirror string, concurrency int, fn func(ctx context.Context, doc *LawDocument) (T, error), opts *ForEachLawOptions[T]) (*ForEachLawReport, error) {
+	if opts == nil {
+		opts = &ForEachLawOptions[T]{}
+	}
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	var progress Progress = nopProgress{}
+	if opts.Progress != nil {
+		progress = opts.Progress
+	}
+
+	clock := clockOrDefault(opts.Clock)
+	start := clock.Now()
+	paths, err := mirrorLawPaths(mirror, opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+	report := &ForEachLawReport{Documents: len(paths)}
+	progress.OnStart(len(paths))
+
+	failures := make([]*LawFailure, len(paths))
+	sem := make(chan struct{}, concurrency)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	failed := 0
+	for i, rel := range paths {
+		if ctx.Err() == nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			report.Skipped = len(paths) - i
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, rel string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			doc, value, err := processLawDocument(ctx, mirror, rel, !opts.ReadFiles, fn)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+				failures[i] = &LawFailure{Path: doc.Path, ID: doc.ID, Err: err}
+				if opts.OnFailure != nil {
+					opts.OnFailure(*failures[i])
+				}
+			} else {
+				report.Succeeded++
+				if opts.Aggregate != nil {
+					opts.Aggregate(doc, value)
+				}
+			}
+			progress.OnItem(report.Succeeded+failed, rel)
+		}(i, rel)
+	}
+	wg.Wait()
+
+	for _, failure := range failures {
+		if failure != nil {
+			report.Failures = append(report.Failures, *failure)
+		}
+	}
+	report.Duration = clock.Now().Sub(start)
+	progress.OnFinish(ProgressSummary{
+		Total:     len(paths),
+		Succeeded: report.Succeeded,
+		Failed:    len(report.Failures),
+		Duration:  report.Duration,
+	})
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// processLawDocument opens a document of a mirror and calls fn for it, turning panics
+// into errors
+func processLawDocument[T any](ctx context.Context, mirror, rel string, mmap bool, fn func(ctx context.Context, doc *LawDocument) (T, error)) (doc *LawDocument, value T, err error) {
+	doc = &LawDocument{Path: rel, ID: mirrorLawID(rel)}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	if doc.File, err = openMirrorLaw(filepath.Join(mirror, filepath.FromSlash(rel)), mmap); err != nil {
+		return doc, value, err
+	}
+	defer doc.File.Close()
+	value, err = fn(ctx, doc)
+	return doc, value, err
+}
+
+// mirrorLawPaths returns the relative paths of the law documents of a mirror directory
+// in lexical order
+func mirrorLawPaths(mirror string, filter func(path string) bool) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(mirror, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name != mirror && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(mirror, name)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		isXML := strings.EqualFold(path.Ext(rel), ".xml")
+		isLawData := strings.HasPrefix(rel, "law_data/") && strings.HasSuffix(rel, ".resp")
+		if (isXML || isLawData) && (filter == nil || filter(rel)) {
+			paths = append(paths, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirror %s: %w", mirror, err)
+	}
+	return paths, nil
+}
+
+// mirrorLawID returns the law ID or law revision ID of the file of a document: the name
+// of XML files and of law_data responses stored by ID, and the directory of law_data
+// responses stored by title (law_data/<id>/<title>.resp)
+func mirrorLawID(rel string) string {
+	if strings.HasSuffix(rel, ".resp") {
+		dir, file := path.Split(rel)
+		if dir != "law_data/" {
+			return path.Base(dir)
+		}
+		return mirroredLawDataPattern.ReplaceAllString(file, "")
+	}
+	return strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+}
+
+// openMirrorLaw opens a law XML file, or the law_data response of a jplaw-serve mirror
+func openMirrorLaw(name string, mmap bool) (*LawFile, error) {
+	if !strings.HasSuffix(name, ".resp") {
+		return OpenLawFile(name, LawFileOptions{Mmap: mmap})
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirrored response: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirrored response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("mirrored response has status %d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && !strings.Contains(contentType, "json") {
+		return nil, fmt.Errorf("mirrored response has unsupported content type %s", contentType)
+	}
+	return lawFileFromLawData(name, body), nil
+}
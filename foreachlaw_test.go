@@ -0,0 +1,91 @@
+package lawapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestForEachLawClock measures a run on the given clock, which the function advances by
+// a minute for each document
+func TestForEachLawClock(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.xml", "b.xml", "c.xml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("<Law><LawBody/></Law>"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clock := NewManualClock(time.Date(2024, 4, 1, 9, 0, 0, 0, JST))
+	progress := &summaryProgress{}
+	report, err := ForEachLaw(context.Background(), dir, 1, func(ctx context.Context, doc *LawDocument) (struct{}, error) {
+		clock.Advance(time.Minute)
+		return struct{}{}, nil
+	}, &ForEachLawOptions[struct{}]{Clock: clock, Progress: progress})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Succeeded != 3 || report.Duration != 3*time.Minute {
+		t.Errorf("report = %+v, want 3 succeeded in 3m", report)
+	}
+	if progress.summary.Duration != report.Duration {
+		t.Errorf("summary duration = %s, want %s", progress.summary.Duration, report.Duration)
+	}
+}
+
+// TestForEachLawMirroredLawData reads the law_data responses of a jplaw-serve mirror, and
+// decodes them only as the function asks
+func TestForEachLawMirroredLawData(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "law_data"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(map[string]any{
+		"law_info":      map[string]string{"law_id": "325AC0000000131"},
+		"law_full_text": "<Law><LawNum>昭和二十五年法律第百三十一号</LawNum><LawBody><LawTitle>電波法</LawTitle></LawBody></Law>",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"325AC0000000131.resp": string(body),
+		"broken.resp":          "{",
+	}
+	for name, body := range files {
+		resp := fmt.Sprintf("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: %d\r\n\r\n%s", len(body), body)
+		if err := os.WriteFile(filepath.Join(dir, "law_data", name), []byte(resp), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	titles := map[string]string{}
+	report, err := ForEachLaw(context.Background(), dir, 1, func(ctx context.Context, doc *LawDocument) (string, error) {
+		if doc.File.law != nil {
+			return "", fmt.Errorf("%s was decoded before it was asked for", doc.Path)
+		}
+		if got := string(doc.File.Bytes()); got != files[strings.TrimPrefix(doc.Path, "law_data/")] {
+			return "", fmt.Errorf("Bytes() = %q", got)
+		}
+		title, found, err := doc.File.ElementText("LawTitle")
+		if err != nil || !found {
+			return "", fmt.Errorf("LawTitle: %v", err)
+		}
+		if num, _, _ := doc.File.ElementText("LawNum"); num != "昭和二十五年法律第百三十一号" {
+			return "", fmt.Errorf("LawNum = %q", num)
+		}
+		return title, nil
+	}, &ForEachLawOptions[string]{Aggregate: func(doc *LawDocument, title string) { titles[doc.ID] = title }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Succeeded != 1 || titles["325AC0000000131"] != "電波法" {
+		t.Errorf("report = %+v, titles = %v", report, titles)
+	}
+	if len(report.Failures) != 1 || report.Failures[0].ID != "broken" {
+		t.Errorf("failures = %+v, want broken.resp", report.Failures)
+	}
+}
@@ -2,4 +2,7 @@ module go.ngs.io/jplaw-api-v2
 
 go 1.23.12
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	golang.org/x/text v0.25.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,58 @@
+package lawapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// HighlightTag is an HTML tag name that the keyword endpoint wraps around hits, set with
+// GetKeywordParams.HighlightTag
+type HighlightTag string
+
+// Highlight tags allowed by SanitizeHighlightTag
+const (
+	HighlightEm     HighlightTag = "em"
+	HighlightMark   HighlightTag = "mark"
+	HighlightSpan   HighlightTag = "span" // the API default
+	HighlightStrong HighlightTag = "strong"
+	HighlightB      HighlightTag = "b"
+	HighlightI      HighlightTag = "i"
+	HighlightU      HighlightTag = "u"
+)
+
+// ErrInvalidHighlightTag is returned for highlight tags outside the allow-list or with
+// attributes
+var ErrInvalidHighlightTag = errors.New("invalid highlight tag")
+
+// String returns the tag name
+func (t HighlightTag) String() string {
+	return string(t)
+}
+
+// Param returns the tag as a value of GetKeywordParams.HighlightTag
+func (t HighlightTag) Param() *string {
+	s := string(t)
+	return &s
+}
+
+// SanitizeHighlightTag checks a highlight tag against the allow-list of the Highlight
+// constants, so that hits are wrapped in plain inline markup that is safe to embed in
+// HTML. Surrounding spaces and angle brackets are removed and the name is lowercased
+// ("<EM>" is "em"); tags with attributes, closing tags and other tags fail with
+// ErrInvalidHighlightTag. BuildGetKeywordRequest applies it to the parameter.
+func SanitizeHighlightTag(tag string) (string, error) {
+	name := strings.TrimSpace(tag)
+	if strings.HasPrefix(name, "<") && strings.HasSuffix(name, ">") {
+		name = strings.TrimSpace(name[1 : len(name)-1])
+	}
+	name = strings.ToLower(name)
+	switch HighlightTag(name) {
+	case HighlightEm, HighlightMark, HighlightSpan, HighlightStrong, HighlightB, HighlightI, HighlightU:
+		return name, nil
+	}
+	if strings.ContainsAny(name, " \t\n=\"'/<>") {
+		return "", fmt.Errorf("%w %q: only a tag name without attributes is allowed", ErrInvalidHighlightTag, tag)
+	}
+	return "", fmt.Errorf("%w %q: allowed tags are em, mark, span, strong, b, i and u", ErrInvalidHighlightTag, tag)
+}
@@ -0,0 +1,235 @@
+package lawapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// Language selects the language of user-facing labels and messages
+type Language string
+
+// Supported languages
+const (
+	LanguageJapanese Language = "ja"
+	LanguageEnglish  Language = "en"
+)
+
+// DefaultLanguage is the language used when an empty Language is given
+var DefaultLanguage = LanguageEnglish
+
+// ParseLanguage returns the language of a language tag or locale name such as "ja",
+// "ja-JP" or "ja_JP.UTF-8". ok is false for unsupported languages.
+func ParseLanguage(tag string) (lang Language, ok bool) {
+	base := strings.ToLower(tag)
+	if i := strings.IndexAny(base, "-_.@"); i >= 0 {
+		base = base[:i]
+	}
+	switch Language(base) {
+	case LanguageJapanese:
+		return LanguageJapanese, true
+	case LanguageEnglish:
+		return LanguageEnglish, true
+	}
+	return "", false
+}
+
+// localized is a text in each supported language
+type localized struct {
+	ja, en string
+}
+
+func (l localized) in(lang Language) string {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	if lang == LanguageJapanese {
+		return l.ja
+	}
+	return l.en
+}
+
+var lawTypeLabels = map[LawType]localized{
+	LawTypeConstitution:         {"憲法", "Constitution"},
+	LawTypeAct:                  {"法律", "Act"},
+	LawTypeCabinetorder:         {"政令", "Cabinet Order"},
+	LawTypeImperialorder:        {"勅令", "Imperial Order"},
+	LawTypeMinisterialordinance: {"府省令", "Ministerial Ordinance"},
+	LawTypeRule:                 {"規則", "Rule"},
+	LawTypeMisc:                 {"その他", "Miscellaneous"},
+}
+
+// Label returns the name of the law type in a language, or the value itself if unknown
+func (t LawType) Label(lang Language) string {
+	if l, ok := lawTypeLabels[t]; ok {
+		return l.in(lang)
+	}
+	return string(t)
+}
+
+var repealStatusLabels = map[RepealStatus]localized{
+	RepealStatusNone:                {"なし", "None"},
+	RepealStatusRepeal:              {"廃止", "Repealed"},
+	RepealStatusExpire:              {"失効", "Expired"},
+	RepealStatusSuspend:             {"停止", "Suspended"},
+	RepealStatusLossofeffectiveness: {"実効性喪失", "Lost effect"},
+}
+
+// Label returns the name of the repeal status in a language, or the value itself if unknown
+func (s RepealStatus) Label(lang Language) string {
+	if l, ok := repealStatusLabels[s]; ok {
+		return l.in(lang)
+	}
+	return string(s)
+}
+
+var missionLabels = map[Mission]localized{
+	MissionNew:     {"新規制定", "New enactment"},
+	MissionPartial: {"一部改正", "Partial amendment"},
+}
+
+// Label returns the name of the mission in a language, or the value itself if unknown
+func (m Mission) Label(lang Language) string {
+	if l, ok := missionLabels[m]; ok {
+		return l.in(lang)
+	}
+	return string(m)
+}
+
+var categoryLabels = map[CategoryCd]localized{
+	CategoryCdConstitution:         {"憲法", "Constitution"},
+	CategoryCdCriminal:             {"刑事", "Criminal Affairs"},
+	CategoryCdFinanceGeneral:       {"財務通則", "General Finance"},
+	CategoryCdFisheries:            {"水産業", "Fisheries"},
+	CategoryCdTourism:              {"観光", "Tourism"},
+	CategoryCdParliament:           {"国会", "National Diet"},
+	CategoryCdPolice:               {"警察", "Police"},
+	CategoryCdNationalProperty:     {"国有財産", "National Property"},
+	CategoryCdMining:               {"鉱業", "Mining"},
+	CategoryCdPostalService:        {"郵務", "Postal Services"},
+	CategoryCdAdministrativeOrg:    {"行政組織", "Administrative Organization"},
+	CategoryCdFireService:          {"消防", "Fire Services"},
+	CategoryCdNationalTax:          {"国税", "National Taxes"},
+	CategoryCdIndustry:             {"工業", "Manufacturing"},
+	CategoryCdTelecommunications:   {"電気通信", "Telecommunications"},
+	CategoryCdCivilService:         {"国家公務員", "National Public Service"},
+	CategoryCdNationalDevelopment:  {"国土開発", "National Land Development"},
+	CategoryCdBusiness:             {"事業", "Business"},
+	CategoryCdCommerce:             {"商業", "Commerce"},
+	CategoryCdLabor:                {"労働", "Labor"},
+	CategoryCdAdministrativeProc:   {"行政手続", "Administrative Procedure"},
+	CategoryCdLand:                 {"土地", "Land"},
+	CategoryCdNationalBonds:        {"国債", "National Bonds"},
+	CategoryCdFinanceInsurance:     {"金融・保険", "Finance and Insurance"},
+	CategoryCdEnvironmentalProtect: {"環境保全", "Environmental Protection"},
+	CategoryCdStatistics:           {"統計", "Statistics"},
+	CategoryCdCityPlanning:         {"都市計画", "City Planning"},
+	CategoryCdEducation:            {"教育", "Education"},
+	CategoryCdForeignExchangeTrade: {"外国為替・貿易", "Foreign Exchange and Trade"},
+	CategoryCdPublicHealth:         {"厚生", "Public Health"},
+	CategoryCdLocalGovernment:      {"地方自治", "Local Government"},
+	CategoryCdRoads:                {"道路", "Roads"},
+	CategoryCdCulture:              {"文化", "Culture"},
+	CategoryCdLandTransport:        {"陸運", "Land Transport"},
+	CategoryCdSocialWelfare:        {"社会福祉", "Social Welfare"},
+	CategoryCdLocalFinance:         {"地方財政", "Local Finance"},
+	CategoryCdRivers:               {"河川", "Rivers"},
+	CategoryCdIndustryGeneral:      {"産業通則", "General Industry"},
+	CategoryCdMaritimeTransport:    {"海運", "Maritime Transport"},
+	CategoryCdSocialInsurance:      {"社会保険", "Social Insurance"},
+	CategoryCdJudiciary:            {"司法", "Judiciary"},
+	CategoryCdDisasterManagement:   {"災害対策", "Disaster Management"},
+	CategoryCdAgriculture:          {"農業", "Agriculture"},
+	CategoryCdAviation:             {"航空", "Aviation"},
+	CategoryCdDefense:              {"防衛", "Defense"},
+	CategoryCdCivil:                {"民事", "Civil Affairs"},
+	CategoryCdBuildingHousing:      {"建築・住宅", "Building and Housing"},
+	CategoryCdForestry:             {"林業", "Forestry"},
+	CategoryCdFreightTransport:     {"貨物運送", "Freight Transport"},
+	CategoryCdForeignAffairs:       {"外事", "Foreign Affairs"},
+}
+
+// Label returns the name of the category in a language, or the code itself if unknown
+func (c CategoryCd) Label(lang Language) string {
+	if l, ok := categoryLabels[c]; ok {
+		return l.in(lang)
+	}
+	return string(c)
+}
+
+// Label returns the name of the era in a language ("令和" or "Reiwa")
+func (e LawNumEra) Label(lang Language) string {
+	if kanji := e.Kanji(); kanji != "" {
+		return localized{kanji, string(e)}.in(lang)
+	}
+	return string(e)
+}
+
+var errorCategoryLabels = map[ErrorCategory]localized{
+	ErrorInternal:    {"内部エラー", "Internal error"},
+	ErrorNetwork:     {"ネットワークエラー", "Network error"},
+	ErrorNotFound:    {"見つかりません", "Not found"},
+	ErrorRateLimited: {"リクエスト制限", "Rate limited"},
+	ErrorValidation:  {"検証エラー", "Validation error"},
+}
+
+// Label returns a short description of the category in a language
+func (c ErrorCategory) Label(lang Language) string {
+	if l, ok := errorCategoryLabels[c]; ok {
+		return l.in(lang)
+	}
+	return string(c)
+}
+
+// errorMessagesJa are the Japanese messages of errors, in the order they are checked
+var errorMessagesJa = []struct {
+	err error
+	msg string
+}{
+	{ErrBudgetExhausted, "リクエスト数の上限に達しました"},
+	{ErrCursorMismatch, "カーソルが別の検索条件のものです"},
+	{ErrDownloadChanged, "ダウンロード中に内容が変更されました"},
+	{ErrInvalidHighlightTag, "ハイライトタグが不正です"},
+	{ErrInvalidSearchQuery, "検索式が不正です"},
+	{ErrManifestUnsigned, "マニフェストに署名がありません"},
+	{ErrManifestSignature, "マニフェストの署名が不正です"},
+	{ErrProvisionNotFound, "条項が見つかりません"},
+	{ErrSuperseded, "新しい検索に置き換えられました"},
+	{ErrTextNotFound, "改正する文言が見つかりません"},
+	{fs.ErrNotExist, "ファイルが見つかりません"},
+	{context.DeadlineExceeded, "タイムアウトしました"},
+	{context.Canceled, "キャンセルされました"},
+}
+
+// LocalizeError returns the message of an error for users of a language. Errors are
+// written in English; in Japanese, a description of the package error, API error or error
+// category is followed by the original message for details.
+func LocalizeError(err error, lang Language) string {
+	if err == nil {
+		return ""
+	}
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	if lang != LanguageJapanese {
+		return err.Error()
+	}
+	var summary string
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		summary = fmt.Sprintf("APIエラー（ステータス %d）", apiErr.StatusCode)
+	} else {
+		for _, m := range errorMessagesJa {
+			if errors.Is(err, m.err) {
+				summary = m.msg
+				break
+			}
+		}
+	}
+	if summary == "" {
+		summary = ErrorCategoryOf(err).Label(lang)
+	}
+	return summary + "：" + err.Error()
+}
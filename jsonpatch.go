@@ -0,0 +1,394 @@
+package lawapi
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JSONPatchOperation is an operation of a JSON Patch document (RFC 6902). DiffJSON
+// produces add, remove and replace operations; ApplyJSONPatch also accepts test.
+type JSONPatchOperation struct {
+	Op string `json:"op"`
+	// Path is a JSON Pointer (RFC 6901) to the target location
+	Path string `json:"path"`
+	// Value is the value added, replaced or tested, absent for remove
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// maxLCSCells bounds the table of the longest common subsequence of two arrays; longer
+// arrays are compared element by element at the same index
+const maxLCSCells = 1 << 22
+
+// DiffJSON returns a JSON Patch transforming the JSON document from into to. Changed
+// members of objects are patched recursively. Array elements are matched by their longest
+// common subsequence, so that an element inserted into or removed from a list of provisions
+// is a single operation rather than a change of every following element, and elements
+// between matches are patched in place.
+func DiffJSON(from, to []byte) ([]JSONPatchOperation, error) {
+	a, err := decodeJSONValue(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := decodeJSONValue(to)
+	if err != nil {
+		return nil, err
+	}
+	var ops []JSONPatchOperation
+	if err := diffJSONValue(&ops, "", a, b); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+// ApplyJSONPatch applies the operations of a JSON Patch to a JSON document, returning the
+// patched document. Operations are applied in order and the first failing one is returned
+// as an error. move and copy are not supported.
+func ApplyJSONPatch(doc []byte, patch []JSONPatchOperation) ([]byte, error) {
+	v, err := decodeJSONValue(doc)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range patch {
+		var value any
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, fmt.Errorf("json patch operation %d (%s %s): value is missing", i, op.Op, op.Path)
+			}
+			if value, err = decodeJSONValue(op.Value); err != nil {
+				return nil, fmt.Errorf("json patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf("json patch operation %d: unsupported operation %q", i, op.Op)
+		}
+		tokens, err := parseJSONPointer(op.Path)
+		if err == nil {
+			v, err = applyJSONPatchOperation(v, tokens, op.Op, value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("json patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(v)
+}
+
+func decodeJSONValue(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	return v, nil
+}
+
+func diffJSONValue(ops *[]JSONPatchOperation, path string, from, to any) error {
+	switch a := from.(type) {
+	case map[string]any:
+		if b, ok := to.(map[string]any); ok {
+			return diffJSONObject(ops, path, a, b)
+		}
+	case []any:
+		if b, ok := to.([]any); ok {
+			return diffJSONArray(ops, path, a, b)
+		}
+	}
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+	return appendJSONPatch(ops, "replace", path, to)
+}
+
+func diffJSONObject(ops *[]JSONPatchOperation, path string, from, to map[string]any) error {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		a, inFrom := from[key]
+		b, inTo := to[key]
+		child := path + "/" + escapeJSONPointer(key)
+		var err error
+		switch {
+		case !inTo:
+			*ops = append(*ops, JSONPatchOperation{Op: "remove", Path: child})
+		case !inFrom:
+			err = appendJSONPatch(ops, "add", child, b)
+		default:
+			err = diffJSONValue(ops, child, a, b)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func diffJSONArray(ops *[]JSONPatchOperation, path string, from, to []any) error {
+	matches, err := matchJSONArrays(from, to)
+	if err != nil {
+		return err
+	}
+	// k is the index in the array as patched so far
+	i, j, k := 0, 0, 0
+	for _, m := range append(matches, [2]int{len(from), len(to)}) {
+		for ; i < m[0] && j < m[1]; i, j, k = i+1, j+1, k+1 {
+			if err := diffJSONValue(ops, path+"/"+strconv.Itoa(k), from[i], to[j]); err != nil {
+				return err
+			}
+		}
+		for ; i < m[0]; i++ {
+			*ops = append(*ops, JSONPatchOperation{Op: "remove", Path: path + "/" + strconv.Itoa(k)})
+		}
+		for ; j < m[1]; j, k = j+1, k+1 {
+			if err := appendJSONPatch(ops, "add", path+"/"+strconv.Itoa(k), to[j]); err != nil {
+				return err
+			}
+		}
+		// Skip the matching element
+		i, j, k = i+1, j+1, k+1
+	}
+	return nil
+}
+
+// matchJSONArrays returns the index pairs of equal elements of the longest common
+// subsequence of two arrays, in order
+func matchJSONArrays(from, to []any) ([][2]int, error) {
+	if len(from)*len(to) > maxLCSCells {
+		return nil, nil
+	}
+	encode := func(values []any) ([]string, error) {
+		keys := make([]string, len(values))
+		for i, v := range values {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			keys[i] = string(data)
+		}
+		return keys, nil
+	}
+	a, err := encode(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := encode(to)
+	if err != nil {
+		return nil, err
+	}
+
+	// lengths[i][j] is the length of the common subsequence of a[i:] and b[j:]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	var matches [][2]int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			matches = append(matches, [2]int{i, j})
+			i, j = i+1, j+1
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return matches, nil
+}
+
+func appendJSONPatch(ops *[]JSONPatchOperation, op, path string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	*ops = append(*ops, JSONPatchOperation{Op: op, Path: path, Value: data})
+	return nil
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapeJSONPointer(token string) string {
+	return jsonPointerEscaper.Replace(token)
+}
+
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// applyJSONPatchOperation applies an operation to the location tokens below node and
+// returns the patched node
+func applyJSONPatchOperation(node any, tokens []string, op string, value any) (any, error) {
+	if len(tokens) == 0 {
+		switch op {
+		case "add", "replace":
+			return value, nil
+		case "test":
+			if !reflect.DeepEqual(node, value) {
+				return nil, fmt.Errorf("test failed")
+			}
+			return node, nil
+		}
+		return nil, fmt.Errorf("cannot remove the whole document")
+	}
+	token, rest := tokens[0], tokens[1:]
+	switch n := node.(type) {
+	case map[string]any:
+		if len(rest) == 0 && op == "add" {
+			n[token] = value
+			return n, nil
+		}
+		child, ok := n[token]
+		if !ok {
+			return nil, fmt.Errorf("member %q does not exist", token)
+		}
+		if len(rest) == 0 && op == "remove" {
+			delete(n, token)
+			return n, nil
+		}
+		child, err := applyJSONPatchOperation(child, rest, op, value)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = child
+		return n, nil
+	case []any:
+		if len(rest) == 0 && op == "add" {
+			if token == "-" {
+				return append(n, value), nil
+			}
+			i, err := jsonArrayIndex(token, len(n))
+			if err != nil {
+				return nil, err
+			}
+			return slices.Insert(n, i, value), nil
+		}
+		i, err := jsonArrayIndex(token, len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) == 0 && op == "remove" {
+			return slices.Delete(n, i, i+1), nil
+		}
+		if n[i], err = applyJSONPatchOperation(n[i], rest, op, value); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("cannot address %q in a value that is neither an object nor an array", token)
+}
+
+// jsonArrayIndex parses an array index token no greater than last
+func jsonArrayIndex(token string, last int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > last {
+		return 0, fmt.Errorf("array index %d is out of range", i)
+	}
+	return i, nil
+}
+
+// RevisionPatch is a JSON Patch turning the law data of one revision of a law into the
+// next
+type RevisionPatch struct {
+	LawID string `json:"law_id"`
+	// BaseRevisionID is the revision the patch applies to, or empty for the first revision,
+	// whose patch adds the whole document
+	BaseRevisionID string `json:"base_revision_id,omitempty"`
+	// RevisionID is the revision the patch produces
+	RevisionID string               `json:"revision_id"`
+	Patch      []JSONPatchOperation `json:"patch"`
+}
+
+// RevisionPatches calls fn with a JSON Patch for each revision of a law in chronological
+// order of revision IDs, turning the law data (as JSON, with the law text as a JSON tree)
+// of the preceding revision into that of the revision. Services keeping their own copies
+// can replicate the law by applying the patches with ApplyJSONPatch or any RFC 6902
+// implementation. If baseRevisionID is empty, the first patch adds the whole first
+// revision; otherwise the patches start from the revision baseRevisionID, which the
+// caller already has. Patches are computed as revisions are retrieved, and an error
+// returned by fn stops the iteration and is returned.
+func (c *Client) RevisionPatches(ctx context.Context, lawID, baseRevisionID string, fn func(RevisionPatch) error) error {
+	resp, err := c.GetRevisionsWithContext(ctx, lawID, nil)
+	if err != nil {
+		return err
+	}
+	revisions := slices.Clone(resp.Revisions)
+	slices.SortFunc(revisions, func(a, b RevisionInfo) int {
+		return cmp.Compare(a.LawRevisionId, b.LawRevisionId)
+	})
+
+	var base []byte
+	if baseRevisionID != "" {
+		i := slices.IndexFunc(revisions, func(rev RevisionInfo) bool { return rev.LawRevisionId == baseRevisionID })
+		if i < 0 {
+			return WithErrorCategory(fmt.Errorf("revision %s is not a revision of %s", baseRevisionID, lawID), ErrorNotFound)
+		}
+		if base, err = c.revisionJSON(ctx, baseRevisionID); err != nil {
+			return err
+		}
+		revisions = revisions[i+1:]
+	}
+	for _, rev := range revisions {
+		doc, err := c.revisionJSON(ctx, rev.LawRevisionId)
+		if err != nil {
+			return err
+		}
+		patch := RevisionPatch{LawID: lawID, BaseRevisionID: baseRevisionID, RevisionID: rev.LawRevisionId}
+		if base == nil {
+			patch.Patch = []JSONPatchOperation{{Op: "add", Path: "", Value: doc}}
+		} else if patch.Patch, err = DiffJSON(base, doc); err != nil {
+			return fmt.Errorf("failed to compare %s with %s: %w", rev.LawRevisionId, baseRevisionID, err)
+		}
+		if err := fn(patch); err != nil {
+			return err
+		}
+		base, baseRevisionID = doc, rev.LawRevisionId
+	}
+	return nil
+}
+
+// revisionJSON returns the law data of a revision with the law text as a JSON tree
+func (c *Client) revisionJSON(ctx context.Context, revisionID string) ([]byte, error) {
+	format := ResponseFormatJson
+	data, err := c.GetLawDataWithContext(ctx, revisionID, &GetLawDataParams{LawFullTextFormat: &format})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve %s: %w", revisionID, err)
+	}
+	return json.Marshal(data)
+}
@@ -0,0 +1,114 @@
+package lawapi
+
+import (
+	"strconv"
+	"strings"
+)
+
+var kanjiDigits = map[rune]int64{
+	'〇': 0, '零': 0, '一': 1, '二': 2, '三': 3, '四': 4,
+	'五': 5, '六': 6, '七': 7, '八': 8, '九': 9,
+	'０': 0, '１': 1, '２': 2, '３': 3, '４': 4,
+	'５': 5, '６': 6, '７': 7, '８': 8, '９': 9,
+	'0': 0, '1': 1, '2': 2, '3': 3, '4': 4,
+	'5': 5, '6': 6, '7': 7, '8': 8, '9': 9,
+}
+
+var kanjiSmallUnits = map[rune]int64{'十': 10, '百': 100, '千': 1000}
+
+var kanjiLargeUnits = map[rune]int64{'万': 10000, '億': 100000000, '兆': 1000000000000}
+
+// ParseKanjiNumber parses a number written in kanji (e.g. "三百五十", "二万") or
+// in full-width/half-width digits. Separators such as "，" and "," are ignored.
+func ParseKanjiNumber(s string) (int64, bool) {
+	s = strings.NewReplacer(",", "", "，", "", "、", "").Replace(strings.TrimSpace(s))
+	if s == "" {
+		return 0, false
+	}
+
+	var total, section, digits int64
+	hasDigits := false
+	for _, r := range s {
+		if d, ok := kanjiDigits[r]; ok {
+			digits = digits*10 + d
+			hasDigits = true
+			continue
+		}
+		if unit, ok := kanjiSmallUnits[r]; ok {
+			if !hasDigits {
+				digits = 1
+			}
+			section += digits * unit
+			digits, hasDigits = 0, false
+			continue
+		}
+		if unit, ok := kanjiLargeUnits[r]; ok {
+			section += digits
+			if section == 0 {
+				section = 1
+			}
+			total += section * unit
+			section, digits, hasDigits = 0, 0, false
+			continue
+		}
+		return 0, false
+	}
+	return total + section + digits, true
+}
+
+// FormatKanjiNumber formats a non-negative number in kanji as used in law text (e.g. 125 → "百二十五")
+func FormatKanjiNumber(n int64) string {
+	if n == 0 {
+		return "〇"
+	}
+	if n < 0 {
+		return "マイナス" + FormatKanjiNumber(-n)
+	}
+
+	digits := []string{"", "一", "二", "三", "四", "五", "六", "七", "八", "九"}
+	formatSection := func(v int64) string {
+		var sb strings.Builder
+		for _, u := range []struct {
+			value int64
+			name  string
+		}{{1000, "千"}, {100, "百"}, {10, "十"}} {
+			d := v / u.value
+			v %= u.value
+			if d == 0 {
+				continue
+			}
+			if d > 1 {
+				sb.WriteString(digits[d])
+			}
+			sb.WriteString(u.name)
+		}
+		sb.WriteString(digits[v])
+		return sb.String()
+	}
+
+	var sb strings.Builder
+	for _, u := range []struct {
+		value int64
+		name  string
+	}{{1000000000000, "兆"}, {100000000, "億"}, {10000, "万"}, {1, ""}} {
+		section := n / u.value
+		n %= u.value
+		if section == 0 {
+			continue
+		}
+		sb.WriteString(formatSection(section))
+		sb.WriteString(u.name)
+	}
+	return sb.String()
+}
+
+// formatKanjiNum formats a Num attribute such as "2" or "2_3" as kanji ("二", "二の三")
+func formatKanjiNum(num string) string {
+	parts := strings.Split(num, "_")
+	for i, part := range parts {
+		if v, err := strconv.ParseInt(part, 10, 64); err == nil {
+			parts[i] = FormatKanjiNumber(v)
+		}
+	}
+	return strings.Join(parts, "の")
+}
@@ -0,0 +1,398 @@
+// Package lawapistub is a stub server of the Japan Law API for contract tests. Like a
+// Prism mock server, it validates the parameters of incoming requests against the
+// parameter schemas of the OpenAPI specification and answers with the example payloads
+// of the specification, so that tests can check both the requests a client sends and
+// how the code consuming it handles the responses:
+//
+//	stub := lawapistub.NewServer()
+//	ts := httptest.NewServer(stub)
+//	defer ts.Close()
+//	client := lawapi.NewClient()
+//	client.SetBaseURL(ts.URL)
+//	// ... exercise the code under test ...
+//	if err := stub.Err(); err != nil {
+//		t.Fatal(err)
+//	}
+//
+// The operations are generated from the specification by clientgen -stub.
+package lawapistub
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// operation is an operation of the specification
+type operation struct {
+	method      string
+	path        string
+	operationID string
+	params      []parameter
+	responses   []response
+}
+
+// parameter is a path or query parameter of an operation with the constraints of its
+// schema
+type parameter struct {
+	name     string
+	in       string
+	required bool
+	// array parameters take repeated or comma-separated values
+	array bool
+	// kind is the type of the values: string, integer, number, boolean, date or date-time
+	kind string
+	enum []string
+}
+
+// response is the example payload of a response status and content type
+type response struct {
+	status      int
+	contentType string
+	body        string
+}
+
+// Request is a request received by the stub
+type Request struct {
+	Method string
+	Path   string
+	// OperationID is the operation of the specification the request matched, or empty
+	OperationID string
+	// PathParams are the values of the path parameters of the operation
+	PathParams map[string]string
+	Query      url.Values
+	// Status is the status code of the response
+	Status int
+	// Violations are the ways the request does not conform to the specification
+	Violations []string
+}
+
+// Server serves the operations of the specification. Requests with invalid parameters
+// are answered with 400 and an error_info payload describing the violations. Otherwise
+// the example of the first 2xx response is served, or of the status requested with the
+// Prism header "Prefer: code=400", in the format of the response_format parameter or the
+// Accept header (JSON by default). A Server is safe for concurrent use.
+type Server struct {
+	mu        sync.Mutex
+	requests  []Request
+	overrides map[string][]response
+}
+
+// NewServer creates a stub server
+func NewServer() *Server {
+	return &Server{overrides: map[string][]response{}}
+}
+
+// SpecVersion returns the version of the specification the stub was generated from
+func SpecVersion() string {
+	return specVersion
+}
+
+// SetResponse replaces the example payload of a status and content type of an operation
+// (e.g. "get-laws", 200, "application/json"), for tests that need specific data. The
+// requests of the operation are still validated.
+func (s *Server) SetResponse(operationID string, status int, contentType, body string) error {
+	if findOperation(operationID) == nil {
+		return fmt.Errorf("unknown operation %q", operationID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	overrides := slices.DeleteFunc(s.overrides[operationID], func(r response) bool {
+		return r.status == status && r.contentType == contentType
+	})
+	s.overrides[operationID] = append(overrides, response{status, contentType, body})
+	return nil
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// Err returns an error listing the violations of the requests received so far, or nil
+// if all of them conformed to the specification
+func (s *Server) Err() error {
+	var errs []error
+	for _, req := range s.Requests() {
+		for _, violation := range req.Violations {
+			errs = append(errs, fmt.Errorf("%s %s: %s", req.Method, req.Path, violation))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Reset forgets the received requests and the responses set with SetResponse
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.overrides = map[string][]response{}
+}
+
+// ServeHTTP serves a request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query()}
+	defer func() {
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		s.mu.Unlock()
+	}()
+	xmlFormat := wantsXML(r)
+
+	op, pathParams, allowed := matchOperation(r.Method, r.URL.Path)
+	if op == nil {
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			req.Status = http.StatusMethodNotAllowed
+		} else {
+			req.Status = http.StatusNotFound
+		}
+		req.Violations = []string{fmt.Sprintf("no operation for %s %s", r.Method, r.URL.Path)}
+		writeError(w, req.Status, req.Violations[0], xmlFormat)
+		return
+	}
+	req.OperationID = op.operationID
+	req.PathParams = pathParams
+
+	req.Violations = validate(op, pathParams, req.Query)
+	if len(req.Violations) > 0 {
+		req.Status = http.StatusBadRequest
+		writeError(w, req.Status, strings.Join(req.Violations, "; "), xmlFormat)
+		return
+	}
+
+	status := 0
+	if prefer := r.Header.Get("Prefer"); prefer != "" {
+		for _, pref := range strings.Split(prefer, ",") {
+			if code, ok := strings.CutPrefix(strings.TrimSpace(pref), "code="); ok {
+				n, err := strconv.Atoi(code)
+				if err != nil {
+					req.Status = http.StatusBadRequest
+					writeError(w, req.Status, fmt.Sprintf("invalid Prefer header %q", prefer), xmlFormat)
+					return
+				}
+				status = n
+			}
+		}
+	}
+	resp, ok := s.response(op, status, xmlFormat)
+	if !ok {
+		req.Status = http.StatusNotImplemented
+		writeError(w, req.Status, fmt.Sprintf("operation %s has no example response with status %d", op.operationID, status), xmlFormat)
+		return
+	}
+	req.Status = resp.status
+	w.Header().Set("Content-Type", mediaType(resp.contentType))
+	w.WriteHeader(resp.status)
+	w.Write([]byte(resp.body))
+}
+
+// response returns the response of an operation with a status, or the first 2xx status
+// if status is 0, preferring the requested format
+func (s *Server) response(op *operation, status int, xmlFormat bool) (response, bool) {
+	s.mu.Lock()
+	candidates := append(slices.Clone(s.overrides[op.operationID]), op.responses...)
+	s.mu.Unlock()
+	if status == 0 {
+		for _, r := range candidates {
+			if r.status >= 200 && r.status < 300 && (status == 0 || r.status < status) {
+				status = r.status
+			}
+		}
+	}
+	want := "application/json"
+	if xmlFormat {
+		want = "application/xml"
+	}
+	var found *response
+	for i, r := range candidates {
+		if r.status != status {
+			continue
+		}
+		// Overrides come first, and the requested format before the others
+		if r.contentType == want {
+			return r, true
+		}
+		if found == nil || (found.contentType != "*/*" && r.contentType == "*/*") {
+			found = &candidates[i]
+		}
+	}
+	if found == nil {
+		return response{}, false
+	}
+	return *found, true
+}
+
+// findOperation returns the operation with an ID, or nil
+func findOperation(operationID string) *operation {
+	for i := range operations {
+		if operations[i].operationID == operationID {
+			return &operations[i]
+		}
+	}
+	return nil
+}
+
+// matchOperation returns the operation of a method and path with the values of its path
+// parameters, or the methods allowed for the path if the method does not match
+func matchOperation(method, path string) (*operation, map[string]string, []string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var allowed []string
+	for i := range operations {
+		op := &operations[i]
+		params, ok := matchPath(op.path, segments)
+		if !ok {
+			continue
+		}
+		if op.method != method {
+			allowed = append(allowed, op.method)
+			continue
+		}
+		return op, params, nil
+	}
+	return nil, nil, allowed
+}
+
+// matchPath matches the segments of a path against a path template
+func matchPath(template string, segments []string) (map[string]string, bool) {
+	parts := strings.Split(strings.Trim(template, "/"), "/")
+	if len(parts) != len(segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, part := range parts {
+		if name, ok := strings.CutPrefix(part, "{"); ok {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[strings.TrimSuffix(name, "}")] = segments[i]
+		} else if part != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// validate checks the path and query parameters of a request against the parameters of
+// its operation
+func validate(op *operation, pathParams map[string]string, query url.Values) []string {
+	var violations []string
+	for name := range query {
+		if !slices.ContainsFunc(op.params, func(p parameter) bool { return p.name == name && p.in == "query" }) {
+			violations = append(violations, fmt.Sprintf("unknown query parameter %s", name))
+		}
+	}
+	for _, p := range op.params {
+		var values []string
+		switch p.in {
+		case "path":
+			if v, ok := pathParams[p.name]; ok {
+				values = []string{v}
+			}
+		case "query":
+			values = query[p.name]
+		default:
+			continue
+		}
+		if len(values) == 0 {
+			if p.required {
+				violations = append(violations, fmt.Sprintf("%s parameter %s is required", p.in, p.name))
+			}
+			continue
+		}
+		if p.array {
+			var split []string
+			for _, v := range values {
+				split = append(split, strings.Split(v, ",")...)
+			}
+			values = split
+		} else if len(values) > 1 {
+			violations = append(violations, fmt.Sprintf("%s parameter %s is given %d times", p.in, p.name, len(values)))
+			continue
+		}
+		for _, v := range values {
+			if err := p.check(v); err != nil {
+				violations = append(violations, fmt.Sprintf("%s parameter %s: %v", p.in, p.name, err))
+			}
+		}
+	}
+	slices.Sort(violations)
+	return violations
+}
+
+// check checks a value against the schema of the parameter
+func (p parameter) check(v string) error {
+	var err error
+	switch p.kind {
+	case "integer":
+		_, err = strconv.ParseInt(v, 10, 64)
+	case "number":
+		_, err = strconv.ParseFloat(v, 64)
+	case "boolean":
+		if v != "true" && v != "false" {
+			err = errors.New("not true or false")
+		}
+	case "date":
+		_, err = time.Parse("2006-01-02", v)
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, v)
+	}
+	if err != nil {
+		return fmt.Errorf("%q is not a valid %s", v, p.kind)
+	}
+	if len(p.enum) > 0 && !slices.Contains(p.enum, v) {
+		return fmt.Errorf("%q is not one of %s", v, strings.Join(p.enum, ", "))
+	}
+	return nil
+}
+
+// wantsXML reports whether a request asks for XML, with the response_format parameter
+// or else the Accept header
+func wantsXML(r *http.Request) bool {
+	if format := r.URL.Query().Get("response_format"); format != "" {
+		return format == "xml"
+	}
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "xml") && !strings.Contains(accept, "json")
+}
+
+// mediaType returns the Content-Type header of a response content type
+func mediaType(contentType string) string {
+	switch {
+	case contentType == "*/*":
+		return "application/octet-stream"
+	case strings.Contains(contentType, "json") || strings.Contains(contentType, "xml"):
+		return contentType + "; charset=utf-8"
+	}
+	return contentType
+}
+
+// writeError writes an error_info payload for an error of the stub. Its code is the
+// status followed by 000, which the API itself does not use.
+func writeError(w http.ResponseWriter, status int, message string, xmlFormat bool) {
+	code := fmt.Sprintf("%d000", status)
+	if xmlFormat {
+		var sb strings.Builder
+		sb.WriteString("<error_info>\n    <code>" + code + "</code>\n    <message>")
+		xml.EscapeText(&sb, []byte(message))
+		sb.WriteString("</message>\n</error_info>\n")
+		w.Header().Set("Content-Type", mediaType("application/xml"))
+		w.WriteHeader(status)
+		w.Write([]byte(sb.String()))
+		return
+	}
+	body, _ := json.Marshal(map[string]string{"code": code, "message": message})
+	w.Header().Set("Content-Type", mediaType("application/json"))
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
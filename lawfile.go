@@ -0,0 +1,200 @@
+package lawapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// LawFileOptions configures OpenLawFile
+type LawFileOptions struct {
+	// Mmap maps the file into memory instead of reading it onto the heap. The pages of a
+	// mapped file are loaded as they are read and can be dropped by the operating system
+	// under memory pressure, which keeps the resident memory of scans over a whole local
+	// mirror low. Where mapping is not supported, the file is read.
+	Mmap bool
+}
+
+// LawFile is a law XML file whose content is parsed lazily: Law builds the tree on its
+// first call, and ElementText reads single elements, such as LawTitle, without building
+// it. The trees and strings it returns stay valid after Close. Its methods are safe for
+// concurrent use, but must not be called concurrently with Close.
+type LawFile struct {
+	path  string
+	data  []byte
+	unmap func() error
+	// lawData is set if data is the JSON body of a law_data response instead of law XML
+	lawData bool
+
+	once sync.Once
+	law  *LawNode
+	err  error
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// OpenLawFile opens a law XML file
+func OpenLawFile(path string, opts LawFileOptions) (*LawFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	lf := &LawFile{path: path}
+	if opts.Mmap && info.Size() > 0 && int64(int(info.Size())) == info.Size() {
+		data, unmap, err := mmapFile(f, int(info.Size()))
+		if err == nil {
+			lf.data, lf.unmap = data, unmap
+			return lf, nil
+		}
+		if !errors.Is(err, errors.ErrUnsupported) {
+			return nil, fmt.Errorf("failed to map %s: %w", path, err)
+		}
+	}
+	if lf.data, err = io.ReadAll(f); err != nil {
+		return nil, err
+	}
+	return lf, nil
+}
+
+// lawFileFromLawData returns a LawFile of the JSON body of a law_data response, such as
+// a mirrored one, whose law_full_text is decoded on the first call of Law
+func lawFileFromLawData(path string, body []byte) *LawFile {
+	return &LawFile{path: path, data: body, lawData: true}
+}
+
+// parseLawDataBody builds the tree of the JSON body of a law_data response
+func parseLawDataBody(body []byte) (*LawNode, error) {
+	var lawData LawDataResponse
+	if err := json.Unmarshal(body, &lawData); err != nil {
+		return nil, fmt.Errorf("failed to decode law_data response: %w", err)
+	}
+	return ParseLawFullText(&lawData)
+}
+
+// Path returns the path of the file
+func (f *LawFile) Path() string {
+	return f.path
+}
+
+// Size returns the size of the file in bytes
+func (f *LawFile) Size() int {
+	return len(f.data)
+}
+
+// Mapped reports whether the file is mapped into memory
+func (f *LawFile) Mapped() bool {
+	return f.unmap != nil
+}
+
+// Bytes returns the content of the file, or the JSON body of a law_data response. For
+// mapped files it is valid until Close and must not be modified.
+func (f *LawFile) Bytes() []byte {
+	return f.data
+}
+
+// Law returns the parsed tree of the file, parsing it on the first call
+func (f *LawFile) Law() (*LawNode, error) {
+	f.once.Do(func() {
+		if err := f.checkOpen(); err != nil {
+			f.err = err
+			return
+		}
+		if f.lawData {
+			f.law, f.err = parseLawDataBody(f.data)
+			return
+		}
+		f.law, f.err = ParseLawXML(bytes.NewReader(f.data))
+	})
+	return f.law, f.err
+}
+
+// ElementText returns the text of the first element with a tag (e.g. "LawTitle" or
+// "LawNum"), reading the file only up to the end of that element, or false if there is
+// none. The JSON of a law_data response cannot be read that way, so the tree is built with
+// Law instead.
+func (f *LawFile) ElementText(tag string) (string, bool, error) {
+	if err := f.checkOpen(); err != nil {
+		return "", false, err
+	}
+	if f.lawData {
+		law, err := f.Law()
+		if err != nil {
+			return "", false, err
+		}
+		node := law
+		if law.Tag != tag {
+			node = law.Find(tag)
+		}
+		if node == nil {
+			return "", false, nil
+		}
+		return strings.TrimSpace(node.PlainText()), true, nil
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(f.data))
+	var sb strings.Builder
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return "", false, nil
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("failed to parse law XML: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth > 0 || t.Name.Local == tag {
+				depth++
+			}
+		case xml.EndElement:
+			if depth > 0 {
+				depth--
+				if depth == 0 {
+					return strings.TrimSpace(sb.String()), true, nil
+				}
+			}
+		case xml.CharData:
+			if depth > 0 {
+				sb.Write(t)
+			}
+		}
+	}
+}
+
+// Close releases the content of the file, unmapping it if it is mapped
+func (f *LawFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	data := f.data
+	f.data = nil
+	if f.unmap != nil && data != nil {
+		return f.unmap()
+	}
+	return nil
+}
+
+// checkOpen returns os.ErrClosed if the file is closed
+func (f *LawFile) checkOpen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return os.ErrClosed
+	}
+	return nil
+}
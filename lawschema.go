@@ -0,0 +1,318 @@
+package lawapi
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SchemaViolation is a deviation of a law tree from the structure of the law XML schema
+type SchemaViolation struct {
+	// Path locates the element, with 1-based positions among same-named siblings
+	// (e.g. /Law/LawBody/MainProvision/Article[3]/Paragraph)
+	Path string `json:"path"`
+	// Message describes the violation
+	Message string `json:"message"`
+}
+
+// String returns the path and message of the violation
+func (v SchemaViolation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// elementSchema describes the content model of an element. Content is a sequence of
+// particles: element names with an optional quantifier (?, * or +), or choices between
+// names separated by "|" whose quantifier applies to the chosen name. Elements with text
+// have mixed content, whose elements may appear in any order. Attrs maps attribute
+// names to their allowed values separated by "|", or "" for any value; a trailing "!" on
+// the name marks a required attribute.
+type elementSchema struct {
+	content string
+	text    bool
+	attrs   map[string]string
+}
+
+const (
+	yesNo       = "true|false"
+	inlineText  = "Line|Ruby|Sup|Sub*"
+	sentenceSeq = "Sentence|Column|Table+"
+)
+
+// lawSchema is a structural subset of the e-Gov law XML schema (XMLSchemaForJapaneseLaw_v3):
+// the law, its provisions down to sub-items, the table of contents and inline text.
+// Tables, figures, appendices and amendment provisions are checked for their placement but
+// not for their content.
+var lawSchema = map[string]elementSchema{
+	"Law": {content: "LawNum LawBody", attrs: map[string]string{
+		"Era!":            "Meiji|Taisho|Showa|Heisei|Reiwa",
+		"Year!":           "",
+		"Num!":            "",
+		"PromulgateMonth": "",
+		"PromulgateDay":   "",
+		"LawType!":        "Constitution|Act|CabinetOrder|ImperialOrder|MinisterialOrdinance|Rule|Misc",
+		"Lang!":           "ja|en",
+	}},
+	"LawNum": {text: true},
+	"LawBody": {content: "LawTitle EnactStatement* TOC? Preamble? MainProvision SupplProvision* AppdxTable* AppdxNote* AppdxStyle* Appdx* AppdxFig* AppdxFormat*",
+		attrs: map[string]string{"Subject": ""}},
+	"LawTitle":       {text: true, content: inlineText, attrs: map[string]string{"Kana": "", "Abbrev": "", "AbbrevKana": ""}},
+	"EnactStatement": {text: true, content: inlineText},
+	"Preamble":       {content: "Paragraph+"},
+	"MainProvision":  {content: "Part|Chapter|Section|Article|Paragraph+", attrs: map[string]string{"Extract": yesNo}},
+
+	"Part":       {content: "PartTitle Article* Chapter+", attrs: map[string]string{"Num!": "", "Delete": yesNo, "Hide": yesNo}},
+	"Chapter":    {content: "ChapterTitle Article* Section*", attrs: map[string]string{"Num!": "", "Delete": yesNo, "Hide": yesNo}},
+	"Section":    {content: "SectionTitle Article* Subsection|Division*", attrs: map[string]string{"Num!": "", "Delete": yesNo, "Hide": yesNo}},
+	"Subsection": {content: "SubsectionTitle Article* Division*", attrs: map[string]string{"Num!": "", "Delete": yesNo, "Hide": yesNo}},
+	"Division":   {content: "DivisionTitle Article+", attrs: map[string]string{"Num!": "", "Delete": yesNo, "Hide": yesNo}},
+
+	"PartTitle":       {text: true, content: inlineText},
+	"ChapterTitle":    {text: true, content: inlineText},
+	"SectionTitle":    {text: true, content: inlineText},
+	"SubsectionTitle": {text: true, content: inlineText},
+	"DivisionTitle":   {text: true, content: inlineText},
+
+	"Article":        {content: "ArticleCaption? ArticleTitle Paragraph+ SupplNote?", attrs: map[string]string{"Num!": "", "Delete": yesNo, "Hide": yesNo}},
+	"ArticleCaption": {text: true, content: inlineText, attrs: map[string]string{"CommonCaption": yesNo}},
+	"ArticleTitle":   {text: true, content: inlineText},
+	"SupplNote":      {text: true, content: inlineText},
+
+	"Paragraph": {content: "ParagraphCaption? ParagraphNum ParagraphSentence AmendProvision* Class* TableStruct* FigStruct* StyleStruct* Item* List*",
+		attrs: map[string]string{"Num!": "", "OldStyle": yesNo, "OldNum": yesNo, "Hide": yesNo}},
+	"ParagraphCaption":  {text: true, content: inlineText, attrs: map[string]string{"CommonCaption": yesNo}},
+	"ParagraphNum":      {text: true, content: inlineText},
+	"ParagraphSentence": {content: "Sentence+"},
+	"Class":             {content: "ClassTitle? ClassSentence Item*", attrs: map[string]string{"Num!": ""}},
+	"ClassTitle":        {text: true, content: inlineText},
+	"ClassSentence":     {content: sentenceSeq},
+
+	"Item": {content: "ItemTitle? ItemSentence Subitem1* TableStruct* FigStruct* StyleStruct* List*",
+		attrs: map[string]string{"Num!": "", "Delete": yesNo, "Hide": yesNo}},
+	"ItemTitle":    {text: true, content: inlineText},
+	"ItemSentence": {content: sentenceSeq},
+
+	"Sentence": {text: true, content: "Line|QuoteStruct|ArithFormula|Ruby|Sup|Sub*", attrs: map[string]string{
+		"Num":         "",
+		"Function":    "main|proviso",
+		"Indent":      "Paragraph|Item|Subitem1|Subitem2|Subitem3|Subitem4|Subitem5|Subitem6|Subitem7|Subitem8|Subitem9|Subitem10",
+		"WritingMode": "vertical|horizontal",
+	}},
+	"Column": {content: "Sentence+", attrs: map[string]string{"Num": "", "LineBreak": yesNo, "Align": "left|center|right|justify"}},
+
+	"SupplProvision": {content: "SupplProvisionLabel Chapter|Article|Paragraph+ SupplProvisionAppdxTable* SupplProvisionAppdxStyle* SupplProvisionAppdx*",
+		attrs: map[string]string{"Type": "New|Amend", "AmendLawNum": "", "Extract": yesNo}},
+	"SupplProvisionLabel": {text: true, content: inlineText},
+
+	"TOC":                {content: "TOCLabel? TOCPreambleLabel? TOCPart|TOCChapter|TOCSection|TOCArticle* TOCSupplProvision? TOCAppdxTableLabel*"},
+	"TOCLabel":           {text: true, content: inlineText},
+	"TOCPreambleLabel":   {text: true, content: inlineText},
+	"TOCAppdxTableLabel": {text: true, content: inlineText},
+	"TOCPart":            {content: "PartTitle ArticleRange? TOCChapter*", attrs: map[string]string{"Num!": "", "Delete": yesNo}},
+	"TOCChapter":         {content: "ChapterTitle ArticleRange? TOCSection*", attrs: map[string]string{"Num!": "", "Delete": yesNo}},
+	"TOCSection":         {content: "SectionTitle ArticleRange? TOCSubsection|TOCDivision*", attrs: map[string]string{"Num!": "", "Delete": yesNo}},
+	"TOCSubsection":      {content: "SubsectionTitle ArticleRange? TOCDivision*", attrs: map[string]string{"Num!": "", "Delete": yesNo}},
+	"TOCDivision":        {content: "DivisionTitle ArticleRange?", attrs: map[string]string{"Num!": "", "Delete": yesNo}},
+	"TOCArticle":         {content: "ArticleTitle ArticleCaption", attrs: map[string]string{"Num!": "", "Delete": yesNo}},
+	"TOCSupplProvision":  {content: "SupplProvisionLabel ArticleRange? TOCArticle|TOCChapter*"},
+	"ArticleRange":       {text: true, content: inlineText},
+
+	"Line": {text: true, content: "QuoteStruct|ArithFormula|Ruby|Sup|Sub*", attrs: map[string]string{"Style": "dotted|double|none|solid"}},
+	"Ruby": {text: true, content: "Rt*"},
+	"Rt":   {text: true},
+	"Sup":  {text: true},
+	"Sub":  {text: true},
+}
+
+func init() {
+	// Sub-items nest ten levels deep with the same content model
+	for i := 1; i <= 10; i++ {
+		name := "Subitem" + strconv.Itoa(i)
+		content := name + "Title? " + name + "Sentence"
+		if i < 10 {
+			content += " Subitem" + strconv.Itoa(i+1) + "*"
+		}
+		lawSchema[name] = elementSchema{content: content + " TableStruct* FigStruct* StyleStruct* List*",
+			attrs: map[string]string{"Num!": "", "Delete": yesNo, "Hide": yesNo}}
+		lawSchema[name+"Title"] = elementSchema{text: true, content: inlineText}
+		lawSchema[name+"Sentence"] = elementSchema{content: sentenceSeq}
+	}
+}
+
+// particle is one position of a content model
+type particle struct {
+	names    []string
+	min, max int // max < 0 means unbounded
+}
+
+func (p particle) allows(tag string) bool {
+	return slices.Contains(p.names, tag)
+}
+
+func (p particle) String() string {
+	return strings.Join(p.names, " or ")
+}
+
+var lawSchemaParticles = sync.OnceValue(func() map[string][]particle {
+	particles := make(map[string][]particle, len(lawSchema))
+	for tag, schema := range lawSchema {
+		var ps []particle
+		for _, token := range strings.Fields(schema.content) {
+			p := particle{min: 1, max: 1}
+			switch token[len(token)-1] {
+			case '?':
+				p.min, token = 0, token[:len(token)-1]
+			case '*':
+				p.min, p.max, token = 0, -1, token[:len(token)-1]
+			case '+':
+				p.max, token = -1, token[:len(token)-1]
+			}
+			p.names = strings.Split(token, "|")
+			ps = append(ps, p)
+		}
+		particles[tag] = ps
+	}
+	return particles
+})
+
+// ValidateLawXML parses law XML and checks it against the structure of the law XML schema
+func ValidateLawXML(r io.Reader) ([]SchemaViolation, error) {
+	root, err := ParseLawXML(r)
+	if err != nil {
+		return nil, err
+	}
+	return root.Validate(), nil
+}
+
+// Validate checks a law tree against the structure of the e-Gov law XML schema: the
+// order and number of child elements, text content, and required and enumerated
+// attributes. It covers the law, its provisions down to sub-items, the table of contents
+// and inline text; tables, figures, appendices and amendment provisions are only checked
+// for their placement. Violations are returned in document order.
+func (n *LawNode) Validate() []SchemaViolation {
+	var violations []SchemaViolation
+	if n.Tag != "Law" {
+		violations = append(violations, SchemaViolation{Path: "/" + n.Tag, Message: "root element must be Law"})
+	}
+	validateNode(n, "/"+n.Tag, &violations)
+	return violations
+}
+
+func validateNode(n *LawNode, path string, violations *[]SchemaViolation) {
+	report := func(format string, args ...any) {
+		*violations = append(*violations, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	schema, known := lawSchema[n.Tag]
+	if known {
+		validateAttrs(n, schema, report)
+		validateContent(n, schema, lawSchemaParticles()[n.Tag], report)
+	}
+
+	counts := map[string]int{}
+	for _, c := range n.Children {
+		if !c.IsText() {
+			counts[c.Tag]++
+		}
+	}
+	seen := map[string]int{}
+	for _, c := range n.Children {
+		if c.IsText() {
+			continue
+		}
+		seen[c.Tag]++
+		childPath := path + "/" + c.Tag
+		if counts[c.Tag] > 1 {
+			childPath += "[" + strconv.Itoa(seen[c.Tag]) + "]"
+		}
+		if known {
+			validateNode(c, childPath, violations)
+		}
+	}
+}
+
+func validateAttrs(n *LawNode, schema elementSchema, report func(format string, args ...any)) {
+	var names []string
+	for name := range n.Attr {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	allowed := map[string]string{}
+	for name, values := range schema.attrs {
+		name, required := strings.CutSuffix(name, "!")
+		allowed[name] = values
+		if _, ok := n.Attr[name]; required && !ok {
+			report("missing required attribute %s", name)
+		}
+	}
+	for _, name := range names {
+		values, ok := allowed[name]
+		if !ok {
+			report("attribute %s is not allowed", name)
+		} else if values != "" && !slices.Contains(strings.Split(values, "|"), n.Attr[name]) {
+			report("attribute %s has invalid value %q (want %s)", name, n.Attr[name], strings.ReplaceAll(values, "|", ", "))
+		}
+	}
+}
+
+func validateContent(n *LawNode, schema elementSchema, particles []particle, report func(format string, args ...any)) {
+	pi, count, chosen := 0, 0, ""
+	for _, c := range n.Children {
+		if c.IsText() {
+			if !schema.text && strings.TrimSpace(c.Text) != "" {
+				report("text is not allowed in %s", n.Tag)
+			}
+			continue
+		}
+		if schema.text {
+			// Mixed content allows its elements in any order between the text
+			if !slices.ContainsFunc(particles, func(p particle) bool { return p.allows(c.Tag) }) {
+				report("%s is not allowed in %s", c.Tag, n.Tag)
+			}
+			continue
+		}
+
+		// The child belongs to the current particle if it has room, or else to the next
+		// particle allowing it
+		next := -1
+		for j := pi; j < len(particles); j++ {
+			p := particles[j]
+			if !p.allows(c.Tag) {
+				continue
+			}
+			if j > pi || ((p.max < 0 || count < p.max) && (chosen == "" || chosen == c.Tag)) {
+				next = j
+				break
+			}
+		}
+		if next < 0 {
+			switch {
+			case pi < len(particles) && particles[pi].allows(c.Tag) && chosen != c.Tag:
+				report("%s cannot follow %s", c.Tag, chosen)
+			case pi < len(particles) && particles[pi].allows(c.Tag):
+				report("too many %s", c.Tag)
+			case slices.ContainsFunc(particles, func(p particle) bool { return p.allows(c.Tag) }):
+				report("%s is out of order", c.Tag)
+			default:
+				report("%s is not allowed in %s", c.Tag, n.Tag)
+			}
+			continue
+		}
+		for ; pi < next; pi, count, chosen = pi+1, 0, "" {
+			if count < particles[pi].min {
+				report("missing %s before %s", particles[pi], c.Tag)
+			}
+		}
+		count++
+		chosen = c.Tag
+	}
+	for ; pi < len(particles); pi, count = pi+1, 0 {
+		if count < particles[pi].min {
+			report("missing %s", particles[pi])
+		}
+	}
+}
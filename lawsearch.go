@@ -0,0 +1,597 @@
+package lawapi
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LawSearchOptions configures LawNode.Search
+type LawSearchOptions struct {
+	// Regexp makes the term a regular expression (RE2 syntax) instead of a substring
+	Regexp bool
+	// Exact matches the text as written. By default, the text is normalized as by
+	// NormalizeText (full-width alphanumerics, old-form kanji, bracket variants and
+	// whitespace) before matching, and so is a substring term; regular expressions are
+	// matched against the normalized text as given.
+	Exact bool
+	// ContextWidth is the number of characters of context on each side of a hit (default: 20)
+	ContextWidth int
+	// Limit is the maximum number of hits, or 0 for all
+	Limit int
+	// LawID and Revision are the law ID and revision ID used in the provision IDs of hits
+	LawID, Revision string
+	// Timeout bounds the duration of the search, or 0 for no limit
+	Timeout time.Duration
+}
+
+// LawSearchHit is an occurrence of a search term in a sentence
+type LawSearchHit struct {
+	// ProvisionID is the canonical ID of the provision containing the hit
+	ProvisionID string `json:"provision_id"`
+	// Provision is the citation of the provision (e.g. 第二条第二項第一号)
+	Provision string `json:"provision"`
+	// Before is the context before the match
+	Before string `json:"before"`
+	// Match is the matched text as written in the law
+	Match string `json:"match"`
+	// After is the context after the match
+	After string `json:"after"`
+	// Offset is the position of the match in the sentence text, in characters
+	Offset int `json:"offset"`
+	// Ref is the sentence containing the hit
+	Ref SentenceRef `json:"-"`
+}
+
+// Search finds a term in the sentences of a parsed law, as a substring or regular
+// expression, complementing the keyword endpoint that searches all laws. Hits are
+// returned in document order with the provision containing them and context snippets.
+// Matches do not span sentences.
+func (n *LawNode) Search(term string, opts *LawSearchOptions) ([]LawSearchHit, error) {
+	q := &LawQuery{}
+	t, err := q.addTerm(term, opts != nil && opts.Regexp, false)
+	if err != nil {
+		return nil, err
+	}
+	q.root = t
+	return n.SearchQuery(context.Background(), q, opts)
+}
+
+// SearchQuery finds the sentences of a parsed law matching a query (see ParseLawQuery)
+// and returns the hits of its terms that are not negated, in document order. The search
+// stops with the context error, returning the hits found so far, when ctx is done or
+// opts.Timeout has passed. The Regexp option does not apply: regular expressions are
+// written /…/ in queries.
+func (n *LawNode) SearchQuery(ctx context.Context, q *LawQuery, opts *LawSearchOptions) ([]LawSearchHit, error) {
+	var o LawSearchOptions
+	if opts != nil {
+		o = *opts
+	}
+	width := o.ContextWidth
+	if width <= 0 {
+		width = 20
+	}
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	needles := make([][]rune, len(q.terms))
+	for i, t := range q.terms {
+		if t.re == nil {
+			text := t.text
+			if !o.Exact {
+				text = NormalizeText(text)
+			}
+			needles[i] = []rune(text)
+		}
+	}
+	spans := make([][][2]int, len(q.terms))
+	matched := make([]bool, len(q.terms))
+
+	var hits []LawSearchHit
+	for _, ref := range n.Sentences() {
+		if err := ctx.Err(); err != nil {
+			return hits, fmt.Errorf("search stopped: %w", err)
+		}
+		text := []rune(ref.Sentence.PlainText())
+		searched, origin := searchText(text, o.Exact)
+		for i, t := range q.terms {
+			if t.re != nil {
+				spans[i] = regexpRuneSpans(t.re, searched)
+			} else {
+				spans[i] = substringRuneSpans(searched, needles[i])
+			}
+			matched[i] = len(spans[i]) > 0
+		}
+		if !q.root.eval(matched) {
+			continue
+		}
+
+		var sentenceSpans [][2]int
+		for i, t := range q.terms {
+			if !t.negated {
+				sentenceSpans = append(sentenceSpans, spans[i]...)
+			}
+		}
+		slices.SortFunc(sentenceSpans, func(a, b [2]int) int {
+			return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(b[1], a[1]))
+		})
+		sentenceSpans = slices.Compact(sentenceSpans)
+		for _, span := range sentenceSpans {
+			// Map the match back to the characters of the text it was normalized from
+			start, end := origin[span[0]], origin[span[1]-1]+1
+			hits = append(hits, LawSearchHit{
+				ProvisionID: ref.ProvisionID(o.LawID, o.Revision).String(),
+				Provision:   ref.Label(),
+				Before:      string(text[max(start-width, 0):start]),
+				Match:       string(text[start:end]),
+				After:       string(text[end:min(end+width, len(text))]),
+				Offset:      start,
+				Ref:         ref,
+			})
+			if o.Limit > 0 && len(hits) == o.Limit {
+				return hits, nil
+			}
+		}
+	}
+	return hits, nil
+}
+
+// searchText returns the text to search and, for each of its characters, the index of
+// the character of text it comes from. Unless exact, the text is normalized character by
+// character with the stages of NormalizeText.
+func searchText(text []rune, exact bool) ([]rune, []int) {
+	origin := make([]int, 0, len(text))
+	if exact {
+		for i := range text {
+			origin = append(origin, i)
+		}
+		return text, origin
+	}
+	searched := make([]rune, 0, len(text))
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			if len(searched) > 0 && searched[len(searched)-1] != ' ' {
+				searched = append(searched, ' ')
+				origin = append(origin, i)
+			}
+			continue
+		}
+		for _, nr := range NormalizeBrackets(NormalizeVariants(NormalizeNFKC(string(r)))) {
+			searched = append(searched, nr)
+			origin = append(origin, i)
+		}
+	}
+	return searched, origin
+}
+
+// substringRuneSpans returns the character spans of the occurrences of needle in text
+func substringRuneSpans(text, needle []rune) [][2]int {
+	if len(needle) == 0 {
+		return nil
+	}
+	var spans [][2]int
+	for _, pos := range runeIndexAll(text, needle) {
+		spans = append(spans, [2]int{pos, pos + len(needle)})
+	}
+	return spans
+}
+
+// regexpRuneSpans returns the character spans of the non-empty matches of re in text
+func regexpRuneSpans(re *regexp.Regexp, text []rune) [][2]int {
+	s := string(text)
+	var spans [][2]int
+	for _, loc := range re.FindAllStringIndex(s, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		start := utf8.RuneCountInString(s[:loc[0]])
+		spans = append(spans, [2]int{start, start + utf8.RuneCountInString(s[loc[0]:loc[1]])})
+	}
+	return spans
+}
+
+// ErrInvalidSearchQuery is returned for search queries and regular expressions that
+// cannot be parsed
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// maxSearchPatternLength is the maximum length of regular expressions in searches
+const maxSearchPatternLength = 1000
+
+// LawQuery is a parsed query for LawNode.SearchQuery
+type LawQuery struct {
+	root  lawQueryNode
+	terms []*lawQueryTerm
+}
+
+// lawQueryNode is a boolean expression over the terms of a query
+type lawQueryNode interface {
+	// eval reports whether a sentence matches, given whether each term occurs in it
+	eval(matched []bool) bool
+	String() string
+}
+
+// lawQueryTerm is a substring, phrase or regular expression
+type lawQueryTerm struct {
+	index   int
+	text    string
+	re      *regexp.Regexp
+	negated bool
+}
+
+func (t *lawQueryTerm) eval(matched []bool) bool {
+	return matched[t.index]
+}
+
+func (t *lawQueryTerm) String() string {
+	switch {
+	case t.re != nil:
+		return "/" + strings.ReplaceAll(t.text, "/", `\/`) + "/"
+	case t.text == "" || strings.ContainsFunc(t.text, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`()"`, r)
+	}) || strings.HasPrefix(t.text, "-") || strings.HasPrefix(t.text, "/") || lawQueryOperators[t.text] != 0:
+		return `"` + t.text + `"`
+	}
+	return t.text
+}
+
+type lawQueryAnd []lawQueryNode
+
+func (q lawQueryAnd) eval(matched []bool) bool {
+	for _, node := range q {
+		if !node.eval(matched) {
+			return false
+		}
+	}
+	return true
+}
+
+func (q lawQueryAnd) String() string {
+	parts := make([]string, len(q))
+	for i, node := range q {
+		parts[i] = node.String()
+		if _, ok := node.(lawQueryOr); ok {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+type lawQueryOr []lawQueryNode
+
+func (q lawQueryOr) eval(matched []bool) bool {
+	for _, node := range q {
+		if node.eval(matched) {
+			return true
+		}
+	}
+	return false
+}
+
+func (q lawQueryOr) String() string {
+	parts := make([]string, len(q))
+	for i, node := range q {
+		parts[i] = node.String()
+	}
+	return strings.Join(parts, " OR ")
+}
+
+type lawQueryNot struct {
+	node lawQueryNode
+}
+
+func (q lawQueryNot) eval(matched []bool) bool {
+	return !q.node.eval(matched)
+}
+
+func (q lawQueryNot) String() string {
+	if _, ok := q.node.(*lawQueryTerm); ok {
+		return "-" + q.node.String()
+	}
+	return "-(" + q.node.String() + ")"
+}
+
+// String returns the query in the syntax of ParseLawQuery
+func (q *LawQuery) String() string {
+	return q.root.String()
+}
+
+// addTerm adds a term to the query
+func (q *LawQuery) addTerm(text string, isRegexp, negated bool) (*lawQueryTerm, error) {
+	t := &lawQueryTerm{index: len(q.terms), text: text, negated: negated}
+	if isRegexp {
+		if len(text) > maxSearchPatternLength {
+			return nil, fmt.Errorf("%w: regular expression longer than %d characters", ErrInvalidSearchQuery, maxSearchPatternLength)
+		}
+		re, err := regexp.Compile(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSearchQuery, err)
+		}
+		t.re = re
+	}
+	q.terms = append(q.terms, t)
+	return t, nil
+}
+
+// lawQueryOperators are the operator words of queries
+var lawQueryOperators = map[string]byte{"AND": '&', "OR": '|', "NOT": '-'}
+
+// lawQueryToken is a token of a query: a word (w), phrase (p), regular expression (r),
+// parenthesis or operator (&, |, -)
+type lawQueryToken struct {
+	kind byte
+	text string
+}
+
+// ParseLawQuery parses a query for LawNode.SearchQuery. A query is made of terms:
+//
+//   - words, matched as substrings (個人情報)
+//   - phrases in double quotes, which may contain spaces and operator words ("personal data")
+//   - regular expressions in slashes, in RE2 syntax, with \/ for a slash (/第[一二三]条/)
+//
+// combined with the operators, from the highest precedence:
+//
+//   - NOT term or -term, for sentences without the term
+//   - term AND term, or just term term, for sentences with both
+//   - term OR term, for sentences with either
+//
+// and ASCII parentheses for grouping. Full-width brackets are part of terms. Queries are
+// evaluated per sentence, and must have a term that is not negated. Regular expressions
+// match in time linear in the length of the text and are limited to 1000 characters.
+func ParseLawQuery(query string) (*LawQuery, error) {
+	tokens, err := tokenizeLawQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: empty query", ErrInvalidSearchQuery)
+	}
+	p := &lawQueryParser{tokens: tokens, query: &LawQuery{}}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(tokens) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidSearchQuery, tokens[p.pos].text)
+	}
+	if !slices.ContainsFunc(p.query.terms, func(t *lawQueryTerm) bool { return !t.negated }) {
+		return nil, fmt.Errorf("%w: only negated terms", ErrInvalidSearchQuery)
+	}
+	p.query.root = root
+	return p.query, nil
+}
+
+// tokenizeLawQuery splits a query into tokens
+func tokenizeLawQuery(query string) ([]lawQueryToken, error) {
+	var tokens []lawQueryToken
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, lawQueryToken{kind: byte(r), text: string(r)})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')':
+			tokens = append(tokens, lawQueryToken{kind: '-', text: "-"})
+			i++
+		case r == '"':
+			end := slices.Index(runes[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated phrase", ErrInvalidSearchQuery)
+			}
+			tokens = append(tokens, lawQueryToken{kind: 'p', text: string(runes[i+1 : i+1+end])})
+			i += end + 2
+		case r == '/':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '/'; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) && runes[j+1] == '/' {
+					j++
+				}
+				sb.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("%w: unterminated regular expression", ErrInvalidSearchQuery)
+			}
+			tokens = append(tokens, lawQueryToken{kind: 'r', text: sb.String()})
+			i = j + 1
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune(`()"`, runes[j]) {
+				j++
+			}
+			word := string(runes[i:j])
+			kind := lawQueryOperators[word]
+			if kind == 0 {
+				kind = 'w'
+			}
+			tokens = append(tokens, lawQueryToken{kind: kind, text: word})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+// lawQueryParser parses tokens by recursive descent
+type lawQueryParser struct {
+	tokens []lawQueryToken
+	pos    int
+	query  *LawQuery
+	// negated is whether the term being parsed is under an odd number of NOTs
+	negated bool
+}
+
+// peek returns the kind of the next token, or 0 at the end
+func (p *lawQueryParser) peek() byte {
+	if p.pos == len(p.tokens) {
+		return 0
+	}
+	return p.tokens[p.pos].kind
+}
+
+func (p *lawQueryParser) parseOr() (lawQueryNode, error) {
+	var nodes lawQueryOr
+	for {
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+		if p.peek() != '|' {
+			break
+		}
+		p.pos++
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *lawQueryParser) parseAnd() (lawQueryNode, error) {
+	var nodes lawQueryAnd
+	for {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+		if p.peek() == '&' {
+			p.pos++
+		} else if kind := p.peek(); kind == 0 || kind == ')' || kind == '|' {
+			break
+		}
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *lawQueryParser) parseUnary() (lawQueryNode, error) {
+	if p.pos == len(p.tokens) {
+		return nil, fmt.Errorf("%w: missing term at end", ErrInvalidSearchQuery)
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch tok.kind {
+	case '-':
+		p.negated = !p.negated
+		node, err := p.parseUnary()
+		p.negated = !p.negated
+		if err != nil {
+			return nil, err
+		}
+		return lawQueryNot{node}, nil
+	case '(':
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("%w: missing )", ErrInvalidSearchQuery)
+		}
+		p.pos++
+		return node, nil
+	case 'w', 'p', 'r':
+		return p.query.addTerm(tok.text, tok.kind == 'r', p.negated)
+	}
+	return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidSearchQuery, tok.text)
+}
+
+// MirrorSearchOptions configures SearchMirror
+type MirrorSearchOptions struct {
+	// Exact, ContextWidth and Limit are as in LawSearchOptions. Limit applies to the hits
+	// of all documents.
+	Exact        bool
+	ContextWidth int
+	Limit        int
+	// Timeout bounds the duration of the whole search, or 0 for no limit
+	Timeout time.Duration
+	// Concurrency is the number of documents searched at a time (default: the number of CPUs)
+	Concurrency int
+	// Filter is as in ForEachLawOptions
+	Filter func(path string) bool
+}
+
+// MirrorSearchHit is a hit of SearchMirror in a document of a mirror
+type MirrorSearchHit struct {
+	// Path is the path of the document relative to the mirror directory
+	Path string `json:"path"`
+	// LawTitle is the title of the law of the document
+	LawTitle string `json:"law_title"`
+	LawSearchHit
+}
+
+// SearchMirror runs a query (see ParseLawQuery) over the laws of a mirror directory, as
+// ForEachLaw reads them, and returns the hits ordered by document path, then in document
+// order. Provision IDs use the law IDs of the documents. Documents that cannot be read or
+// parsed are failures of the report. When ctx is done or opts.Timeout has passed, the
+// search stops with an error wrapping the context error and returns the hits of the
+// documents searched so far.
+func SearchMirror(ctx context.Context, mirror string, q *LawQuery, opts *MirrorSearchOptions) ([]MirrorSearchHit, *ForEachLawReport, error) {
+	var o MirrorSearchOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	var hits []MirrorSearchHit
+	report, err := ForEachLaw(ctx, mirror, o.Concurrency, func(ctx context.Context, doc *LawDocument) ([]MirrorSearchHit, error) {
+		law, err := doc.File.Law()
+		if err != nil {
+			return nil, err
+		}
+		title, _, err := doc.File.ElementText("LawTitle")
+		if err != nil {
+			return nil, err
+		}
+		lawHits, err := law.SearchQuery(ctx, q, &LawSearchOptions{
+			Exact:        o.Exact,
+			ContextWidth: o.ContextWidth,
+			Limit:        o.Limit,
+			LawID:        doc.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		docHits := make([]MirrorSearchHit, len(lawHits))
+		for i, h := range lawHits {
+			docHits[i] = MirrorSearchHit{Path: doc.Path, LawTitle: title, LawSearchHit: h}
+		}
+		return docHits, nil
+	}, &ForEachLawOptions[[]MirrorSearchHit]{
+		Filter: o.Filter,
+		Aggregate: func(doc *LawDocument, docHits []MirrorSearchHit) {
+			hits = append(hits, docHits...)
+		},
+	})
+
+	// Documents finish in any order; sort them so that the first Limit hits are stable
+	slices.SortStableFunc(hits, func(a, b MirrorSearchHit) int { return strings.Compare(a.Path, b.Path) })
+	if o.Limit > 0 && len(hits) > o.Limit {
+		hits = hits[:o.Limit]
+	}
+	if err != nil {
+		if report != nil && ctx.Err() != nil {
+			return hits, report, fmt.Errorf("search stopped: %w", err)
+		}
+		return hits, report, err
+	}
+	return hits, report, nil
+}
@@ -0,0 +1,215 @@
+package lawapi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const searchTestLaw = `<Law><LawBody><LawTitle>試験法</LawTitle><MainProvision>
+<Article Num="1"><ArticleTitle>第一条</ArticleTitle><Paragraph Num="1"><ParagraphNum/><ParagraphSentence><Sentence>甲は、乙に従う。</Sentence></ParagraphSentence></Paragraph></Article>
+<Article Num="2"><ArticleTitle>第二条</ArticleTitle><Paragraph Num="1"><ParagraphNum/><ParagraphSentence><Sentence>丙は、乙に従う。</Sentence></ParagraphSentence></Paragraph></Article>
+<Article Num="3"><ArticleTitle>第三条</ArticleTitle><Paragraph Num="1"><ParagraphNum/><ParagraphSentence><Sentence>甲及び丙は、届け出なければならない。</Sentence></ParagraphSentence></Paragraph></Article>
+<Article Num="4"><ArticleTitle>第四条</ArticleTitle><Paragraph Num="1"><ParagraphNum/><ParagraphSentence><Sentence>ａ/ｂの規定は、OR条件とする。</Sentence></ParagraphSentence></Paragraph></Article>
+</MainProvision></LawBody></Law>`
+
+func parseSearchTestLaw(t *testing.T) *LawNode {
+	t.Helper()
+	law, err := ParseLawXML(strings.NewReader(searchTestLaw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return law
+}
+
+func TestParseLawQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		// want is the query as rendered by String
+		want string
+		// wantErr is a part of the error message
+		wantErr string
+	}{
+		{query: "a b OR c", want: "a b OR c"},
+		{query: "a OR b c", want: "a OR b c"},
+		{query: "a AND (b OR c)", want: "a (b OR c)"},
+		{query: "(a OR b) AND c OR d", want: "(a OR b) c OR d"},
+		{query: "NOT a b", want: "-a b"},
+		{query: "-(a OR b) c", want: "-(a OR b) c"},
+		{query: "NOT NOT a", want: "-(-a)"},
+		{query: `"a b" "OR" "-c"`, want: `"a b" "OR" "-c"`},
+		{query: `/a\/b/ /第[一二]条/`, want: `/a\/b/ /第[一二]条/`},
+		{query: "a-b", want: "a-b"},
+		{query: "", wantErr: "empty query"},
+		{query: "   ", wantErr: "empty query"},
+		{query: "-a", wantErr: "only negated terms"},
+		{query: "NOT a", wantErr: "only negated terms"},
+		{query: "-(a b)", wantErr: "only negated terms"},
+		{query: "-a OR NOT b", wantErr: "only negated terms"},
+		{query: `"a b`, wantErr: "unterminated phrase"},
+		{query: `/a\/`, wantErr: "unterminated regular expression"},
+		{query: "(a b", wantErr: "missing )"},
+		{query: "a b)", wantErr: `unexpected ")"`},
+		{query: "a OR", wantErr: "missing term at end"},
+		{query: "a OR OR b", wantErr: `unexpected "OR"`},
+		{query: "/(/", wantErr: "error parsing regexp"},
+		{query: "/" + strings.Repeat("a", maxSearchPatternLength+1) + "/", wantErr: "regular expression longer than 1000 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseLawQuery(tt.query)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidSearchQuery) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %s", err, tt.wantErr)
+				}
+				if ErrorCategoryOf(err) != ErrorValidation {
+					t.Errorf("category = %v, want validation", ErrorCategoryOf(err))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := q.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			// The rendered query parses to the same query
+			again, err := ParseLawQuery(q.String())
+			if err != nil || again.String() != tt.want {
+				t.Errorf("reparsed = %v, %v", again, err)
+			}
+		})
+	}
+}
+
+func TestLawNodeSearchQuery(t *testing.T) {
+	law := parseSearchTestLaw(t)
+	tests := []struct {
+		query string
+		// want are the provisions and matches of the hits
+		want []string
+	}{
+		// AND binds tighter than OR: 第三条 matches without 乙
+		{query: "甲 OR 丙 乙", want: []string{"第一条 甲", "第一条 乙", "第二条 丙", "第二条 乙", "第三条 甲", "第三条 丙"}},
+		{query: "(甲 OR 丙) 乙", want: []string{"第一条 甲", "第一条 乙", "第二条 丙", "第二条 乙"}},
+		{query: "甲 AND 丙", want: []string{"第三条 甲", "第三条 丙"}},
+		// Negated terms select sentences and are not hits
+		{query: "乙 -甲", want: []string{"第二条 乙"}},
+		{query: "丙 NOT (甲 乙)", want: []string{"第二条 丙", "第三条 丙"}},
+		// Phrases keep operator words and are normalized like words
+		{query: `"乙に従う"`, want: []string{"第一条 乙に従う", "第二条 乙に従う"}},
+		{query: `"OR条件"`, want: []string{"第四条 OR条件"}},
+		{query: `"a/b"`, want: []string{"第四条 ａ/ｂ"}},
+		// Regular expressions match the normalized text, with \/ for a slash
+		{query: `/a\/b/`, want: []string{"第四条 ａ/ｂ"}},
+		{query: `/[甲丙]は/ 従う`, want: []string{"第一条 甲は", "第一条 従う", "第二条 丙は", "第二条 従う"}},
+		{query: "丁", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseLawQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hits, err := law.SearchQuery(context.Background(), q, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, h := range hits {
+				got = append(got, h.Provision+" "+h.Match)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("hits = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLawNodeSearchQueryStopped(t *testing.T) {
+	law := parseSearchTestLaw(t)
+	q, err := ParseLawQuery("乙")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hits, err := law.SearchQuery(context.Background(), q, &LawSearchOptions{Timeout: time.Nanosecond})
+	if !errors.Is(err, context.DeadlineExceeded) || len(hits) != 0 {
+		t.Errorf("Timeout: hits = %d, err = %v, want DeadlineExceeded", len(hits), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := law.SearchQuery(ctx, q, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled: err = %v, want Canceled", err)
+	}
+
+	// Without a timeout, the search completes and stops at the limit
+	hits, err = law.SearchQuery(context.Background(), q, &LawSearchOptions{Timeout: time.Minute, Limit: 1})
+	if err != nil || len(hits) != 1 {
+		t.Errorf("limited: hits = %d, err = %v, want 1", len(hits), err)
+	}
+}
+
+func TestSearchMirror(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b/415AC0000000057.xml": searchTestLaw,
+		"a/325AC0000000131.xml": strings.ReplaceAll(searchTestLaw, "試験法", "電波法"),
+		"broken.xml":            "<Law>",
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q, err := ParseLawQuery("乙 -丙")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hits, report, err := SearchMirror(context.Background(), dir, q, &MirrorSearchOptions{Concurrency: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, h := range hits {
+		got = append(got, h.Path+" "+h.LawTitle+" "+h.ProvisionID)
+	}
+	want := []string{
+		"a/325AC0000000131.xml 電波法 325AC0000000131::1:1:",
+		"b/415AC0000000057.xml 試験法 415AC0000000057::1:1:",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("hits = %q, want %q", got, want)
+	}
+	if report.Succeeded != 2 || len(report.Failures) != 1 || report.Failures[0].Path != "broken.xml" {
+		t.Errorf("report = %+v, want 2 succeeded and broken.xml failed", report)
+	}
+
+	q, err = ParseLawQuery("乙")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hits, _, err = SearchMirror(context.Background(), dir, q, &MirrorSearchOptions{Limit: 3})
+	if err != nil || len(hits) != 3 || hits[2].Path != "b/415AC0000000057.xml" || hits[2].Provision != "第一条" {
+		t.Errorf("limited: hits = %+v, err = %v, want the first 3 in path order", hits, err)
+	}
+
+	hits, report, err = SearchMirror(context.Background(), dir, q, &MirrorSearchOptions{Timeout: time.Nanosecond})
+	if !errors.Is(err, context.DeadlineExceeded) || len(hits) != 0 {
+		t.Errorf("Timeout: hits = %d, err = %v, want DeadlineExceeded", len(hits), err)
+	}
+	if report == nil || report.Skipped != 3 {
+		t.Errorf("Timeout: report = %+v, want 3 skipped", report)
+	}
+}
@@ -0,0 +1,100 @@
+package lawapi
+
+import "strings"
+
+// textLine is one line of a provision laid out as in printed statute books
+type textLine struct {
+	// text is the line text, starting with the article title or item numbering
+	text string
+	// indent is the nesting depth: 0 for paragraphs, 1 for items, 2 for sub-items and so on
+	indent int
+	// heading is the level of structural headings (1 for 編, 2 for 章, ... and 附則), or 0
+	heading int
+	// article is the citation of the article starting at this line (e.g. 第一条（目的）), or empty
+	article string
+}
+
+// headingLevels are the levels of the title elements of the law structure
+var headingLevels = map[string]int{
+	"PartTitle":           1,
+	"ChapterTitle":        2,
+	"SectionTitle":        3,
+	"SubsectionTitle":     4,
+	"DivisionTitle":       5,
+	"SupplProvisionLabel": 1,
+}
+
+// provisionLines lays out a law, structural element or provision as lines of text: a line
+// per heading, article caption, paragraph, item and sub-item. Tables, figures and other
+// non-sentence content are omitted.
+func provisionLines(n *LawNode) []textLine {
+	var lines []textLine
+	var walk func(node *LawNode, prefix string, indent int)
+	walk = func(node *LawNode, prefix string, indent int) {
+		if level, ok := headingLevels[node.Tag]; ok {
+			lines = append(lines, textLine{text: strings.TrimSpace(node.PlainText()), heading: level})
+			return
+		}
+		switch {
+		case node.Tag == "Article":
+			start := len(lines)
+			caption := strings.TrimSpace(node.Child("ArticleCaption").PlainText())
+			if caption != "" {
+				lines = append(lines, textLine{text: caption, indent: 1})
+			}
+			title := strings.TrimSpace(node.Child("ArticleTitle").PlainText())
+			for i, paragraph := range node.Paragraphs() {
+				if i == 0 {
+					walk(paragraph, title, indent)
+				} else {
+					walk(paragraph, "", indent)
+				}
+			}
+			if start < len(lines) {
+				lines[start].article = title + caption
+			}
+		case node.Tag == "Paragraph" || node.Tag == "Item" || subitemLevel(node.Tag) > 0:
+			if prefix == "" {
+				if node.Tag == "Paragraph" {
+					prefix = strings.TrimSpace(node.Child("ParagraphNum").PlainText())
+				} else {
+					prefix = node.ItemTitle()
+				}
+			}
+			text := sentenceText(node.Child(node.Tag + "Sentence"))
+			if prefix != "" && text != "" {
+				text = prefix + "　" + text
+			} else if text == "" {
+				text = prefix
+			}
+			if text != "" {
+				lines = append(lines, textLine{text: text, indent: indent})
+			}
+			for _, item := range node.Items() {
+				walk(item, "", indent+1)
+			}
+		case node.IsText() || node.Tag == "LawNum" || node.Tag == "LawTitle" || node.Tag == "TOC" || node.Tag == "Rt":
+		default:
+			for _, child := range node.Children {
+				walk(child, "", indent)
+			}
+		}
+	}
+	walk(n, "", 0)
+	return lines
+}
+
+// sentenceText returns the text of a sentence container, separating columns by a full-width space
+func sentenceText(n *LawNode) string {
+	if n == nil {
+		return ""
+	}
+	if columns := n.ChildrenByTag("Column"); len(columns) > 0 {
+		texts := make([]string, len(columns))
+		for i, column := range columns {
+			texts[i] = strings.TrimSpace(column.PlainText())
+		}
+		return strings.Join(texts, "　")
+	}
+	return strings.TrimSpace(n.PlainText())
+}
@@ -0,0 +1,517 @@
+package lawapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LawNode represents an element of the law full text tree.
+// Elements use Tag, Attr and Children; text nodes only have Text set.
+type LawNode struct {
+	Tag      string
+	Attr     map[string]string
+	Children []*LawNode
+	Text     string
+
+	// attrOrder is the order of the attributes in the parsed XML, used by WriteLawXML
+	attrOrder []string
+	// provenance is the provenance of a root element, or nil
+	provenance *Provenance
+}
+
+// IsText reports whether the node is a text node
+func (n *LawNode) IsText() bool {
+	return n.Tag == ""
+}
+
+// UnmarshalJSON implements json.Unmarshaler for LawNode
+func (n *LawNode) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*n = LawNode{Text: text}
+		return nil
+	}
+
+	var elem struct {
+		Tag      string            `json:"tag"`
+		Attr     map[string]string `json:"attr"`
+		Children []*LawNode        `json:"children"`
+	}
+	if err := json.Unmarshal(data, &elem); err != nil {
+		return err
+	}
+	*n = LawNode{Tag: elem.Tag, Attr: elem.Attr, Children: elem.Children}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for LawNode
+func (n LawNode) MarshalJSON() ([]byte, error) {
+	if n.IsText() {
+		return json.Marshal(n.Text)
+	}
+	attr := n.Attr
+	if attr == nil {
+		attr = map[string]string{}
+	}
+	children := n.Children
+	if children == nil {
+		children = []*LawNode{}
+	}
+	return json.Marshal(struct {
+		Tag      string            `json:"tag"`
+		Attr     map[string]string `json:"attr"`
+		Children []*LawNode        `json:"children"`
+	}{n.Tag, attr, children})
+}
+
+// ParseLawXML parses law XML into a LawNode tree. A provenance comment written by
+// WriteLawXML before the root element sets the provenance of the tree.
+func ParseLawXML(r io.Reader) (*LawNode, error) {
+	decoder := xml.NewDecoder(r)
+	var stack []*LawNode
+	var root *LawNode
+	var provenance *Provenance
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse law XML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &LawNode{Tag: t.Name.Local, Attr: map[string]string{}}
+			for _, a := range t.Attr {
+				node.Attr[a.Name.Local] = a.Value
+				node.attrOrder = append(node.attrOrder, a.Name.Local)
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+			} else if root == nil {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(stack) == 0 {
+				continue
+			}
+			text := string(t)
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, &LawNode{Text: text})
+		case xml.Comment:
+			if root == nil {
+				if p, ok := parseProvenanceComment(string(t)); ok {
+					provenance = &p
+				}
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("failed to parse law XML: no root element")
+	}
+	root.provenance = provenance
+	return root, nil
+}
+
+// ParseLawFullText converts the law_full_text field of a law data response into a LawNode tree.
+// Both the JSON tree form and the (optionally Base64 encoded) XML string form are supported.
+// The tree's provenance records the revision ID, the package version and the content hash.
+func ParseLawFullText(resp *LawDataResponse) (*LawNode, error) {
+	law, err := parseLawFullText(resp)
+	if err != nil {
+		return nil, err
+	}
+	p := Provenance{PackageVersion: PackageVersion(), ContentSHA256: LawContentSHA256(law)}
+	if resp.RevisionInfo != nil {
+		p.LawRevisionID = resp.RevisionInfo.LawRevisionId
+	}
+	law.SetProvenance(p)
+	return law, nil
+}
+
+func parseLawFullText(resp *LawDataResponse) (*LawNode, error) {
+	if resp == nil || resp.LawFullText == nil || *resp.LawFullText == nil {
+		return nil, fmt.Errorf("law_full_text is not present in the response")
+	}
+
+	if s, ok := (*resp.LawFullText).(string); ok {
+		data := []byte(s)
+		if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+			decoded, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode law_full_text: %w", err)
+			}
+			data = decoded
+		}
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+			var node LawNode
+			if err := json.Unmarshal(data, &node); err != nil {
+				return nil, fmt.Errorf("failed to decode law_full_text: %w", err)
+			}
+			return &node, nil
+		}
+		return ParseLawXML(bytes.NewReader(data))
+	}
+
+	data, err := json.Marshal(*resp.LawFullText)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode law_full_text: %w", err)
+	}
+	var node LawNode
+	if err := json.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("failed to decode law_full_text: %w", err)
+	}
+	return &node, nil
+}
+
+// Walk calls fn for the node and its descendants in document order.
+// Children of a node are skipped when fn returns false.
+func (n *LawNode) Walk(fn func(node *LawNode) bool) {
+	if n == nil || !fn(n) {
+		return
+	}
+	for _, child := range n.Children {
+		child.Walk(fn)
+	}
+}
+
+// FindAll returns all descendant elements with the given tag in document order
+func (n *LawNode) FindAll(tag string) []*LawNode {
+	if n == nil {
+		return nil
+	}
+	var nodes []*LawNode
+	for _, child := range n.Children {
+		child.Walk(func(node *LawNode) bool {
+			if node.Tag == tag {
+				nodes = append(nodes, node)
+			}
+			return true
+		})
+	}
+	return nodes
+}
+
+// Find returns the first descendant element with the given tag, or nil
+func (n *LawNode) Find(tag string) *LawNode {
+	if n == nil {
+		return nil
+	}
+	var found *LawNode
+	for _, child := range n.Children {
+		child.Walk(func(node *LawNode) bool {
+			if found != nil {
+				return false
+			}
+			if node.Tag == tag {
+				found = node
+				return false
+			}
+			return true
+		})
+		if found != nil {
+			break
+		}
+	}
+	return found
+}
+
+// clone returns a deep copy of the node without its provenance
+func (n *LawNode) clone() *LawNode {
+	c := &LawNode{Tag: n.Tag, Text: n.Text, attrOrder: n.attrOrder}
+	if n.Attr != nil {
+		c.Attr = make(map[string]string, len(n.Attr))
+		for k, v := range n.Attr {
+			c.Attr[k] = v
+		}
+	}
+	for _, child := range n.Children {
+		c.Children = append(c.Children, child.clone())
+	}
+	return c
+}
+
+// Child returns the first direct child element with the given tag, or nil
+func (n *LawNode) Child(tag string) *LawNode {
+	if n == nil {
+		return nil
+	}
+	for _, child := range n.Children {
+		if child.Tag == tag {
+			return child
+		}
+	}
+	return nil
+}
+
+// ChildrenByTag returns the direct child elements with the given tag
+func (n *LawNode) ChildrenByTag(tag string) []*LawNode {
+	if n == nil {
+		return nil
+	}
+	var nodes []*LawNode
+	for _, child := range n.Children {
+		if child.Tag == tag {
+			nodes = append(nodes, child)
+		}
+	}
+	return nodes
+}
+
+// Num returns the Num attribute of the element
+func (n *LawNode) Num() string {
+	if n == nil {
+		return ""
+	}
+	return n.Attr["Num"]
+}
+
+// PlainText returns the concatenated text content of the node.
+// Ruby readings (Rt elements) are omitted.
+func (n *LawNode) PlainText() string {
+	if n == nil {
+		return ""
+	}
+	var sb strings.Builder
+	n.Walk(func(node *LawNode) bool {
+		if node.Tag == "Rt" {
+			return false
+		}
+		if node.IsText() {
+			sb.WriteString(node.Text)
+		}
+		return true
+	})
+	return sb.String()
+}
+
+// Articles returns the articles of the main provision in document order
+func (n *LawNode) Articles() []*LawNode {
+	main := n.Find("MainProvision")
+	if main == nil {
+		return nil
+	}
+	return main.FindAll("Article")
+}
+
+// Article returns the main provision article with the given Num attribute, or nil
+func (n *LawNode) Article(num string) *LawNode {
+	for _, article := range n.Articles() {
+		if article.Num() == num {
+			return article
+		}
+	}
+	return nil
+}
+
+// Title returns the law title of the tree, or an empty string
+func (n *LawNode) Title() string {
+	return n.Find("LawTitle").PlainText()
+}
+
+// Paragraphs returns the paragraphs (項) of an article
+func (n *LawNode) Paragraphs() []*LawNode {
+	return n.ChildrenByTag("Paragraph")
+}
+
+// Paragraph returns the paragraph of an article with the given Num attribute, or nil
+func (n *LawNode) Paragraph(num string) *LawNode {
+	return childByNum(n.Paragraphs(), num)
+}
+
+// Items returns the items of a paragraph (号) or the sub-items of an item or sub-item (イロハ, (1)(2)(3), ...)
+func (n *LawNode) Items() []*LawNode {
+	if n == nil {
+		return nil
+	}
+	switch level := subitemLevel(n.Tag); {
+	case n.Tag == "Paragraph":
+		return n.ChildrenByTag("Item")
+	case n.Tag == "Item":
+		return n.ChildrenByTag("Subitem1")
+	case level > 0:
+		return n.ChildrenByTag("Subitem" + strconv.Itoa(level+1))
+	}
+	return nil
+}
+
+// Item returns the item or sub-item of the node with the given Num attribute, or nil
+func (n *LawNode) Item(num string) *LawNode {
+	return childByNum(n.Items(), num)
+}
+
+// ItemTitle returns the numbering of a paragraph, item or sub-item as written in the
+// law (e.g. "２", "三", "イ", "（１）"), or an empty string
+func (n *LawNode) ItemTitle() string {
+	if n == nil {
+		return ""
+	}
+	return strings.TrimSpace(n.Child(n.Tag + "Title").PlainText())
+}
+
+// MainSentence returns the main sentence (本文) of a paragraph, item or sub-item.
+// When the provision has no proviso, this is its only sentence.
+func (n *LawNode) MainSentence() *LawNode {
+	for _, sentence := range n.ownSentences() {
+		if sentence.Attr["Function"] != "proviso" {
+			return sentence
+		}
+	}
+	return nil
+}
+
+// Proviso returns the proviso (ただし書) of a paragraph, item or sub-item, or nil
+func (n *LawNode) Proviso() *LawNode {
+	for _, sentence := range n.ownSentences() {
+		if sentence.Attr["Function"] == "proviso" {
+			return sentence
+		}
+	}
+	return nil
+}
+
+// ownSentences returns the sentences of a provision excluding those of nested items
+func (n *LawNode) ownSentences() []*LawNode {
+	if n == nil {
+		return nil
+	}
+	return n.Child(n.Tag + "Sentence").FindAll("Sentence")
+}
+
+// subitemLevel returns N for a SubitemN tag, or 0
+func subitemLevel(tag string) int {
+	if !strings.HasPrefix(tag, "Subitem") {
+		return 0
+	}
+	level, err := strconv.Atoi(strings.TrimPrefix(tag, "Subitem"))
+	if err != nil {
+		return 0
+	}
+	return level
+}
+
+func childByNum(nodes []*LawNode, num string) *LawNode {
+	for _, node := range nodes {
+		if node.Num() == num {
+			return node
+		}
+	}
+	return nil
+}
+
+// SentenceRef is a sentence together with the provisions that contain it
+type SentenceRef struct {
+	// Article is the containing article, or nil for sentences outside articles
+	Article *LawNode
+	// Paragraph is the containing paragraph, or nil
+	Paragraph *LawNode
+	// Item is the containing item (号), or nil
+	Item *LawNode
+	// Subitems are the containing sub-items from the outermost (イロハ) inwards
+	Subitems []*LawNode
+	// Sentence is the Sentence element
+	Sentence *LawNode
+	// SupplProvision reports whether the sentence belongs to a supplementary provision (附則)
+	SupplProvision bool
+	// Suppl is the containing SupplProvision element, or nil
+	Suppl *LawNode
+	// SupplIndex is the position of Suppl among the supplementary provisions of the law,
+	// from 1: the original 附則 comes first and those of amending laws follow. Sentences
+	// counts them within the node it is called on. Zero is taken as 1.
+	SupplIndex int
+	// Proviso reports whether the sentence is a proviso (ただし書)
+	Proviso bool
+}
+
+// Sentences returns all sentences of the law body in document order
+func (n *LawNode) Sentences() []SentenceRef {
+	if n == nil {
+		return nil
+	}
+	var refs []SentenceRef
+	suppls := 0
+	var walk func(node *LawNode, ctx SentenceRef)
+	walk = func(node *LawNode, ctx SentenceRef) {
+		switch node.Tag {
+		case "SupplProvision":
+			suppls++
+			ctx.SupplProvision, ctx.Suppl, ctx.SupplIndex = true, node, suppls
+		case "Article":
+			ctx.Article, ctx.Paragraph, ctx.Item, ctx.Subitems = node, nil, nil, nil
+		case "Paragraph":
+			ctx.Paragraph, ctx.Item, ctx.Subitems = node, nil, nil
+		case "Item":
+			ctx.Item, ctx.Subitems = node, nil
+		case "Sentence":
+			ctx.Sentence = node
+			ctx.Proviso = node.Attr["Function"] == "proviso"
+			refs = append(refs, ctx)
+			return
+		case "Rt":
+			return
+		default:
+			if subitemLevel(node.Tag) > 0 {
+				ctx.Subitems = append(ctx.Subitems[:len(ctx.Subitems):len(ctx.Subitems)], node)
+			}
+		}
+		for _, child := range node.Children {
+			walk(child, ctx)
+		}
+	}
+	walk(n, SentenceRef{})
+	return refs
+}
+
+// Label returns a human-readable citation of the provision containing the sentence
+// (e.g. "第二条第二項第一号", "第五条第三号イ", "第十条第二項ただし書"). Supplementary
+// provisions of amending laws are cited with the law number of the amendment (e.g.
+// "附則（平成二九年六月二日法律第四五号）第二条").
+func (r SentenceRef) Label() string {
+	var sb strings.Builder
+	if r.SupplProvision {
+		sb.WriteString("附則")
+		if r.Suppl != nil && r.Suppl.Attr["AmendLawNum"] != "" {
+			sb.WriteString("（" + r.Suppl.Attr["AmendLawNum"] + "）")
+		}
+	}
+	if r.Article != nil {
+		title := strings.TrimSpace(r.Article.Child("ArticleTitle").PlainText())
+		if title == "" {
+			title = "第" + formatKanjiNum(r.Article.Num()) + "条"
+		}
+		sb.WriteString(title)
+		if r.Paragraph != nil && len(r.Article.ChildrenByTag("Paragraph")) > 1 {
+			sb.WriteString("第" + formatKanjiNum(r.Paragraph.Num()) + "項")
+		}
+	} else if r.Paragraph != nil && r.Paragraph.Num() != "" {
+		sb.WriteString("第" + formatKanjiNum(r.Paragraph.Num()) + "項")
+	}
+	if r.Item != nil {
+		sb.WriteString("第" + formatKanjiNum(r.Item.Num()) + "号")
+	}
+	for _, subitem := range r.Subitems {
+		sb.WriteString(subitem.ItemTitle())
+	}
+	if r.Proviso {
+		sb.WriteString("ただし書")
+	}
+	return sb.String()
+}
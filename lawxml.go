@@ -0,0 +1,83 @@
+package lawapi
+
+import (
+	"bufio"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// xmlTextEscaper escapes character data. Line breaks are kept, as in e-Gov law XML.
+var xmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// xmlAttrEscaper escapes attribute values, including whitespace that attribute value
+// normalization would otherwise turn into spaces
+var xmlAttrEscaper = strings.NewReplacer(
+	"&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;",
+	"\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;",
+)
+
+// WriteLawXML writes a law tree as law XML with an XML declaration, so that trees parsed
+// with ParseLawXML or ParseLawFullText, possibly modified, can be written back.
+// Attributes keep the order they were parsed in; attributes added afterwards and those
+// of trees built in code or parsed from JSON follow in name order. Elements are written
+// without indentation, since whitespace inside mixed content such as Sentence is
+// significant, and elements without children are written as empty-element tags
+// (e.g. <ParagraphNum/>). The provenance of the tree, if any, is written as a comment
+// before the root element. Parsing the output with ParseLawXML yields an equal tree.
+func WriteLawXML(w io.Writer, law *LawNode) error {
+	return writeLawXML(w, law, true)
+}
+
+func writeLawXML(w io.Writer, law *LawNode, withProvenance bool) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	if comment := provenanceComment(law.Provenance()); withProvenance && comment != "" {
+		bw.WriteString("<!--" + comment + "-->\n")
+	}
+	writeXMLNode(bw, law)
+	bw.WriteString("\n")
+	return bw.Flush()
+}
+
+func writeXMLNode(w *bufio.Writer, n *LawNode) {
+	if n.IsText() {
+		xmlTextEscaper.WriteString(w, n.Text)
+		return
+	}
+	w.WriteString("<" + n.Tag)
+	for _, name := range n.orderedAttrs() {
+		w.WriteString(" " + name + `="`)
+		xmlAttrEscaper.WriteString(w, n.Attr[name])
+		w.WriteString(`"`)
+	}
+	if len(n.Children) == 0 {
+		w.WriteString("/>")
+		return
+	}
+	w.WriteString(">")
+	for _, c := range n.Children {
+		writeXMLNode(w, c)
+	}
+	w.WriteString("</" + n.Tag + ">")
+}
+
+// orderedAttrs returns the attribute names of the element in parsed order, followed by
+// the other attributes in name order
+func (n *LawNode) orderedAttrs() []string {
+	names := make([]string, 0, len(n.Attr))
+	for _, name := range n.attrOrder {
+		if _, ok := n.Attr[name]; ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	parsed := len(names)
+	for name := range n.Attr {
+		if !slices.Contains(names[:parsed], name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[parsed:])
+	return names
+}
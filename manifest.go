@@ -0,0 +1,300 @@
+package lawapi
+
+import (
+	"cmp"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ManifestFileName is the name of the manifest in the root of a snapshot directory
+const ManifestFileName = "MANIFEST.json"
+
+var (
+	// ErrManifestUnsigned is returned when verifying the signature of an unsigned manifest
+	ErrManifestUnsigned = errors.New("manifest is not signed")
+	// ErrManifestSignature is returned when the signature of a manifest does not match the
+	// key or the content
+	ErrManifestSignature = errors.New("manifest signature is invalid")
+)
+
+// Manifest lists the files of a snapshot directory, such as a jplaw-serve mirror or a
+// directory of exported documents, with their hashes. A manifest signed with an ed25519
+// key guarantees the integrity and origin of the snapshot to whoever trusts the public key.
+type Manifest struct {
+	CreatedAt time.Time `json:"created_at"`
+	// PackageVersion is the version of this package that built the manifest
+	PackageVersion string `json:"package_version,omitempty"`
+	// Files are the files of the snapshot, sorted by path
+	Files []ManifestFile `json:"files"`
+	// KeyID identifies the signing key: the hex SHA-256 of the public key, shortened to 16
+	// digits. It is informational; signatures are verified with a trusted public key.
+	KeyID string `json:"key_id,omitempty"`
+	// Signature is the ed25519 signature of the manifest without KeyID and Signature
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// ManifestFile is a file listed in a manifest
+type ManifestFile struct {
+	// Path is the slash-separated path relative to the snapshot directory
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestMismatch is a difference between a manifest and its snapshot directory
+type ManifestMismatch struct {
+	Path string `json:"path"`
+	// Problem is "missing", "modified" or "unexpected"
+	Problem string `json:"problem"`
+}
+
+func (m ManifestMismatch) String() string {
+	return m.Path + ": " + m.Problem
+}
+
+// BuildManifest hashes the regular files below dir. The manifest itself and hidden
+// files such as the temporary files of interrupted writes are left out.
+func BuildManifest(dir string) (*Manifest, error) {
+	return BuildManifestWithClock(dir, nil)
+}
+
+// BuildManifestWithClock is like BuildManifest but takes the creation time from clock
+// (default: DefaultClock)
+func BuildManifestWithClock(dir string, clock Clock) (*Manifest, error) {
+	files, err := manifestFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{CreatedAt: clockOrDefault(clock).Now().UTC(), PackageVersion: PackageVersion(), Files: []ManifestFile{}}
+	for _, path := range files {
+		file, err := hashManifestFile(dir, path)
+		if err != nil {
+			return nil, err
+		}
+		m.Files = append(m.Files, file)
+	}
+	return m, nil
+}
+
+// manifestFiles returns the slash-separated paths of the files a manifest of dir lists
+func manifestFiles(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel = filepath.ToSlash(rel); rel != ManifestFileName {
+			paths = append(paths, rel)
+		}
+		return nil
+	})
+	slices.Sort(paths)
+	return paths, err
+}
+
+func hashManifestFile(dir, path string) (ManifestFile, error) {
+	f, err := os.Open(filepath.Join(dir, filepath.FromSlash(path)))
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	return ManifestFile{Path: path, Size: size, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// signedBytes returns the JSON encoding the signature covers
+func (m *Manifest) signedBytes() ([]byte, error) {
+	unsigned := *m
+	unsigned.KeyID, unsigned.Signature = "", nil
+	unsigned.Files = slices.Clone(m.Files)
+	slices.SortFunc(unsigned.Files, func(a, b ManifestFile) int { return cmp.Compare(a.Path, b.Path) })
+	return json.Marshal(unsigned)
+}
+
+// Sign signs the manifest with an ed25519 private key
+func (m *Manifest) Sign(key ed25519.PrivateKey) error {
+	data, err := m.signedBytes()
+	if err != nil {
+		return err
+	}
+	m.KeyID = ManifestKeyID(key.Public().(ed25519.PublicKey))
+	m.Signature = ed25519.Sign(key, data)
+	return nil
+}
+
+// VerifySignature checks the signature of the manifest with a trusted ed25519 public key.
+// It returns ErrManifestUnsigned or ErrManifestSignature if the manifest is unsigned or
+// the signature does not match.
+func (m *Manifest) VerifySignature(key ed25519.PublicKey) error {
+	if len(m.Signature) == 0 {
+		return ErrManifestUnsigned
+	}
+	data, err := m.signedBytes()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(key, data, m.Signature) {
+		return ErrManifestSignature
+	}
+	return nil
+}
+
+// Check compares the files below dir with the manifest and returns the files missing,
+// modified or not listed, sorted by path
+func (m *Manifest) Check(dir string) ([]ManifestMismatch, error) {
+	paths, err := manifestFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	present := map[string]bool{}
+	for _, path := range paths {
+		present[path] = true
+	}
+	var mismatches []ManifestMismatch
+	for _, want := range m.Files {
+		if !present[want.Path] {
+			mismatches = append(mismatches, ManifestMismatch{Path: want.Path, Problem: "missing"})
+			continue
+		}
+		delete(present, want.Path)
+		got, err := hashManifestFile(dir, want.Path)
+		if err != nil {
+			return nil, err
+		}
+		if got != want {
+			mismatches = append(mismatches, ManifestMismatch{Path: want.Path, Problem: "modified"})
+		}
+	}
+	for path := range present {
+		mismatches = append(mismatches, ManifestMismatch{Path: path, Problem: "unexpected"})
+	}
+	slices.SortFunc(mismatches, func(a, b ManifestMismatch) int { return cmp.Compare(a.Path, b.Path) })
+	return mismatches, nil
+}
+
+// VerifySnapshot reads the manifest of a snapshot directory, checks its signature with
+// key and compares the files with it. An invalid signature is returned as an error
+// before any file is compared.
+func VerifySnapshot(dir string, key ed25519.PublicKey) ([]ManifestMismatch, error) {
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.VerifySignature(key); err != nil {
+		return nil, err
+	}
+	return m.Check(dir)
+}
+
+// ReadManifest reads the manifest of a snapshot directory
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest in %s: %w", dir, err)
+	}
+	return &m, nil
+}
+
+// WriteManifest writes the manifest to the root of a snapshot directory
+func WriteManifest(dir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(filepath.Join(dir, ManifestFileName), append(data, '\n'), 0o644)
+}
+
+// ManifestKeyID returns the key ID of a public key, as recorded in the manifests signed
+// with its private key
+func ManifestKeyID(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// GenerateManifestKey generates an ed25519 key pair for signing manifests, encoded as
+// PEM: the private key in PKCS #8, the public key in PKIX form
+func GenerateManifestKey() (privatePEM, publicPEM []byte, err error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, nil, err
+	}
+	privatePEM = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if der, err = x509.MarshalPKIXPublicKey(public); err != nil {
+		return nil, nil, err
+	}
+	return privatePEM, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParseManifestPrivateKey parses a PEM-encoded PKCS #8 ed25519 private key, as written by
+// GenerateManifestKey or openssl genpkey -algorithm ed25519
+func ParseManifestPrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("no PEM private key found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is %T, not ed25519", key)
+	}
+	return private, nil
+}
+
+// ParseManifestPublicKey parses a PEM-encoded PKIX ed25519 public key
+func ParseManifestPublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("no PEM public key found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is %T, not ed25519", key)
+	}
+	return public, nil
+}
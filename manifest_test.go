@@ -0,0 +1,38 @@
+package lawapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBuildManifestWithClock(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "law.xml"), []byte("<Law/>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		clock Clock
+		want  time.Time
+	}{
+		{"given clock", NewManualClock(time.Date(2024, 4, 1, 9, 0, 0, 0, JST)), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"default clock", nil, time.Date(2019, 4, 30, 15, 0, 0, 0, time.UTC)},
+	}
+	setDefaultClock(t, NewManualClock(time.Date(2019, 5, 1, 0, 0, 0, 0, JST)))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := BuildManifestWithClock(dir, tt.clock)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !m.CreatedAt.Equal(tt.want) || m.CreatedAt.Location() != time.UTC {
+				t.Errorf("CreatedAt = %s, want %s", m.CreatedAt, tt.want)
+			}
+			if len(m.Files) != 1 || m.Files[0].Path != "law.xml" {
+				t.Errorf("files = %+v", m.Files)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package lawapi
+
+import (
+	"strings"
+	"unicode"
+)
+
+// LawMetrics contains text metrics for a law document
+type LawMetrics struct {
+	// Characters is the number of non-whitespace characters in the law body
+	Characters int `json:"characters"`
+	// Sentences is the number of Sentence elements
+	Sentences int `json:"sentences"`
+	// Articles is the number of articles in the main provision
+	Articles int `json:"articles"`
+	// Paragraphs is the number of paragraphs in the main provision
+	Paragraphs int `json:"paragraphs"`
+	// AverageSentenceLength is the average number of characters per sentence
+	AverageSentenceLength float64 `json:"average_sentence_length"`
+	// ArticleMetrics contains the per-article breakdown of the main provision
+	ArticleMetrics []ArticleMetrics `json:"article_metrics,omitempty"`
+}
+
+// ArticleMetrics contains text metrics for a single article
+type ArticleMetrics struct {
+	// Num is the Num attribute of the article (e.g. "2_3" for 第二条の三)
+	Num string `json:"num"`
+	// Title is the article title (e.g. "第一条")
+	Title string `json:"title,omitempty"`
+	// Caption is the article caption (e.g. "（目的）")
+	Caption string `json:"caption,omitempty"`
+	// Characters is the number of non-whitespace characters in the article sentences
+	Characters int `json:"characters"`
+	// Sentences is the number of Sentence elements in the article
+	Sentences int `json:"sentences"`
+	// Paragraphs is the number of paragraphs in the article
+	Paragraphs int `json:"paragraphs"`
+	// Items is the number of items (号) in the article
+	Items int `json:"items"`
+	// AverageSentenceLength is the average number of characters per sentence
+	AverageSentenceLength float64 `json:"average_sentence_length"`
+}
+
+// ComputeMetrics computes text metrics for a parsed law document
+func ComputeMetrics(law *LawNode) *LawMetrics {
+	metrics := &LawMetrics{}
+	if law == nil {
+		return metrics
+	}
+
+	body := law.Find("LawBody")
+	if body == nil {
+		body = law
+	}
+	metrics.Characters = countCharacters(body.PlainText())
+
+	sentenceChars := 0
+	for _, sentence := range body.FindAll("Sentence") {
+		metrics.Sentences++
+		sentenceChars += countCharacters(sentence.PlainText())
+	}
+	metrics.AverageSentenceLength = average(sentenceChars, metrics.Sentences)
+
+	for _, article := range law.Articles() {
+		metrics.Articles++
+		am := computeArticleMetrics(article)
+		metrics.Paragraphs += am.Paragraphs
+		metrics.ArticleMetrics = append(metrics.ArticleMetrics, am)
+	}
+
+	return metrics
+}
+
+func computeArticleMetrics(article *LawNode) ArticleMetrics {
+	am := ArticleMetrics{
+		Num:        article.Num(),
+		Title:      strings.TrimSpace(article.Child("ArticleTitle").PlainText()),
+		Caption:    strings.TrimSpace(article.Child("ArticleCaption").PlainText()),
+		Paragraphs: len(article.ChildrenByTag("Paragraph")),
+		Items:      len(article.FindAll("Item")),
+	}
+	for _, sentence := range article.FindAll("Sentence") {
+		am.Sentences++
+		am.Characters += countCharacters(sentence.PlainText())
+	}
+	am.AverageSentenceLength = average(am.Characters, am.Sentences)
+	return am
+}
+
+func countCharacters(s string) int {
+	count := 0
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			count++
+		}
+	}
+	return count
+}
+
+func average(total, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return float64(total) / float64(count)
+}
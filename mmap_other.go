@@ -0,0 +1,13 @@
+//go:build !unix
+
+package lawapi
+
+import (
+	"errors"
+	"os"
+)
+
+// mmapFile is not supported on this platform; OpenLawFile reads the file instead
+func mmapFile(f *os.File, size int) ([]byte, func() error, error) {
+	return nil, nil, errors.ErrUnsupported
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package lawapi
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile maps a file read-only into memory and returns its content and the function
+// unmapping it
+func mmapFile(f *os.File, size int) ([]byte, func() error, error) {
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
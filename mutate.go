@@ -0,0 +1,283 @@
+package lawapi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrProvisionNotFound is returned by tree mutations when the provision to change does not exist
+var ErrProvisionNotFound = errors.New("provision not found")
+
+// ErrTextNotFound is returned by amendments when a quoted text to change does not occur in
+// the provision
+var ErrTextNotFound = errors.New("text not found")
+
+// NewArticle builds an article of the main provision with one paragraph per sentence
+// text, numbered from 1. The caption (e.g. "（目的）") may be empty. The article title is
+// set from num (e.g. "第五条の二" for "5_2").
+func NewArticle(num, caption string, paragraphs ...string) *LawNode {
+	article := &LawNode{Tag: "Article", Attr: map[string]string{"Num": num}}
+	if caption != "" {
+		article.Children = append(article.Children, textElement("ArticleCaption", caption))
+	}
+	article.Children = append(article.Children, textElement("ArticleTitle", articleTitle(num)))
+	for i, text := range paragraphs {
+		title := ""
+		if i > 0 {
+			// Paragraph numbers are written in full-width digits (e.g. "２")
+			title = strings.Map(func(r rune) rune {
+				return r - '0' + '０'
+			}, strconv.Itoa(i+1))
+		}
+		article.Children = append(article.Children, newProvision("Paragraph", strconv.Itoa(i+1), title, text))
+	}
+	return article
+}
+
+// newProvision builds a paragraph, item or sub-item with a title (e.g. "２", "三", "イ")
+// and one sentence
+func newProvision(tag, num, title, text string) *LawNode {
+	titleTag := tag + "Title"
+	if tag == "Paragraph" {
+		titleTag = "ParagraphNum"
+	}
+	titleNode := &LawNode{Tag: titleTag, Attr: map[string]string{}}
+	if title != "" {
+		titleNode = textElement(titleTag, title)
+	}
+	sentence := textElement("Sentence", text)
+	sentence.Attr["Num"] = "1"
+	return &LawNode{
+		Tag:  tag,
+		Attr: map[string]string{"Num": num},
+		Children: []*LawNode{
+			titleNode,
+			{Tag: tag + "Sentence", Attr: map[string]string{}, Children: []*LawNode{sentence}},
+		},
+	}
+}
+
+// InsertArticleAfter inserts an article into the main provision after the article
+// numbered after, in the same chapter or section. An article without a Num attribute
+// gets the following branch number (枝番), e.g. 5_2 (第五条の二) after article 5 or 5_3
+// after 5_2, and its title is set accordingly. The number must not be taken. The article
+// must conform to the law XML schema (see Validate). The table of contents is refreshed
+// with RefreshTOC.
+func (n *LawNode) InsertArticleAfter(after string, article *LawNode) error {
+	prev := n.Article(after)
+	if prev == nil {
+		return fmt.Errorf("article %s: %w", after, ErrProvisionNotFound)
+	}
+	if article.Tag != "Article" {
+		return fmt.Errorf("cannot insert %s as an article", article.Tag)
+	}
+	if article.Attr == nil {
+		article.Attr = map[string]string{}
+	}
+	num := article.Num()
+	if num == "" {
+		num = nextBranchNum(after)
+		article.Attr["Num"] = num
+		if title := article.Child("ArticleTitle"); title != nil {
+			setText(title, articleTitle(num))
+		}
+	}
+	if n.Article(num) != nil {
+		return fmt.Errorf("article %s already exists", num)
+	}
+	var violations []SchemaViolation
+	validateNode(article, "/Article", &violations)
+	if len(violations) > 0 {
+		return fmt.Errorf("invalid article: %s", violations[0])
+	}
+
+	parent := n.parentOf(prev)
+	i := slices.Index(parent.Children, prev)
+	parent.Children = slices.Insert(parent.Children, i+1, article)
+	n.RefreshTOC()
+	return nil
+}
+
+// RemoveArticle removes the main provision article numbered num. Following articles
+// keep their numbers; use RenumberArticles to close the gap (繰上げ).
+func (n *LawNode) RemoveArticle(num string) error {
+	article := n.Article(num)
+	if article == nil {
+		return fmt.Errorf("article %s: %w", num, ErrProvisionNotFound)
+	}
+	parent := n.parentOf(article)
+	parent.Children = slices.DeleteFunc(parent.Children, func(c *LawNode) bool { return c == article })
+	n.RefreshTOC()
+	return nil
+}
+
+// RenumberArticles shifts the numbers of the main provision articles from the article
+// numbered from to the end by delta (繰下げ for positive delta, 繰上げ for negative),
+// keeping branch numbers (e.g. 7_2 becomes 8_2), and updates their titles and the table
+// of contents. References to the articles in the text are not changed.
+func (n *LawNode) RenumberArticles(from string, delta int) error {
+	articles := n.Articles()
+	i := slices.IndexFunc(articles, func(a *LawNode) bool { return a.Num() == from })
+	if i < 0 {
+		return fmt.Errorf("article %s: %w", from, ErrProvisionNotFound)
+	}
+	taken := map[string]bool{}
+	for _, article := range articles[:i] {
+		taken[article.Num()] = true
+	}
+	nums := make([]string, 0, len(articles)-i)
+	for _, article := range articles[i:] {
+		head, branch, _ := strings.Cut(article.Num(), "_")
+		v, err := strconv.Atoi(head)
+		if err != nil || v+delta < 1 {
+			return fmt.Errorf("article %s cannot be renumbered by %d", article.Num(), delta)
+		}
+		num := strconv.Itoa(v + delta)
+		if branch != "" {
+			num += "_" + branch
+		}
+		if taken[num] {
+			return fmt.Errorf("renumbering article %s would duplicate article %s", article.Num(), num)
+		}
+		nums = append(nums, num)
+	}
+	for j, article := range articles[i:] {
+		article.Attr["Num"] = nums[j]
+		if title := article.Child("ArticleTitle"); title != nil {
+			setText(title, articleTitle(nums[j]))
+		}
+	}
+	n.RefreshTOC()
+	return nil
+}
+
+// ReplaceSentenceText replaces every occurrence of old with new in the text of a
+// Sentence element (「old」を「new」に改める). Occurrences must lie within one text node,
+// i.e. not span ruby or other inline elements. It returns the number of replacements.
+func (n *LawNode) ReplaceSentenceText(old, new string) (int, error) {
+	if n.Tag != "Sentence" {
+		return 0, fmt.Errorf("cannot replace text of %s", n.Tag)
+	}
+	if old == "" {
+		return 0, errors.New("empty text to replace")
+	}
+	count := replaceText(n, old, new)
+	if count == 0 {
+		return 0, fmt.Errorf("%q: %w", old, ErrProvisionNotFound)
+	}
+	return count, nil
+}
+
+// replaceText replaces old with new in the text nodes of an element, skipping ruby
+// readings, and returns the number of replacements
+func replaceText(n *LawNode, old, new string) int {
+	count := 0
+	n.Walk(func(node *LawNode) bool {
+		if node.Tag == "Rt" {
+			return false
+		}
+		if node.IsText() {
+			count += strings.Count(node.Text, old)
+			node.Text = strings.ReplaceAll(node.Text, old, new)
+		}
+		return true
+	})
+	return count
+}
+
+// RefreshTOC updates the article ranges (e.g. （第一条―第三条）) of the table of contents
+// from the articles of the parts, chapters and sections they list. Entries are matched
+// to the law body by their Num attributes; entries without a counterpart are left as is.
+func (n *LawNode) RefreshTOC() {
+	toc, main := n.Find("TOC"), n.Find("MainProvision")
+	if toc == nil || main == nil {
+		return
+	}
+	refreshTOCEntries(toc, main)
+}
+
+func refreshTOCEntries(toc, body *LawNode) {
+	for _, entry := range toc.Children {
+		tag, ok := strings.CutPrefix(entry.Tag, "TOC")
+		if !ok || tag == "Article" || tag == "SupplProvision" {
+			continue
+		}
+		var part *LawNode
+		for _, c := range body.ChildrenByTag(tag) {
+			if c.Num() == entry.Num() {
+				part = c
+				break
+			}
+		}
+		if part == nil {
+			continue
+		}
+		if r := entry.Child("ArticleRange"); r != nil {
+			if articles := part.FindAll("Article"); len(articles) > 0 {
+				first := articleTitleOf(articles[0])
+				last := articleTitleOf(articles[len(articles)-1])
+				if first == last {
+					setText(r, "（"+first+"）")
+				} else {
+					setText(r, "（"+first+"―"+last+"）")
+				}
+			}
+		}
+		refreshTOCEntries(entry, part)
+	}
+}
+
+// articleTitle formats the title of an article number (e.g. "第五条の二" for "5_2")
+func articleTitle(num string) string {
+	head, branch, _ := strings.Cut(num, "_")
+	title := "第" + formatKanjiNum(head) + "条"
+	if branch != "" {
+		title += "の" + formatKanjiNum(branch)
+	}
+	return title
+}
+
+func articleTitleOf(article *LawNode) string {
+	if title := strings.TrimSpace(article.Child("ArticleTitle").PlainText()); title != "" {
+		return title
+	}
+	return articleTitle(article.Num())
+}
+
+// nextBranchNum returns the branch number following an article number (e.g. 5_2 after 5,
+// 5_3 after 5_2)
+func nextBranchNum(after string) string {
+	head, branch, _ := strings.Cut(after, "_")
+	next := 2
+	if b, err := strconv.Atoi(branch); err == nil {
+		next = b + 1
+	}
+	return head + "_" + strconv.Itoa(next)
+}
+
+// parentOf returns the parent element of a descendant, or nil
+func (n *LawNode) parentOf(child *LawNode) *LawNode {
+	var parent *LawNode
+	n.Walk(func(node *LawNode) bool {
+		if parent != nil {
+			return false
+		}
+		if slices.Contains(node.Children, child) {
+			parent = node
+			return false
+		}
+		return true
+	})
+	return parent
+}
+
+func textElement(tag, text string) *LawNode {
+	return &LawNode{Tag: tag, Attr: map[string]string{}, Children: []*LawNode{{Text: text}}}
+}
+
+func setText(node *LawNode, text string) {
+	node.Children = []*LawNode{{Text: text}}
+}
@@ -0,0 +1,360 @@
+package lawapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Files of a namespace directory
+const (
+	// AnnotationsFileName is the file of the AnnotationStore of a namespace
+	AnnotationsFileName = "annotations.jsonl"
+	// PinsFileName is the file of the Pins of a namespace
+	PinsFileName = "pins.yaml"
+	// WatchListsFileName is the file of the WatchLists of a namespace
+	WatchListsFileName = "lists.yaml"
+)
+
+// ErrInvalidNamespace is returned for namespace names that are not allowed
+var ErrInvalidNamespace = errors.New("invalid namespace")
+
+// namespacePattern matches the allowed namespace names. They are lower case, so that two
+// names never share a directory on case-insensitive file systems, and do not end with a
+// dot, which Windows drops.
+var namespacePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9_.-]{0,62}[a-z0-9_-])?$`)
+
+// ValidateNamespace checks that a namespace name is 1 to 64 lower case ASCII letters,
+// digits, dots, hyphens and underscores, starting with a letter or digit and not ending
+// with a dot, and that it is not a Windows device name such as "con" or "nul.a"
+func ValidateNamespace(name string) error {
+	if !namespacePattern.MatchString(name) || isReservedFilename(name) {
+		return WithErrorCategory(fmt.Errorf("%w %q", ErrInvalidNamespace, name), ErrorValidation)
+	}
+	return nil
+}
+
+// Namespaces partitions annotations, pins and watch lists by tenant or user. Each
+// namespace is a directory below the root holding its AnnotationsFileName, PinsFileName
+// and WatchListsFileName, created on the first change. Namespace names are validated, so
+// that the stores of a namespace never read or write the files of another. A Namespaces
+// is safe for concurrent use; the stores of a namespace must not be changed by concurrent
+// processes.
+type Namespaces struct {
+	root string
+	// Clock stamps annotations and exports (default: DefaultClock)
+	Clock Clock
+
+	mu sync.Mutex
+	// locks serialize the changes of pins and watch lists per namespace
+	locks       map[string]*sync.Mutex
+	annotations map[string]*AnnotationStore
+}
+
+// NamespaceExport is the data of a namespace, written by Namespaces.Export
+type NamespaceExport struct {
+	// Namespace is the exported namespace
+	Namespace  string    `json:"namespace"`
+	ExportedAt time.Time `json:"exported_at"`
+	// PackageVersion is the version of this package that wrote the export
+	PackageVersion string `json:"package_version,omitempty"`
+	// Annotations include the tombstones of removed annotations
+	Annotations []UserAnnotation `json:"annotations"`
+	Pins        Pins             `json:"pins"`
+	WatchLists  WatchLists       `json:"watch_lists"`
+}
+
+// NewNamespaces creates namespaces stored below the root directory
+func NewNamespaces(root string) *Namespaces {
+	return &Namespaces{root: root, locks: map[string]*sync.Mutex{}, annotations: map[string]*AnnotationStore{}}
+}
+
+// Dir returns the directory of a namespace
+func (n *Namespaces) Dir(namespace string) (string, error) {
+	if err := ValidateNamespace(namespace); err != nil {
+		return "", err
+	}
+	return filepath.Join(n.root, namespace), nil
+}
+
+// List returns the namespaces with a directory, sorted
+func (n *Namespaces) List() ([]string, error) {
+	entries, err := os.ReadDir(n.root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var namespaces []string
+	for _, entry := range entries {
+		if entry.IsDir() && ValidateNamespace(entry.Name()) == nil {
+			namespaces = append(namespaces, entry.Name())
+		}
+	}
+	return namespaces, nil
+}
+
+// Annotations returns the annotation store of a namespace. The store is shared by the
+// callers for the namespace.
+func (n *Namespaces) Annotations(namespace string) (*AnnotationStore, error) {
+	dir, err := n.Dir(namespace)
+	if err != nil {
+		return nil, err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if store, ok := n.annotations[namespace]; ok {
+		return store, nil
+	}
+	store, err := NewAnnotationStore(AnnotationStoreOptions{Path: filepath.Join(dir, AnnotationsFileName), Clock: n.Clock})
+	if err != nil {
+		return nil, err
+	}
+	n.annotations[namespace] = store
+	return store, nil
+}
+
+// Pins returns the pins of a namespace
+func (n *Namespaces) Pins(namespace string) (Pins, error) {
+	unlock, err := n.lock(namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return n.loadPins(namespace)
+}
+
+// UpdatePins changes the pins of a namespace with fn and saves them unless fn fails.
+// Updates of the same namespace are serialized.
+func (n *Namespaces) UpdatePins(namespace string, fn func(Pins) error) error {
+	unlock, err := n.lock(namespace)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	pins, err := n.loadPins(namespace)
+	if err != nil {
+		return err
+	}
+	if err := fn(pins); err != nil {
+		return err
+	}
+	return n.saveFile(namespace, PinsFileName, pins.Save)
+}
+
+// WatchLists returns the watch lists of a namespace
+func (n *Namespaces) WatchLists(namespace string) (WatchLists, error) {
+	unlock, err := n.lock(namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return n.loadWatchLists(namespace)
+}
+
+// UpdateWatchLists changes the watch lists of a namespace with fn and saves them unless
+// fn fails. Updates of the same namespace are serialized.
+func (n *Namespaces) UpdateWatchLists(namespace string, fn func(WatchLists) error) error {
+	unlock, err := n.lock(namespace)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	lists, err := n.loadWatchLists(namespace)
+	if err != nil {
+		return err
+	}
+	if err := fn(lists); err != nil {
+		return err
+	}
+	return n.saveFile(namespace, WatchListsFileName, lists.Save)
+}
+
+// Export writes the annotations, pins and watch lists of a namespace as one JSON
+// NamespaceExport
+func (n *Namespaces) Export(namespace string, w io.Writer) error {
+	store, err := n.Annotations(namespace)
+	if err != nil {
+		return err
+	}
+	export := NamespaceExport{
+		Namespace:      namespace,
+		ExportedAt:     clockOrDefault(n.Clock).Now().UTC(),
+		PackageVersion: PackageVersion(),
+	}
+	store.mu.Lock()
+	export.Annotations = store.sorted()
+	store.mu.Unlock()
+	if export.Pins, err = n.Pins(namespace); err != nil {
+		return err
+	}
+	if export.WatchLists, err = n.WatchLists(namespace); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(export)
+}
+
+// Import merges a NamespaceExport, possibly of another namespace, into a namespace:
+// annotations as AnnotationStore.Import merges them, and pins and watch lists replacing
+// those of the same law or name. The export is validated before anything is changed.
+func (n *Namespaces) Import(namespace string, r io.Reader) error {
+	var export NamespaceExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return WithErrorCategory(fmt.Errorf("failed to parse namespace export: %w", err), ErrorValidation)
+	}
+	annotations := make([]*UserAnnotation, len(export.Annotations))
+	for i, a := range export.Annotations {
+		if a.ID == "" {
+			return WithErrorCategory(fmt.Errorf("annotation %d has no ID", i+1), ErrorValidation)
+		}
+		if !a.Deleted {
+			if err := a.validate(); err != nil {
+				return fmt.Errorf("annotation %s: %w", a.ID, err)
+			}
+		}
+		annotations[i] = &a
+	}
+	for lawID, revisionID := range export.Pins {
+		if revisionID == "" {
+			return WithErrorCategory(fmt.Errorf("pin of %s has no revision", lawID), ErrorValidation)
+		}
+	}
+
+	store, err := n.Annotations(namespace)
+	if err != nil {
+		return err
+	}
+	if _, err := store.merge(annotations); err != nil {
+		return err
+	}
+	if len(export.Pins) > 0 {
+		err := n.UpdatePins(namespace, func(pins Pins) error {
+			for lawID, revisionID := range export.Pins {
+				pins[lawID] = revisionID
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if len(export.WatchLists) > 0 {
+		return n.UpdateWatchLists(namespace, func(lists WatchLists) error {
+			for name, list := range export.WatchLists {
+				if list == nil {
+					list = &WatchList{}
+				}
+				lists[name] = list
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// Remove deletes a namespace with all its data
+func (n *Namespaces) Remove(namespace string) error {
+	dir, err := n.Dir(namespace)
+	if err != nil {
+		return err
+	}
+	unlock, err := n.lock(namespace)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	n.mu.Lock()
+	delete(n.annotations, namespace)
+	n.mu.Unlock()
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return WithErrorCategory(fmt.Errorf("namespace %s does not exist", namespace), ErrorNotFound)
+	}
+	return os.RemoveAll(dir)
+}
+
+// lock locks the pins and watch lists of a namespace and returns the function unlocking
+// them
+func (n *Namespaces) lock(namespace string) (func(), error) {
+	if err := ValidateNamespace(namespace); err != nil {
+		return nil, err
+	}
+	n.mu.Lock()
+	l, ok := n.locks[namespace]
+	if !ok {
+		l = &sync.Mutex{}
+		n.locks[namespace] = l
+	}
+	n.mu.Unlock()
+	l.Lock()
+	return l.Unlock, nil
+}
+
+func (n *Namespaces) loadPins(namespace string) (Pins, error) {
+	var pins Pins
+	err := n.loadFile(namespace, PinsFileName, func(r io.Reader) (err error) {
+		pins, err = LoadPins(r)
+		return err
+	})
+	if pins == nil && err == nil {
+		pins = Pins{}
+	}
+	return pins, err
+}
+
+func (n *Namespaces) loadWatchLists(namespace string) (WatchLists, error) {
+	var lists WatchLists
+	err := n.loadFile(namespace, WatchListsFileName, func(r io.Reader) (err error) {
+		lists, err = LoadWatchLists(r)
+		return err
+	})
+	if lists == nil && err == nil {
+		lists = WatchLists{}
+	}
+	return lists, err
+}
+
+// loadFile reads a file of a namespace with load, if it exists
+func (n *Namespaces) loadFile(namespace, name string, load func(io.Reader) error) error {
+	dir, err := n.Dir(namespace)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, name)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := load(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// saveFile writes a file of a namespace atomically with save
+func (n *Namespaces) saveFile(namespace, name string, save func(io.Writer) error) error {
+	dir, err := n.Dir(namespace)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := save(&buf); err != nil {
+		return err
+	}
+	return WriteFileAtomic(filepath.Join(dir, name), buf.Bytes(), 0o644)
+}
@@ -0,0 +1,47 @@
+package lawapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateNamespace(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"tenant-a", true},
+		{"acme", true},
+		{"a", true},
+		{"v1.2_b", true},
+		{strings.Repeat("a", 64), true},
+		{strings.Repeat("a", 65), false},
+		{"", false},
+		{"Acme", false},
+		{"ACME", false},
+		{"-acme", false},
+		{".acme", false},
+		{"acme.", false},
+		{"..", false},
+		{"a/b", false},
+		{`a\b`, false},
+		{"テナント", false},
+		{"con", false},
+		{"nul", false},
+		{"nul.a", false},
+		{"com1", false},
+		{"lpt9.x", false},
+		{"console", true},
+		{"com10", true},
+	}
+	for _, tt := range tests {
+		err := ValidateNamespace(tt.name)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateNamespace(%q) = %v, want valid %v", tt.name, err, tt.valid)
+		}
+		if err != nil && (!errors.Is(err, ErrInvalidNamespace) || ErrorCategoryOf(err) != ErrorValidation) {
+			t.Errorf("ValidateNamespace(%q) = %v, want ErrInvalidNamespace with the validation category", tt.name, err)
+		}
+	}
+}
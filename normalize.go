@@ -0,0 +1,85 @@
+package lawapi
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeStage is a single text transformation of a Normalizer
+type NormalizeStage func(string) string
+
+// Normalizer applies a configurable sequence of stages to law text, so that comparisons
+// between revisions are not affected by superficial differences in character forms
+type Normalizer struct {
+	Stages []NormalizeStage
+}
+
+// DefaultNormalizeStages are the stages used by NewNormalizer when none are given
+var DefaultNormalizeStages = []NormalizeStage{
+	NormalizeNFKC,
+	NormalizeVariants,
+	NormalizeBrackets,
+	NormalizeWhitespace,
+}
+
+// NewNormalizer creates a normalizer applying the stages in order, or DefaultNormalizeStages if none are given
+func NewNormalizer(stages ...NormalizeStage) *Normalizer {
+	if len(stages) == 0 {
+		stages = DefaultNormalizeStages
+	}
+	return &Normalizer{Stages: stages}
+}
+
+// Normalize applies all stages to s
+func (n *Normalizer) Normalize(s string) string {
+	for _, stage := range n.Stages {
+		s = stage(s)
+	}
+	return s
+}
+
+// NormalizeText normalizes s with DefaultNormalizeStages
+func NormalizeText(s string) string {
+	return NewNormalizer().Normalize(s)
+}
+
+// NormalizeNFKC applies Unicode NFKC normalization, which folds full-width
+// alphanumerics and ideographic spaces into their half-width forms
+func NormalizeNFKC(s string) string {
+	return norm.NFKC.String(s)
+}
+
+// variantReplacer maps old-form and variant kanji and iteration marks found in older laws to their standard forms
+var variantReplacer = strings.NewReplacer(
+	"國", "国", "學", "学", "會", "会", "與", "与", "條", "条", "號", "号",
+	"從", "従", "權", "権", "證", "証", "團", "団", "關", "関", "體", "体",
+	"廳", "庁", "區", "区", "縣", "県", "處", "処", "舊", "旧", "臺", "台",
+	"辯", "弁", "檢", "検", "營", "営", "濟", "済", "實", "実", "據", "拠",
+	"擔", "担", "賣", "売", "參", "参", "數", "数", "變", "変", "稅", "税",
+	"緖", "緒", "髙", "高", "﨑", "崎", "邊", "辺", "邉", "辺", "齋", "斎",
+	"〻", "々",
+)
+
+// NormalizeVariants replaces old-form and variant kanji with their standard forms and unifies iteration marks
+func NormalizeVariants(s string) string {
+	return variantReplacer.Replace(s)
+}
+
+// bracketReplacer maps bracket variants to ASCII parentheses and square brackets, and 『』 to 「」
+var bracketReplacer = strings.NewReplacer(
+	"（", "(", "）", ")", "〔", "(", "〕", ")", "⦅", "(", "⦆", ")",
+	"［", "[", "］", "]", "【", "[", "】", "]", "〘", "[", "〙", "]",
+	"『", "「", "』", "」", "｢", "「", "｣", "」",
+)
+
+// NormalizeBrackets standardizes bracket variants
+func NormalizeBrackets(s string) string {
+	return bracketReplacer.Replace(s)
+}
+
+// NormalizeWhitespace trims s and collapses runs of whitespace, including ideographic spaces, into a single space
+func NormalizeWhitespace(s string) string {
+	return strings.Join(strings.FieldsFunc(s, unicode.IsSpace), " ")
+}
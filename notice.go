@@ -0,0 +1,74 @@
+package lawapi
+
+import (
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Notice describes the laws a generated document reproduces, for the data usage notice
+// embedded in it
+type Notice struct {
+	// Titles are the titles of the laws in the document
+	Titles []string
+	// Edited reports whether the document rearranges or annotates the text, like
+	// comparison tables, rather than reproducing it
+	Edited bool
+	// RetrievedAt is the latest retrieval time in the provenance of the laws, or zero
+	RetrievedAt time.Time
+}
+
+// DefaultNoticeTemplate credits e-Gov法令検索 as the source of the law data, as its terms of
+// use require of redistributed content, and states that edited documents were edited
+var DefaultNoticeTemplate = template.Must(template.New("notice").Parse(
+	`{{define "titles"}}{{range $i, $t := .}}{{if $i}}、{{end}}「{{$t}}」{{end}}{{end}}
+{{- if .Edited}}e-Gov法令検索（https://laws.e-gov.go.jp/）の{{if .Titles}}{{template "titles" .Titles}}{{else}}データ{{end}}を加工して作成
+{{- else}}出典：e-Gov法令検索（https://laws.e-gov.go.jp/）{{template "titles" .Titles}}{{end}}
+{{- if not .RetrievedAt.IsZero}}（{{.RetrievedAt.Format "2006年1月2日"}}取得）{{end}}`))
+
+// NoticeTemplate renders the notice that WriteLawHTML, RenderLawPDF, WriteEPUB,
+// WriteComparisonDOCX and WriteExtractDOCX embed in their output. Templates receive a
+// Notice; each line of the output becomes a paragraph. Redistributors set it to their
+// own attribution and terms, or to nil to leave notices out.
+var NoticeTemplate = DefaultNoticeTemplate
+
+// Text renders the notice with NoticeTemplate, or returns empty if it is nil
+func (n Notice) Text() (string, error) {
+	if NoticeTemplate == nil {
+		return "", nil
+	}
+	var b strings.Builder
+	if err := NoticeTemplate.Execute(&b, n); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// lines renders the notice as lines, without empty ones
+func (n Notice) lines() ([]string, error) {
+	text, err := n.Text()
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// lawNotice returns the notice of a document reproducing laws
+func lawNotice(edited bool, laws ...*LawNode) Notice {
+	n := Notice{Edited: edited}
+	for _, law := range laws {
+		if title := law.Title(); title != "" {
+			n.Titles = append(n.Titles, title)
+		}
+		if fetched := law.Provenance().FetchedAt; fetched.After(n.RetrievedAt) {
+			n.RetrievedAt = fetched
+		}
+	}
+	return n
+}
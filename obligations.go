@@ -0,0 +1,131 @@
+package lawapi
+
+import (
+	"encoding/csv"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ObligationKind classifies an obligation by its predicate
+type ObligationKind string
+
+const (
+	// ObligationDuty is a duty to act (「…しなければならない」)
+	ObligationDuty ObligationKind = "duty"
+	// ObligationProhibition is a prohibition (「…してはならない」)
+	ObligationProhibition ObligationKind = "prohibition"
+	// ObligationEffort is a duty to endeavor (努力義務,「…努めなければならない」)
+	ObligationEffort ObligationKind = "effort"
+)
+
+// Obligation is a sentence imposing a duty or prohibition, as an entry of a compliance
+// checklist
+type Obligation struct {
+	// ProvisionID is the canonical ID of the provision containing the sentence
+	ProvisionID string `json:"provision_id"`
+	// Provision is the citation of the provision (e.g. 第二条第二項第三号ただし書)
+	Provision string `json:"provision"`
+	// Kind is the kind of obligation
+	Kind ObligationKind `json:"kind"`
+	// Subject is who bears the obligation (the part before 「は、」, or 何人 for
+	// 「何人も、」), empty if the sentence names none
+	Subject string `json:"subject,omitempty"`
+	// Text is the sentence text
+	Text string `json:"text"`
+	// Items are the items (号) of the paragraph listing what the obligation covers, for
+	// sentences referring to them (「次に掲げる事項」「次の各号」)
+	Items []string `json:"items,omitempty"`
+}
+
+var (
+	obligationPattern = regexp.MustCompile(`(努めなければならない|なければならない|[てで]はならない)`)
+	// subjectConditionSuffixes end clauses before 「は、」 that state conditions
+	// (「…ときは、」「…場合においては、」「…については、」) rather than subjects
+	subjectConditionSuffixes = []string{"とき", "において", "に", "て", "こと"}
+	// subjectLeadPattern matches the conjunctions and clauses preceding a subject
+	subjectLeadPattern = regexp.MustCompile(`^.*(?:ただし、|において、|また、)`)
+)
+
+// ExtractObligations extracts the sentences imposing duties and prohibitions from a law,
+// for compliance checklists. Sentences are classified by their last obligation predicate:
+// 「…しなければならない」 (ObligationDuty), 「…してはならない」 (ObligationProhibition) and
+// 「…努めなければならない」 (ObligationEffort). Provision IDs are formed with lawID and
+// revision as in SentenceRef.ProvisionID. Obligations defined by reference to other
+// provisions (「前条の規定は…について準用する」) are not detected.
+func ExtractObligations(law *LawNode, lawID, revision string) []Obligation {
+	var obligations []Obligation
+	for _, ref := range law.Sentences() {
+		text := strings.TrimSpace(ref.Sentence.PlainText())
+		matches := obligationPattern.FindAllStringIndex(text, -1)
+		if matches == nil {
+			continue
+		}
+		last := matches[len(matches)-1]
+		obligation := Obligation{
+			ProvisionID: ref.ProvisionID(lawID, revision).String(),
+			Provision:   ref.Label(),
+			Kind:        obligationKind(text[last[0]:last[1]]),
+			Subject:     obligationSubject(text[:last[0]]),
+			Text:        text,
+		}
+		if ref.Item == nil && (strings.Contains(text, "次に掲げる") || strings.Contains(text, "次の各号")) {
+			for _, item := range ref.Paragraph.Items() {
+				itemText := strings.TrimSpace(item.Child("ItemSentence").PlainText())
+				obligation.Items = append(obligation.Items, strings.TrimSpace(item.ItemTitle()+"　"+itemText))
+			}
+		}
+		obligations = append(obligations, obligation)
+	}
+	return obligations
+}
+
+func obligationKind(predicate string) ObligationKind {
+	switch {
+	case strings.HasPrefix(predicate, "努め"):
+		return ObligationEffort
+	case strings.HasSuffix(predicate, "はならない") && !strings.HasSuffix(predicate, "なければならない"):
+		return ObligationProhibition
+	}
+	return ObligationDuty
+}
+
+// obligationSubject returns the subject of an obligation from the text before its
+// predicate: the first clause before 「は、」 that is not a condition
+func obligationSubject(s string) string {
+	if strings.HasPrefix(s, "何人も") {
+		return "何人"
+	}
+	clauses := strings.Split(s, "は、")
+	// The last clause is not followed by 「は、」
+	for _, clause := range clauses[:len(clauses)-1] {
+		conditional := false
+		for _, suffix := range subjectConditionSuffixes {
+			if strings.HasSuffix(clause, suffix) {
+				conditional = true
+				break
+			}
+		}
+		if !conditional {
+			return subjectLeadPattern.ReplaceAllString(clause, "")
+		}
+	}
+	return ""
+}
+
+// WriteObligationsCSV writes obligations as a checklist in CSV with a header row. Items
+// are written in one cell, one per line.
+func WriteObligationsCSV(w io.Writer, obligations []Obligation) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"provision_id", "provision", "kind", "subject", "text", "items"}); err != nil {
+		return err
+	}
+	for _, o := range obligations {
+		record := []string{o.ProvisionID, o.Provision, string(o.Kind), o.Subject, o.Text, strings.Join(o.Items, "\n")}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,105 @@
+package lawapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// OpenSearchMapping is an index body (settings and mappings) for SearchDocument in
+// Elasticsearch or OpenSearch. Text fields use a Japanese analyzer built on kuromoji,
+// which requires the analysis-kuromoji plugin.
+const OpenSearchMapping = `{
+  "settings": {
+    "analysis": {
+      "analyzer": {
+        "ja": {
+          "type": "custom",
+          "tokenizer": "kuromoji_tokenizer",
+          "filter": ["kuromoji_baseform", "kuromoji_part_of_speech", "cjk_width", "ja_stop", "kuromoji_stemmer", "lowercase"]
+        }
+      }
+    }
+  },
+  "mappings": {
+    "properties": {
+      "id": {"type": "keyword"},
+      "kind": {"type": "keyword"},
+      "law_id": {"type": "keyword"},
+      "law_revision_id": {"type": "keyword"},
+      "law_num": {"type": "keyword"},
+      "law_title": {"type": "text", "analyzer": "ja", "fields": {"keyword": {"type": "keyword"}}},
+      "law_title_kana": {"type": "keyword"},
+      "law_type": {"type": "keyword"},
+      "category": {"type": "keyword"},
+      "authorities": {"type": "keyword"},
+      "tags": {"type": "keyword"},
+      "promulgation_date": {"type": "date", "format": "yyyy-MM-dd"},
+      "provision": {"type": "keyword"},
+      "article_caption": {"type": "text", "analyzer": "ja"},
+      "text": {"type": "text", "analyzer": "ja"},
+      "content_hash": {"type": "keyword"}
+    }
+  }
+}
+`
+
+// BulkWriter writes documents in the NDJSON format of the Elasticsearch/OpenSearch bulk API.
+// Each document becomes an index action keyed by its ID, so re-indexing replaces documents in place.
+type BulkWriter struct {
+	w     *bufio.Writer
+	index string
+}
+
+// NewBulkWriter creates a writer of bulk requests targeting the given index
+func NewBulkWriter(w io.Writer, index string) *BulkWriter {
+	return &BulkWriter{w: bufio.NewWriter(w), index: index}
+}
+
+// Write writes index actions for the documents
+func (b *BulkWriter) Write(docs ...SearchDocument) error {
+	for _, doc := range docs {
+		action := map[string]map[string]string{"index": {"_index": b.index, "_id": doc.ID}}
+		if err := b.writeLine(action); err != nil {
+			return err
+		}
+		if err := b.writeLine(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Push implements SearchIndexer by writing index actions
+func (b *BulkWriter) Push(ctx context.Context, docs []SearchDocument) error {
+	return b.Write(docs...)
+}
+
+// Delete writes delete actions for the document IDs
+func (b *BulkWriter) Delete(ids ...string) error {
+	for _, id := range ids {
+		action := map[string]map[string]string{"delete": {"_index": b.index, "_id": id}}
+		if err := b.writeLine(action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes buffered data to the underlying writer. The bulk API requires the
+// request body to end with a newline, which every action already does.
+func (b *BulkWriter) Flush() error {
+	return b.w.Flush()
+}
+
+func (b *BulkWriter) writeLine(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := b.w.Write(data); err != nil {
+		return err
+	}
+	return b.w.WriteByte('\n')
+}
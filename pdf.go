@@ -0,0 +1,180 @@
+package lawapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// PDFRenderer converts an HTML document to PDF
+type PDFRenderer interface {
+	RenderPDF(ctx context.Context, html io.Reader, w io.Writer) error
+}
+
+// CommandRenderer is a PDFRenderer running an external converter. The HTML is written to a
+// temporary file and the PDF read back from another.
+type CommandRenderer struct {
+	// Name is the command name or path
+	Name string
+	// Args are the command arguments, in which "{input}" and "{output}" are replaced by the
+	// paths of the HTML and PDF files (e.g. "--print-to-pdf={output}")
+	Args []string
+}
+
+// WkhtmltopdfRenderer returns a renderer running wkhtmltopdf
+func WkhtmltopdfRenderer() *CommandRenderer {
+	return &CommandRenderer{Name: "wkhtmltopdf", Args: []string{"--quiet", "--encoding", "utf-8", "{input}", "{output}"}}
+}
+
+// WeasyPrintRenderer returns a renderer running WeasyPrint
+func WeasyPrintRenderer() *CommandRenderer {
+	return &CommandRenderer{Name: "weasyprint", Args: []string{"{input}", "{output}"}}
+}
+
+// RenderPDF implements PDFRenderer
+func (r *CommandRenderer) RenderPDF(ctx context.Context, html io.Reader, w io.Writer) error {
+	dir, err := os.MkdirTemp("", "lawapi-pdf")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	input, output := filepath.Join(dir, "input.html"), filepath.Join(dir, "output.pdf")
+	f, err := os.Create(input)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, html); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	placeholders := strings.NewReplacer("{input}", input, "{output}", output)
+	args := make([]string, len(r.Args))
+	for i, arg := range r.Args {
+		args[i] = placeholders.Replace(arg)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.Name, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+			return fmt.Errorf("%s failed: %w: %s", r.Name, err, msg)
+		}
+		return fmt.Errorf("%s failed: %w", r.Name, err)
+	}
+
+	pdf, err := os.Open(output)
+	if err != nil {
+		return fmt.Errorf("%s produced no output: %w", r.Name, err)
+	}
+	defer pdf.Close()
+	_, err = io.Copy(w, pdf)
+	return err
+}
+
+// WriteLawHTML writes a law as a standalone HTML document laid out for printing, with
+// headings for the law structure and one paragraph per article paragraph, item and sub-item.
+// The provenance of the law is written as a jplaw-provenance meta element, and the notice
+// of NoticeTemplate closes the document. The document is sanitized with HTMLOutputPolicy.
+func WriteLawHTML(w io.Writer, law *LawNode) error {
+	type htmlLine struct {
+		Text    string
+		Padding int
+		Heading int
+	}
+	var lines []htmlLine
+	for _, line := range provisionLines(law.Find("LawBody")) {
+		heading := line.heading
+		if heading > 0 {
+			// h1 is the law title
+			heading = min(heading+1, 6)
+		}
+		lines = append(lines, htmlLine{Text: line.text, Padding: line.indent + 1, Heading: heading})
+	}
+	notice, err := lawNotice(false, law).lines()
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{
+		"Title":      law.Title(),
+		"LawNum":     law.Find("LawNum").PlainText(),
+		"Lines":      lines,
+		"Provenance": provenanceMeta(law.Provenance()),
+		"Notice":     notice,
+	}
+	if HTMLOutputPolicy == nil {
+		return lawHTMLTemplate.Execute(w, data)
+	}
+	var buf strings.Builder
+	if err := lawHTMLTemplate.Execute(&buf, data); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, HTMLOutputPolicy.Sanitize(buf.String()))
+	return err
+}
+
+// RenderLawPDF renders a law to PDF with WriteLawHTML and the renderer
+func RenderLawPDF(ctx context.Context, w io.Writer, law *LawNode, renderer PDFRenderer) error {
+	var html bytes.Buffer
+	if err := WriteLawHTML(&html, law); err != nil {
+		return err
+	}
+	return renderer.RenderPDF(ctx, &html, w)
+}
+
+var lawHTMLTemplate = template.Must(template.New("law").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+{{with .Provenance}}<meta name="jplaw-provenance" content="{{.}}">
+{{end}}<title>{{.Title}}</title>
+<style>
+@page { size: A4; margin: 25mm 20mm; }
+body { font-family: serif; line-height: 1.7; }
+h1, h2, h3, h4, h5, h6 { font-family: sans-serif; page-break-after: avoid; }
+.lawnum { text-align: right; }
+p { margin: 0; text-indent: -1em; }
+.notice { margin-top: 2em; font-size: smaller; }
+.notice p { text-indent: 0; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- with .LawNum}}
+<p class="lawnum">{{.}}</p>
+{{- end}}
+{{- range .Lines}}
+{{- if eq .Heading 2}}
+<h2>{{.Text}}</h2>
+{{- else if eq .Heading 3}}
+<h3>{{.Text}}</h3>
+{{- else if eq .Heading 4}}
+<h4>{{.Text}}</h4>
+{{- else if eq .Heading 5}}
+<h5>{{.Text}}</h5>
+{{- else if eq .Heading 6}}
+<h6>{{.Text}}</h6>
+{{- else}}
+<p style="padding-left: {{.Padding}}em">{{.Text}}</p>
+{{- end}}
+{{- end}}
+{{- if .Notice}}
+<footer class="notice">
+{{- range .Notice}}
+<p>{{.}}</p>
+{{- end}}
+</footer>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,116 @@
+package lawapi
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Imprisonment is a term of imprisonment parsed from a penal provision.
+// Terms are expressed in months; zero means no bound was given.
+type Imprisonment struct {
+	// Kind is the kind of imprisonment (拘禁刑, 懲役, 禁錮, 拘留)
+	Kind string `json:"kind"`
+	// MinMonths is the minimum term in months (以上)
+	MinMonths int `json:"min_months,omitempty"`
+	// MaxMonths is the maximum term in months (以下)
+	MaxMonths int `json:"max_months,omitempty"`
+	// Life reports whether life imprisonment (無期) is an alternative
+	Life bool `json:"life,omitempty"`
+}
+
+// Fine is a monetary penalty parsed from a penal provision
+type Fine struct {
+	// Kind is the kind of monetary penalty (罰金, 科料, 過料)
+	Kind string `json:"kind"`
+	// MaxYen is the maximum amount in yen
+	MaxYen int64 `json:"max_yen"`
+}
+
+// Penalty is a penal provision (罰則) extracted from a law
+type Penalty struct {
+	// Provision is the citation of the penal provision (e.g. "第五条")
+	Provision string `json:"provision"`
+	// Text is the sentence text
+	Text string `json:"text"`
+	// Offender describes who is punished (the part before "は、")
+	Offender string `json:"offender,omitempty"`
+	// Imprisonments contains the terms of imprisonment
+	Imprisonments []Imprisonment `json:"imprisonments,omitempty"`
+	// Fines contains the monetary penalties
+	Fines []Fine `json:"fines,omitempty"`
+	// Cumulative reports whether imprisonment and fine may be imposed together (併科)
+	Cumulative bool `json:"cumulative,omitempty"`
+	// Corporate reports whether the provision punishes a corporation or employer (両罰規定)
+	Corporate bool `json:"corporate,omitempty"`
+}
+
+const kanjiNumberClass = `[〇一二三四五六七八九十百千万億兆０-９0-9，,]+`
+
+var (
+	penaltySentencePattern = regexp.MustCompile(`(に処する|に処し|を科する)`)
+	imprisonmentPattern    = regexp.MustCompile(`(無期(?:若しくは|又は))?(?:(` + kanjiNumberClass + `)(年|月)以上)?(?:(` + kanjiNumberClass + `)(年|月)以下)?の(拘禁刑|懲役|禁錮|禁固)`)
+	detentionPattern       = regexp.MustCompile(`拘留`)
+	finePattern            = regexp.MustCompile(`(` + kanjiNumberClass + `)円以下の(罰金|科料|過料)`)
+	corporatePattern       = regexp.MustCompile(`法人の代表者|行為者を罰するほか|各本条の罰金刑`)
+)
+
+// ExtractPenalties extracts penal provisions from a parsed law document.
+// Sentences that impose a punishment (「…に処する。」) are parsed for terms of
+// imprisonment and fine amounts.
+func ExtractPenalties(law *LawNode) []Penalty {
+	var penalties []Penalty
+	for _, ref := range law.Sentences() {
+		text := strings.TrimSpace(ref.Sentence.PlainText())
+		loc := penaltySentencePattern.FindStringIndex(text)
+		if loc == nil {
+			continue
+		}
+
+		penalty := Penalty{
+			Provision:  ref.Label(),
+			Text:       text,
+			Cumulative: strings.Contains(text, "併科"),
+			Corporate:  corporatePattern.MatchString(text),
+		}
+		if i := strings.LastIndex(text[:loc[0]], "は、"); i > 0 {
+			penalty.Offender = text[:i]
+		}
+
+		for _, m := range imprisonmentPattern.FindAllStringSubmatch(text, -1) {
+			imprisonment := Imprisonment{Kind: m[6], Life: m[1] != ""}
+			if m[2] != "" {
+				imprisonment.MinMonths = parseTermMonths(m[2], m[3])
+			}
+			if m[4] != "" {
+				imprisonment.MaxMonths = parseTermMonths(m[4], m[5])
+			}
+			if imprisonment.MinMonths == 0 && imprisonment.MaxMonths == 0 && !imprisonment.Life {
+				continue
+			}
+			penalty.Imprisonments = append(penalty.Imprisonments, imprisonment)
+		}
+		if detentionPattern.MatchString(text) {
+			penalty.Imprisonments = append(penalty.Imprisonments, Imprisonment{Kind: "拘留"})
+		}
+
+		for _, m := range finePattern.FindAllStringSubmatch(text, -1) {
+			if amount, ok := ParseKanjiNumber(m[1]); ok {
+				penalty.Fines = append(penalty.Fines, Fine{Kind: m[2], MaxYen: amount})
+			}
+		}
+
+		penalties = append(penalties, penalty)
+	}
+	return penalties
+}
+
+func parseTermMonths(num, unit string) int {
+	n, ok := ParseKanjiNumber(num)
+	if !ok {
+		return 0
+	}
+	if unit == "年" {
+		n *= 12
+	}
+	return int(n)
+}
@@ -0,0 +1,204 @@
+package lawapi
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PeriodKind is how a period relates to the event it is counted from
+type PeriodKind string
+
+const (
+	// PeriodWithin is a time limit (三十日以内, 一年を超えない範囲内, 五年を経過しない)
+	PeriodWithin PeriodKind = "within"
+	// PeriodAfter is the time at which a period has elapsed (五年を経過した日, 六月経過後)
+	PeriodAfter PeriodKind = "after"
+	// PeriodBefore is a time limit before the event (開始の日の十日前まで)
+	PeriodBefore PeriodKind = "before"
+	// PeriodDuration is a length of time, such as a term of validity (五年間)
+	PeriodDuration PeriodKind = "duration"
+	// PeriodAtLeast is a minimum length (三年以上, 一年を超える)
+	PeriodAtLeast PeriodKind = "at_least"
+	// PeriodAtMost is a maximum length (三年以下, 六月未満)
+	PeriodAtMost PeriodKind = "at_most"
+)
+
+// Period is a length of time in the units written in law text. Weeks are counted as
+// seven days.
+type Period struct {
+	Years  int `json:"years,omitempty"`
+	Months int `json:"months,omitempty"`
+	Days   int `json:"days,omitempty"`
+	Hours  int `json:"hours,omitempty"`
+}
+
+// PeriodExpression is a period in a provision, with the event it is counted from, for
+// tracking deadlines
+type PeriodExpression struct {
+	// ProvisionID is the canonical ID of the provision containing the expression
+	ProvisionID string `json:"provision_id"`
+	// Provision is the citation of the provision (e.g. 第二条第二項第三号ただし書)
+	Provision string `json:"provision"`
+	// Text is the expression as written, from the trigger if there is one
+	// (e.g. 処分があつたことを知つた日の翌日から起算して三月以内)
+	Text string     `json:"text"`
+	Kind PeriodKind `json:"kind"`
+	// Period is the length of the period
+	Period Period `json:"period"`
+	// Trigger is the event the period is counted from, or before for PeriodBefore, as
+	// written (e.g. 処分があつたことを知つた日の翌日), or empty if the sentence leaves it
+	// implicit
+	Trigger string `json:"trigger,omitempty"`
+	// FirstDayIncluded reports whether the day of the trigger is counted (起算して); by
+	// default it is not (初日不算入, Civil Code Article 140)
+	FirstDayIncluded bool `json:"first_day_included,omitempty"`
+	// Sentence is the text of the sentence
+	Sentence string `json:"sentence"`
+}
+
+const periodUnitClass = `(?:年|箇月|か月|ヵ月|ヶ月|カ月|ケ月|月|週間|日|時間)`
+
+var (
+	periodExpressionPattern = regexp.MustCompile(`((?:` + kanjiNumberClass + periodUnitClass + `)+)` +
+		`(以内|を超えない範囲内|を経過した(?:日|とき|後)|を経過する日|を経過しない|経過後|を経過するまで|前まで|前に|前|間|以上|を超える|以下|未満|後)`)
+	periodUnitPattern = regexp.MustCompile(`(` + kanjiNumberClass + `)(` + periodUnitClass + `)`)
+	// periodPenaltyPattern follows terms of imprisonment, which are not periods to track
+	periodPenaltyPattern = regexp.MustCompile(`^の(?:拘禁刑|懲役|禁錮|禁固|有期)`)
+	periodEraPattern     = regexp.MustCompile(`(?:明治|大正|昭和|平成|令和)$`)
+)
+
+var periodKinds = map[string]PeriodKind{
+	"以内": PeriodWithin, "を超えない範囲内": PeriodWithin, "を経過する日": PeriodWithin, "を経過するまで": PeriodWithin, "を経過しない": PeriodWithin,
+	"を経過した日": PeriodAfter, "を経過したとき": PeriodAfter, "を経過した後": PeriodAfter, "経過後": PeriodAfter, "後": PeriodAfter,
+	"前まで": PeriodBefore, "前に": PeriodBefore, "前": PeriodBefore,
+	"間":  PeriodDuration,
+	"以上": PeriodAtLeast, "を超える": PeriodAtLeast,
+	"以下": PeriodAtMost, "未満": PeriodAtMost,
+}
+
+// ExtractPeriods extracts the periods of a law (「三十日以内」「五年を経過した日」
+// 「十日前まで」「五年間」) with the events they are counted from. Terms of imprisonment
+// (「三年以下の懲役」) and years of eras are left out. Provision IDs are formed with lawID
+// and revision as in SentenceRef.ProvisionID.
+func ExtractPeriods(law *LawNode, lawID, revision string) []PeriodExpression {
+	var periods []PeriodExpression
+	for _, ref := range law.Sentences() {
+		text := strings.TrimSpace(ref.Sentence.PlainText())
+		for _, loc := range periodExpressionPattern.FindAllStringSubmatchIndex(text, -1) {
+			qualifier := text[loc[4]:loc[5]]
+			if periodPenaltyPattern.MatchString(text[loc[1]:]) || periodEraPattern.MatchString(text[:loc[0]]) {
+				continue
+			}
+			p := PeriodExpression{
+				ProvisionID: ref.ProvisionID(lawID, revision).String(),
+				Provision:   ref.Label(),
+				Kind:        periodKinds[qualifier],
+				Period:      parsePeriod(text[loc[2]:loc[3]]),
+				Sentence:    text,
+			}
+			var start int
+			p.Trigger, p.FirstDayIncluded, start = periodTrigger(text[:loc[0]], p.Kind)
+			p.Text = text[start:loc[1]]
+			periods = append(periods, p)
+		}
+	}
+	return periods
+}
+
+// parsePeriod sums the numbers and units of a period (e.g. 一年六月)
+func parsePeriod(s string) Period {
+	var p Period
+	for _, m := range periodUnitPattern.FindAllStringSubmatch(s, -1) {
+		n64, _ := ParseKanjiNumber(m[1])
+		n := int(n64)
+		switch m[2] {
+		case "年":
+			p.Years += n
+		case "日":
+			p.Days += n
+		case "週間":
+			p.Days += 7 * n
+		case "時間":
+			p.Hours += n
+		default:
+			p.Months += n
+		}
+	}
+	return p
+}
+
+// periodTrigger returns the event a period is counted from, written before it as
+// 「…から（起算して）」, or for periods before an event as 「…の」, whether the first day is
+// included, and the start of the expression in the text before the period
+func periodTrigger(before string, kind PeriodKind) (trigger string, firstDayIncluded bool, start int) {
+	rest, firstDayIncluded := strings.CutSuffix(before, "起算して")
+	var ok bool
+	if kind == PeriodBefore {
+		rest, ok = strings.CutSuffix(rest, "の")
+	} else {
+		rest, ok = strings.CutSuffix(rest, "から")
+	}
+	if !ok {
+		return "", false, len(before)
+	}
+	if i := strings.LastIndexAny(rest, "、。"); i >= 0 {
+		start = i + len("、")
+	}
+	return rest[start:], firstDayIncluded, start
+}
+
+// Deadline returns the date on which a period counted from the given date of its trigger
+// ends, for PeriodWithin (the last day of the period), PeriodAfter (the day after the
+// period has elapsed) and PeriodBefore (the last day leaving the full period before the
+// trigger) expressions in days, months and years. Unless FirstDayIncluded, counting starts
+// on the day after the trigger. Periods of months and years end on the day before the
+// day corresponding to their first day, or on the last day of the month if there is no
+// such day (Civil Code Articles 140 to 143).
+func (p PeriodExpression) Deadline(trigger Date) (Date, bool) {
+	if p.Period.Hours != 0 || p.Period == (Period{}) {
+		return Date{}, false
+	}
+	t := time.Time(trigger)
+	switch p.Kind {
+	case PeriodWithin, PeriodAfter:
+		first := t
+		if !p.FirstDayIncluded {
+			first = t.AddDate(0, 0, 1)
+		}
+		end := periodEnd(first, p.Period)
+		if p.Kind == PeriodAfter {
+			end = end.AddDate(0, 0, 1)
+		}
+		return Date(end), true
+	case PeriodBefore:
+		// The period is counted backwards from the day before the trigger and must
+		// elapse in full, so the deadline is the day before its first day
+		months := p.Period.Years*12 + p.Period.Months
+		return Date(addMonthsClamped(t, -months).AddDate(0, 0, -p.Period.Days-1)), true
+	}
+	return Date{}, false
+}
+
+// periodEnd returns the last day of a period starting on first
+func periodEnd(first time.Time, p Period) time.Time {
+	end := first.AddDate(0, 0, -1)
+	if months := p.Years*12 + p.Months; months > 0 {
+		end = addMonthsClamped(first, months)
+		if end.Day() == first.Day() {
+			end = end.AddDate(0, 0, -1)
+		}
+	}
+	return end.AddDate(0, 0, p.Days)
+}
+
+// addMonthsClamped adds months to t, moving to the last day of the month when the
+// corresponding day does not exist
+func addMonthsClamped(t time.Time, months int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	return time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, t.Location())
+}
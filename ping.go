@@ -0,0 +1,66 @@
+package lawapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// PingResult is the outcome of a health probe of the API server
+type PingResult struct {
+	// Available reports whether the server answered the probe successfully
+	Available bool `json:"available"`
+	// StatusCode is the HTTP status of the response, or 0 if there was none
+	StatusCode int `json:"status_code,omitempty"`
+	// Latency is the time until the response was read completely, or until the failure
+	// (nanoseconds in JSON)
+	Latency time.Duration `json:"latency"`
+	// CheckedAt is the time the probe was sent
+	CheckedAt time.Time `json:"checked_at"`
+	// Error describes why the server is not available
+	Error string `json:"error,omitempty"`
+}
+
+// Ping probes the server with the smallest search (laws with limit=1) and reports whether
+// it is available and how fast it answered, for readiness checks and health endpoints.
+// The probe is sent once, without retries. Unavailability is reported in the result;
+// the error is also returned, so that callers can treat it like any other failed request.
+// Like the other calls, the probe carries the correlation ID of ctx, or a new one, which
+// errors report (see CorrelationIDOf).
+func (c *Client) Ping(ctx context.Context) (*PingResult, error) {
+	clock := c.currentClock()
+	result := &PingResult{CheckedAt: clock.Now()}
+	err := c.ping(ctx, result)
+	result.Latency = clock.Now().Sub(result.CheckedAt)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+	result.Available = true
+	return result, nil
+}
+
+func (c *Client) ping(ctx context.Context, result *PingResult) error {
+	ctx, correlationID := ensureCorrelationID(ctx)
+	req, err := c.BuildGetLawsRequest(ctx, &GetLawsParams{Limit: Int32Ptr(1)})
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return withCorrelationID(fmt.Errorf("failed to execute request: %w", err), correlationID)
+	}
+	defer resp.Body.Close()
+	result.StatusCode = resp.StatusCode
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return withCorrelationID(fmt.Errorf("failed to read response: %w", err), correlationID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body), CorrelationID: correlationID}
+	}
+	return nil
+}
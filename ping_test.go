@@ -0,0 +1,56 @@
+package lawapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPingCorrelationID(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		id     string
+	}{
+		{"given ID", http.StatusServiceUnavailable, "probe-1"},
+		{"new ID", http.StatusServiceUnavailable, ""},
+		{"available", http.StatusOK, "probe-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r.Header.Get(CorrelationIDHeader)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"laws":[]}`))
+			}))
+			defer server.Close()
+			client := NewClient()
+			client.SetBaseURL(server.URL)
+
+			ctx := context.Background()
+			if tt.id != "" {
+				ctx = WithCorrelationID(ctx, tt.id)
+			}
+			result, err := client.Ping(ctx)
+			if received == "" || (tt.id != "" && received != tt.id) {
+				t.Fatalf("request carried correlation ID %q, want %q", received, tt.id)
+			}
+			if tt.status == http.StatusOK {
+				if err != nil || !result.Available {
+					t.Fatalf("Ping() = %+v, %v", result, err)
+				}
+				return
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.CorrelationID != received {
+				t.Fatalf("got %v, want an APIError with correlation ID %q", err, received)
+			}
+			if got := CorrelationIDOf(err); got != received {
+				t.Errorf("CorrelationIDOf() = %q, want %q", got, received)
+			}
+		})
+	}
+}
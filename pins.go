@@ -0,0 +1,135 @@
+package lawapi
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Pins maps law IDs to the revision IDs an application relies on, so that it keeps
+// retrieving the exact text it was validated against until the pins are moved
+// deliberately. CheckPins reports pins with newer revisions.
+type Pins map[string]string
+
+// LoadPins reads pins from a YAML mapping of law IDs to revision IDs, e.g.
+// {325AC0000000131: 325AC0000000131_20240401_505AC0000000050}
+func LoadPins(r io.Reader) (Pins, error) {
+	pins := Pins{}
+	if err := yaml.NewDecoder(r).Decode(&pins); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse pins: %w", err)
+	}
+	for lawID, revisionID := range pins {
+		if revisionID == "" {
+			return nil, fmt.Errorf("pin of %s has no revision", lawID)
+		}
+	}
+	return pins, nil
+}
+
+// Save writes the pins as YAML sorted by law ID
+func (p Pins) Save(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	if err := enc.Encode(map[string]string(p)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// Revision returns the revision ID pinned for a law, or the law ID itself if the law is
+// not pinned, for use as the lawIdOrNumOrRevisionId argument of GetLawData
+func (p Pins) Revision(lawID string) string {
+	if revisionID, ok := p[lawID]; ok {
+		return revisionID
+	}
+	return lawID
+}
+
+// PinCurrent pins a law to its revision currently in force and returns the revision ID
+func (c *Client) PinCurrent(ctx context.Context, pins Pins, lawID string) (string, error) {
+	resp, err := c.GetRevisionsWithContext(ctx, lawID, nil)
+	if err != nil {
+		return "", err
+	}
+	i := slices.IndexFunc(resp.Revisions, func(rev RevisionInfo) bool {
+		return rev.GetCurrentRevisionStatus() == CurrentRevisionStatusCurrentenforced
+	})
+	if i < 0 {
+		return "", WithErrorCategory(fmt.Errorf("%s has no revision in force", lawID), ErrorNotFound)
+	}
+	pins[lawID] = resp.Revisions[i].LawRevisionId
+	return pins[lawID], nil
+}
+
+// PinStatus compares a pin with the revisions of the law
+type PinStatus struct {
+	LawID string `json:"law_id"`
+	// RevisionID is the pinned revision
+	RevisionID string `json:"revision_id"`
+	// CurrentRevisionID is the revision currently in force, if any
+	CurrentRevisionID string `json:"current_revision_id,omitempty"`
+	// Newer are the revisions after the pinned one in chronological order of their IDs,
+	// including revisions not in force yet
+	Newer []string `json:"newer,omitempty"`
+	// Error is why the pin could not be checked, if so
+	Error string `json:"error,omitempty"`
+}
+
+// Outdated reports whether newer revisions than the pinned one are available
+func (s PinStatus) Outdated() bool {
+	return len(s.Newer) > 0
+}
+
+// CheckPins compares each pin with the revisions of its law, in order of law IDs.
+// Failures to check a pin, including pinned revisions the API does not list, are
+// reported in its Error, and only the end of ctx is returned as an error.
+func (c *Client) CheckPins(ctx context.Context, pins Pins) ([]PinStatus, error) {
+	lawIDs := make([]string, 0, len(pins))
+	for lawID := range pins {
+		lawIDs = append(lawIDs, lawID)
+	}
+	slices.Sort(lawIDs)
+
+	statuses := make([]PinStatus, 0, len(pins))
+	for _, lawID := range lawIDs {
+		status := PinStatus{LawID: lawID, RevisionID: pins[lawID]}
+		if err := c.checkPin(ctx, &status); err != nil {
+			if ctx.Err() != nil {
+				return statuses, ctx.Err()
+			}
+			status.Error = err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (c *Client) checkPin(ctx context.Context, status *PinStatus) error {
+	resp, err := c.GetRevisionsWithContext(ctx, status.LawID, nil)
+	if err != nil {
+		return err
+	}
+	revisions := slices.Clone(resp.Revisions)
+	slices.SortFunc(revisions, func(a, b RevisionInfo) int {
+		return cmp.Compare(a.LawRevisionId, b.LawRevisionId)
+	})
+	pinned := -1
+	for i, rev := range revisions {
+		if rev.LawRevisionId == status.RevisionID {
+			pinned = i
+		}
+		if rev.GetCurrentRevisionStatus() == CurrentRevisionStatusCurrentenforced {
+			status.CurrentRevisionID = rev.LawRevisionId
+		}
+	}
+	if pinned < 0 {
+		return fmt.Errorf("pinned revision %s is not a revision of %s", status.RevisionID, status.LawID)
+	}
+	for _, rev := range revisions[pinned+1:] {
+		status.Newer = append(status.Newer, rev.LawRevisionId)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package lawapi
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress receives progress notifications from long operations such as batch fetches
+type Progress interface {
+	// OnStart is called once before processing with the total number of items
+	OnStart(total int)
+	// OnItem is called after each item with the number of items done so far
+	OnItem(done int, item string)
+	// OnFinish is called once after all items were processed
+	OnFinish(summary ProgressSummary)
+}
+
+// ProgressSummary summarizes a finished operation
+type ProgressSummary struct {
+	Total     int
+	Succeeded int
+	Failed    int
+	Duration  time.Duration
+}
+
+type nopProgress struct{}
+
+func (nopProgress) OnStart(total int)                {}
+func (nopProgress) OnItem(done int, item string)     {}
+func (nopProgress) OnFinish(summary ProgressSummary) {}
+
+// TerminalProgress renders a single-line progress bar, redrawn in place with a carriage return
+type TerminalProgress struct {
+	w     io.Writer
+	width int
+
+	mu    sync.Mutex
+	total int
+}
+
+// NewTerminalProgress creates a progress bar writing to w (typically os.Stderr)
+func NewTerminalProgress(w io.Writer) *TerminalProgress {
+	return &TerminalProgress{w: w, width: 30}
+}
+
+// OnStart implements Progress
+func (p *TerminalProgress) OnStart(total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total = total
+	p.draw(0, "")
+}
+
+// OnItem implements Progress
+func (p *TerminalProgress) OnItem(done int, item string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.draw(done, item)
+}
+
+// OnFinish implements Progress
+func (p *TerminalProgress) OnFinish(summary ProgressSummary) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.draw(summary.Succeeded+summary.Failed, "")
+	fmt.Fprintf(p.w, "\n%d succeeded, %d failed in %s\n", summary.Succeeded, summary.Failed, summary.Duration.Round(time.Millisecond))
+}
+
+func (p *TerminalProgress) draw(done int, item string) {
+	filled := p.width
+	if p.total > 0 {
+		filled = p.width * done / p.total
+	}
+	bar := strings.Repeat("#", filled) + strings.Repeat(" ", p.width-filled)
+	fmt.Fprintf(p.w, "\r[%s] %d/%d %s\033[K", bar, done, p.total, item)
+}
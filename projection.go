@@ -0,0 +1,123 @@
+package lawapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Projection selects the JSON fields kept when re-serializing responses, so that proxies
+// can pass on only what their clients need, e.g. without the heavy law_full_text. Fields
+// are named by their JSON names, either alone to match at any depth ("law_title") or as a
+// dotted path from the top level ("revision_info.law_title"); array elements are
+// traversed without an index ("laws.law_info.law_id").
+type Projection struct {
+	include []string
+	exclude []string
+}
+
+// Fields returns a projection keeping only the named fields and the objects containing
+// them. Matching fields are kept with all their content.
+func Fields(fields ...string) *Projection {
+	return &Projection{include: fields}
+}
+
+// OmitFields returns a projection keeping everything but the named fields
+func OmitFields(fields ...string) *Projection {
+	return &Projection{exclude: fields}
+}
+
+// Omit additionally removes the named fields, e.g. from the content of included fields
+func (p *Projection) Omit(fields ...string) *Projection {
+	return &Projection{include: p.include, exclude: append(append([]string(nil), p.exclude...), fields...)}
+}
+
+// Project encodes v as JSON with the projection applied
+func (p *Projection) Project(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return p.ProjectJSON(data)
+}
+
+// ProjectJSON applies the projection to a JSON document. Numbers are kept as written;
+// object keys are sorted.
+func (p *Projection) ProjectJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON for projection: %w", err)
+	}
+	if p.include != nil {
+		doc, _ = p.keep(doc, nil)
+	}
+	doc = p.omit(doc, nil)
+	return json.Marshal(doc)
+}
+
+// keep returns the parts of v below path that contain included fields, and whether there
+// are any
+func (p *Projection) keep(v any, path []string) (any, bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		kept := map[string]any{}
+		for key, value := range v {
+			child := append(path[:len(path):len(path)], key)
+			if matchesField(p.include, child) {
+				kept[key] = value
+			} else if value, ok := p.keep(value, child); ok {
+				kept[key] = value
+			}
+		}
+		return kept, len(kept) > 0
+	case []any:
+		var kept []any
+		for _, elem := range v {
+			if elem, ok := p.keep(elem, path); ok {
+				kept = append(kept, elem)
+			}
+		}
+		return kept, len(kept) > 0
+	}
+	return nil, false
+}
+
+// omit removes the excluded fields below path from v
+func (p *Projection) omit(v any, path []string) any {
+	if len(p.exclude) == 0 {
+		return v
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			child := append(path[:len(path):len(path)], key)
+			if matchesField(p.exclude, child) {
+				delete(v, key)
+			} else {
+				v[key] = p.omit(value, child)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = p.omit(v[i], path)
+		}
+	}
+	return v
+}
+
+// matchesField reports whether a field path matches any of the field names or dotted paths
+func matchesField(fields []string, path []string) bool {
+	for _, field := range fields {
+		if strings.Contains(field, ".") {
+			if field == strings.Join(path, ".") {
+				return true
+			}
+		} else if field == path[len(path)-1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package lawapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// modulePath is the module path of this package, used to find its version in build info
+const modulePath = "go.ngs.io/jplaw-api-v2"
+
+// Provenance records where a stored or derived artifact came from, so that datasets built
+// from the API can be audited. Law trees carry the provenance of the retrieved revision
+// (see LawNode.Provenance), and WriteLawXML, WriteLawHTML, RenderLawPDF, WriteEPUB and
+// WriteExtractDOCX stamp it on their output.
+type Provenance struct {
+	// BaseURL is the API root the content was retrieved from, or empty if unknown
+	BaseURL string `json:"base_url,omitempty"`
+	// FetchedAt is when the content was retrieved, or zero if unknown
+	FetchedAt time.Time `json:"fetched_at"`
+	// LawRevisionID is the ID of the law revision, or empty
+	LawRevisionID string `json:"law_revision_id,omitempty"`
+	// PackageVersion is the version of this package that retrieved the content (see
+	// PackageVersion)
+	PackageVersion string `json:"package_version,omitempty"`
+	// ContentSHA256 is the hex SHA-256 of the content as retrieved: of the law XML written
+	// by WriteLawXML for law trees (see LawContentSHA256), of the response body for API
+	// responses
+	ContentSHA256 string `json:"content_sha256,omitempty"`
+}
+
+// IsZero reports whether no provenance is recorded
+func (p Provenance) IsZero() bool {
+	return p.BaseURL == "" && p.FetchedAt.IsZero() && p.LawRevisionID == "" && p.PackageVersion == "" && p.ContentSHA256 == ""
+}
+
+// String returns the provenance as JSON
+func (p Provenance) String() string {
+	data, _ := json.Marshal(p)
+	return string(data)
+}
+
+// Provenance headers stamp API responses stored or served by mirrors
+const (
+	ProvenanceBaseURLHeader        = "X-Jplaw-Base-Url"
+	ProvenanceFetchedAtHeader      = "X-Jplaw-Fetched-At"
+	ProvenanceRevisionHeader       = "X-Jplaw-Law-Revision-Id"
+	ProvenancePackageVersionHeader = "X-Jplaw-Package-Version"
+	ProvenanceContentSHA256Header  = "X-Jplaw-Content-Sha256"
+)
+
+// SetHeader sets the provenance headers of the non-empty fields
+func (p Provenance) SetHeader(h http.Header) {
+	for name, value := range map[string]string{
+		ProvenanceBaseURLHeader:        p.BaseURL,
+		ProvenanceRevisionHeader:       p.LawRevisionID,
+		ProvenancePackageVersionHeader: p.PackageVersion,
+		ProvenanceContentSHA256Header:  p.ContentSHA256,
+	} {
+		if value != "" {
+			h.Set(name, value)
+		}
+	}
+	if !p.FetchedAt.IsZero() {
+		h.Set(ProvenanceFetchedAtHeader, p.FetchedAt.UTC().Format(time.RFC3339))
+	}
+}
+
+// ProvenanceFromHeader reads the provenance headers set by SetHeader
+func ProvenanceFromHeader(h http.Header) Provenance {
+	p := Provenance{
+		BaseURL:        h.Get(ProvenanceBaseURLHeader),
+		LawRevisionID:  h.Get(ProvenanceRevisionHeader),
+		PackageVersion: h.Get(ProvenancePackageVersionHeader),
+		ContentSHA256:  h.Get(ProvenanceContentSHA256Header),
+	}
+	p.FetchedAt, _ = time.Parse(time.RFC3339, h.Get(ProvenanceFetchedAtHeader))
+	return p
+}
+
+// PackageVersion returns the module version of this package from the build info of the
+// running binary (e.g. "v1.4.0"), or "(devel)" when it is built from a working tree
+func PackageVersion() string {
+	return packageVersion()
+}
+
+var packageVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "(devel)"
+})
+
+// LawContentSHA256 returns the hex SHA-256 of the law XML written by WriteLawXML for a
+// tree, leaving out its provenance comment
+func LawContentSHA256(law *LawNode) string {
+	var buf bytes.Buffer
+	writeLawXML(&buf, law, false)
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// Provenance returns the provenance of a law tree: set by ParseLawFullText and
+// Client.GetLawTree, read back from provenance comments by ParseLawXML, or SetProvenance.
+// Trees built otherwise have none.
+func (n *LawNode) Provenance() Provenance {
+	if n == nil || n.provenance == nil {
+		return Provenance{}
+	}
+	return *n.provenance
+}
+
+// SetProvenance sets the provenance of a law tree
+func (n *LawNode) SetProvenance(p Provenance) {
+	if p.IsZero() {
+		n.provenance = nil
+		return
+	}
+	n.provenance = &p
+}
+
+// GetLawTree retrieves the full text of a law revision as a tree, with its provenance
+// including the base URL of the client and the time of retrieval
+func (c *Client) GetLawTree(ctx context.Context, lawIdOrNumOrRevisionId string) (*LawNode, error) {
+	data, err := c.GetLawDataWithContext(ctx, lawIdOrNumOrRevisionId, nil)
+	if err != nil {
+		return nil, err
+	}
+	fetchedAt := c.currentClock().Now()
+	law, err := ParseLawFullText(data)
+	if err != nil {
+		return nil, err
+	}
+	p := law.Provenance()
+	p.BaseURL, p.FetchedAt = c.baseURL, fetchedAt
+	law.SetProvenance(p)
+	return law, nil
+}
+
+// provenanceCommentPrefix starts the XML comment holding the provenance of law XML
+const provenanceCommentPrefix = "jplaw-provenance "
+
+// provenanceComment returns the provenance as the text of an XML comment, or empty
+func provenanceComment(p Provenance) string {
+	if p.IsZero() {
+		return ""
+	}
+	// "--" cannot occur in XML comments; JSON decodes the escaped form back
+	return provenanceCommentPrefix + strings.ReplaceAll(p.String(), "--", `-\u002d`)
+}
+
+// provenanceMeta returns the provenance as the content of a jplaw-provenance meta
+// element, or empty
+func provenanceMeta(p Provenance) string {
+	if p.IsZero() {
+		return ""
+	}
+	return p.String()
+}
+
+// parseProvenanceComment parses the text of an XML comment written by provenanceComment
+func parseProvenanceComment(comment string) (Provenance, bool) {
+	data, ok := strings.CutPrefix(strings.TrimSpace(comment), provenanceCommentPrefix)
+	if !ok {
+		return Provenance{}, false
+	}
+	var p Provenance
+	if json.Unmarshal([]byte(data), &p) != nil {
+		return Provenance{}, false
+	}
+	return p, true
+}
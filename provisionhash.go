@@ -0,0 +1,95 @@
+package lawapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// ProvisionHash is the content hash of an article or paragraph, for telling which
+// provisions changed between revisions without diffing their text
+type ProvisionHash struct {
+	// ProvisionID is the canonical ID of the provision with empty law and revision
+	// segments (e.g. "::2:1:"), so that it is the same in every revision
+	ProvisionID string `json:"provision_id"`
+	// Label is the citation of the provision (e.g. 第二条第一項)
+	Label string `json:"label"`
+	// SHA256 is the hex SHA-256 of the normalized text of the provision
+	SHA256 string `json:"sha256"`
+}
+
+// ContentHash returns the hex SHA-256 of the text of a provision normalized with
+// NormalizeText, so that hashes do not change with character forms, brackets or
+// whitespace. The text of an article is its caption and its paragraphs, one per line, as
+// compared by GenerateComparisonTable; other provisions hash their whole text, including
+// their items. Ruby readings are left out.
+func (n *LawNode) ContentHash() string {
+	var text string
+	if n.Tag == "Article" {
+		lines := []string{NormalizeText(n.Child("ArticleCaption").PlainText())}
+		for _, paragraph := range n.Paragraphs() {
+			lines = append(lines, NormalizeText(paragraph.PlainText()))
+		}
+		text = strings.Join(lines, "\n")
+	} else {
+		text = NormalizeText(n.PlainText())
+	}
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// ProvisionHashes returns the content hashes of the articles of a law, each followed by
+// its paragraphs, in document order. Laws and supplementary provisions without articles
+// have hashes of their paragraphs.
+func (n *LawNode) ProvisionHashes() []ProvisionHash {
+	var hashes []ProvisionHash
+	add := func(ref SentenceRef, node *LawNode) {
+		hashes = append(hashes, ProvisionHash{ProvisionID: ref.ProvisionID("", "").String(), Label: ref.Label(), SHA256: node.ContentHash()})
+	}
+	for _, ref := range n.provisionRefs() {
+		if ref.Article == nil {
+			add(ref, ref.Paragraph)
+			continue
+		}
+		add(ref, ref.Article)
+		for _, paragraph := range ref.Article.Paragraphs() {
+			ref.Paragraph = paragraph
+			add(ref, paragraph)
+		}
+	}
+	return hashes
+}
+
+// ProvisionHashChange is a provision whose hash differs between two revisions
+type ProvisionHashChange struct {
+	ProvisionID string `json:"provision_id"`
+	Label       string `json:"label"`
+	// Status is ComparisonModified, ComparisonAdded or ComparisonDeleted
+	Status ComparisonStatus `json:"status"`
+}
+
+// DiffProvisionHashes returns the provisions that were modified, added or deleted
+// between two revisions, given their hashes: first those of the new revision in its
+// order, then the deleted ones in the order of the old revision
+func DiffProvisionHashes(oldHashes, newHashes []ProvisionHash) []ProvisionHashChange {
+	old := make(map[string]string, len(oldHashes))
+	for _, h := range oldHashes {
+		old[h.ProvisionID] = h.SHA256
+	}
+	current := make(map[string]bool, len(newHashes))
+	var changes []ProvisionHashChange
+	for _, h := range newHashes {
+		current[h.ProvisionID] = true
+		if sum, ok := old[h.ProvisionID]; !ok {
+			changes = append(changes, ProvisionHashChange{ProvisionID: h.ProvisionID, Label: h.Label, Status: ComparisonAdded})
+		} else if sum != h.SHA256 {
+			changes = append(changes, ProvisionHashChange{ProvisionID: h.ProvisionID, Label: h.Label, Status: ComparisonModified})
+		}
+	}
+	for _, h := range oldHashes {
+		if !current[h.ProvisionID] {
+			changes = append(changes, ProvisionHashChange{ProvisionID: h.ProvisionID, Label: h.Label, Status: ComparisonDeleted})
+		}
+	}
+	return changes
+}
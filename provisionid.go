@@ -0,0 +1,214 @@
+package lawapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ProvisionID is a canonical, machine-readable identifier of a provision, formatted as
+//
+//	lawId:revision:article:paragraph:item
+//
+// Segments below the identified provision are empty (e.g. "405AC0000000088::2::" for
+// article 2 of the current revision). Article, paragraph and item segments hold the Num
+// attributes of the law XML, so branch articles look like "2_3". Articles of
+// supplementary provisions (附則) are prefixed with "s" ("s" alone for 附則 without
+// articles). Supplementary provisions after the first, such as those of amending laws in
+// consolidated texts, are told apart by their position from 1 after an "@" (e.g. "s2@3"
+// for article 2 of the third 附則, or "s@3" if it has no articles). Nested sub-items are
+// appended to the item segment separated by dots (e.g. "3.1.2" for 第三号イ(2)).
+type ProvisionID struct {
+	// LawID is the law ID
+	LawID string
+	// Revision is the law revision ID, or empty for the current revision
+	Revision string
+	// Article is the Num attribute of the article, with an "s" prefix and an "@" position
+	// suffix in supplementary provisions
+	Article string
+	// Paragraph is the Num attribute of the paragraph
+	Paragraph string
+	// Item is the Num attribute of the item, followed by dot-separated sub-item Num attributes
+	Item string
+}
+
+// String formats the ID as lawId:revision:article:paragraph:item
+func (id ProvisionID) String() string {
+	return strings.Join([]string{id.LawID, id.Revision, id.Article, id.Paragraph, id.Item}, ":")
+}
+
+// ParseProvisionID parses an ID formatted by ProvisionID.String
+func ParseProvisionID(s string) (ProvisionID, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 5 {
+		return ProvisionID{}, fmt.Errorf("invalid provision ID %q: expected 5 segments, got %d", s, len(parts))
+	}
+	if parts[0] == "" {
+		return ProvisionID{}, fmt.Errorf("invalid provision ID %q: missing law ID", s)
+	}
+	id := ProvisionID{LawID: parts[0], Revision: parts[1], Article: parts[2], Paragraph: parts[3], Item: parts[4]}
+	if id.Item != "" && id.Paragraph == "" {
+		return ProvisionID{}, fmt.Errorf("invalid provision ID %q: missing enclosing provision", s)
+	}
+	return id, nil
+}
+
+// ProvisionID returns the canonical ID of the provision containing the sentence
+func (r SentenceRef) ProvisionID(lawID, revision string) ProvisionID {
+	id := ProvisionID{LawID: lawID, Revision: revision}
+	if r.SupplProvision {
+		num := ""
+		if r.Article != nil {
+			num = r.Article.Num()
+		}
+		id.Article = supplArticle(num, r.SupplIndex)
+	} else if r.Article != nil {
+		id.Article = r.Article.Num()
+	}
+	if r.Paragraph != nil {
+		id.Paragraph = r.Paragraph.Num()
+	}
+	if r.Item != nil {
+		nums := []string{r.Item.Num()}
+		for _, subitem := range r.Subitems {
+			nums = append(nums, subitem.Num())
+		}
+		id.Item = strings.Join(nums, ".")
+	}
+	return id
+}
+
+// Provision returns the article, paragraph, item or sub-item identified by id, or nil.
+// The law and revision segments are not checked against the tree.
+func (n *LawNode) Provision(id ProvisionID) *LawNode {
+	var node *LawNode
+	if id.Article == "" {
+		// Laws without articles consist of paragraphs directly under the main provision
+		if id.Paragraph == "" {
+			return nil
+		}
+		node = n.Find("MainProvision")
+	} else if num, index, ok := parseSupplArticle(id.Article); ok {
+		node = n.supplProvision(index)
+		if num != "" {
+			node = childByNum(node.FindAll("Article"), num)
+		}
+	} else {
+		node = n.Article(id.Article)
+	}
+
+	if id.Paragraph != "" {
+		node = node.Paragraph(id.Paragraph)
+	}
+	if id.Item != "" {
+		for _, num := range strings.Split(id.Item, ".") {
+			node = node.Item(num)
+		}
+	}
+	return node
+}
+
+// provisionRef resolves id like Provision and returns the enclosing provisions, whose
+// Label cites the provision
+func (n *LawNode) provisionRef(id ProvisionID) (SentenceRef, bool) {
+	var ref SentenceRef
+	num, index, suppl := parseSupplArticle(id.Article)
+	if suppl {
+		ref.SupplProvision, ref.SupplIndex = true, index
+		if ref.Suppl = n.supplProvision(index); ref.Suppl == nil {
+			return SentenceRef{}, false
+		}
+	}
+	if id.Article != "" && (!suppl || num != "") {
+		if ref.Article = n.Provision(ProvisionID{Article: id.Article}); ref.Article == nil {
+			return SentenceRef{}, false
+		}
+	}
+	if id.Paragraph != "" {
+		if ref.Paragraph = n.Provision(ProvisionID{Article: id.Article, Paragraph: id.Paragraph}); ref.Paragraph == nil {
+			return SentenceRef{}, false
+		}
+	}
+	if id.Item != "" {
+		nums := strings.Split(id.Item, ".")
+		if ref.Item = ref.Paragraph.Item(nums[0]); ref.Item == nil {
+			return SentenceRef{}, false
+		}
+		node := ref.Item
+		for _, num := range nums[1:] {
+			if node = node.Item(num); node == nil {
+				return SentenceRef{}, false
+			}
+			ref.Subitems = append(ref.Subitems, node)
+		}
+	}
+	return ref, n.Provision(id) != nil
+}
+
+// provisionRefs returns the articles of the law, and the paragraphs outside articles
+// (those of laws without articles and of 附則 without articles), in document order. Their
+// provision IDs are unique within the law.
+func (n *LawNode) provisionRefs() []SentenceRef {
+	var refs []SentenceRef
+	suppls := 0
+	var walk func(node *LawNode, ctx SentenceRef)
+	walk = func(node *LawNode, ctx SentenceRef) {
+		switch node.Tag {
+		case "SupplProvision":
+			suppls++
+			ctx.SupplProvision, ctx.Suppl, ctx.SupplIndex = true, node, suppls
+		case "Article":
+			ctx.Article = node
+			refs = append(refs, ctx)
+			return
+		case "Paragraph":
+			ctx.Paragraph = node
+			refs = append(refs, ctx)
+			return
+		}
+		for _, child := range node.Children {
+			walk(child, ctx)
+		}
+	}
+	if n != nil {
+		walk(n, SentenceRef{})
+	}
+	return refs
+}
+
+// supplArticle returns the article segment of an article of the supplementary provision
+// at a position, or of the supplementary provision itself if num is empty
+func supplArticle(num string, index int) string {
+	if index > 1 {
+		return "s" + num + "@" + strconv.Itoa(index)
+	}
+	return "s" + num
+}
+
+// parseSupplArticle splits the article segment of a supplementary provision into the Num
+// of the article, empty for the supplementary provision itself, and the position of the
+// supplementary provision, or returns false for other segments
+func parseSupplArticle(article string) (num string, index int, ok bool) {
+	rest, ok := strings.CutPrefix(article, "s")
+	if !ok {
+		return "", 0, false
+	}
+	num, position, found := strings.Cut(rest, "@")
+	if !found {
+		return num, 1, true
+	}
+	index, err := strconv.Atoi(position)
+	if err != nil || index < 2 {
+		return "", 0, false
+	}
+	return num, index, true
+}
+
+// supplProvision returns the supplementary provision at a position from 1, or nil
+func (n *LawNode) supplProvision(index int) *LawNode {
+	suppls := n.FindAll("SupplProvision")
+	if index < 1 || index > len(suppls) {
+		return nil
+	}
+	return suppls[index-1]
+}
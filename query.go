@@ -0,0 +1,303 @@
+package lawapi
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Query is a search predicate combinable with And and Or. Queries compile to one
+// GetLaws or GetKeyword call per disjunct, whose results are filtered and merged
+// client-side.
+type Query interface {
+	// disjuncts returns the query in disjunctive normal form
+	disjuncts() [][]queryTerm
+}
+
+// queryTerm restricts a single search
+type queryTerm func(s *querySearch)
+
+func (t queryTerm) disjuncts() [][]queryTerm {
+	return [][]queryTerm{{t}}
+}
+
+type andQuery []Query
+
+func (q andQuery) disjuncts() [][]queryTerm {
+	result := [][]queryTerm{{}}
+	for _, sub := range q {
+		var next [][]queryTerm
+		for _, left := range result {
+			for _, right := range sub.disjuncts() {
+				next = append(next, append(slices.Clip(left), right...))
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+type orQuery []Query
+
+func (q orQuery) disjuncts() [][]queryTerm {
+	var result [][]queryTerm
+	for _, sub := range q {
+		result = append(result, sub.disjuncts()...)
+	}
+	return result
+}
+
+// And matches laws matching all queries
+func And(queries ...Query) Query {
+	return andQuery(queries)
+}
+
+// Or matches laws matching any of the queries
+func Or(queries ...Query) Query {
+	return orQuery(queries)
+}
+
+// TitleContains matches laws whose title contains s. Titles are compared after NormalizeText.
+func TitleContains(s string) Query {
+	return queryTerm(func(search *querySearch) {
+		search.titles = append(search.titles, s)
+	})
+}
+
+// HasKeyword matches laws whose text contains the keyword
+func HasKeyword(keyword string) Query {
+	return queryTerm(func(search *querySearch) {
+		search.keywords = append(search.keywords, keyword)
+	})
+}
+
+// InCategory matches laws in any of the categories
+func InCategory(categories ...CategoryCd) Query {
+	return queryTerm(func(search *querySearch) {
+		search.categories = intersect(search.categories, categories)
+	})
+}
+
+// OfLawType matches laws of any of the types
+func OfLawType(lawTypes ...LawType) Query {
+	return queryTerm(func(search *querySearch) {
+		search.lawTypes = intersect(search.lawTypes, lawTypes)
+	})
+}
+
+// PromulgatedBetween matches laws promulgated between from and to, inclusive.
+// A zero date leaves that side of the range open.
+func PromulgatedBetween(from, to Date) Query {
+	return queryTerm(func(search *querySearch) {
+		if !time.Time(from).IsZero() && (search.from == nil || time.Time(from).After(time.Time(*search.from))) {
+			search.from = &from
+		}
+		if !time.Time(to).IsZero() && (search.to == nil || time.Time(to).Before(time.Time(*search.to))) {
+			search.to = &to
+		}
+	})
+}
+
+// intersect restricts a set of allowed values, where nil means any value
+func intersect[T comparable](current, values []T) []T {
+	if current == nil {
+		return slices.Clone(values)
+	}
+	result := []T{}
+	for _, v := range current {
+		if slices.Contains(values, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// querySearch is a conjunction of terms compiled into a single API call
+type querySearch struct {
+	titles     []string
+	keywords   []string
+	categories []CategoryCd
+	lawTypes   []LawType
+	from, to   *Date
+	// authorities is checked client-side
+	authorities []string
+}
+
+// empty reports whether the conjunction cannot match any law
+func (s *querySearch) empty() bool {
+	return s.categories != nil && len(s.categories) == 0 ||
+		s.lawTypes != nil && len(s.lawTypes) == 0 ||
+		s.authorities != nil && len(s.authorities) == 0 ||
+		s.from != nil && s.to != nil && time.Time(*s.from).After(time.Time(*s.to))
+}
+
+// matches applies the terms the API call could not express
+func (s *querySearch) matches(item *LawItem) bool {
+	title := ""
+	if item.RevisionInfo != nil {
+		title = NormalizeText(item.RevisionInfo.LawTitle)
+	}
+	for i, t := range s.titles {
+		// GetLaws filters by the first title, GetKeyword by none
+		if i == 0 && len(s.keywords) == 0 {
+			continue
+		}
+		if !strings.Contains(title, NormalizeText(t)) {
+			return false
+		}
+	}
+	if s.authorities != nil && !slices.ContainsFunc(lawItemAuthorities(item), func(a string) bool {
+		return slices.Contains(s.authorities, a)
+	}) {
+		return false
+	}
+	return true
+}
+
+// SearchOptions configures Client.Search
+type SearchOptions struct {
+	// MaxResultsPerCall limits the number of results fetched for each compiled API call (default: 1000)
+	MaxResultsPerCall int
+	// PageSize is the number of results per request (default: 100)
+	PageSize int32
+}
+
+// Search runs a query and returns the matching laws, deduplicated by law ID and sorted with
+// SortLawItems so that the result does not depend on the order of API responses.
+// Disjuncts containing HasKeyword use GetKeyword; others use GetLaws.
+func (c *Client) Search(ctx context.Context, q Query, opts *SearchOptions) ([]LawItem, error) {
+	maxResults, pageSize := 1000, int32(100)
+	if opts != nil {
+		if opts.MaxResultsPerCall > 0 {
+			maxResults = opts.MaxResultsPerCall
+		}
+		if opts.PageSize > 0 {
+			pageSize = opts.PageSize
+		}
+	}
+
+	var results []LawItem
+	seen := map[string]bool{}
+	for _, terms := range q.disjuncts() {
+		search := &querySearch{}
+		for _, term := range terms {
+			term(search)
+		}
+		if search.empty() {
+			continue
+		}
+
+		items, err := c.runQuerySearch(ctx, search, maxResults, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if !search.matches(&item) {
+				continue
+			}
+			id := ""
+			if item.LawInfo != nil {
+				id = item.LawInfo.LawId
+			}
+			if id != "" && seen[id] {
+				continue
+			}
+			seen[id] = true
+			results = append(results, item)
+		}
+	}
+	SortLawItems(results)
+	return results, nil
+}
+
+// SortLawItems sorts law entries by law ID, then by revision ID. Entries without law
+// information come last. The sort is stable, so the order is fully deterministic.
+func SortLawItems(items []LawItem) {
+	key := func(item LawItem) (string, string) {
+		var lawID, revisionID string
+		if item.LawInfo != nil {
+			lawID = item.LawInfo.LawId
+		}
+		if item.RevisionInfo != nil {
+			revisionID = item.RevisionInfo.LawRevisionId
+		}
+		return lawID, revisionID
+	}
+	slices.SortStableFunc(items, func(a, b LawItem) int {
+		aID, aRev := key(a)
+		bID, bRev := key(b)
+		if (aID == "") != (bID == "") {
+			if aID == "" {
+				return 1
+			}
+			return -1
+		}
+		if c := strings.Compare(aID, bID); c != 0 {
+			return c
+		}
+		return strings.Compare(aRev, bRev)
+	})
+}
+
+func (c *Client) runQuerySearch(ctx context.Context, search *querySearch, maxResults int, pageSize int32) ([]LawItem, error) {
+	var items []LawItem
+	offset := int32(0)
+	for len(items) < maxResults {
+		if len(search.keywords) > 0 {
+			params := &GetKeywordParams{
+				Keyword:              strings.Join(search.keywords, " "),
+				PromulgationDateFrom: search.from,
+				PromulgationDateTo:   search.to,
+				Limit:                Int32Ptr(pageSize),
+				Offset:               Int32Ptr(offset),
+			}
+			if search.categories != nil {
+				params.CategoryCd = &search.categories
+			}
+			if search.lawTypes != nil {
+				params.LawType = &search.lawTypes
+			}
+			resp, err := c.GetKeywordWithContext(ctx, params)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range resp.Items {
+				items = append(items, LawItem{LawInfo: item.LawInfo, RevisionInfo: item.RevisionInfo})
+			}
+			if resp.NextOffset <= 0 || len(resp.Items) == 0 {
+				break
+			}
+			offset = int32(resp.NextOffset)
+		} else {
+			params := &GetLawsParams{
+				PromulgationDateFrom: search.from,
+				PromulgationDateTo:   search.to,
+				Limit:                Int32Ptr(pageSize),
+				Offset:               Int32Ptr(offset),
+			}
+			if len(search.titles) > 0 {
+				params.LawTitle = StringPtr(search.titles[0])
+			}
+			if search.categories != nil {
+				params.CategoryCd = &search.categories
+			}
+			if search.lawTypes != nil {
+				params.LawType = &search.lawTypes
+			}
+			resp, err := c.GetLawsWithContext(ctx, params)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, resp.Laws...)
+			if resp.NextOffset <= 0 || len(resp.Laws) == 0 {
+				break
+			}
+			offset = int32(resp.NextOffset)
+		}
+	}
+	if len(items) > maxResults {
+		items = items[:maxResults]
+	}
+	return items, nil
+}
@@ -0,0 +1,209 @@
+package lawapi
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testLawItem(lawID, revisionID, title string) LawItem {
+	item := LawItem{}
+	if lawID != "" {
+		item.LawInfo = &LawInfo{LawId: lawID}
+	}
+	if revisionID != "" || title != "" {
+		item.RevisionInfo = &RevisionInfo{LawRevisionId: revisionID, LawTitle: title}
+	}
+	return item
+}
+
+// lawItemKeys returns the law ID, revision ID and title of each item, for comparing orders
+func lawItemKeys(items []LawItem) []string {
+	keys := []string{}
+	for _, item := range items {
+		var lawID, revisionID, title string
+		if item.LawInfo != nil {
+			lawID = item.LawInfo.LawId
+		}
+		if item.RevisionInfo != nil {
+			revisionID, title = item.RevisionInfo.LawRevisionId, item.RevisionInfo.LawTitle
+		}
+		keys = append(keys, lawID+"/"+revisionID+"/"+title)
+	}
+	return keys
+}
+
+func TestSortLawItems(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []LawItem
+		want  []string
+	}{
+		{
+			name:  "empty",
+			items: nil,
+			want:  []string{},
+		},
+		{
+			name: "by law ID",
+			items: []LawItem{
+				testLawItem("B", "", ""),
+				testLawItem("C", "", ""),
+				testLawItem("A", "", ""),
+			},
+			want: []string{"A//", "B//", "C//"},
+		},
+		{
+			name: "by revision ID within a law",
+			items: []LawItem{
+				testLawItem("A", "A_2", ""),
+				testLawItem("B", "B_1", ""),
+				testLawItem("A", "A_1", ""),
+				testLawItem("A", "", ""),
+			},
+			want: []string{"A//", "A/A_1/", "A/A_2/", "B/B_1/"},
+		},
+		{
+			name: "without law information last",
+			items: []LawItem{
+				testLawItem("", "", "untitled"),
+				testLawItem("B", "", ""),
+				testLawItem("", "X_1", ""),
+				testLawItem("A", "", ""),
+			},
+			want: []string{"A//", "B//", "//untitled", "/X_1/"},
+		},
+		{
+			name: "stable for equal keys",
+			items: []LawItem{
+				testLawItem("A", "A_1", "second"),
+				testLawItem("A", "A_1", "first"),
+				testLawItem("", "", "third"),
+				testLawItem("", "", "fourth"),
+			},
+			want: []string{"A/A_1/second", "A/A_1/first", "//third", "//fourth"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := slices.Clone(tt.items)
+			SortLawItems(items)
+			if got := lawItemKeys(items); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSortLawItemsPermutations sorts shuffles of the same entries with distinct keys and
+// expects a single order
+func TestSortLawItemsPermutations(t *testing.T) {
+	items := []LawItem{
+		testLawItem("A", "A_1", ""),
+		testLawItem("A", "A_2", ""),
+		testLawItem("B", "", ""),
+		testLawItem("C", "C_1", ""),
+		testLawItem("", "Z_1", ""),
+	}
+	want := lawItemKeys(items)
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 50; i++ {
+		shuffled := slices.Clone(items)
+		r.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		SortLawItems(shuffled)
+		if got := lawItemKeys(shuffled); !slices.Equal(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+// newShuffledLawsServer serves /laws from laws in the order of a permutation, filtered by
+// law_title and paged by limit and offset
+func newShuffledLawsServer(t *testing.T, laws []LawItem, seed uint64) *httptest.Server {
+	shuffled := slices.Clone(laws)
+	r := rand.New(rand.NewPCG(seed, seed))
+	r.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/laws" {
+			http.NotFound(w, r)
+			return
+		}
+		query := r.URL.Query()
+		var matched []LawItem
+		for _, item := range shuffled {
+			if strings.Contains(item.RevisionInfo.LawTitle, query.Get("law_title")) {
+				matched = append(matched, item)
+			}
+		}
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		end := min(offset+limit, len(matched))
+		resp := LawsResponse{Count: int64(end - offset), TotalCount: int64(len(matched)), Laws: matched[offset:end]}
+		if end < len(matched) {
+			resp.NextOffset = int64(end)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestSearchDeterministic runs the same query against servers returning the same laws in
+// different orders and expects identical results
+func TestSearchDeterministic(t *testing.T) {
+	laws := []LawItem{
+		testLawItem("405AC0000000088", "405AC0000000088_1", "行政手続法"),
+		testLawItem("415AC0000000057", "415AC0000000057_1", "個人情報の保護に関する法律"),
+		testLawItem("325AC0000000131", "325AC0000000131_1", "電波法"),
+		testLawItem("411AC0000000127", "411AC0000000127_1", "国旗及び国歌に関する法律"),
+		testLawItem("347AC0000000057", "347AC0000000057_1", "労働安全衛生法"),
+		testLawItem("322AC0000000049", "322AC0000000049_1", "労働基準法"),
+	}
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{
+			name:  "single call",
+			query: TitleContains("労働"),
+			want:  []string{"322AC0000000049", "347AC0000000057"},
+		},
+		{
+			name:  "overlapping disjuncts",
+			query: Or(TitleContains("法律"), TitleContains("個人情報"), TitleContains("電波")),
+			want:  []string{"325AC0000000131", "411AC0000000127", "415AC0000000057"},
+		},
+		{
+			name:  "conjunction filtered client-side",
+			query: And(TitleContains("法"), TitleContains("手続")),
+			want:  []string{"405AC0000000088"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := uint64(1); seed <= 5; seed++ {
+				client := NewClient()
+				client.SetBaseURL(newShuffledLawsServer(t, laws, seed).URL)
+				items, err := client.Search(context.Background(), tt.query, &SearchOptions{PageSize: 2})
+				if err != nil {
+					t.Fatal(err)
+				}
+				var got []string
+				for _, item := range items {
+					got = append(got, item.LawInfo.LawId)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("seed %d: got %v, want %v", seed, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Package rediscache provides a Redis-backed implementation of lawapi.Cache,
+// so horizontally scaled services can share one law data cache.
+package rediscache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	lawapi "go.ngs.io/jplaw-api-v2"
+)
+
+var _ lawapi.Cache = (*Cache)(nil)
+
+// Options configures a Cache
+type Options struct {
+	// Prefix is prepended to every key (default: "jplaw:")
+	Prefix string
+	// TTL is the time-to-live of stored entries; 0 stores entries without expiration
+	TTL time.Duration
+	// Shards is the number of hash tags keys are spread over, so entries are
+	// distributed across the slots of a Redis Cluster (default: 16)
+	Shards int
+	// CompressThreshold is the payload size in bytes above which values are
+	// gzip-compressed (default: 8192); a negative value disables compression
+	CompressThreshold int
+	// Timeout bounds each Redis command (default: 2 seconds)
+	Timeout time.Duration
+	// OnError is called when a Redis command fails; failed reads are treated as cache misses
+	OnError func(err error)
+}
+
+// Cache is a Redis-backed implementation of lawapi.Cache
+type Cache struct {
+	client redis.UniversalClient
+	opts   Options
+}
+
+const (
+	flagRaw  byte = 0
+	flagGzip byte = 1
+)
+
+// New creates a cache storing entries in Redis through the given client
+func New(client redis.UniversalClient, opts Options) *Cache {
+	if opts.Prefix == "" {
+		opts.Prefix = "jplaw:"
+	}
+	if opts.Shards <= 0 {
+		opts.Shards = 16
+	}
+	if opts.CompressThreshold == 0 {
+		opts.CompressThreshold = 8192
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Second
+	}
+	return &Cache{client: client, opts: opts}
+}
+
+// Get implements lawapi.Cache
+func (c *Cache) Get(key string) ([]byte, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.Timeout)
+	defer cancel()
+
+	data, err := c.client.Get(ctx, c.redisKey(key)).Bytes()
+	if err == redis.Nil {
+		return nil, false
+	}
+	if err != nil {
+		c.reportError(fmt.Errorf("failed to get %s: %w", key, err))
+		return nil, false
+	}
+	value, err := decode(data)
+	if err != nil {
+		c.reportError(fmt.Errorf("failed to decode %s: %w", key, err))
+		return nil, false
+	}
+	return value, true
+}
+
+// Set implements lawapi.Cache
+func (c *Cache) Set(key string, value []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.Timeout)
+	defer cancel()
+
+	data, err := c.encode(value)
+	if err != nil {
+		c.reportError(fmt.Errorf("failed to encode %s: %w", key, err))
+		return
+	}
+	if err := c.client.Set(ctx, c.redisKey(key), data, c.opts.TTL).Err(); err != nil {
+		c.reportError(fmt.Errorf("failed to set %s: %w", key, err))
+	}
+}
+
+// Delete implements lawapi.Cache
+func (c *Cache) Delete(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.Timeout)
+	defer cancel()
+
+	if err := c.client.Del(ctx, c.redisKey(key)).Err(); err != nil {
+		c.reportError(fmt.Errorf("failed to delete %s: %w", key, err))
+	}
+}
+
+// DeletePrefix implements lawapi.Cache.
+// Matching keys are found with SCAN on every master node.
+func (c *Cache) DeletePrefix(prefix string) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.Timeout)
+	defer cancel()
+
+	pattern := escapePattern(c.opts.Prefix) + "{*}:" + escapePattern(prefix) + "*"
+	scan := func(ctx context.Context, client redis.Cmdable) error {
+		iter := client.Scan(ctx, 0, pattern, 100).Iterator()
+		for iter.Next(ctx) {
+			if err := client.Del(ctx, iter.Val()).Err(); err != nil {
+				return err
+			}
+		}
+		return iter.Err()
+	}
+
+	var err error
+	if cluster, ok := c.client.(*redis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return scan(ctx, client)
+		})
+	} else {
+		err = scan(ctx, c.client)
+	}
+	if err != nil {
+		c.reportError(fmt.Errorf("failed to delete prefix %s: %w", prefix, err))
+	}
+}
+
+// redisKey returns the Redis key of a cache key, including its shard hash tag
+func (c *Cache) redisKey(key string) string {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	shard := h.Sum32() % uint32(c.opts.Shards)
+	return fmt.Sprintf("%s{%d}:%s", c.opts.Prefix, shard, key)
+}
+
+func (c *Cache) encode(value []byte) ([]byte, error) {
+	if c.opts.CompressThreshold < 0 || len(value) <= c.opts.CompressThreshold {
+		return append([]byte{flagRaw}, value...), nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(flagGzip)
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(value); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decode(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty value")
+	}
+	switch data[0] {
+	case flagRaw:
+		return data[1:], nil
+	case flagGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(data[1:]))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	default:
+		return nil, fmt.Errorf("unknown encoding flag %d", data[0])
+	}
+}
+
+func (c *Cache) reportError(err error) {
+	if c.opts.OnError != nil {
+		c.opts.OnError(err)
+	}
+}
+
+// escapePattern escapes glob characters for use in a SCAN MATCH pattern
+func escapePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`).Replace(s)
+}
@@ -0,0 +1,15 @@
+module go.ngs.io/jplaw-api-v2/rediscache
+
+go 1.23.12
+
+require (
+	github.com/redis/go-redis/v9 v9.9.0
+	go.ngs.io/jplaw-api-v2 v0.0.0-20261016102908-3b2641761358
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	golang.org/x/text v0.25.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
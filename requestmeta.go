@@ -0,0 +1,148 @@
+package lawapi
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type jobIDKey struct{}
+
+type tenantKey struct{}
+
+// WithJobID returns a context attributing the requests made with it to a job
+func WithJobID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, jobIDKey{}, id)
+}
+
+// WithTenant returns a context attributing the requests made with it to a tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// RequestMetadata is the attribution of a request, carried by its context
+type RequestMetadata struct {
+	// JobID is set by WithJobID, or empty
+	JobID string
+	// Tenant is set by WithTenant, or empty
+	Tenant string
+	// Priority is set by WithPriority, or PriorityNormal
+	Priority Priority
+	// CorrelationID identifies the call the request belongs to, the same for all its
+	// attempts (see WithCorrelationID), or empty
+	CorrelationID string
+}
+
+// MetadataFromContext returns the request metadata carried by ctx
+func MetadataFromContext(ctx context.Context) RequestMetadata {
+	md := RequestMetadata{Priority: PriorityFromContext(ctx)}
+	md.JobID, _ = ctx.Value(jobIDKey{}).(string)
+	md.Tenant, _ = ctx.Value(tenantKey{}).(string)
+	md.CorrelationID = CorrelationIDFromContext(ctx)
+	return md
+}
+
+// RequestEvent describes a finished API request
+type RequestEvent struct {
+	// Metadata is the metadata of the request context
+	Metadata RequestMetadata
+	// Method is the HTTP method
+	Method string
+	// Endpoint is the first path segment below the API root (e.g. "law_data"), without IDs
+	Endpoint string
+	// URL is the full request URL
+	URL string
+	// StatusCode is the response status, or 0 when the request failed
+	StatusCode int
+	// Duration is the time until the response headers were received
+	Duration time.Duration
+	// Err is the transport error, or nil
+	Err error
+}
+
+// RequestHook receives an event for every request, e.g. to log it or record metrics per job
+type RequestHook func(event RequestEvent)
+
+// HookTransport returns an http.RoundTripper calling hooks after each request sent through
+// base (default: http.DefaultTransport). Use it with Client.SetHTTPClient. Durations are
+// measured on DefaultClock.
+func HookTransport(base http.RoundTripper, hooks ...RequestHook) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &hookTransport{base: base, hooks: hooks}
+}
+
+type hookTransport struct {
+	base  http.RoundTripper
+	hooks []RequestHook
+}
+
+func (t *hookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clock := DefaultClock
+	start := clock.Now()
+	resp, err := t.base.RoundTrip(req)
+	event := RequestEvent{
+		Metadata: requestMetadata(req),
+		Method:   req.Method,
+		Endpoint: requestEndpoint(req),
+		URL:      req.URL.String(),
+		Duration: clock.Now().Sub(start),
+		Err:      err,
+	}
+	if resp != nil {
+		event.StatusCode = resp.StatusCode
+	}
+	for _, hook := range t.hooks {
+		hook(event)
+	}
+	return resp, err
+}
+
+// requestMetadata returns the metadata of a request, with the correlation ID of its
+// header if its context has none
+func requestMetadata(req *http.Request) RequestMetadata {
+	md := MetadataFromContext(req.Context())
+	if md.CorrelationID == "" {
+		md.CorrelationID = req.Header.Get(CorrelationIDHeader)
+	}
+	return md
+}
+
+// requestEndpoint returns the first path segment below the API root
+func requestEndpoint(req *http.Request) string {
+	path := strings.TrimPrefix(req.URL.Path, "/")
+	path = strings.TrimPrefix(path, "api/2/")
+	endpoint, _, _ := strings.Cut(path, "/")
+	return endpoint
+}
+
+// LogRequests returns a hook logging each request with its job, tenant and correlation ID
+// to logger (log.Default() if nil)
+func LogRequests(logger *log.Logger) RequestHook {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(event RequestEvent) {
+		var sb strings.Builder
+		sb.WriteString("lawapi:")
+		if event.Metadata.JobID != "" {
+			sb.WriteString(" job=" + event.Metadata.JobID)
+		}
+		if event.Metadata.Tenant != "" {
+			sb.WriteString(" tenant=" + event.Metadata.Tenant)
+		}
+		sb.WriteString(" priority=" + event.Metadata.Priority.String())
+		if event.Metadata.CorrelationID != "" {
+			sb.WriteString(" id=" + event.Metadata.CorrelationID)
+		}
+		sb.WriteString(" " + event.Method + " " + event.URL)
+		if event.Err != nil {
+			logger.Printf("%s failed after %s: %v", sb.String(), event.Duration.Round(time.Millisecond), event.Err)
+			return
+		}
+		logger.Printf("%s %d %s", sb.String(), event.StatusCode, event.Duration.Round(time.Millisecond))
+	}
+}
@@ -0,0 +1,321 @@
+package lawapi
+
+import (
+	"html"
+	"slices"
+	"strings"
+)
+
+// HTMLPolicy lists the markup that Sanitize keeps, so that rendered documents and keyword
+// snippets can be embedded in applications without passing on unexpected markup from the
+// source data. Elements that are not allowed are removed but their text is kept, except for
+// elements whose content is code or raw text (script, style, iframe, ...), which are
+// removed with their content. Comments are removed, text and attribute values are
+// re-escaped, and unclosed elements are closed.
+type HTMLPolicy struct {
+	// Elements maps the allowed element names to their allowed attribute names. Event
+	// handler attributes (on*) are never allowed.
+	Elements map[string][]string
+	// URLSchemes are the schemes allowed in URL attributes such as href and src besides
+	// relative URLs (default http, https and mailto)
+	URLSchemes []string
+}
+
+// HighlightPolicy allows the highlight tags of keyword snippets (see HighlightTag) without
+// attributes
+var HighlightPolicy = &HTMLPolicy{Elements: map[string][]string{
+	"em": nil, "mark": nil, "span": nil, "strong": nil, "b": nil, "i": nil, "u": nil,
+}}
+
+// DocumentPolicy allows the markup of standalone documents written by this package and
+// the jplaw commands: document structure, headings, paragraphs, tables, inline formatting,
+// style sheets and class and style attributes
+var DocumentPolicy = &HTMLPolicy{Elements: map[string][]string{
+	"html": {"lang"}, "head": nil, "meta": {"charset", "name", "content"}, "title": nil, "style": nil,
+	"body": nil, "header": {"class"}, "footer": {"class"}, "section": {"class", "id"}, "div": {"class", "id"},
+	"h1": {"class", "id"}, "h2": {"class", "id"}, "h3": {"class", "id"}, "h4": {"class", "id"}, "h5": {"class", "id"}, "h6": {"class", "id"},
+	"p": {"class", "style"}, "span": {"class"}, "a": {"href", "id", "class"}, "br": nil,
+	"table": {"class"}, "thead": nil, "tbody": nil, "tr": {"class"}, "th": {"class", "colspan", "rowspan"}, "td": {"class", "colspan", "rowspan"},
+	"ol": nil, "ul": nil, "li": nil, "em": nil, "strong": nil, "b": nil, "i": nil, "u": nil, "mark": nil,
+	"sub": nil, "sup": nil, "ruby": nil, "rt": nil, "rp": nil,
+}}
+
+// HTMLOutputPolicy is the policy with which WriteLawHTML and the jplaw compare command
+// sanitize their output. Set it to nil to write the output unsanitized.
+var HTMLOutputPolicy = DocumentPolicy
+
+// rawTextElements are elements whose content is not markup and is removed with them
+var rawTextElements = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true, "xmp": true, "iframe": true,
+	"noembed": true, "noframes": true, "noscript": true, "template": true, "plaintext": true,
+}
+
+// voidElements are elements without content or end tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// urlAttributes are attributes whose values are URLs
+var urlAttributes = map[string]bool{
+	"href": true, "src": true, "cite": true, "action": true, "formaction": true, "poster": true, "background": true,
+}
+
+// Sanitize returns s with the markup the policy does not allow removed
+func (p *HTMLPolicy) Sanitize(s string) string {
+	var sb strings.Builder
+	var open []string
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			sb.WriteString(escapeHTMLText(s))
+			break
+		}
+		sb.WriteString(escapeHTMLText(s[:i]))
+		s = s[i:]
+		switch {
+		case strings.HasPrefix(s, "<!--"):
+			if end := strings.Index(s[4:], "-->"); end >= 0 {
+				s = s[4+end+3:]
+			} else {
+				s = ""
+			}
+		case strings.HasPrefix(s, "<!"), strings.HasPrefix(s, "<?"):
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				s = ""
+				continue
+			}
+			if strings.EqualFold(strings.TrimSpace(s[2:end]), "doctype html") && p.allows("html") {
+				sb.WriteString("<!DOCTYPE html>")
+			}
+			s = s[end+1:]
+		default:
+			tag, rest, ok := parseHTMLTag(s)
+			if !ok {
+				sb.WriteString("&lt;")
+				s = s[1:]
+				continue
+			}
+			s = rest
+			switch {
+			case tag.closing:
+				if !p.allows(tag.name) {
+					continue
+				}
+				if i := slices.Index(open, tag.name); i >= 0 {
+					for j := len(open) - 1; j >= i; j-- {
+						sb.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+				}
+			case rawTextElements[tag.name]:
+				content, rest := cutRawText(s, tag.name)
+				s = rest
+				if !p.allows(tag.name) || tag.name == "script" {
+					continue
+				}
+				if tag.name == "style" && !safeCSS(content) {
+					continue
+				}
+				if tag.name != "style" {
+					content = escapeHTMLText(content)
+				}
+				sb.WriteString(p.startTag(tag) + content + "</" + tag.name + ">")
+			case p.allows(tag.name):
+				sb.WriteString(p.startTag(tag))
+				if !voidElements[tag.name] {
+					open = append(open, tag.name)
+				}
+			}
+		}
+	}
+	for j := len(open) - 1; j >= 0; j-- {
+		sb.WriteString("</" + open[j] + ">")
+	}
+	return sb.String()
+}
+
+// allows reports whether the policy allows an element
+func (p *HTMLPolicy) allows(name string) bool {
+	_, ok := p.Elements[name]
+	return ok
+}
+
+// startTag writes a start tag with the allowed attributes
+func (p *HTMLPolicy) startTag(tag htmlTag) string {
+	var sb strings.Builder
+	sb.WriteString("<" + tag.name)
+	var written []string
+	for _, attr := range tag.attrs {
+		if strings.HasPrefix(attr.name, "on") || slices.Contains(written, attr.name) || !slices.Contains(p.Elements[tag.name], attr.name) {
+			continue
+		}
+		if urlAttributes[attr.name] && !p.allowsURL(attr.value) || attr.name == "style" && !safeCSS(attr.value) {
+			continue
+		}
+		written = append(written, attr.name)
+		sb.WriteString(" " + attr.name + `="` + html.EscapeString(attr.value) + `"`)
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
+
+// allowsURL reports whether a URL is relative or has an allowed scheme
+func (p *HTMLPolicy) allowsURL(u string) bool {
+	// Browsers ignore whitespace and control characters in schemes
+	u = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, u)
+	colon := strings.IndexByte(u, ':')
+	if colon < 0 || strings.ContainsAny(u[:colon], "/?#") {
+		return true
+	}
+	schemes := p.URLSchemes
+	if schemes == nil {
+		schemes = []string{"http", "https", "mailto"}
+	}
+	return slices.Contains(schemes, strings.ToLower(u[:colon]))
+}
+
+// safeCSS reports whether a style sheet or style attribute has no constructs that load
+// resources or run code
+func safeCSS(css string) bool {
+	css = strings.ToLower(css)
+	for _, unsafe := range []string{"url(", "image(", "image-set(", "expression", "javascript:", "@import", "behavior", "-moz-binding", "\\", "<"} {
+		if strings.Contains(css, unsafe) {
+			return false
+		}
+	}
+	return true
+}
+
+// escapeHTMLText re-escapes text, decoding the character references it contains first
+func escapeHTMLText(s string) string {
+	return html.EscapeString(html.UnescapeString(s))
+}
+
+// htmlTag is a start or end tag
+type htmlTag struct {
+	name    string
+	closing bool
+	attrs   []htmlAttr
+}
+
+type htmlAttr struct {
+	name, value string
+}
+
+// parseHTMLTag parses the tag at the start of s and returns the rest of s. It fails if the
+// "<" does not start a tag, which is then text. A tag that is not terminated extends to
+// the end of s.
+func parseHTMLTag(s string) (htmlTag, string, bool) {
+	var tag htmlTag
+	i := 1
+	if i < len(s) && s[i] == '/' {
+		tag.closing = true
+		i++
+	}
+	if i >= len(s) || !isASCIILetter(s[i]) {
+		return tag, s, false
+	}
+	start := i
+	for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '/' && s[i] != '>' {
+		i++
+	}
+	tag.name = strings.ToLower(s[start:i])
+	for i < len(s) {
+		switch {
+		case isHTMLSpace(s[i]) || s[i] == '/':
+			i++
+		case s[i] == '>':
+			return tag, s[i+1:], true
+		default:
+			start := i
+			for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '=' && s[i] != '>' && (s[i] != '/' || i == start) {
+				i++
+			}
+			attr := htmlAttr{name: strings.ToLower(s[start:i])}
+			for i < len(s) && isHTMLSpace(s[i]) {
+				i++
+			}
+			if i < len(s) && s[i] == '=' {
+				i++
+				for i < len(s) && isHTMLSpace(s[i]) {
+					i++
+				}
+				if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+					end := strings.IndexByte(s[i+1:], s[i])
+					if end < 0 {
+						return tag, "", true
+					}
+					attr.value = s[i+1 : i+1+end]
+					i += end + 2
+				} else {
+					start := i
+					for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '>' {
+						i++
+					}
+					attr.value = s[start:i]
+				}
+				attr.value = html.UnescapeString(attr.value)
+			}
+			if !tag.closing {
+				tag.attrs = append(tag.attrs, attr)
+			}
+		}
+	}
+	return tag, "", true
+}
+
+// cutRawText returns the content of a raw text element up to its end tag, and the rest of
+// s after the end tag
+func cutRawText(s, name string) (string, string) {
+	lower := strings.ToLower(s)
+	for i := 0; ; {
+		j := strings.Index(lower[i:], "</"+name)
+		if j < 0 {
+			return s, ""
+		}
+		j += i
+		end := j + 2 + len(name)
+		if end == len(s) || isHTMLSpace(s[end]) || s[end] == '>' || s[end] == '/' {
+			rest := ""
+			if k := strings.IndexByte(s[end:], '>'); k >= 0 {
+				rest = s[end+k+1:]
+			}
+			return s[:j], rest
+		}
+		i = end
+	}
+}
+
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// SafeHTML returns the text of the sentence, which contains the highlight tags of the
+// keyword search, sanitized with HighlightPolicy
+func (s KeywordSentence) SafeHTML() string {
+	return HighlightPolicy.Sanitize(s.Text)
+}
+
+// Sanitize sanitizes the text of all sentences in the response with a policy (default
+// HighlightPolicy), so that the response can be passed on to clients that embed it
+func (r *KeywordResponse) Sanitize(policy *HTMLPolicy) {
+	if policy == nil {
+		policy = HighlightPolicy
+	}
+	for i := range r.Items {
+		for j := range r.Items[i].Sentences {
+			r.Items[i].Sentences[j].Text = policy.Sanitize(r.Items[i].Sentences[j].Text)
+		}
+	}
+}
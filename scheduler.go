@@ -0,0 +1,198 @@
+package lawapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Priority is the scheduling priority of a request
+type Priority int
+
+const (
+	// PriorityLow is for bulk jobs such as mirroring
+	PriorityLow Priority = iota
+	// PriorityNormal is the default priority
+	PriorityNormal
+	// PriorityHigh is for interactive requests such as searches
+	PriorityHigh
+)
+
+// String returns the name of the priority
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	}
+	return "normal"
+}
+
+type priorityKey struct{}
+
+// WithPriority returns a context scheduling the requests made with it at priority p
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority set by WithPriority, or PriorityNormal
+func PriorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok && p >= PriorityLow && p <= PriorityHigh {
+		return p
+	}
+	return PriorityNormal
+}
+
+// SchedulerOptions configures a Scheduler
+type SchedulerOptions struct {
+	// Concurrency is the maximum number of requests in flight (default: DefaultBatchConcurrency)
+	Concurrency int
+	// Weights are the relative shares of each priority when requests wait
+	// (default: 8 for PriorityHigh, 4 for PriorityNormal, 1 for PriorityLow)
+	Weights map[Priority]int
+}
+
+// Scheduler shares a limited number of concurrent requests between jobs using one Client.
+// When requests wait, slots are handed out by weighted fair queueing over the priorities
+// set with WithPriority, so interactive requests are not starved behind bulk jobs while bulk
+// jobs still make progress.
+type Scheduler struct {
+	concurrency int
+	weights     [3]float64
+
+	mu       sync.Mutex
+	inFlight int
+	queues   [3][]chan struct{}
+	// pass is the virtual time of each priority, advanced by 1/weight per granted request
+	pass  [3]float64
+	clock float64
+}
+
+// NewScheduler creates a scheduler
+func NewScheduler(opts SchedulerOptions) *Scheduler {
+	s := &Scheduler{concurrency: opts.Concurrency, weights: [3]float64{1, 4, 8}}
+	if s.concurrency <= 0 {
+		s.concurrency = DefaultBatchConcurrency
+	}
+	for p, weight := range opts.Weights {
+		if p >= PriorityLow && p <= PriorityHigh && weight > 0 {
+			s.weights[p] = float64(weight)
+		}
+	}
+	return s
+}
+
+// Acquire waits for a request slot at the priority of ctx. The returned function releases
+// the slot and must be called exactly once.
+func (s *Scheduler) Acquire(ctx context.Context) (release func(), err error) {
+	p := PriorityFromContext(ctx)
+	s.mu.Lock()
+	if s.inFlight < s.concurrency && s.waiting() == 0 {
+		s.inFlight++
+		s.mu.Unlock()
+		return s.releaseFunc(), nil
+	}
+	if len(s.queues[p]) == 0 {
+		// An idle priority does not accumulate credit while it is idle
+		s.pass[p] = max(s.pass[p], s.clock)
+	}
+	ready := make(chan struct{})
+	s.queues[p] = append(s.queues[p], ready)
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return s.releaseFunc(), nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for i, waiter := range s.queues[p] {
+			if waiter == ready {
+				s.queues[p] = append(s.queues[p][:i], s.queues[p][i+1:]...)
+				s.mu.Unlock()
+				return nil, ctx.Err()
+			}
+		}
+		s.mu.Unlock()
+		// The slot was handed over concurrently; pass it on
+		s.release()
+		return nil, ctx.Err()
+	}
+}
+
+// Transport returns an http.RoundTripper scheduling the requests sent through base
+// (default: http.DefaultTransport). A slot is held until the response body is closed.
+// Use it with Client.SetHTTPClient.
+func (s *Scheduler) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &schedulerTransport{scheduler: s, base: base}
+}
+
+type schedulerTransport struct {
+	scheduler *Scheduler
+	base      http.RoundTripper
+}
+
+func (t *schedulerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.scheduler.Acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody releases a scheduler slot when the response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+func (s *Scheduler) releaseFunc() func() {
+	var once sync.Once
+	return func() { once.Do(s.release) }
+}
+
+// release hands the slot to the waiting priority with the smallest virtual time, or frees it
+func (s *Scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := -1
+	for p := range s.queues {
+		if len(s.queues[p]) > 0 && (next < 0 || s.pass[p] < s.pass[next] || s.pass[p] == s.pass[next] && p > next) {
+			next = p
+		}
+	}
+	if next < 0 {
+		s.inFlight--
+		return
+	}
+	ready := s.queues[next][0]
+	s.queues[next] = s.queues[next][1:]
+	s.clock = s.pass[next]
+	s.pass[next] += 1 / s.weights[next]
+	close(ready)
+}
+
+func (s *Scheduler) waiting() int {
+	total := 0
+	for _, queue := range s.queues {
+		total += len(queue)
+	}
+	return total
+}
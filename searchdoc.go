@@ -0,0 +1,219 @@
+package lawapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SearchDocument is a law, article or paragraph flattened for external search engines
+type SearchDocument struct {
+	// ID is a stable document ID: the law ID for law documents and the ProvisionID for
+	// articles and paragraphs
+	ID string `json:"id"`
+	// Kind is "law" for whole laws, "article" for articles and "paragraph" for paragraphs
+	// outside articles
+	Kind string `json:"kind"`
+	// LawID is the law ID
+	LawID string `json:"law_id"`
+	// LawRevisionID is the revision the text was taken from
+	LawRevisionID string `json:"law_revision_id,omitempty"`
+	// LawNum is the law number (e.g. 昭和二十五年法律第百三十一号)
+	LawNum string `json:"law_num,omitempty"`
+	// LawTitle is the law title
+	LawTitle string `json:"law_title"`
+	// LawTitleKana is the reading of the law title
+	LawTitleKana string `json:"law_title_kana,omitempty"`
+	// LawType is the law type (Act, CabinetOrder, ...)
+	LawType string `json:"law_type,omitempty"`
+	// Category is the category name of the law
+	Category string `json:"category,omitempty"`
+	// Authorities are the issuing authorities inferred by InferAuthorities
+	Authorities []string `json:"authorities,omitempty"`
+	// Tags are topics assigned by TopicRules
+	Tags []string `json:"tags,omitempty"`
+	// PromulgationDate is the promulgation date in YYYY-MM-DD format
+	PromulgationDate string `json:"promulgation_date,omitempty"`
+	// Provision is the citation of the article or paragraph (e.g. 第二条), empty for law
+	// documents
+	Provision string `json:"provision,omitempty"`
+	// ArticleCaption is the caption of the article (e.g. （定義）), empty for law documents
+	ArticleCaption string `json:"article_caption,omitempty"`
+	// Text is the plain text of the law, or the sentences of the article or paragraph
+	Text string `json:"text"`
+	// ContentHash is a hash of the other fields except LawRevisionID, for detecting changed
+	// documents
+	ContentHash string `json:"content_hash"`
+}
+
+// Hash computes the content hash of the document over all fields except ContentHash and
+// LawRevisionID, so that documents a new revision leaves unchanged keep their hashes
+func (d SearchDocument) Hash() string {
+	d.ContentHash = ""
+	d.LawRevisionID = ""
+	data, _ := json.Marshal(d)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// NewSearchDocuments flattens a law into one law document followed by one document per
+// article of the main and supplementary provisions, in document order. Laws and
+// supplementary provisions without articles have a document per paragraph instead.
+func NewSearchDocuments(data *LawDataResponse, law *LawNode) []SearchDocument {
+	base := SearchDocument{Kind: "law", LawTitle: law.Title()}
+	if info := data.LawInfo; info != nil {
+		base.LawID = info.LawId
+		base.LawNum = info.LawNum
+		base.Authorities = InferAuthorities(info.LawNum)
+		if info.LawType != nil {
+			base.LawType = string(*info.LawType)
+		}
+		if date := info.PromulgationDate.String(); !strings.HasPrefix(date, "0001-") {
+			base.PromulgationDate = date
+		}
+	}
+	if rev := data.RevisionInfo; rev != nil {
+		base.LawRevisionID = rev.LawRevisionId
+		base.LawTitleKana = rev.LawTitleKana
+		base.Category = rev.Category
+		if rev.LawTitle != "" {
+			base.LawTitle = rev.LawTitle
+		}
+	}
+
+	lawDoc := base
+	lawDoc.ID = base.LawID
+	lawDoc.Text = strings.TrimSpace(law.Find("LawBody").PlainText())
+	lawDoc.ContentHash = lawDoc.Hash()
+	docs := []SearchDocument{lawDoc}
+
+	for _, ref := range law.provisionRefs() {
+		doc := base
+		doc.ID = ref.ProvisionID(base.LawID, "").String()
+		doc.Provision = ref.Label()
+		node := ref.Article
+		if node != nil {
+			doc.Kind = "article"
+			doc.ArticleCaption = strings.TrimSpace(node.Child("ArticleCaption").PlainText())
+		} else {
+			doc.Kind = "paragraph"
+			node = ref.Paragraph
+		}
+		var text strings.Builder
+		for _, sentence := range node.FindAll("Sentence") {
+			text.WriteString(sentence.PlainText())
+		}
+		doc.Text = strings.TrimSpace(text.String())
+		doc.ContentHash = doc.Hash()
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+// DocumentSource produces search documents with stable IDs and content hashes, for
+// consumption by any indexer
+type DocumentSource interface {
+	// Documents calls yield for each document, stopping at the first error
+	Documents(ctx context.Context, yield func(doc SearchDocument) error) error
+}
+
+// DocumentSlice is a DocumentSource over documents in memory
+type DocumentSlice []SearchDocument
+
+// Documents implements DocumentSource
+func (s DocumentSlice) Documents(ctx context.Context, yield func(doc SearchDocument) error) error {
+	for _, doc := range s {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := yield(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LawDocumentSource is a DocumentSource retrieving laws from the API and flattening them with NewSearchDocuments
+type LawDocumentSource struct {
+	// Client retrieves the law data
+	Client *Client
+	// LawIDs are the laws to retrieve
+	LawIDs []string
+}
+
+// Documents implements DocumentSource
+func (s *LawDocumentSource) Documents(ctx context.Context, yield func(doc SearchDocument) error) error {
+	for _, lawID := range s.LawIDs {
+		data, err := s.Client.GetLawDataWithContext(ctx, lawID, nil)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve %s: %w", lawID, err)
+		}
+		law, err := ParseLawFullText(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", lawID, err)
+		}
+		for _, doc := range NewSearchDocuments(data, law) {
+			if err := yield(doc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// IndexStats summarizes an IndexDocuments run
+type IndexStats struct {
+	// Pushed is the number of new or changed documents pushed to the indexer
+	Pushed int
+	// Unchanged is the number of documents skipped because their content hash was known
+	Unchanged int
+}
+
+// IndexDocuments pushes the documents of a source to an indexer in batches of batchSize
+// (default: 500). Documents whose content hash equals hashes[ID] are skipped; hashes is
+// updated with the pushed documents and may be persisted between runs. A nil hashes pushes everything.
+func IndexDocuments(ctx context.Context, source DocumentSource, indexer SearchIndexer, hashes map[string]string, batchSize int) (IndexStats, error) {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	var stats IndexStats
+	var batch []SearchDocument
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := indexer.Push(ctx, batch); err != nil {
+			return err
+		}
+		stats.Pushed += len(batch)
+		if hashes != nil {
+			for _, doc := range batch {
+				hashes[doc.ID] = doc.ContentHash
+			}
+		}
+		batch = nil
+		return nil
+	}
+
+	err := source.Documents(ctx, func(doc SearchDocument) error {
+		if doc.ContentHash == "" {
+			doc.ContentHash = doc.Hash()
+		}
+		if hashes != nil && hashes[doc.ID] == doc.ContentHash {
+			stats.Unchanged++
+			return nil
+		}
+		batch = append(batch, doc)
+		if len(batch) >= batchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+	return stats, flush()
+}
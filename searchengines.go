@@ -0,0 +1,191 @@
+package lawapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SearchIndexer pushes documents to a search engine, replacing documents with the same ID
+type SearchIndexer interface {
+	Push(ctx context.Context, docs []SearchDocument) error
+}
+
+// MeilisearchIndexer pushes documents to a Meilisearch index
+type MeilisearchIndexer struct {
+	// BaseURL is the Meilisearch URL (e.g. "http://localhost:7700")
+	BaseURL string
+	// APIKey is the API key, or empty
+	APIKey string
+	// Index is the index UID
+	Index string
+	// HTTPClient is the HTTP client (default: http.DefaultClient)
+	HTTPClient *http.Client
+}
+
+// meilisearchDocument is a SearchDocument with an ID restricted to the characters Meilisearch accepts
+type meilisearchDocument struct {
+	SearchDocument
+	ID string `json:"id"`
+}
+
+// MeilisearchID converts a document ID to the form stored in Meilisearch, which only
+// accepts alphanumerics, hyphens and underscores. Colons are replaced by hyphens, dots by
+// double underscores and the "@" of supplementary provisions by "-at-"; none occurs in
+// law IDs.
+func MeilisearchID(id string) string {
+	return strings.NewReplacer(":", "-", ".", "__", "@", "-at-").Replace(id)
+}
+
+// Configure sets the searchable, filterable and sortable attributes of the index
+func (m *MeilisearchIndexer) Configure(ctx context.Context) error {
+	settings := map[string]interface{}{
+		"searchableAttributes": []string{"law_title", "law_title_kana", "article_caption", "text", "law_num"},
+		"filterableAttributes": []string{"kind", "law_id", "law_type", "category", "authorities", "tags", "promulgation_date"},
+		"sortableAttributes":   []string{"promulgation_date", "law_id"},
+	}
+	_, err := m.do(ctx, "PATCH", "/indexes/"+url.PathEscape(m.Index)+"/settings", "application/json", settings)
+	return err
+}
+
+// Push adds or replaces documents. Meilisearch indexes them asynchronously.
+func (m *MeilisearchIndexer) Push(ctx context.Context, docs []SearchDocument) error {
+	converted := make([]meilisearchDocument, len(docs))
+	for i, doc := range docs {
+		converted[i] = meilisearchDocument{SearchDocument: doc, ID: MeilisearchID(doc.ID)}
+	}
+	_, err := m.do(ctx, "POST", "/indexes/"+url.PathEscape(m.Index)+"/documents?primaryKey=id", "application/json", converted)
+	return err
+}
+
+func (m *MeilisearchIndexer) do(ctx context.Context, method, path, contentType string, body interface{}) ([]byte, error) {
+	header := http.Header{}
+	if m.APIKey != "" {
+		header.Set("Authorization", "Bearer "+m.APIKey)
+	}
+	return doSearchEngineRequest(ctx, m.HTTPClient, method, strings.TrimSuffix(m.BaseURL, "/")+path, contentType, header, body)
+}
+
+// TypesenseIndexer pushes documents to a Typesense collection
+type TypesenseIndexer struct {
+	// BaseURL is the Typesense URL (e.g. "http://localhost:8108")
+	BaseURL string
+	// APIKey is the API key
+	APIKey string
+	// Collection is the collection name
+	Collection string
+	// HTTPClient is the HTTP client (default: http.DefaultClient)
+	HTTPClient *http.Client
+}
+
+// CreateCollection creates the collection with a schema for SearchDocument, using the
+// Japanese locale for text fields and facets on kind, law type, category, authorities and tags
+func (t *TypesenseIndexer) CreateCollection(ctx context.Context) error {
+	field := func(name, typ string, facet, optional bool, locale string) map[string]interface{} {
+		f := map[string]interface{}{"name": name, "type": typ, "facet": facet, "optional": optional}
+		if locale != "" {
+			f["locale"] = locale
+		}
+		return f
+	}
+	schema := map[string]interface{}{
+		"name": t.Collection,
+		"fields": []map[string]interface{}{
+			field("kind", "string", true, false, ""),
+			field("law_id", "string", false, false, ""),
+			field("law_revision_id", "string", false, true, ""),
+			field("law_num", "string", false, true, "ja"),
+			field("law_title", "string", false, false, "ja"),
+			field("law_title_kana", "string", false, true, "ja"),
+			field("law_type", "string", true, true, ""),
+			field("category", "string", true, true, ""),
+			field("authorities", "string[]", true, true, ""),
+			field("tags", "string[]", true, true, ""),
+			field("promulgation_date", "string", false, true, ""),
+			field("provision", "string", false, true, ""),
+			field("article_caption", "string", false, true, "ja"),
+			field("text", "string", false, false, "ja"),
+			field("content_hash", "string", false, false, ""),
+		},
+	}
+	_, err := t.do(ctx, "POST", "/collections", "application/json", schema)
+	return err
+}
+
+// Push upserts documents with the JSONL import API
+func (t *TypesenseIndexer) Push(ctx context.Context, docs []SearchDocument) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, doc := range docs {
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+	}
+	resp, err := t.do(ctx, "POST", "/collections/"+url.PathEscape(t.Collection)+"/documents/import?action=upsert", "text/plain", buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	// The import API reports failures per document with a successful status
+	decoder := json.NewDecoder(bytes.NewReader(resp))
+	for i := 0; ; i++ {
+		var result struct {
+			Success bool   `json:"success"`
+			Error   string `json:"error"`
+		}
+		if err := decoder.Decode(&result); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to decode import result: %w", err)
+		}
+		if !result.Success && i < len(docs) {
+			return fmt.Errorf("failed to import %s: %s", docs[i].ID, result.Error)
+		}
+	}
+}
+
+func (t *TypesenseIndexer) do(ctx context.Context, method, path, contentType string, body interface{}) ([]byte, error) {
+	header := http.Header{}
+	header.Set("X-TYPESENSE-API-KEY", t.APIKey)
+	return doSearchEngineRequest(ctx, t.HTTPClient, method, strings.TrimSuffix(t.BaseURL, "/")+path, contentType, header, body)
+}
+
+// doSearchEngineRequest sends body (JSON-encoded unless it is a []byte), checks the response status and returns the response body
+func doSearchEngineRequest(ctx context.Context, client *http.Client, method, rawURL, contentType string, header http.Header, body interface{}) ([]byte, error) {
+	data, ok := body.([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", contentType)
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	return respBody, nil
+}
@@ -0,0 +1,122 @@
+package lawapi
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed spec/v2/lawapi-v2.yaml
+var specYAML []byte
+
+// SpecInfo is metadata of the OpenAPI specification the client was generated from
+type SpecInfo struct {
+	// Title is the API title
+	Title string
+	// Version is the API version (e.g. "2.1.138")
+	Version string
+	// BaseURL is the first server URL of the specification
+	BaseURL string
+	// Endpoints lists the operations sorted by path and method
+	Endpoints []SpecEndpoint
+	// Enums maps generated enum type names (e.g. "LawType") to their values
+	Enums map[string][]string
+}
+
+// SpecEndpoint is an operation of the specification
+type SpecEndpoint struct {
+	// Method is the HTTP method
+	Method string
+	// Path is the path template (e.g. "/law_data/{law_id_or_num_or_revision_id}")
+	Path string
+	// OperationID is the operation ID of the specification (e.g. "get-law_data")
+	OperationID string
+	// Summary is the operation summary
+	Summary string
+}
+
+var parseSpec = sync.OnceValues(func() (*SpecInfo, error) {
+	var doc struct {
+		Info struct {
+			Title   string `yaml:"title"`
+			Version string `yaml:"version"`
+		} `yaml:"info"`
+		Servers []struct {
+			URL string `yaml:"url"`
+		} `yaml:"servers"`
+		Paths map[string]map[string]struct {
+			OperationID string `yaml:"operationId"`
+			Summary     string `yaml:"summary"`
+		} `yaml:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Enum []string `yaml:"enum"`
+			} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(specYAML, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded spec: %w", err)
+	}
+
+	info := &SpecInfo{
+		Title:   doc.Info.Title,
+		Version: doc.Info.Version,
+		Enums:   map[string][]string{},
+	}
+	if len(doc.Servers) > 0 {
+		info.BaseURL = doc.Servers[0].URL
+	}
+	for path, operations := range doc.Paths {
+		for method, operation := range operations {
+			info.Endpoints = append(info.Endpoints, SpecEndpoint{
+				Method:      strings.ToUpper(method),
+				Path:        path,
+				OperationID: operation.OperationID,
+				Summary:     strings.TrimSpace(operation.Summary),
+			})
+		}
+	}
+	sort.Slice(info.Endpoints, func(i, j int) bool {
+		a, b := info.Endpoints[i], info.Endpoints[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	for name, schema := range doc.Components.Schemas {
+		if len(schema.Enum) > 0 {
+			info.Enums[specTypeName(name)] = schema.Enum
+		}
+	}
+	return info, nil
+})
+
+// Spec returns metadata of the OpenAPI specification embedded in the package,
+// i.e. the specification the client was generated from. The result is shared
+// and must not be modified.
+func Spec() *SpecInfo {
+	info, err := parseSpec()
+	if err != nil {
+		// The embedded spec is generated alongside the package and always valid
+		panic(err)
+	}
+	return info
+}
+
+// SpecYAML returns the embedded OpenAPI specification as YAML
+func SpecYAML() []byte {
+	return append([]byte(nil), specYAML...)
+}
+
+// specTypeName converts a schema name to its generated type name (e.g. "law_type" → "LawType")
+func specTypeName(name string) string {
+	var sb strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == ' ' }) {
+		sb.WriteString(strings.ToUpper(word[:1]) + strings.ToLower(word[1:]))
+	}
+	return sb.String()
+}
@@ -0,0 +1,116 @@
+package lawapi
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SubsetSpec selects a slice of the corpus, so that a mirror or export can start with the
+// laws of interest instead of the whole corpus
+type SubsetSpec struct {
+	// Categories selects the laws in any of the categories
+	Categories []CategoryCd `json:"categories,omitempty" yaml:"categories,omitempty"`
+	// LawTypes restricts the laws selected by Categories to these types
+	LawTypes []LawType `json:"law_types,omitempty" yaml:"law_types,omitempty"`
+	// LawIDs selects additional laws by ID
+	LawIDs []string `json:"law_ids,omitempty" yaml:"law_ids,omitempty"`
+}
+
+// SubsetPresets are subsets of common fields by name, for use with SubsetPreset
+var SubsetPresets = map[string]SubsetSpec{
+	"civil":        {Categories: []CategoryCd{CategoryCdCivil, CategoryCdCommerce, CategoryCdJudiciary}},
+	"construction": {Categories: []CategoryCd{CategoryCdBuildingHousing, CategoryCdCityPlanning, CategoryCdLand, CategoryCdRoads, CategoryCdRivers}},
+	"criminal":     {Categories: []CategoryCd{CategoryCdCriminal, CategoryCdPolice}},
+	"environment":  {Categories: []CategoryCd{CategoryCdEnvironmentalProtect}},
+	"finance":      {Categories: []CategoryCd{CategoryCdFinanceInsurance, CategoryCdForeignExchangeTrade, CategoryCdFinanceGeneral, CategoryCdNationalBonds}},
+	"health":       {Categories: []CategoryCd{CategoryCdPublicHealth, CategoryCdSocialWelfare, CategoryCdSocialInsurance}},
+	"labor":        {Categories: []CategoryCd{CategoryCdLabor, CategoryCdSocialInsurance}},
+	"local":        {Categories: []CategoryCd{CategoryCdLocalGovernment, CategoryCdLocalFinance}},
+	"tax":          {Categories: []CategoryCd{CategoryCdNationalTax, CategoryCdLocalFinance}},
+	"telecom":      {Categories: []CategoryCd{CategoryCdTelecommunications, CategoryCdPostalService}},
+	"transport":    {Categories: []CategoryCd{CategoryCdLandTransport, CategoryCdMaritimeTransport, CategoryCdAviation, CategoryCdFreightTransport}},
+}
+
+// SubsetPreset returns the preset subset of a name in SubsetPresets
+func SubsetPreset(name string) (SubsetSpec, error) {
+	spec, ok := SubsetPresets[name]
+	if !ok {
+		return SubsetSpec{}, WithErrorCategory(fmt.Errorf("unknown subset preset %q (presets: %s)", name, strings.Join(SubsetPresetNames(), ", ")), ErrorValidation)
+	}
+	return spec, nil
+}
+
+// SubsetPresetNames returns the sorted names of SubsetPresets
+func SubsetPresetNames() []string {
+	names := make([]string, 0, len(SubsetPresets))
+	for name := range SubsetPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Merge returns the union of two subsets. Law types restrict the categories of both.
+func (s SubsetSpec) Merge(other SubsetSpec) SubsetSpec {
+	return SubsetSpec{
+		Categories: sortedUnion(s.Categories, other.Categories),
+		LawTypes:   sortedUnion(s.LawTypes, other.LawTypes),
+		LawIDs:     sortedUnion(s.LawIDs, other.LawIDs),
+	}
+}
+
+// sortedUnion returns the sorted distinct values of a and b
+func sortedUnion[T cmp.Ordered](a, b []T) []T {
+	result := slices.Concat(a, b)
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// Query returns the query of the laws selected by Categories and LawTypes, or nil if the
+// subset has no categories
+func (s SubsetSpec) Query() Query {
+	if len(s.Categories) == 0 {
+		return nil
+	}
+	q := InCategory(s.Categories...)
+	if len(s.LawTypes) > 0 {
+		q = And(q, OfLawType(s.LawTypes...))
+	}
+	return q
+}
+
+// ResolveSubset returns the sorted IDs of the laws in a subset, searching the categories
+// with Search. Unless opts says otherwise, all laws of the categories are fetched.
+func (c *Client) ResolveSubset(ctx context.Context, spec SubsetSpec, opts *SearchOptions) ([]string, error) {
+	ids := slices.Clone(spec.LawIDs)
+	if q := spec.Query(); q != nil {
+		o := SearchOptions{MaxResultsPerCall: 100000, PageSize: 1000}
+		if opts != nil {
+			o = *opts
+		}
+		items, err := c.Search(ctx, q, &o)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.LawInfo != nil && item.LawInfo.LawId != "" {
+				ids = append(ids, item.LawInfo.LawId)
+			}
+		}
+	}
+	return sortedUnion(ids, nil), nil
+}
+
+// SubsetAPIPaths returns the law_data API paths of laws, relative to the API root, as
+// downloaded into a mirror with "jplaw-serve -fetch"
+func SubsetAPIPaths(lawIDs []string) []string {
+	paths := make([]string, len(lawIDs))
+	for i, id := range lawIDs {
+		paths[i] = "law_data/" + id
+	}
+	return paths
+}
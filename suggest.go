@@ -0,0 +1,143 @@
+package lawapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSuperseded is returned by KeywordSuggester.Suggest when a newer query was
+// submitted before the debounce delay elapsed
+var ErrSuperseded = errors.New("query superseded by a newer query")
+
+// KeywordSuggester coalesces rapid successive keyword queries, such as those sent
+// while a user is typing, for autocomplete backends. Use one suggester per input session.
+//
+// Each query waits for the debounce delay; queries superseded by a newer query during
+// that time return ErrSuperseded. Queries that normalize to the same keyword share a
+// single in-flight request. In-flight requests are canceled when a newer query for
+// another keyword is sent, their callers getting ErrSuperseded, and when the contexts of
+// all their callers are done.
+type KeywordSuggester struct {
+	client *Client
+	params GetKeywordParams
+	delay  time.Duration
+
+	mu         sync.Mutex
+	generation uint64
+	inflight   map[string]*keywordCall
+}
+
+type keywordCall struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	// waiters is the number of callers waiting for the result
+	waiters int
+	// superseded is set when a newer query canceled the request
+	superseded bool
+	result     *KeywordResponse
+	err        error
+}
+
+// NewKeywordSuggester creates a suggester sending keyword queries with the given base
+// parameters (the Keyword field is ignored) after the debounce delay
+func NewKeywordSuggester(client *Client, params *GetKeywordParams, delay time.Duration) *KeywordSuggester {
+	s := &KeywordSuggester{
+		client:   client,
+		delay:    delay,
+		inflight: map[string]*keywordCall{},
+	}
+	if params != nil {
+		s.params = *params
+	}
+	return s
+}
+
+// Suggest searches the keyword once the debounce delay has elapsed without a newer query.
+// It returns ErrSuperseded when a newer query arrives first, and ctx.Err() when ctx is done
+// before the result is available.
+func (s *KeywordSuggester) Suggest(ctx context.Context, keyword string) (*KeywordResponse, error) {
+	keyword = NormalizeKeyword(keyword)
+
+	s.mu.Lock()
+	s.generation++
+	generation := s.generation
+	s.mu.Unlock()
+
+	if err := sleepContext(ctx, s.client.currentClock(), s.delay); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.generation != generation {
+		s.mu.Unlock()
+		return nil, ErrSuperseded
+	}
+	for other, otherCall := range s.inflight {
+		if other != keyword {
+			otherCall.superseded = true
+			otherCall.cancel()
+			delete(s.inflight, other)
+		}
+	}
+	call, ok := s.inflight[keyword]
+	if !ok {
+		// The request outlives the caller that started it as long as others wait for it
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &keywordCall{done: make(chan struct{}), cancel: cancel}
+		s.inflight[keyword] = call
+		go s.run(callCtx, keyword, call)
+	}
+	call.waiters++
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		s.release(keyword, call)
+		return nil, ctx.Err()
+	case <-call.done:
+		return call.result, call.err
+	}
+}
+
+// release removes a caller whose context is done from the waiters of a request, and
+// cancels the request if it was the last
+func (s *KeywordSuggester) release(keyword string, call *keywordCall) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	call.waiters--
+	if call.waiters == 0 {
+		call.cancel()
+		if s.inflight[keyword] == call {
+			delete(s.inflight, keyword)
+		}
+	}
+}
+
+func (s *KeywordSuggester) run(ctx context.Context, keyword string, call *keywordCall) {
+	params := s.params
+	params.Keyword = keyword
+	result, err := s.client.GetKeywordWithContext(ctx, &params)
+
+	s.mu.Lock()
+	if s.inflight[keyword] == call {
+		delete(s.inflight, keyword)
+	}
+	if call.superseded {
+		result, err = nil, ErrSuperseded
+	}
+	call.result, call.err = result, err
+	s.mu.Unlock()
+	call.cancel()
+	close(call.done)
+}
+
+// NormalizeKeyword trims a keyword query and collapses runs of half-width and
+// full-width spaces into a single half-width space
+func NormalizeKeyword(keyword string) string {
+	return strings.Join(strings.FieldsFunc(keyword, func(r rune) bool {
+		return r == ' ' || r == '　' || r == '\t' || r == '\n'
+	}), " ")
+}
@@ -0,0 +1,164 @@
+package lawapi
+
+import (
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// Table is a text table whose columns are aligned by display width, so that Japanese text
+// lines up in terminals where full-width characters take two columns
+type Table struct {
+	Header []string
+	Rows   [][]string
+	// MaxColumnWidth truncates cells wider than this many columns with "…", or 0 for no limit
+	MaxColumnWidth int
+	// AmbiguousWide counts characters of ambiguous East Asian width (○, ※, Greek and
+	// Cyrillic letters, ...) as two columns, as terminals in Japanese locales often do
+	AmbiguousWide bool
+}
+
+// DisplayWidth returns the number of terminal columns of s: two for East Asian wide and
+// full-width characters, none for combining marks and control characters, and one for
+// others, including characters of ambiguous width
+func DisplayWidth(s string) int {
+	return displayWidth(s, false)
+}
+
+func displayWidth(s string, ambiguousWide bool) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r, ambiguousWide)
+	}
+	return n
+}
+
+func runeWidth(r rune, ambiguousWide bool) int {
+	if unicode.IsControl(r) || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	case width.EastAsianAmbiguous:
+		if ambiguousWide {
+			return 2
+		}
+	}
+	return 1
+}
+
+// Write writes the table: the header, a rule, and the rows, with columns separated by
+// two spaces. Line breaks and tabs in cells are written as spaces.
+func (t *Table) Write(w io.Writer) error {
+	rows := make([][]string, 0, len(t.Rows)+1)
+	if t.Header != nil {
+		rows = append(rows, t.Header)
+	}
+	rows = append(rows, t.Rows...)
+
+	var widths []int
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = make([]string, len(row))
+		for j, cell := range row {
+			cell = t.cell(cell)
+			cells[i][j] = cell
+			if j == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[j] = max(widths[j], displayWidth(cell, t.AmbiguousWide))
+		}
+	}
+
+	var sb strings.Builder
+	writeRow := func(row []string) {
+		line := ""
+		for j, cell := range row {
+			if j > 0 {
+				line += "  "
+			}
+			line += cell + strings.Repeat(" ", widths[j]-displayWidth(cell, t.AmbiguousWide))
+		}
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	for i, row := range cells {
+		writeRow(row)
+		if i == 0 && t.Header != nil {
+			rule := make([]string, len(widths))
+			for j, n := range widths {
+				rule[j] = strings.Repeat("-", n)
+			}
+			writeRow(rule)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// cell prepares the text of a cell: whitespace is flattened and long text truncated
+func (t *Table) cell(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		return r
+	}, s)
+	if t.MaxColumnWidth <= 0 || displayWidth(s, t.AmbiguousWide) <= t.MaxColumnWidth {
+		return s
+	}
+	ellipsis := runeWidth('…', t.AmbiguousWide)
+	var sb strings.Builder
+	n := 0
+	for _, r := range s {
+		w := runeWidth(r, t.AmbiguousWide)
+		if n+w > t.MaxColumnWidth-ellipsis {
+			break
+		}
+		sb.WriteRune(r)
+		n += w
+	}
+	return sb.String() + "…"
+}
+
+// LawRecordsTable returns a table of law records
+func LawRecordsTable(records []LawRecord) *Table {
+	t := &Table{Header: []string{"LAW ID", "LAW NUM", "TITLE", "TYPE", "CATEGORY", "PROMULGATED", "REVISION"}}
+	for _, r := range records {
+		promulgated := ""
+		if r.PromulgationDate != nil {
+			promulgated = r.PromulgationDate.String()
+		}
+		t.Rows = append(t.Rows, []string{r.LawID, r.LawNum, r.Title, string(r.LawType), r.Category, promulgated, r.RevisionID})
+	}
+	return t
+}
+
+// Table returns a table of the law entries of the response
+func (r *LawsResponse) Table() *Table {
+	return LawRecordsTable(r.Flatten())
+}
+
+// Table returns a table of the revisions of the response, with their enforcement dates
+// and amending laws
+func (r *LawRevisionsResponse) Table() *Table {
+	t := &Table{Header: []string{"REVISION", "TITLE", "ENFORCED", "AMENDMENT LAW NUM", "STATUS", "REPEAL"}}
+	if r == nil {
+		return t
+	}
+	for i := range r.Revisions {
+		rev := &r.Revisions[i]
+		enforced := ""
+		if date := rev.GetAmendmentEnforcementDate(); !time.Time(date).IsZero() {
+			enforced = date.String()
+		}
+		t.Rows = append(t.Rows, []string{
+			rev.GetLawRevisionId(), rev.GetLawTitle(), enforced, rev.GetAmendmentLawNum(),
+			string(rev.GetCurrentRevisionStatus()), string(rev.GetRepealStatus()),
+		})
+	}
+	return t
+}
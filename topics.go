@@ -0,0 +1,122 @@
+package lawapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TopicRule tags laws matching its conditions with a topic. Each non-empty condition must
+// hold, and a condition holds when any of its values matches. Text is compared after
+// NormalizeText.
+type TopicRule struct {
+	// Topic is the tag given to matching laws (e.g. "radio spectrum")
+	Topic string `yaml:"topic" json:"topic"`
+	// TitleContains matches laws whose title contains any of the strings
+	TitleContains []string `yaml:"title_contains,omitempty" json:"title_contains,omitempty"`
+	// TextContains matches laws whose text contains any of the strings
+	TextContains []string `yaml:"text_contains,omitempty" json:"text_contains,omitempty"`
+	// Categories matches laws in any of the categories, by name (e.g. "情報通信")
+	Categories []string `yaml:"categories,omitempty" json:"categories,omitempty"`
+	// LawTypes matches laws of any of the types (e.g. "Act")
+	LawTypes []string `yaml:"law_types,omitempty" json:"law_types,omitempty"`
+	// Authorities matches laws issued by any of the authorities, as inferred by InferAuthorities
+	Authorities []string `yaml:"authorities,omitempty" json:"authorities,omitempty"`
+}
+
+// TopicRules is an ordered set of tagging rules
+type TopicRules []TopicRule
+
+// LoadTopicRules reads rules from a YAML list of TopicRule mappings using the yaml field
+// names, e.g. {topic: radio spectrum, title_contains: [電波]}
+func LoadTopicRules(r io.Reader) (TopicRules, error) {
+	var rules TopicRules
+	if err := yaml.NewDecoder(r).Decode(&rules); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse topic rules: %w", err)
+	}
+	for i, rule := range rules {
+		if strings.TrimSpace(rule.Topic) == "" {
+			return nil, fmt.Errorf("topic rule %d has no topic", i+1)
+		}
+		if len(rule.TitleContains)+len(rule.TextContains)+len(rule.Categories)+len(rule.LawTypes)+len(rule.Authorities) == 0 {
+			return nil, fmt.Errorf("topic rule %q has no conditions", rule.Topic)
+		}
+	}
+	return rules, nil
+}
+
+// Matches reports whether the rule matches a search document
+func (r TopicRule) Matches(doc SearchDocument) bool {
+	if len(r.TitleContains) > 0 && !containsAnyNormalized(doc.LawTitle, r.TitleContains) {
+		return false
+	}
+	if len(r.TextContains) > 0 && !containsAnyNormalized(doc.Text, r.TextContains) {
+		return false
+	}
+	if len(r.Categories) > 0 && !slices.Contains(r.Categories, doc.Category) {
+		return false
+	}
+	if len(r.LawTypes) > 0 && !slices.Contains(r.LawTypes, doc.LawType) {
+		return false
+	}
+	if len(r.Authorities) > 0 && !slices.ContainsFunc(doc.Authorities, func(a string) bool {
+		return slices.Contains(r.Authorities, a)
+	}) {
+		return false
+	}
+	return true
+}
+
+// Tags returns the topics of the rules matching a search document, sorted and deduplicated
+func (rules TopicRules) Tags(doc SearchDocument) []string {
+	var tags []string
+	for _, rule := range rules {
+		if rule.Matches(doc) {
+			tags = append(tags, rule.Topic)
+		}
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+func containsAnyNormalized(s string, substrings []string) bool {
+	s = NormalizeText(s)
+	for _, sub := range substrings {
+		if strings.Contains(s, NormalizeText(sub)) {
+			return true
+		}
+	}
+	return false
+}
+
+// TaggingSource is a DocumentSource setting the Tags of the documents of another source.
+// Laws are tagged by their law document, whose tags also apply to the article documents of
+// the same law that follow it, as NewSearchDocuments produces them. Articles without a
+// preceding law document are tagged by their own content.
+type TaggingSource struct {
+	// Source produces the documents to tag
+	Source DocumentSource
+	// Rules are the tagging rules
+	Rules TopicRules
+}
+
+// Documents implements DocumentSource
+func (s *TaggingSource) Documents(ctx context.Context, yield func(doc SearchDocument) error) error {
+	lawID, lawTags := "", []string(nil)
+	return s.Source.Documents(ctx, func(doc SearchDocument) error {
+		if doc.Kind == "law" {
+			lawID, lawTags = doc.LawID, s.Rules.Tags(doc)
+			doc.Tags = lawTags
+		} else if doc.LawID == lawID {
+			doc.Tags = lawTags
+		} else {
+			doc.Tags = s.Rules.Tags(doc)
+		}
+		doc.ContentHash = doc.Hash()
+		return yield(doc)
+	})
+}
@@ -22,6 +22,16 @@ const (
 	AmendmentType8 AmendmentType = "8"
 )
 
+// IsKnown reports whether the value is one of the values of AmendmentType in the specification.
+// Values added to the API later are kept as received when decoding leniently.
+func (a AmendmentType) IsKnown() bool {
+	switch a {
+	case AmendmentType1, AmendmentType3, AmendmentType8:
+		return true
+	}
+	return false
+}
+
 // AttachedFile represents field from the API response
 type AttachedFile struct {
 	// LawRevisionId represents law ID
@@ -96,6 +106,16 @@ const (
 	CategoryCdForeignAffairs CategoryCd = "050"
 )
 
+// IsKnown reports whether the value is one of the values of CategoryCd in the specification.
+// Values added to the API later are kept as received when decoding leniently.
+func (c CategoryCd) IsKnown() bool {
+	switch c {
+	case CategoryCdConstitution, CategoryCdCriminal, CategoryCdFinanceGeneral, CategoryCdFisheries, CategoryCdTourism, CategoryCdParliament, CategoryCdPolice, CategoryCdNationalProperty, CategoryCdMining, CategoryCdPostalService, CategoryCdAdministrativeOrg, CategoryCdFireService, CategoryCdNationalTax, CategoryCdIndustry, CategoryCdTelecommunications, CategoryCdCivilService, CategoryCdNationalDevelopment, CategoryCdBusiness, CategoryCdCommerce, CategoryCdLabor, CategoryCdAdministrativeProc, CategoryCdLand, CategoryCdNationalBonds, CategoryCdFinanceInsurance, CategoryCdEnvironmentalProtect, CategoryCdStatistics, CategoryCdCityPlanning, CategoryCdEducation, CategoryCdForeignExchangeTrade, CategoryCdPublicHealth, CategoryCdLocalGovernment, CategoryCdRoads, CategoryCdCulture, CategoryCdLandTransport, CategoryCdSocialWelfare, CategoryCdLocalFinance, CategoryCdRivers, CategoryCdIndustryGeneral, CategoryCdMaritimeTransport, CategoryCdSocialInsurance, CategoryCdJudiciary, CategoryCdDisasterManagement, CategoryCdAgriculture, CategoryCdAviation, CategoryCdDefense, CategoryCdCivil, CategoryCdBuildingHousing, CategoryCdForestry, CategoryCdFreightTransport, CategoryCdForeignAffairs:
+		return true
+	}
+	return false
+}
+
 // CurrentRevisionStatus represents historyのstatus: * `CurrentEnforced` - 現施行法令 * `UnEnforced` - 未施行法令 * `PreviousEnforced` - 過去施行法令 * `Repeal` - repeal法令（repeal・失効・実効性喪失）
 type CurrentRevisionStatus string
 
@@ -106,6 +126,16 @@ const (
 	CurrentRevisionStatusRepeal CurrentRevisionStatus = "Repeal"
 )
 
+// IsKnown reports whether the value is one of the values of CurrentRevisionStatus in the specification.
+// Values added to the API later are kept as received when decoding leniently.
+func (c CurrentRevisionStatus) IsKnown() bool {
+	switch c {
+	case CurrentRevisionStatusCurrentenforced, CurrentRevisionStatusUnenforced, CurrentRevisionStatusPreviousenforced, CurrentRevisionStatusRepeal:
+		return true
+	}
+	return false
+}
+
 // Elm represents field from the API response
 type Elm string
 
@@ -128,6 +158,16 @@ const (
 	FileTypeDocx FileType = "docx"
 )
 
+// IsKnown reports whether the value is one of the values of FileType in the specification.
+// Values added to the API later are kept as received when decoding leniently.
+func (f FileType) IsKnown() bool {
+	switch f {
+	case FileTypeXml, FileTypeJson, FileTypeHtml, FileTypeRtf, FileTypeDocx:
+		return true
+	}
+	return false
+}
+
 // KeywordResponse represents field from the API response
 type KeywordResponse struct {
 	// Items represents law ID単位のinformationリスト * `revision_info` - 指定時点において効力を持つ版のメタinformation
@@ -180,6 +220,16 @@ const (
 	LawNumEraReiwa LawNumEra = "Reiwa"
 )
 
+// IsKnown reports whether the value is one of the values of LawNumEra in the specification.
+// Values added to the API later are kept as received when decoding leniently.
+func (l LawNumEra) IsKnown() bool {
+	switch l {
+	case LawNumEraMeiji, LawNumEraTaisho, LawNumEraShowa, LawNumEraHeisei, LawNumEraReiwa:
+		return true
+	}
+	return false
+}
+
 // LawNumType represents law numberの法令type: * `Constitution` - 憲法 * `Act` - 法律 * `CabinetOrder` - 政令 * `ImperialOrder` - 勅令 * `MinisterialOrdinance` - 府省令 * `Rule` - 規則 * `Misc` - その他
 type LawNumType string
 
@@ -193,6 +243,16 @@ const (
 	LawNumTypeMisc LawNumType = "Misc"
 )
 
+// IsKnown reports whether the value is one of the values of LawNumType in the specification.
+// Values added to the API later are kept as received when decoding leniently.
+func (l LawNumType) IsKnown() bool {
+	switch l {
+	case LawNumTypeConstitution, LawNumTypeAct, LawNumTypeCabinetorder, LawNumTypeImperialorder, LawNumTypeMinisterialordinance, LawNumTypeRule, LawNumTypeMisc:
+		return true
+	}
+	return false
+}
+
 // LawRevisionsResponse represents field from the API response
 type LawRevisionsResponse struct {
 	LawInfo LawInfo `json:"law_info"`
@@ -213,6 +273,16 @@ const (
 	LawTypeMisc LawType = "Misc"
 )
 
+// IsKnown reports whether the value is one of the values of LawType in the specification.
+// Values added to the API later are kept as received when decoding leniently.
+func (l LawType) IsKnown() bool {
+	switch l {
+	case LawTypeConstitution, LawTypeAct, LawTypeCabinetorder, LawTypeImperialorder, LawTypeMinisterialordinance, LawTypeRule, LawTypeMisc:
+		return true
+	}
+	return false
+}
+
 // LawsResponse represents field from the API response
 type LawsResponse struct {
 	// Count represents field from the API response
@@ -233,6 +303,16 @@ const (
 	MissionPartial Mission = "Partial"
 )
 
+// IsKnown reports whether the value is one of the values of Mission in the specification.
+// Values added to the API later are kept as received when decoding leniently.
+func (m Mission) IsKnown() bool {
+	switch m {
+	case MissionNew, MissionPartial:
+		return true
+	}
+	return false
+}
+
 // RepealStatus represents repeal等のstatus: * `None` - repeal・失効等のstatusなし * `Repeal` - repeal * `Expire` - 失効 * `Suspend` - 停止 * `LossOfEffectiveness` - 実効性喪失
 type RepealStatus string
 
@@ -244,6 +324,16 @@ const (
 	RepealStatusLossofeffectiveness RepealStatus = "LossOfEffectiveness"
 )
 
+// IsKnown reports whether the value is one of the values of RepealStatus in the specification.
+// Values added to the API later are kept as received when decoding leniently.
+func (r RepealStatus) IsKnown() bool {
+	switch r {
+	case RepealStatusNone, RepealStatusRepeal, RepealStatusExpire, RepealStatusSuspend, RepealStatusLossofeffectiveness:
+		return true
+	}
+	return false
+}
+
 // ResponseFormat represents レスポンスformat（`json` 又は `xml`）
 type ResponseFormat string
 
@@ -252,6 +342,16 @@ const (
 	ResponseFormatXml ResponseFormat = "xml"
 )
 
+// IsKnown reports whether the value is one of the values of ResponseFormat in the specification.
+// Values added to the API later are kept as received when decoding leniently.
+func (r ResponseFormat) IsKnown() bool {
+	switch r {
+	case ResponseFormatJson, ResponseFormatXml:
+		return true
+	}
+	return false
+}
+
 // RevisionInfo represents field from the API response
 type RevisionInfo struct {
 	// Abbrev represents field from the API response
@@ -0,0 +1,206 @@
+package lawapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EmbeddingProvider computes embedding vectors of texts, e.g. by calling an embedding model API
+type EmbeddingProvider interface {
+	// Embed returns one vector per text, in the order of the texts
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// VectorEntry is an embedded passage of law text
+type VectorEntry struct {
+	// ID identifies the passage, typically a ProvisionID
+	ID string `json:"id"`
+	// LawID is the law the passage belongs to
+	LawID string `json:"law_id"`
+	// Label is a human-readable citation of the passage (e.g. "第二条")
+	Label string `json:"label"`
+	// Text is the embedded text
+	Text string `json:"text"`
+	// Vector is the embedding of Text
+	Vector []float32 `json:"vector"`
+}
+
+// VectorMatch is a search result of VectorIndex.Search
+type VectorMatch struct {
+	VectorEntry
+	// Score is the cosine similarity to the query
+	Score float64
+}
+
+// VectorIndex is an in-memory index of embedded passages for semantic search, persisted
+// as a flat JSON Lines file with Save and LoadVectorIndex. Search is exhaustive, which
+// is fast enough for the article-level passages of thousands of laws.
+type VectorIndex struct {
+	mu      sync.RWMutex
+	entries map[string]VectorEntry
+}
+
+// NewVectorIndex creates an empty index
+func NewVectorIndex() *VectorIndex {
+	return &VectorIndex{entries: map[string]VectorEntry{}}
+}
+
+// Add adds entries to the index, replacing entries with the same ID
+func (ix *VectorIndex) Add(entries ...VectorEntry) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for _, entry := range entries {
+		ix.entries[entry.ID] = entry
+	}
+}
+
+// RemoveLaw removes all entries of a law
+func (ix *VectorIndex) RemoveLaw(lawID string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for id, entry := range ix.entries {
+		if entry.LawID == lawID {
+			delete(ix.entries, id)
+		}
+	}
+}
+
+// Len returns the number of entries
+func (ix *VectorIndex) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.entries)
+}
+
+// IndexLaw embeds the articles of a parsed law and adds them to the index, replacing
+// previously indexed passages of the law. Each article is one passage identified by
+// its ProvisionID; laws and supplementary provisions without articles are indexed by
+// paragraph.
+func (ix *VectorIndex) IndexLaw(ctx context.Context, provider EmbeddingProvider, lawID string, law *LawNode) error {
+	var entries []VectorEntry
+	for _, passage := range law.provisionRefs() {
+		node := passage.Article
+		if node == nil {
+			node = passage.Paragraph
+		}
+		text := strings.TrimSpace(node.PlainText())
+		if text == "" {
+			continue
+		}
+		entries = append(entries, VectorEntry{ID: passage.ProvisionID(lawID, "").String(), LawID: lawID, Label: passage.Label(), Text: text})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	texts := make([]string, len(entries))
+	for i, entry := range entries {
+		texts[i] = entry.Text
+	}
+	vectors, err := provider.Embed(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("failed to embed %s: %w", lawID, err)
+	}
+	if len(vectors) != len(entries) {
+		return fmt.Errorf("failed to embed %s: expected %d vectors, got %d", lawID, len(entries), len(vectors))
+	}
+	for i := range entries {
+		entries[i].Vector = vectors[i]
+	}
+
+	ix.RemoveLaw(lawID)
+	ix.Add(entries...)
+	return nil
+}
+
+// Search embeds the query and returns the k entries most similar to it, most similar first
+func (ix *VectorIndex) Search(ctx context.Context, provider EmbeddingProvider, query string, k int) ([]VectorMatch, error) {
+	vectors, err := provider.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("failed to embed query: expected 1 vector, got %d", len(vectors))
+	}
+	return ix.SearchVector(vectors[0], k), nil
+}
+
+// SearchVector returns the k entries most similar to the vector, most similar first
+func (ix *VectorIndex) SearchVector(vector []float32, k int) []VectorMatch {
+	ix.mu.RLock()
+	matches := make([]VectorMatch, 0, len(ix.entries))
+	for _, entry := range ix.entries {
+		matches = append(matches, VectorMatch{VectorEntry: entry, Score: cosineSimilarity(vector, entry.Vector)})
+	}
+	ix.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if k > 0 && len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches
+}
+
+// Save writes the index as JSON Lines, one entry per line, sorted by ID
+func (ix *VectorIndex) Save(w io.Writer) error {
+	ix.mu.RLock()
+	ids := make([]string, 0, len(ix.entries))
+	for id := range ix.entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	for _, id := range ids {
+		if err := encoder.Encode(ix.entries[id]); err != nil {
+			ix.mu.RUnlock()
+			return err
+		}
+	}
+	ix.mu.RUnlock()
+	return bw.Flush()
+}
+
+// LoadVectorIndex reads an index written by VectorIndex.Save
+func LoadVectorIndex(r io.Reader) (*VectorIndex, error) {
+	ix := NewVectorIndex()
+	decoder := json.NewDecoder(r)
+	for {
+		var entry VectorEntry
+		if err := decoder.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read vector index: %w", err)
+		}
+		ix.entries[entry.ID] = entry
+	}
+	return ix, nil
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
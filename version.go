@@ -0,0 +1,100 @@
+package lawapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// specPath is the location of the OpenAPI specification published by the server, relative to the base URL
+const specPath = "/swagger-ui/lawapi-v2.yaml"
+
+// VersionCheck compares the API version of the server with the version the client was generated against
+type VersionCheck struct {
+	// ClientVersion is the version of the embedded specification
+	ClientVersion string
+	// ServerVersion is the version of the specification published by the server
+	ServerVersion string
+}
+
+// Drifted reports whether the server version differs from the client version
+func (v *VersionCheck) Drifted() bool {
+	return v.ClientVersion != v.ServerVersion
+}
+
+// Compatible reports whether both versions share the same major version
+func (v *VersionCheck) Compatible() bool {
+	major := func(version string) string {
+		return strings.SplitN(version, ".", 2)[0]
+	}
+	return major(v.ClientVersion) == major(v.ServerVersion)
+}
+
+// Warning returns a human-readable warning about version drift, or an empty string
+func (v *VersionCheck) Warning() string {
+	switch {
+	case !v.Drifted():
+		return ""
+	case !v.Compatible():
+		return fmt.Sprintf("lawapi: server API version %s is incompatible with client version %s; regenerate the client", v.ServerVersion, v.ClientVersion)
+	default:
+		return fmt.Sprintf("lawapi: server API version %s differs from client version %s", v.ServerVersion, v.ClientVersion)
+	}
+}
+
+// CheckVersion fetches the specification published by the server and compares its
+// version with the embedded specification
+func (c *Client) CheckVersion(ctx context.Context) (*VersionCheck, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+specPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var doc struct {
+		Info struct {
+			Version string `yaml:"version"`
+		} `yaml:"info"`
+	}
+	if err := yaml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode server spec: %w", err)
+	}
+	if doc.Info.Version == "" {
+		return nil, fmt.Errorf("server spec has no version")
+	}
+	return &VersionCheck{ClientVersion: Spec().Version, ServerVersion: doc.Info.Version}, nil
+}
+
+// WarnVersionDrift checks the server version and logs a warning to logger (log.Default() if nil)
+// when it differs from the client version. Failures to check are logged as well, so it can be
+// called at startup without affecting the application.
+func (c *Client) WarnVersionDrift(ctx context.Context, logger *log.Logger) {
+	if logger == nil {
+		logger = log.Default()
+	}
+	check, err := c.CheckVersion(ctx)
+	if err != nil {
+		logger.Printf("lawapi: failed to check server API version: %v", err)
+		return
+	}
+	if warning := check.Warning(); warning != "" {
+		logger.Print(warning)
+	}
+}
@@ -0,0 +1,117 @@
+package lawapi
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// eras lists the eras from the newest to the oldest with their first day
+var eras = []struct {
+	era   LawNumEra
+	kanji string
+	start time.Time
+}{
+	{LawNumEraReiwa, "令和", time.Date(2019, 5, 1, 0, 0, 0, 0, JST)},
+	{LawNumEraHeisei, "平成", time.Date(1989, 1, 8, 0, 0, 0, 0, JST)},
+	{LawNumEraShowa, "昭和", time.Date(1926, 12, 25, 0, 0, 0, 0, JST)},
+	{LawNumEraTaisho, "大正", time.Date(1912, 7, 30, 0, 0, 0, 0, JST)},
+	{LawNumEraMeiji, "明治", time.Date(1868, 1, 25, 0, 0, 0, 0, JST)},
+}
+
+// Kanji returns the Japanese name of the era (e.g. "令和"), or an empty string for unknown eras
+func (e LawNumEra) Kanji() string {
+	for _, def := range eras {
+		if def.era == e {
+			return def.kanji
+		}
+	}
+	return ""
+}
+
+// Year converts a year of the era to the Gregorian year (e.g. Reiwa 6 → 2024).
+// It returns 0 for unknown eras.
+func (e LawNumEra) Year(eraYear int) int {
+	for _, def := range eras {
+		if def.era == e {
+			return def.start.Year() + eraYear - 1
+		}
+	}
+	return 0
+}
+
+// ParseEra returns the era with the given Japanese name (e.g. "令和")
+func ParseEra(kanji string) (LawNumEra, bool) {
+	for _, def := range eras {
+		if def.kanji == kanji {
+			return def.era, true
+		}
+	}
+	return "", false
+}
+
+// EraOf returns the era and the year of the era of a point in time in Japan Standard Time.
+// ok is false before the Meiji era.
+func EraOf(t time.Time) (era LawNumEra, eraYear int, ok bool) {
+	t = t.In(JST)
+	for _, def := range eras {
+		if !t.Before(def.start) {
+			return def.era, t.Year() - def.start.Year() + 1, true
+		}
+	}
+	return "", 0, false
+}
+
+// FormatWareki formats a date in the Japanese calendar as used in law text, e.g.
+// "令和6年5月27日". The first year of an era is written as 元年. It returns an empty
+// string for dates before the Meiji era.
+func FormatWareki(t time.Time) string {
+	era, eraYear, ok := EraOf(t)
+	if !ok {
+		return ""
+	}
+	t = t.In(JST)
+	year := strconv.Itoa(eraYear)
+	if eraYear == 1 {
+		year = "元"
+	}
+	return fmt.Sprintf("%s%s年%d月%d日", era.Kanji(), year, int(t.Month()), t.Day())
+}
+
+// Wareki formats the date in the Japanese calendar (see FormatWareki)
+func (d Date) Wareki() string {
+	return FormatWareki(d.Time())
+}
+
+var warekiPattern = regexp.MustCompile(`^\s*(明治|大正|昭和|平成|令和)\s*(元|[0-9０-９〇一二三四五六七八九十百]+)\s*年\s*([0-9０-９〇一二三四五六七八九十]+)\s*月\s*([0-9０-９〇一二三四五六七八九十]+)\s*日\s*$`)
+
+// ParseWareki parses a date written in the Japanese calendar, with Arabic, full-width
+// or kanji numerals and 元年 for the first year (e.g. "令和6年5月27日", "平成元年一月八日")
+func ParseWareki(s string) (Date, error) {
+	m := warekiPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Date{}, fmt.Errorf("invalid wareki date %q", s)
+	}
+	era, _ := ParseEra(m[1])
+
+	eraYear := int64(1)
+	if m[2] != "元" {
+		eraYear, _ = ParseKanjiNumber(m[2])
+	}
+	month, _ := ParseKanjiNumber(m[3])
+	day, _ := ParseKanjiNumber(m[4])
+
+	t := time.Date(era.Year(int(eraYear)), time.Month(month), int(day), 0, 0, 0, 0, time.UTC)
+	if eraYear < 1 || int64(t.Month()) != month || int64(t.Day()) != day {
+		return Date{}, fmt.Errorf("invalid wareki date %q", s)
+	}
+	// Dates past the end of an era are accepted, as laws enacted before an era change
+	// refer to later dates in the old era (e.g. 平成三十二年四月一日)
+	for _, def := range eras {
+		if def.era == era && time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, JST).Before(def.start) {
+			return Date{}, fmt.Errorf("invalid wareki date %q: before the start of %s", s, era.Kanji())
+		}
+	}
+	return Date(t), nil
+}
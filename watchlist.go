@@ -0,0 +1,68 @@
+package lawapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WatchList is a set of laws to follow, given by law ID or by title query
+type WatchList struct {
+	// LawIDs are law IDs
+	LawIDs []string `yaml:"law_ids,omitempty" json:"law_ids,omitempty"`
+	// Titles are title queries; laws whose title contains any of them belong to the list
+	Titles []string `yaml:"titles,omitempty" json:"titles,omitempty"`
+}
+
+// WatchLists maps the names of watch lists to the lists
+type WatchLists map[string]*WatchList
+
+// LoadWatchLists reads watch lists from a YAML mapping of names to lists, e.g.
+// {radio: {law_ids: [325AC0000000131], titles: [電波]}}
+func LoadWatchLists(r io.Reader) (WatchLists, error) {
+	lists := WatchLists{}
+	if err := yaml.NewDecoder(r).Decode(&lists); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse watch lists: %w", err)
+	}
+	for name, list := range lists {
+		if list == nil {
+			lists[name] = &WatchList{}
+		}
+	}
+	return lists, nil
+}
+
+// Save writes the watch lists as YAML sorted by name
+func (l WatchLists) Save(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	if err := enc.Encode(map[string]*WatchList(l)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// ResolveWatchList returns the law IDs of a watch list, searching its title queries,
+// sorted
+func (c *Client) ResolveWatchList(ctx context.Context, list *WatchList) ([]string, error) {
+	ids := slices.Clone(list.LawIDs)
+	if len(list.Titles) > 0 {
+		var queries []Query
+		for _, title := range list.Titles {
+			queries = append(queries, TitleContains(title))
+		}
+		laws, err := c.Search(ctx, Or(queries...), nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, law := range laws {
+			if law.LawInfo != nil {
+				ids = append(ids, law.LawInfo.LawId)
+			}
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids), nil
+}